@@ -0,0 +1,168 @@
+package admin
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"smart-parcel-locker/backend/adapter/http/middleware"
+	"smart-parcel-locker/backend/domain/admin"
+	"smart-parcel-locker/backend/pkg/logger"
+	"smart-parcel-locker/backend/pkg/response"
+	adminusecase "smart-parcel-locker/backend/usecase/admin"
+)
+
+type loginRequest struct {
+	Username     string `json:"username"`
+	Password     string `json:"password"`
+	OTPCode      string `json:"otp_code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+type setupPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// Login exchanges a username and password for an access and refresh token. Admins with
+// two-factor authentication also send otp_code or recovery_code.
+func (h *Handler) Login(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	var req loginRequest
+	if err := c.BodyParser(&req); err != nil {
+		logger.Warn(c.Context(), "admin login invalid body", map[string]interface{}{
+			"error": err.Error(),
+		}, requestURL)
+		return adminInvalidRequest(c, "invalid request body")
+	}
+	req.Username = strings.TrimSpace(req.Username)
+	if req.Username == "" || req.Password == "" {
+		return adminInvalidRequest(c, "username and password are required")
+	}
+	result, err := h.uc.Login(c.Context(), adminusecase.LoginInput{
+		Username:     req.Username,
+		Password:     req.Password,
+		Code:         req.OTPCode,
+		RecoveryCode: req.RecoveryCode,
+		IP:           c.IP(),
+		UserAgent:    c.Get(fiber.HeaderUserAgent),
+	})
+	if err != nil {
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: sessionToResponse(result)})
+}
+
+// Refresh rotates a session's tokens using its refresh token.
+func (h *Handler) Refresh(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	var req refreshRequest
+	if err := c.BodyParser(&req); err != nil {
+		logger.Warn(c.Context(), "admin refresh invalid body", map[string]interface{}{
+			"error": err.Error(),
+		}, requestURL)
+		return adminInvalidRequest(c, "invalid request body")
+	}
+	if req.RefreshToken == "" {
+		return adminInvalidRequest(c, "refresh_token is required")
+	}
+	result, err := h.uc.Refresh(c.Context(), req.RefreshToken)
+	if err != nil {
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: sessionToResponse(result)})
+}
+
+// Logout revokes the session of the presented access token.
+func (h *Handler) Logout(c *fiber.Ctx) error {
+	principal := middleware.AdminPrincipal(c)
+	if err := h.uc.Logout(c.Context(), principal.SessionID); err != nil {
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true})
+}
+
+// Me returns the authenticated admin with their permissions and two-factor state.
+func (h *Handler) Me(c *fiber.Ctx) error {
+	principal := middleware.AdminPrincipal(c)
+	result, err := h.uc.Get(c.Context(), principal.AdminID)
+	if err != nil {
+		return mapError(c, err)
+	}
+	mfa, err := h.uc.MFAStatus(c.Context(), principal.AdminID)
+	if err != nil {
+		return mapError(c, err)
+	}
+	data := adminToResponse(result)
+	data["permissions"] = admin.PermissionsFor(result.Role)
+	data["mfa"] = mfaStatusToResponse(mfa)
+	return c.JSON(response.APIResponse{Success: true, Data: data})
+}
+
+// SetupPassword sets a password using a one-time setup token.
+func (h *Handler) SetupPassword(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	var req setupPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		logger.Warn(c.Context(), "admin setup password invalid body", map[string]interface{}{
+			"error": err.Error(),
+		}, requestURL)
+		return adminInvalidRequest(c, "invalid request body")
+	}
+	if req.Token == "" || req.Password == "" {
+		return adminInvalidRequest(c, "token and password are required")
+	}
+	result, err := h.uc.SetPassword(c.Context(), req.Token, req.Password)
+	if err != nil {
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: adminToResponse(result)})
+}
+
+// ChangePassword replaces the signed-in admin's password. Other sessions are revoked; the one
+// making the request stays open.
+func (h *Handler) ChangePassword(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	var req changePasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		logger.Warn(c.Context(), "admin change password invalid body", map[string]interface{}{
+			"error": err.Error(),
+		}, requestURL)
+		return adminInvalidRequest(c, "invalid request body")
+	}
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		return adminInvalidRequest(c, "current_password and new_password are required")
+	}
+	principal := middleware.AdminPrincipal(c)
+	if err := h.uc.ChangePassword(c.UserContext(), adminusecase.ChangePasswordInput{
+		AdminID:         principal.AdminID,
+		SessionID:       principal.SessionID,
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+	}); err != nil {
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true})
+}
+
+func sessionToResponse(result *adminusecase.SessionResult) map[string]interface{} {
+	return map[string]interface{}{
+		"token_type":         "Bearer",
+		"access_token":       result.AccessToken,
+		"expires_at":         result.ExpiresAt,
+		"refresh_token":      result.RefreshToken,
+		"refresh_expires_at": result.RefreshExpiresAt,
+		// The session can only enrol TOTP until the admin completes enrolment.
+		"mfa_enrollment_required": result.MFAEnrollmentRequired,
+		"admin":                   adminToResponse(result.Admin),
+	}
+}
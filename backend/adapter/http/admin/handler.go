@@ -2,18 +2,21 @@ package admin
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"smart-parcel-locker/backend/adapter/http/middleware"
 	"smart-parcel-locker/backend/domain/admin"
+	"smart-parcel-locker/backend/pkg/errorx"
 	"smart-parcel-locker/backend/pkg/logger"
 	"smart-parcel-locker/backend/pkg/response"
 	adminusecase "smart-parcel-locker/backend/usecase/admin"
 )
 
-// Handler exposes admin CRUD endpoints.
+// Handler exposes admin CRUD, login session and password setup endpoints.
 type Handler struct {
 	uc *adminusecase.UseCase
 }
@@ -23,9 +26,14 @@ func NewHandler(uc *adminusecase.UseCase) *Handler {
 }
 
 type createRequest struct {
-	Username     string `json:"username"`
-	PasswordHash string `json:"password_hash"`
-	Role         string `json:"role"`
+	Username    string   `json:"username"`
+	Role        string   `json:"role"`
+	LocationIDs []string `json:"location_ids"`
+}
+
+type roleRequest struct {
+	Role        string   `json:"role"`
+	LocationIDs []string `json:"location_ids"`
 }
 
 func (h *Handler) Create(c *fiber.Ctx) error {
@@ -37,30 +45,32 @@ func (h *Handler) Create(c *fiber.Ctx) error {
 		}, requestURL)
 		return adminInvalidRequest(c, "invalid request body")
 	}
-	if req.Username == "" || req.PasswordHash == "" || req.Role == "" {
+	req.Username = strings.TrimSpace(req.Username)
+	if req.Username == "" || req.Role == "" {
 		logger.Warn(c.Context(), "admin create missing fields", map[string]interface{}{
 			"username": req.Username,
 			"role":     req.Role,
 		}, requestURL)
-		return adminInvalidRequest(c, "username, password_hash, and role are required")
+		return adminInvalidRequest(c, "username and role are required")
 	}
-	if req.Role != "ADMIN" {
-		logger.Warn(c.Context(), "admin create invalid role", map[string]interface{}{
-			"username": req.Username,
-			"role":     req.Role,
-		}, requestURL)
-		return adminInvalidRequest(c, "role must be ADMIN")
+	locationIDs, err := parseLocationIDs(req.LocationIDs)
+	if err != nil {
+		return adminError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid location_ids")
 	}
 	logger.Info(c.Context(), "admin create request received", map[string]interface{}{
 		"username": req.Username,
 		"role":     req.Role,
 	}, requestURL)
-	result, err := h.uc.Create(c.Context(), adminusecase.CreateInput{
-		Username:     req.Username,
-		PasswordHash: req.PasswordHash,
-		Role:         req.Role,
+	result, err := h.uc.Create(c.UserContext(), adminusecase.CreateInput{
+		Username:    req.Username,
+		Role:        req.Role,
+		LocationIDs: locationIDs,
 	})
 	if err != nil {
+		var appErr errorx.Error
+		if errors.As(err, &appErr) {
+			return mapError(c, err)
+		}
 		logger.Error(c.Context(), "admin create failed unexpectedly", map[string]interface{}{
 			"username": req.Username,
 			"role":     req.Role,
@@ -69,11 +79,13 @@ func (h *Handler) Create(c *fiber.Ctx) error {
 		return adminError(c, fiber.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
 	}
 	logger.Info(c.Context(), "admin created", map[string]interface{}{
-		"adminId":  result.ID,
-		"username": result.Username,
-		"role":     result.Role,
+		"adminId":  result.Admin.ID,
+		"username": result.Admin.Username,
+		"role":     result.Admin.Role,
 	}, requestURL)
-	return c.Status(fiber.StatusCreated).JSON(response.APIResponse{Success: true, Data: adminToResponse(result)})
+	data := adminToResponse(result.Admin)
+	data["setup"] = setupToResponse(result.Setup)
+	return c.Status(fiber.StatusCreated).JSON(response.APIResponse{Success: true, Data: data})
 }
 
 func (h *Handler) Get(c *fiber.Ctx) error {
@@ -89,7 +101,7 @@ func (h *Handler) Get(c *fiber.Ctx) error {
 	logger.Info(c.Context(), "admin get request received", map[string]interface{}{
 		"adminId": id.String(),
 	}, requestURL)
-	result, err := h.uc.Get(c.Context(), id)
+	result, err := h.uc.Get(c.UserContext(), id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			logger.Warn(c.Context(), "admin not found", map[string]interface{}{
@@ -130,34 +142,36 @@ func (h *Handler) Update(c *fiber.Ctx) error {
 		}, requestURL)
 		return adminInvalidRequest(c, "invalid request body")
 	}
-	if req.Username == "" || req.PasswordHash == "" || req.Role == "" {
+	req.Username = strings.TrimSpace(req.Username)
+	if req.Username == "" || req.Role == "" {
 		logger.Warn(c.Context(), "admin update missing fields", map[string]interface{}{
 			"adminId":  id.String(),
 			"username": req.Username,
 			"role":     req.Role,
 		}, requestURL)
-		return adminInvalidRequest(c, "username, password_hash, and role are required")
+		return adminInvalidRequest(c, "username and role are required")
 	}
-	if req.Role != "ADMIN" {
-		logger.Warn(c.Context(), "admin update invalid role", map[string]interface{}{
-			"adminId":  id.String(),
-			"username": req.Username,
-			"role":     req.Role,
-		}, requestURL)
-		return adminInvalidRequest(c, "role must be ADMIN")
+	locationIDs, err := parseLocationIDs(req.LocationIDs)
+	if err != nil {
+		return adminError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid location_ids")
 	}
 	logger.Info(c.Context(), "admin update request received", map[string]interface{}{
 		"adminId":  id.String(),
 		"username": req.Username,
 		"role":     req.Role,
 	}, requestURL)
-	result, err := h.uc.Update(c.Context(), adminusecase.UpdateInput{
-		ID:           id,
-		Username:     req.Username,
-		PasswordHash: req.PasswordHash,
-		Role:         req.Role,
+	result, err := h.uc.Update(c.UserContext(), adminusecase.UpdateInput{
+		ID:          id,
+		Username:    req.Username,
+		Role:        req.Role,
+		LocationIDs: locationIDs,
+		ActorID:     middleware.AdminPrincipal(c).AdminID,
 	})
 	if err != nil {
+		var appErr errorx.Error
+		if errors.As(err, &appErr) {
+			return mapError(c, err)
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			logger.Warn(c.Context(), "admin update not found", map[string]interface{}{
 				"adminId": id.String(),
@@ -192,7 +206,11 @@ func (h *Handler) Delete(c *fiber.Ctx) error {
 	logger.Info(c.Context(), "admin delete request received", map[string]interface{}{
 		"adminId": id.String(),
 	}, requestURL)
-	if err := h.uc.Delete(c.Context(), id); err != nil {
+	if err := h.uc.Delete(c.UserContext(), id, middleware.AdminPrincipal(c).AdminID); err != nil {
+		var appErr errorx.Error
+		if errors.As(err, &appErr) {
+			return mapError(c, err)
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			logger.Warn(c.Context(), "admin delete not found", map[string]interface{}{
 				"adminId": id.String(),
@@ -212,6 +230,164 @@ func (h *Handler) Delete(c *fiber.Ctx) error {
 	return c.JSON(response.APIResponse{Success: true})
 }
 
+// SetRole assigns a role and, for roles other than SUPER_ADMIN, optional locations.
+func (h *Handler) SetRole(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return adminError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid id")
+	}
+	var req roleRequest
+	if err := c.BodyParser(&req); err != nil {
+		logger.Warn(c.Context(), "admin set role invalid body", map[string]interface{}{
+			"adminId": id.String(),
+			"error":   err.Error(),
+		}, requestURL)
+		return adminInvalidRequest(c, "invalid request body")
+	}
+	if req.Role == "" {
+		return adminInvalidRequest(c, "role is required")
+	}
+	locationIDs, err := parseLocationIDs(req.LocationIDs)
+	if err != nil {
+		return adminError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid location_ids")
+	}
+	result, err := h.uc.SetRole(c.UserContext(), adminusecase.SetRoleInput{
+		ID:          id,
+		Role:        req.Role,
+		LocationIDs: locationIDs,
+		ActorID:     middleware.AdminPrincipal(c).AdminID,
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return adminError(c, fiber.StatusNotFound, "NOT_FOUND", "not found")
+		}
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: adminToResponse(result)})
+}
+
+// List returns admins filtered by username search (q), role, active flag and location.
+func (h *Handler) List(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	filter := admin.ListFilter{
+		Query:  strings.TrimSpace(c.Query("q")),
+		Role:   strings.ToUpper(strings.TrimSpace(c.Query("role"))),
+		Limit:  c.QueryInt("limit", 50),
+		Offset: c.QueryInt("offset", 0),
+	}
+	switch c.Query("active") {
+	case "":
+	case "true":
+		active := true
+		filter.Active = &active
+	case "false":
+		active := false
+		filter.Active = &active
+	default:
+		return adminInvalidRequest(c, "active must be true or false")
+	}
+	if raw := c.Query("location_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return adminError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid location_id")
+		}
+		filter.LocationID = &id
+	}
+	items, total, err := h.uc.List(c.UserContext(), filter)
+	if err != nil {
+		logger.Warn(c.Context(), "admin list failed", map[string]interface{}{
+			"error": err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	data := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		data = append(data, adminToResponse(item))
+	}
+	return c.JSON(response.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"items":  data,
+			"total":  total,
+			"limit":  filter.Limit,
+			"offset": filter.Offset,
+		},
+	})
+}
+
+// Disable blocks an admin from logging in and revokes their sessions.
+func (h *Handler) Disable(c *fiber.Ctx) error {
+	return h.setActive(c, false)
+}
+
+// Enable lets a disabled admin log in again.
+func (h *Handler) Enable(c *fiber.Ctx) error {
+	return h.setActive(c, true)
+}
+
+func (h *Handler) setActive(c *fiber.Ctx, active bool) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return adminError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid id")
+	}
+	result, err := h.uc.SetActive(c.UserContext(), adminusecase.SetActiveInput{
+		ID:      id,
+		Active:  active,
+		ActorID: middleware.AdminPrincipal(c).AdminID,
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return adminError(c, fiber.StatusNotFound, "NOT_FOUND", "not found")
+		}
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: adminToResponse(result)})
+}
+
+// ResetPassword clears an admin's password and returns a new one-time setup link.
+func (h *Handler) ResetPassword(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return adminError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid id")
+	}
+	setup, err := h.uc.ResetPassword(c.UserContext(), id, middleware.AdminPrincipal(c).AdminID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return adminError(c, fiber.StatusNotFound, "NOT_FOUND", "not found")
+		}
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: map[string]interface{}{
+		"setup": setupToResponse(*setup),
+	}})
+}
+
+// ListRoles returns every role with its permissions.
+func (h *Handler) ListRoles(c *fiber.Ctx) error {
+	roles := make([]map[string]interface{}, 0, len(admin.Roles()))
+	for _, role := range admin.Roles() {
+		roles = append(roles, map[string]interface{}{
+			"role":        role,
+			"permissions": admin.PermissionsFor(role),
+			"scopeable":   admin.Scopeable(role),
+		})
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: roles})
+}
+
+func parseLocationIDs(values []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(values))
+	for _, v := range values {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
 func adminError(c *fiber.Ctx, status int, code, msg string) error {
 	return c.Status(status).JSON(response.Error(code, msg))
 }
@@ -225,11 +401,57 @@ func adminToResponse(result *admin.Admin) map[string]interface{} {
 		return nil
 	}
 	return map[string]interface{}{
-		"id":            result.ID,
-		"username":      result.Username,
-		"password_hash": result.PasswordHash,
-		"role":          result.Role,
-		"created_at":    result.CreatedAt,
-		"updated_at":    result.UpdatedAt,
+		"id":              result.ID,
+		"username":        result.Username,
+		"role":            result.Role,
+		"location_ids":    locationIDsOrEmpty(result.LocationIDs),
+		"password_set":    result.HasPassword(),
+		"password_set_at": result.PasswordSetAt,
+		"is_active":       result.Active(),
+		"disabled_at":     result.DisabledAt,
+		"mfa_enabled":     result.MFAEnabled(),
+		"created_at":      result.CreatedAt,
+		"updated_at":      result.UpdatedAt,
+	}
+}
+
+func locationIDsOrEmpty(ids []uuid.UUID) []uuid.UUID {
+	if ids == nil {
+		return []uuid.UUID{}
+	}
+	return ids
+}
+
+func setupToResponse(setup adminusecase.SetupLink) map[string]interface{} {
+	data := map[string]interface{}{
+		"token":      setup.Token,
+		"expires_at": setup.ExpiresAt,
+	}
+	if setup.URL != "" {
+		data["url"] = setup.URL
+	}
+	return data
+}
+
+func mapError(c *fiber.Ctx, err error) error {
+	var appErr errorx.Error
+	if errors.As(err, &appErr) {
+		return adminError(c, statusFromCode(appErr.Code), appErr.Code, appErr.Message)
+	}
+	return adminError(c, fiber.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+}
+
+func statusFromCode(code string) int {
+	switch code {
+	case "INVALID_REQUEST", "WEAK_PASSWORD", "INVALID_SETUP_TOKEN", "INVALID_ROLE", "INVALID_LOCATION", "INVALID_PASSWORD":
+		return fiber.StatusBadRequest
+	case "INVALID_CREDENTIALS", "UNAUTHORIZED", "INVALID_SESSION", "MFA_REQUIRED", "INVALID_MFA_CODE":
+		return fiber.StatusUnauthorized
+	case "FORBIDDEN", "SELF_ROLE_CHANGE", "SELF_ACCOUNT_CHANGE", "ACCOUNT_DISABLED", "MFA_ENROLLMENT_REQUIRED", "MFA_MANDATORY":
+		return fiber.StatusForbidden
+	case "USERNAME_TAKEN", "LAST_SUPER_ADMIN", "MFA_ALREADY_ENABLED", "MFA_NOT_ENROLLING", "MFA_NOT_ENABLED":
+		return fiber.StatusConflict
+	default:
+		return fiber.StatusInternalServerError
 	}
 }
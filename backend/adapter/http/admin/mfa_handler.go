@@ -0,0 +1,127 @@
+package admin
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/adapter/http/middleware"
+	"smart-parcel-locker/backend/pkg/logger"
+	"smart-parcel-locker/backend/pkg/response"
+	adminusecase "smart-parcel-locker/backend/usecase/admin"
+)
+
+type mfaCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type disableTOTPRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+// BeginTOTP creates a pending authenticator secret for the signed-in admin and returns the
+// otpauth:// URI to render as a QR code.
+func (h *Handler) BeginTOTP(c *fiber.Ctx) error {
+	enrollment, err := h.uc.BeginTOTP(c.UserContext(), middleware.AdminPrincipal(c).AdminID)
+	if err != nil {
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: map[string]interface{}{
+		"secret":           enrollment.Secret,
+		"provisioning_uri": enrollment.ProvisioningURI,
+	}})
+}
+
+// ConfirmTOTP enables two-factor authentication with a code from the authenticator app and
+// returns the recovery codes.
+func (h *Handler) ConfirmTOTP(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	var req mfaCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		logger.Warn(c.Context(), "admin confirm totp invalid body", map[string]interface{}{
+			"error": err.Error(),
+		}, requestURL)
+		return adminInvalidRequest(c, "invalid request body")
+	}
+	if req.Code == "" {
+		return adminInvalidRequest(c, "code is required")
+	}
+	codes, err := h.uc.ConfirmTOTP(c.UserContext(), middleware.AdminPrincipal(c).AdminID, req.Code)
+	if err != nil {
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: map[string]interface{}{
+		"recovery_codes": codes,
+	}})
+}
+
+// RegenerateRecoveryCodes replaces the signed-in admin's recovery codes.
+func (h *Handler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	var req mfaCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		logger.Warn(c.Context(), "admin regenerate recovery codes invalid body", map[string]interface{}{
+			"error": err.Error(),
+		}, requestURL)
+		return adminInvalidRequest(c, "invalid request body")
+	}
+	if req.Code == "" {
+		return adminInvalidRequest(c, "code is required")
+	}
+	codes, err := h.uc.RegenerateRecoveryCodes(c.UserContext(), middleware.AdminPrincipal(c).AdminID, req.Code)
+	if err != nil {
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: map[string]interface{}{
+		"recovery_codes": codes,
+	}})
+}
+
+// DisableTOTP turns off two-factor authentication for the signed-in admin.
+func (h *Handler) DisableTOTP(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	var req disableTOTPRequest
+	if err := c.BodyParser(&req); err != nil {
+		logger.Warn(c.Context(), "admin disable totp invalid body", map[string]interface{}{
+			"error": err.Error(),
+		}, requestURL)
+		return adminInvalidRequest(c, "invalid request body")
+	}
+	if req.Password == "" || req.Code == "" {
+		return adminInvalidRequest(c, "password and code are required")
+	}
+	if err := h.uc.DisableTOTP(c.UserContext(), adminusecase.DisableTOTPInput{
+		AdminID:  middleware.AdminPrincipal(c).AdminID,
+		Password: req.Password,
+		Code:     req.Code,
+	}); err != nil {
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true})
+}
+
+// ResetMFA removes another admin's authenticator and recovery codes and signs them out.
+func (h *Handler) ResetMFA(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return adminError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid id")
+	}
+	if err := h.uc.ResetMFA(c.UserContext(), id, middleware.AdminPrincipal(c).AdminID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return adminError(c, fiber.StatusNotFound, "NOT_FOUND", "not found")
+		}
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true})
+}
+
+func mfaStatusToResponse(status *adminusecase.MFAStatus) map[string]interface{} {
+	return map[string]interface{}{
+		"enabled":                  status.Enabled,
+		"required":                 status.Required,
+		"recovery_codes_remaining": status.RecoveryCodesRemaining,
+	}
+}
@@ -1,11 +1,45 @@
 package admin
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"github.com/gofiber/fiber/v2"
 
-// RegisterRoutes wires admin CRUD endpoints.
+	"smart-parcel-locker/backend/adapter/http/middleware"
+	"smart-parcel-locker/backend/domain/admin"
+	ratelimitdomain "smart-parcel-locker/backend/domain/ratelimit"
+	ratelimitusecase "smart-parcel-locker/backend/usecase/ratelimit"
+)
+
+// RegisterRoutes wires admin account management and role assignment endpoints; all require
+// admins:manage.
 func RegisterRoutes(router fiber.Router, handler *Handler) {
-	router.Post("/", handler.Create)
-	router.Get("/:id", handler.Get)
-	router.Put("/:id", handler.Update)
-	router.Delete("/:id", handler.Delete)
+	manage := middleware.RequirePermission(admin.PermAdminsManage)
+	router.Get("/roles", manage, handler.ListRoles)
+	router.Get("/", manage, handler.List)
+	router.Post("/", manage, handler.Create)
+	router.Get("/:id", manage, handler.Get)
+	router.Put("/:id", manage, handler.Update)
+	router.Put("/:id/role", manage, handler.SetRole)
+	router.Post("/:id/disable", manage, handler.Disable)
+	router.Post("/:id/enable", manage, handler.Enable)
+	router.Post("/:id/password-reset", manage, handler.ResetPassword)
+	router.Post("/:id/mfa-reset", manage, handler.ResetMFA)
+	router.Delete("/:id", manage, handler.Delete)
+}
+
+// RegisterAuthRoutes wires login, session, password and two-factor endpoints. Login, refresh and
+// password setup are public; the rest require an access token. The MFA routes skip permission
+// checks so admins whose role requires two-factor authentication can enrol.
+func RegisterAuthRoutes(router fiber.Router, handler *Handler, requireAdmin fiber.Handler, limiter *ratelimitusecase.Limiter) {
+	router.Post("/login", middleware.RateLimit(limiter, ratelimitusecase.EndpointAdminLogin, map[ratelimitdomain.Dimension]middleware.SubjectFunc{
+		ratelimitdomain.DimensionIP: middleware.ClientIP(),
+	}), handler.Login)
+	router.Post("/refresh", handler.Refresh)
+	router.Post("/password/setup", handler.SetupPassword)
+	router.Post("/password/change", requireAdmin, handler.ChangePassword)
+	router.Post("/logout", requireAdmin, handler.Logout)
+	router.Get("/me", requireAdmin, handler.Me)
+	router.Post("/mfa/totp", requireAdmin, handler.BeginTOTP)
+	router.Post("/mfa/totp/confirm", requireAdmin, handler.ConfirmTOTP)
+	router.Delete("/mfa/totp", requireAdmin, handler.DisableTOTP)
+	router.Post("/mfa/recovery-codes", requireAdmin, handler.RegenerateRecoveryCodes)
 }
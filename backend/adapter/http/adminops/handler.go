@@ -2,11 +2,17 @@ package adminops
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"smart-parcel-locker/backend/adapter/http/middleware"
+	"smart-parcel-locker/backend/domain/admin"
+	"smart-parcel-locker/backend/domain/notification"
+	"smart-parcel-locker/backend/domain/parcel"
+	"smart-parcel-locker/backend/domain/provisioning"
 	"smart-parcel-locker/backend/pkg/errorx"
 	"smart-parcel-locker/backend/pkg/logger"
 	"smart-parcel-locker/backend/pkg/response"
@@ -24,10 +30,11 @@ func NewHandler(uc *adminopsusecase.UseCase) *Handler {
 
 func (h *Handler) CreateLocation(c *fiber.Ctx) error {
 	var req struct {
-		Code     string  `json:"code"`
-		Name     string  `json:"name"`
-		Address  *string `json:"address"`
-		IsActive *bool   `json:"is_active"`
+		Code          string  `json:"code"`
+		Name          string  `json:"name"`
+		Address       *string `json:"address"`
+		IsActive      *bool   `json:"is_active"`
+		DefaultLocale string  `json:"default_locale"`
 	}
 	requestURL := c.OriginalURL()
 	if err := c.BodyParser(&req); err != nil {
@@ -43,15 +50,26 @@ func (h *Handler) CreateLocation(c *fiber.Ctx) error {
 		}, requestURL)
 		return opsInvalidRequest(c, "code and name are required")
 	}
+	var defaultLocale notification.Locale
+	if req.DefaultLocale != "" {
+		if defaultLocale = notification.ParseLocale(req.DefaultLocale); defaultLocale == "" {
+			logger.Warn(c.Context(), "admin location create invalid default_locale", map[string]interface{}{
+				"locationCode":  req.Code,
+				"defaultLocale": req.DefaultLocale,
+			}, requestURL)
+			return opsInvalidRequest(c, "invalid default_locale")
+		}
+	}
 	logger.Info(c.Context(), "admin location create request received", map[string]interface{}{
 		"locationCode": req.Code,
 		"name":         req.Name,
 	}, requestURL)
-	result, err := h.uc.CreateLocation(c.Context(), adminopsusecase.CreateLocationInput{
-		Code:     req.Code,
-		Name:     req.Name,
-		Address:  req.Address,
-		IsActive: req.IsActive,
+	result, err := h.uc.CreateLocation(c.UserContext(), adminopsusecase.CreateLocationInput{
+		Code:          req.Code,
+		Name:          req.Name,
+		Address:       req.Address,
+		IsActive:      req.IsActive,
+		DefaultLocale: string(defaultLocale),
 	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -87,10 +105,11 @@ func (h *Handler) CreateLocation(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusCreated).JSON(response.APIResponse{
 		Success: true,
 		Data: map[string]interface{}{
-			"location_id": result.ID,
-			"code":        result.Code,
-			"name":        result.Name,
-			"is_active":   result.IsActive,
+			"location_id":    result.ID,
+			"code":           result.Code,
+			"name":           result.Name,
+			"is_active":      result.IsActive,
+			"default_locale": result.DefaultLocale,
 		},
 	})
 }
@@ -100,7 +119,7 @@ func (h *Handler) ListLocations(c *fiber.Ctx) error {
 	logger.Info(c.Context(), "admin location list request received", map[string]interface{}{
 		"action": "list_locations",
 	}, requestURL)
-	result, err := h.uc.ListLocations(c.Context())
+	result, err := h.uc.ListLocations(c.UserContext())
 	if err != nil {
 		logger.Error(c.Context(), "admin location list failed unexpectedly", map[string]interface{}{
 			"action": "list_locations",
@@ -108,13 +127,18 @@ func (h *Handler) ListLocations(c *fiber.Ctx) error {
 		}, requestURL)
 		return handleError(c, err)
 	}
+	principal := middleware.AdminPrincipal(c)
 	locations := make([]map[string]interface{}, 0, len(result))
 	for _, loc := range result {
+		if !principal.CanAccessLocation(loc.ID) {
+			continue
+		}
 		locations = append(locations, map[string]interface{}{
-			"location_id": loc.ID,
-			"code":        loc.Code,
-			"name":        loc.Name,
-			"is_active":   loc.IsActive,
+			"location_id":    loc.ID,
+			"code":           loc.Code,
+			"name":           loc.Name,
+			"is_active":      loc.IsActive,
+			"default_locale": loc.DefaultLocale,
 		})
 	}
 	logger.Info(c.Context(), "admin location list succeeded", map[string]interface{}{
@@ -124,6 +148,84 @@ func (h *Handler) ListLocations(c *fiber.Ctx) error {
 	return c.JSON(response.APIResponse{Success: true, Data: locations})
 }
 
+// UpdateLocation changes location details. Omitted fields are left as they are.
+func (h *Handler) UpdateLocation(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	locationID, err := uuid.Parse(c.Params("location_id"))
+	if err != nil {
+		return opsInvalidUUID(c, "location_id")
+	}
+	var req struct {
+		Code          *string `json:"code"`
+		Name          *string `json:"name"`
+		Address       *string `json:"address"`
+		IsActive      *bool   `json:"is_active"`
+		DefaultLocale *string `json:"default_locale"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		logger.Warn(c.Context(), "admin location update invalid body", map[string]interface{}{
+			"locationId": locationID.String(),
+			"error":      err.Error(),
+		}, requestURL)
+		return opsInvalidRequest(c, "invalid request body")
+	}
+	if req.DefaultLocale != nil && *req.DefaultLocale != "" {
+		locale := notification.ParseLocale(*req.DefaultLocale)
+		if locale == "" {
+			return opsInvalidRequest(c, "invalid default_locale")
+		}
+		parsed := string(locale)
+		req.DefaultLocale = &parsed
+	}
+	logger.Info(c.Context(), "admin location update request received", map[string]interface{}{
+		"locationId": locationID.String(),
+	}, requestURL)
+	result, err := h.uc.UpdateLocation(c.UserContext(), adminopsusecase.UpdateLocationInput{
+		ID:            locationID,
+		Code:          req.Code,
+		Name:          req.Name,
+		Address:       req.Address,
+		IsActive:      req.IsActive,
+		DefaultLocale: req.DefaultLocale,
+	})
+	if err != nil {
+		return handleError(c, err)
+	}
+	return c.JSON(response.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"location_id":    result.ID,
+			"code":           result.Code,
+			"name":           result.Name,
+			"address":        result.Address,
+			"is_active":      result.IsActive,
+			"default_locale": result.DefaultLocale,
+		},
+	})
+}
+
+// DeleteLocation deletes or archives a location; see adminops.UseCase.DeleteLocation.
+func (h *Handler) DeleteLocation(c *fiber.Ctx) error {
+	locationID, err := uuid.Parse(c.Params("location_id"))
+	if err != nil {
+		return opsInvalidUUID(c, "location_id")
+	}
+	logger.Info(c.Context(), "admin location delete request received", map[string]interface{}{
+		"locationId": locationID.String(),
+	}, c.OriginalURL())
+	removal, err := h.uc.DeleteLocation(c.UserContext(), locationID)
+	if err != nil {
+		return handleError(c, err)
+	}
+	return c.JSON(response.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"location_id": locationID,
+			"result":      removal,
+		},
+	})
+}
+
 func (h *Handler) CreateLocker(c *fiber.Ctx) error {
 	var req struct {
 		LocationID string `json:"location_id"`
@@ -156,7 +258,14 @@ func (h *Handler) CreateLocker(c *fiber.Ctx) error {
 		}, requestURL)
 		return opsInvalidUUID(c, "location_id")
 	}
-	result, err := h.uc.CreateLocker(c.Context(), adminopsusecase.CreateLockerInput{
+	if principal := middleware.AdminPrincipal(c); !principal.CanAccessLocation(locationID) {
+		logger.Warn(c.Context(), "locker create outside location scope", map[string]interface{}{
+			"adminId":    principal.AdminID.String(),
+			"locationId": req.LocationID,
+		}, requestURL)
+		return handleError(c, admin.ErrLocationForbidden)
+	}
+	result, err := h.uc.CreateLocker(c.UserContext(), adminopsusecase.CreateLockerInput{
 		LocationID: locationID,
 		LockerCode: req.LockerCode,
 		Name:       req.Name,
@@ -207,7 +316,7 @@ func (h *Handler) ListLockers(c *fiber.Ctx) error {
 	logger.Info(c.Context(), "locker list request received", map[string]interface{}{
 		"endpoint": "list_lockers",
 	}, requestURL)
-	result, err := h.uc.ListLockers(c.Context())
+	result, err := h.uc.ListLockers(c.UserContext())
 	if err != nil {
 		logger.Error(c.Context(), "locker list failed unexpectedly", map[string]interface{}{
 			"endpoint": "list_lockers",
@@ -215,8 +324,12 @@ func (h *Handler) ListLockers(c *fiber.Ctx) error {
 		}, requestURL)
 		return handleError(c, err)
 	}
+	principal := middleware.AdminPrincipal(c)
 	lockers := make([]map[string]interface{}, 0, len(result))
 	for _, l := range result {
+		if !principal.CanAccessLocation(l.LocationID) {
+			continue
+		}
 		lockers = append(lockers, map[string]interface{}{
 			"locker_id":   l.ID,
 			"locker_code": l.LockerCode,
@@ -247,6 +360,8 @@ func (h *Handler) UpdateLockerStatus(c *fiber.Ctx) error {
 	}
 	var req struct {
 		Status string `json:"status"`
+		Reason string `json:"reason"`
+		Force  bool   `json:"force"`
 	}
 	if err := c.BodyParser(&req); err != nil {
 		logger.Warn(c.Context(), "locker status update invalid body", map[string]interface{}{
@@ -261,9 +376,11 @@ func (h *Handler) UpdateLockerStatus(c *fiber.Ctx) error {
 		}, requestURL)
 		return opsInvalidRequest(c, "status is required")
 	}
-	result, err := h.uc.UpdateLockerStatus(c.Context(), adminopsusecase.UpdateLockerStatusInput{
+	result, err := h.uc.UpdateLockerStatus(c.UserContext(), adminopsusecase.UpdateLockerStatusInput{
 		LockerID: lockerID,
-		Status:   req.Status,
+		Status:   strings.ToUpper(strings.TrimSpace(req.Status)),
+		Reason:   req.Reason,
+		Force:    req.Force,
 	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -274,7 +391,7 @@ func (h *Handler) UpdateLockerStatus(c *fiber.Ctx) error {
 			}, requestURL)
 		} else {
 			var appErr errorx.Error
-			if errors.As(err, &appErr) || err.Error() == "invalid status" {
+			if errors.As(err, &appErr) {
 				logger.Warn(c.Context(), "locker status update rejected", map[string]interface{}{
 					"lockerId": lockerIDStr,
 					"status":   req.Status,
@@ -303,6 +420,69 @@ func (h *Handler) UpdateLockerStatus(c *fiber.Ctx) error {
 	})
 }
 
+// UpdateLocker changes the code or name of a locker. Omitted fields are left as they are.
+func (h *Handler) UpdateLocker(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	lockerID, err := uuid.Parse(c.Params("locker_id"))
+	if err != nil {
+		return opsInvalidUUID(c, "locker_id")
+	}
+	var req struct {
+		LockerCode *string `json:"locker_code"`
+		Name       *string `json:"name"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		logger.Warn(c.Context(), "locker update invalid body", map[string]interface{}{
+			"lockerId": lockerID.String(),
+			"error":    err.Error(),
+		}, requestURL)
+		return opsInvalidRequest(c, "invalid request body")
+	}
+	logger.Info(c.Context(), "locker update request received", map[string]interface{}{
+		"lockerId": lockerID.String(),
+	}, requestURL)
+	result, err := h.uc.UpdateLocker(c.UserContext(), adminopsusecase.UpdateLockerInput{
+		ID:         lockerID,
+		LockerCode: req.LockerCode,
+		Name:       req.Name,
+	})
+	if err != nil {
+		return handleError(c, err)
+	}
+	return c.JSON(response.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"locker_id":   result.ID,
+			"locker_code": result.LockerCode,
+			"name":        result.Name,
+			"location_id": result.LocationID,
+			"status":      result.Status,
+		},
+	})
+}
+
+// DeleteLocker deletes or archives a locker; see adminops.UseCase.DeleteLocker.
+func (h *Handler) DeleteLocker(c *fiber.Ctx) error {
+	lockerID, err := uuid.Parse(c.Params("locker_id"))
+	if err != nil {
+		return opsInvalidUUID(c, "locker_id")
+	}
+	logger.Info(c.Context(), "locker delete request received", map[string]interface{}{
+		"lockerId": lockerID.String(),
+	}, c.OriginalURL())
+	removal, err := h.uc.DeleteLocker(c.UserContext(), lockerID)
+	if err != nil {
+		return handleError(c, err)
+	}
+	return c.JSON(response.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"locker_id": lockerID,
+			"result":    removal,
+		},
+	})
+}
+
 func (h *Handler) CreateCompartments(c *fiber.Ctx) error {
 	lockerIDStr := c.Params("locker_id")
 	requestURL := c.OriginalURL()
@@ -376,7 +556,7 @@ func (h *Handler) CreateCompartments(c *fiber.Ctx) error {
 		"count":            len(specs),
 		"overdueFeePerDay": overdueFees,
 	}, requestURL)
-	createdCount, err := h.uc.CreateCompartments(c.Context(), adminopsusecase.CreateCompartmentsInput{
+	createdCount, err := h.uc.CreateCompartments(c.UserContext(), adminopsusecase.CreateCompartmentsInput{
 		LockerID:     lockerID,
 		Compartments: specs,
 	})
@@ -429,7 +609,7 @@ func (h *Handler) ListCompartments(c *fiber.Ctx) error {
 	logger.Info(c.Context(), "admin compartment list request received", map[string]interface{}{
 		"lockerId": lockerIDStr,
 	}, requestURL)
-	result, err := h.uc.ListCompartments(c.Context(), lockerID)
+	result, err := h.uc.ListCompartments(c.UserContext(), lockerID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			logger.Warn(c.Context(), "admin compartment list not found", map[string]interface{}{
@@ -460,12 +640,345 @@ func (h *Handler) ListCompartments(c *fiber.Ctx) error {
 	return c.JSON(response.APIResponse{Success: true, Data: comps})
 }
 
+// UpdateCompartment changes the number, size or overdue fee of a compartment. Omitted fields are
+// left as they are.
+func (h *Handler) UpdateCompartment(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	lockerID, err := uuid.Parse(c.Params("locker_id"))
+	if err != nil {
+		return opsInvalidUUID(c, "locker_id")
+	}
+	compartmentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return opsInvalidUUID(c, "id")
+	}
+	var req struct {
+		CompartmentNo    *int    `json:"compartment_no"`
+		Size             *string `json:"size"`
+		OverdueFeePerDay *int    `json:"overdue_fee_per_day"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		logger.Warn(c.Context(), "admin compartment update invalid body", map[string]interface{}{
+			"compartmentId": compartmentID.String(),
+			"error":         err.Error(),
+		}, requestURL)
+		return opsInvalidRequest(c, "invalid request body")
+	}
+	if req.CompartmentNo != nil && *req.CompartmentNo <= 0 {
+		return opsInvalidInput(c, "compartment_no must be greater than 0")
+	}
+	if req.Size != nil && *req.Size != "S" && *req.Size != "M" && *req.Size != "L" {
+		return opsInvalidInput(c, "size must be one of S, M, L")
+	}
+	if req.OverdueFeePerDay != nil && *req.OverdueFeePerDay < 0 {
+		return opsInvalidInput(c, "overdue_fee_per_day must be greater than or equal to 0")
+	}
+	logger.Info(c.Context(), "admin compartment update request received", map[string]interface{}{
+		"lockerId":      lockerID.String(),
+		"compartmentId": compartmentID.String(),
+	}, requestURL)
+	result, err := h.uc.UpdateCompartment(c.UserContext(), adminopsusecase.UpdateCompartmentInput{
+		LockerID:         lockerID,
+		ID:               compartmentID,
+		CompartmentNo:    req.CompartmentNo,
+		Size:             req.Size,
+		OverdueFeePerDay: req.OverdueFeePerDay,
+	})
+	if err != nil {
+		return handleError(c, err)
+	}
+	return c.JSON(response.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"compartment_id":      result.ID,
+			"locker_id":           result.LockerID,
+			"compartment_no":      result.CompartmentNo,
+			"size":                result.Size,
+			"status":              result.Status,
+			"overdue_fee_per_day": result.OverdueFeePerDay,
+		},
+	})
+}
+
+// DeleteCompartment deletes or archives a compartment; see adminops.UseCase.DeleteCompartment.
+func (h *Handler) DeleteCompartment(c *fiber.Ctx) error {
+	lockerID, err := uuid.Parse(c.Params("locker_id"))
+	if err != nil {
+		return opsInvalidUUID(c, "locker_id")
+	}
+	compartmentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return opsInvalidUUID(c, "id")
+	}
+	logger.Info(c.Context(), "admin compartment delete request received", map[string]interface{}{
+		"lockerId":      lockerID.String(),
+		"compartmentId": compartmentID.String(),
+	}, c.OriginalURL())
+	removal, err := h.uc.DeleteCompartment(c.UserContext(), lockerID, compartmentID)
+	if err != nil {
+		return handleError(c, err)
+	}
+	return c.JSON(response.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"compartment_id": compartmentID,
+			"result":         removal,
+		},
+	})
+}
+
+// UpdateCompartmentStatus takes a compartment out of service or returns it to service.
+func (h *Handler) UpdateCompartmentStatus(c *fiber.Ctx) error {
+	lockerIDStr := c.Params("locker_id")
+	compartmentIDStr := c.Params("id")
+	requestURL := c.OriginalURL()
+	lockerID, err := uuid.Parse(lockerIDStr)
+	if err != nil {
+		return opsInvalidUUID(c, "locker_id")
+	}
+	compartmentID, err := uuid.Parse(compartmentIDStr)
+	if err != nil {
+		return opsInvalidUUID(c, "id")
+	}
+	var req struct {
+		Status string `json:"status"`
+		Force  bool   `json:"force"`
+		Reason string `json:"reason"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		logger.Warn(c.Context(), "admin compartment status update invalid body", map[string]interface{}{
+			"compartmentId": compartmentIDStr,
+			"error":         err.Error(),
+		}, requestURL)
+		return opsInvalidRequest(c, "invalid request body")
+	}
+	if req.Status == "" {
+		return opsInvalidRequest(c, "status is required")
+	}
+	logger.Info(c.Context(), "admin compartment status update request received", map[string]interface{}{
+		"lockerId":      lockerIDStr,
+		"compartmentId": compartmentIDStr,
+		"status":        req.Status,
+		"force":         req.Force,
+	}, requestURL)
+	result, err := h.uc.UpdateCompartmentStatus(c.UserContext(), adminopsusecase.UpdateCompartmentStatusInput{
+		LockerID:      lockerID,
+		CompartmentID: compartmentID,
+		Status:        strings.ToUpper(strings.TrimSpace(req.Status)),
+		Force:         req.Force,
+		Reason:        req.Reason,
+	})
+	if err != nil {
+		logger.Warn(c.Context(), "admin compartment status update rejected", map[string]interface{}{
+			"compartmentId": compartmentIDStr,
+			"status":        req.Status,
+			"error":         err.Error(),
+		}, requestURL)
+		return handleError(c, err)
+	}
+	logger.Info(c.Context(), "admin compartment status updated", map[string]interface{}{
+		"compartmentId": result.ID,
+		"status":        result.Status,
+	}, requestURL)
+	return c.JSON(response.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"compartment_id": result.ID,
+			"locker_id":      result.LockerID,
+			"compartment_no": result.CompartmentNo,
+			"size":           result.Size,
+			"status":         result.Status,
+		},
+	})
+}
+
+// ListCompartmentStatusHistory returns the status changes of a compartment, newest first.
+func (h *Handler) ListCompartmentStatusHistory(c *fiber.Ctx) error {
+	lockerID, err := uuid.Parse(c.Params("locker_id"))
+	if err != nil {
+		return opsInvalidUUID(c, "locker_id")
+	}
+	compartmentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return opsInvalidUUID(c, "id")
+	}
+	items, err := h.uc.ListCompartmentStatusChanges(c.UserContext(), lockerID, compartmentID, c.QueryInt("limit", 50))
+	if err != nil {
+		return handleError(c, err)
+	}
+	changes := make([]map[string]interface{}, 0, len(items))
+	for _, ch := range items {
+		changes = append(changes, map[string]interface{}{
+			"id":          ch.ID,
+			"from_status": ch.FromStatus,
+			"to_status":   ch.ToStatus,
+			"reason":      ch.Reason,
+			"forced":      ch.Forced,
+			"actor_id":    ch.ActorID,
+			"created_at":  ch.CreatedAt,
+		})
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: changes})
+}
+
+// ListLockerStatusHistory returns the status changes of a locker, newest first.
+func (h *Handler) ListLockerStatusHistory(c *fiber.Ctx) error {
+	lockerID, err := uuid.Parse(c.Params("locker_id"))
+	if err != nil {
+		return opsInvalidUUID(c, "locker_id")
+	}
+	items, err := h.uc.ListLockerStatusChanges(c.UserContext(), lockerID, c.QueryInt("limit", 50))
+	if err != nil {
+		return handleError(c, err)
+	}
+	changes := make([]map[string]interface{}, 0, len(items))
+	for _, ch := range items {
+		changes = append(changes, map[string]interface{}{
+			"id":          ch.ID,
+			"from_status": ch.FromStatus,
+			"to_status":   ch.ToStatus,
+			"reason":      ch.Reason,
+			"actor_id":    ch.ActorID,
+			"created_at":  ch.CreatedAt,
+		})
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: changes})
+}
+
+// GetCompartmentHardware returns the door and occupancy readings of a compartment.
+func (h *Handler) GetCompartmentHardware(c *fiber.Ctx) error {
+	lockerID, err := uuid.Parse(c.Params("locker_id"))
+	if err != nil {
+		return opsInvalidUUID(c, "locker_id")
+	}
+	compartmentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return opsInvalidUUID(c, "id")
+	}
+	result, err := h.uc.CompartmentHardware(c.UserContext(), lockerID, compartmentID)
+	if err != nil {
+		return handleError(c, err)
+	}
+	return c.JSON(response.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"compartment_id": compartmentID,
+			"door_state":     result.Door,
+			"occupancy":      result.Occupancy,
+		},
+	})
+}
+
+// OpenCompartment opens a compartment door on request of an operator.
+func (h *Handler) OpenCompartment(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	lockerID, err := uuid.Parse(c.Params("locker_id"))
+	if err != nil {
+		return opsInvalidUUID(c, "locker_id")
+	}
+	compartmentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return opsInvalidUUID(c, "id")
+	}
+	if err := h.uc.OpenCompartment(c.UserContext(), lockerID, compartmentID); err != nil {
+		logger.Warn(c.Context(), "admin compartment open rejected", map[string]interface{}{
+			"compartmentId": compartmentID.String(),
+			"error":         err.Error(),
+		}, requestURL)
+		return handleError(c, err)
+	}
+	logger.Info(c.Context(), "admin compartment opened", map[string]interface{}{
+		"compartmentId": compartmentID.String(),
+	}, requestURL)
+	return c.JSON(response.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"compartment_id": compartmentID,
+			"opened":         true,
+		},
+	})
+}
+
+// SearchParcels finds parcels by phone, parcel code, status or locker. Admins scoped to
+// locations only see parcels in lockers at those locations.
+func (h *Handler) SearchParcels(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	filter := parcel.SearchFilter{
+		Phone:       strings.TrimSpace(c.Query("phone")),
+		ParcelCode:  strings.TrimSpace(c.Query("parcel_code")),
+		Status:      parcel.Status(strings.ToUpper(c.Query("status"))),
+		LocationIDs: middleware.AdminPrincipal(c).LocationIDs,
+		Limit:       c.QueryInt("limit", 50),
+		Offset:      c.QueryInt("offset", 0),
+	}
+	if raw := c.Query("locker_id"); raw != "" {
+		lockerID, err := uuid.Parse(raw)
+		if err != nil {
+			return opsInvalidUUID(c, "locker_id")
+		}
+		filter.LockerID = &lockerID
+	}
+	logger.Info(c.Context(), "admin parcel search request received", map[string]interface{}{
+		"parcelCode": filter.ParcelCode,
+		"status":     filter.Status,
+		"scoped":     len(filter.LocationIDs) > 0,
+	}, requestURL)
+	items, total, err := h.uc.SearchParcels(c.UserContext(), filter)
+	if err != nil {
+		return handleError(c, err)
+	}
+	parcels := make([]map[string]interface{}, 0, len(items))
+	for _, p := range items {
+		parcels = append(parcels, map[string]interface{}{
+			"parcel_id":      p.ID,
+			"parcel_code":    p.ParcelCode,
+			"locker_id":      p.LockerID,
+			"compartment_id": p.CompartmentID,
+			"size":           p.Size,
+			"receiver_phone": p.ReceiverPhone,
+			"sender_phone":   p.SenderPhone,
+			"carrier_code":   p.CarrierCode,
+			"status":         p.Status,
+			"deposited_at":   p.DepositedAt,
+			"picked_up_at":   p.PickedUpAt,
+			"expires_at":     p.ExpiresAt,
+			"created_at":     p.CreatedAt,
+		})
+	}
+	return c.JSON(response.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"items":  parcels,
+			"total":  total,
+			"limit":  filter.Limit,
+			"offset": filter.Offset,
+		},
+	})
+}
+
+// LockerLocation resolves the location of the :locker_id route parameter for location-scoped
+// admins. Malformed or unknown lockers are left for the handler to report.
+func (h *Handler) LockerLocation(c *fiber.Ctx) (uuid.UUID, bool, error) {
+	lockerID, err := uuid.Parse(c.Params("locker_id"))
+	if err != nil {
+		return uuid.Nil, false, nil
+	}
+	l, err := h.uc.GetLocker(c.UserContext(), lockerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return uuid.Nil, false, nil
+		}
+		return uuid.Nil, false, err
+	}
+	return l.LocationID, true, nil
+}
+
 func (h *Handler) Overview(c *fiber.Ctx) error {
 	requestURL := c.OriginalURL()
 	logger.Info(c.Context(), "admin overview request received", map[string]interface{}{
 		"action": "overview",
 	}, requestURL)
-	result, err := h.uc.Overview(c.Context())
+	result, err := h.uc.Overview(c.UserContext())
 	if err != nil {
 		logger.Error(c.Context(), "admin overview failed unexpectedly", map[string]interface{}{
 			"action": "overview",
@@ -489,6 +1002,74 @@ func (h *Handler) Overview(c *fiber.Ctx) error {
 	})
 }
 
+// ExportManifest returns the configuration of every location, locker and compartment as a
+// manifest file, JSON by default or CSV with format=csv.
+func (h *Handler) ExportManifest(c *fiber.Ctx) error {
+	format := strings.ToLower(c.Query("format", provisioning.FormatJSON))
+	manifest, err := h.uc.ExportManifest(c.UserContext())
+	if err != nil {
+		logger.Error(c.Context(), "admin manifest export failed unexpectedly", map[string]interface{}{
+			"error": err.Error(),
+		}, c.OriginalURL())
+		return handleError(c, err)
+	}
+	body, err := provisioning.Encode(format, manifest)
+	if err != nil {
+		return handleError(c, err)
+	}
+	if format == provisioning.FormatCSV {
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	} else {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+	}
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="locker-manifest.`+format+`"`)
+	return c.Send(body)
+}
+
+// ImportManifest applies a manifest in one transaction, or only returns the plan with
+// dry_run=true. The format is taken from the format query parameter or the Content-Type.
+func (h *Handler) ImportManifest(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	format := strings.ToLower(c.Query("format"))
+	if format == "" {
+		format = provisioning.FormatJSON
+		if strings.Contains(string(c.Request().Header.ContentType()), "csv") {
+			format = provisioning.FormatCSV
+		}
+	}
+	dryRun := c.QueryBool("dry_run")
+	manifest, err := provisioning.Decode(format, c.Body())
+	if err != nil {
+		logger.Warn(c.Context(), "admin manifest import unreadable", map[string]interface{}{
+			"format": format,
+			"error":  err.Error(),
+		}, requestURL)
+		return handleError(c, err)
+	}
+	plan, err := h.uc.ImportManifest(c.UserContext(), adminopsusecase.ImportManifestInput{
+		Manifest: manifest,
+		DryRun:   dryRun,
+	})
+	if err != nil {
+		return handleError(c, err)
+	}
+	logger.Info(c.Context(), "admin manifest import completed", map[string]interface{}{
+		"dryRun":  dryRun,
+		"changes": len(plan.Changes),
+	}, requestURL)
+	return c.JSON(response.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"dry_run":      dryRun,
+			"applied":      !dryRun && !plan.Empty(),
+			"locations":    plan.Locations,
+			"lockers":      plan.Lockers,
+			"compartments": plan.Compartments,
+			"changes":      plan.Changes,
+		},
+	})
+}
+
 func handleError(c *fiber.Ctx, err error) error {
 	if err == nil {
 		return nil
@@ -496,6 +1077,16 @@ func handleError(c *fiber.Ctx, err error) error {
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return opsError(c, fiber.StatusNotFound, "NOT_FOUND", "not found")
 	}
+	var inUse *adminopsusecase.InUseError
+	if errors.As(err, &inUse) {
+		return inUseError(c, inUse)
+	}
+	var manifestErr *provisioning.ManifestError
+	if errors.As(err, &manifestErr) {
+		resp := response.Error("INVALID_MANIFEST", manifestErr.Error())
+		resp.Data = map[string]interface{}{"problems": manifestErr.Problems}
+		return c.Status(fiber.StatusBadRequest).JSON(resp)
+	}
 	var appErr errorx.Error
 	if errors.As(err, &appErr) {
 		return opsError(c, statusFromCode(appErr.Code), appErr.Code, appErr.Message)
@@ -510,13 +1101,43 @@ func statusFromCode(code string) int {
 	switch code {
 	case "INVALID_REQUEST", "INVALID_UUID", "INVALID_INPUT":
 		return fiber.StatusBadRequest
-	case "NO_AVAILABLE_COMPARTMENT", "INVALID_STATUS_TRANSITION", "LOCKER_INACTIVE":
+	case "FORBIDDEN":
+		return fiber.StatusForbidden
+	case "COMPARTMENT_NOT_FOUND":
+		return fiber.StatusNotFound
+	case "NO_AVAILABLE_COMPARTMENT", "INVALID_STATUS_TRANSITION", "LOCKER_INACTIVE", "COMPARTMENT_OCCUPIED",
+		"LOCKER_HAS_PARCELS", "LOCATION_CODE_TAKEN", "LOCKER_CODE_TAKEN", "COMPARTMENT_NO_TAKEN":
 		return fiber.StatusConflict
+	case "DOOR_OPEN_FAILED", "DOOR_OUTCOME_UNKNOWN", "LOCK_CONTROLLER_UNAVAILABLE":
+		return fiber.StatusServiceUnavailable
 	default:
 		return fiber.StatusInternalServerError
 	}
 }
 
+// inUseError answers a refused delete with the parcels that block it.
+func inUseError(c *fiber.Ctx, err *adminopsusecase.InUseError) error {
+	parcels := make([]map[string]interface{}, 0, len(err.Parcels))
+	for _, p := range err.Parcels {
+		parcels = append(parcels, map[string]interface{}{
+			"parcel_id":      p.ID,
+			"parcel_code":    p.ParcelCode,
+			"locker_id":      p.LockerID,
+			"compartment_id": p.CompartmentID,
+			"status":         p.Status,
+			"deposited_at":   p.DepositedAt,
+		})
+	}
+	resp := response.Error("ENTITY_IN_USE", err.Error())
+	resp.Data = map[string]interface{}{
+		"entity_type":           err.EntityType,
+		"entity_id":             err.EntityID,
+		"blocking_parcel_count": err.Total,
+		"blocking_parcels":      parcels,
+	}
+	return c.Status(fiber.StatusConflict).JSON(resp)
+}
+
 func opsError(c *fiber.Ctx, status int, code, msg string) error {
 	return c.Status(status).JSON(response.Error(code, msg))
 }
@@ -1,18 +1,45 @@
 package adminops
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"github.com/gofiber/fiber/v2"
 
-// RegisterRoutes wires admin operational endpoints.
+	"smart-parcel-locker/backend/adapter/http/middleware"
+	"smart-parcel-locker/backend/domain/admin"
+)
+
+// RegisterRoutes wires admin operational endpoints with the permission each one needs.
+// Locker routes also check the locker's location against location-scoped admins.
 func RegisterRoutes(router fiber.Router, handler *Handler) {
-	router.Post("/locations", handler.CreateLocation)
-	router.Get("/locations", handler.ListLocations)
+	read := middleware.RequirePermission(admin.PermInventoryRead)
+	lockerScope := middleware.RequireLocation(handler.LockerLocation)
+
+	router.Post("/locations", middleware.RequirePermission(admin.PermLocationsWrite), middleware.RequireAllLocations(), handler.CreateLocation)
+	router.Get("/locations", read, handler.ListLocations)
+	router.Patch("/locations/:location_id", middleware.RequirePermission(admin.PermLocationsWrite), middleware.RequireAllLocations(), handler.UpdateLocation)
+	router.Delete("/locations/:location_id", middleware.RequirePermission(admin.PermLocationsWrite), middleware.RequireAllLocations(), handler.DeleteLocation)
+
+	router.Post("/lockers", middleware.RequirePermission(admin.PermLockersWrite), handler.CreateLocker)
+	router.Get("/lockers", read, handler.ListLockers)
+	router.Patch("/lockers/:locker_id", middleware.RequirePermission(admin.PermLockersWrite), lockerScope, handler.UpdateLocker)
+	router.Delete("/lockers/:locker_id", middleware.RequirePermission(admin.PermLockersWrite), lockerScope, handler.DeleteLocker)
+	router.Patch("/lockers/:locker_id/status", middleware.RequirePermission(admin.PermLockersWrite), lockerScope, handler.UpdateLockerStatus)
+	router.Get("/lockers/:locker_id/status-history", read, lockerScope, handler.ListLockerStatusHistory)
+
+	router.Post("/lockers/:locker_id/compartments", middleware.RequirePermission(admin.PermLockersWrite, admin.PermFeesWrite), lockerScope, handler.CreateCompartments)
+	router.Get("/lockers/:locker_id/compartments", read, lockerScope, handler.ListCompartments)
+	router.Patch("/lockers/:locker_id/compartments/:id", middleware.RequirePermission(admin.PermLockersWrite, admin.PermFeesWrite), lockerScope, handler.UpdateCompartment)
+	router.Delete("/lockers/:locker_id/compartments/:id", middleware.RequirePermission(admin.PermLockersWrite), lockerScope, handler.DeleteCompartment)
+	router.Patch("/lockers/:locker_id/compartments/:id/status", middleware.RequirePermission(admin.PermLockersWrite), lockerScope, handler.UpdateCompartmentStatus)
+	router.Get("/lockers/:locker_id/compartments/:id/status-history", read, lockerScope, handler.ListCompartmentStatusHistory)
+	router.Get("/lockers/:locker_id/compartments/:id/hardware", read, lockerScope, handler.GetCompartmentHardware)
+	router.Post("/lockers/:locker_id/compartments/:id/open", middleware.RequirePermission(admin.PermLockersWrite), lockerScope, handler.OpenCompartment)
 
-	router.Post("/lockers", handler.CreateLocker)
-	router.Get("/lockers", handler.ListLockers)
-	router.Patch("/lockers/:locker_id/status", handler.UpdateLockerStatus)
+	router.Get("/parcels", middleware.RequirePermission(admin.PermParcelsRead), handler.SearchParcels)
 
-	router.Post("/lockers/:locker_id/compartments", handler.CreateCompartments)
-	router.Get("/lockers/:locker_id/compartments", handler.ListCompartments)
+	router.Get("/overview", read, middleware.RequireAllLocations(), handler.Overview)
 
-	router.Get("/overview", handler.Overview)
+	router.Get("/provisioning/manifest", read, middleware.RequireAllLocations(), handler.ExportManifest)
+	router.Post("/provisioning/manifest",
+		middleware.RequirePermission(admin.PermLocationsWrite, admin.PermLockersWrite, admin.PermFeesWrite),
+		middleware.RequireAllLocations(), handler.ImportManifest)
 }
@@ -0,0 +1,115 @@
+package audit
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"smart-parcel-locker/backend/domain/audit"
+	"smart-parcel-locker/backend/pkg/errorx"
+	"smart-parcel-locker/backend/pkg/logger"
+	"smart-parcel-locker/backend/pkg/response"
+	auditusecase "smart-parcel-locker/backend/usecase/audit"
+)
+
+// Handler exposes the admin audit log.
+type Handler struct {
+	uc *auditusecase.UseCase
+}
+
+func NewHandler(uc *auditusecase.UseCase) *Handler {
+	return &Handler{uc: uc}
+}
+
+// List returns audit entries newest first, filtered by actor, entity, action and time range.
+func (h *Handler) List(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	filter := audit.Filter{
+		EntityType: strings.TrimSpace(c.Query("entity_type")),
+		EntityID:   strings.TrimSpace(c.Query("entity_id")),
+		Action:     strings.TrimSpace(c.Query("action")),
+		Limit:      c.QueryInt("limit", 50),
+		Offset:     c.QueryInt("offset", 0),
+	}
+	if raw := c.Query("actor_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid actor_id")
+		}
+		filter.ActorID = &id
+	}
+	for _, param := range []struct {
+		name   string
+		target **time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		raw := c.Query(param.name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return writeError(c, fiber.StatusBadRequest, "INVALID_REQUEST", "invalid "+param.name+", expected RFC3339")
+		}
+		*param.target = &t
+	}
+	items, total, err := h.uc.List(c.UserContext(), filter)
+	if err != nil {
+		logger.Warn(c.Context(), "audit log list failed", map[string]interface{}{
+			"error": err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	data := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		data = append(data, entryToResponse(item))
+	}
+	return c.JSON(response.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"items":  data,
+			"total":  total,
+			"limit":  filter.Limit,
+			"offset": filter.Offset,
+		},
+	})
+}
+
+func mapError(c *fiber.Ctx, err error) error {
+	var appErr errorx.Error
+	if errors.As(err, &appErr) {
+		return writeError(c, statusFromCode(appErr.Code), appErr.Code, appErr.Message)
+	}
+	return writeError(c, fiber.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+}
+
+func statusFromCode(code string) int {
+	switch code {
+	case "INVALID_REQUEST":
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+func writeError(c *fiber.Ctx, status int, code, msg string) error {
+	return c.Status(status).JSON(response.Error(code, msg))
+}
+
+func entryToResponse(e *audit.Entry) map[string]interface{} {
+	return map[string]interface{}{
+		"id":             e.ID,
+		"actor_id":       e.ActorID,
+		"actor_username": e.ActorUsername,
+		"action":         e.Action,
+		"entity_type":    e.EntityType,
+		"entity_id":      e.EntityID,
+		"before":         e.Before,
+		"after":          e.After,
+		"ip":             e.IP,
+		"request_id":     e.RequestID,
+		"created_at":     e.CreatedAt,
+	}
+}
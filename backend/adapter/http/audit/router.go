@@ -0,0 +1,14 @@
+package audit
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"smart-parcel-locker/backend/adapter/http/middleware"
+	"smart-parcel-locker/backend/domain/admin"
+)
+
+// RegisterRoutes wires the audit log under /admin. Entries span every location, so reading
+// them needs audit:read and an admin not scoped to specific locations.
+func RegisterRoutes(router fiber.Router, handler *Handler) {
+	router.Get("/audit", middleware.RequirePermission(admin.PermAuditRead), middleware.RequireAllLocations(), handler.List)
+}
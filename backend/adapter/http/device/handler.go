@@ -0,0 +1,185 @@
+package device
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/device"
+	"smart-parcel-locker/backend/pkg/errorx"
+	"smart-parcel-locker/backend/pkg/logger"
+	"smart-parcel-locker/backend/pkg/response"
+	deviceusecase "smart-parcel-locker/backend/usecase/device"
+)
+
+// Handler exposes kiosk device credentials for admins.
+type Handler struct {
+	uc *deviceusecase.UseCase
+}
+
+func NewHandler(uc *deviceusecase.UseCase) *Handler {
+	return &Handler{uc: uc}
+}
+
+type provisionRequest struct {
+	Name string `json:"name"`
+}
+
+// Provision registers a device for the locker; the response is the only place the secret is
+// shown.
+func (h *Handler) Provision(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	lockerID, err := uuid.Parse(c.Params("locker_id"))
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid locker_id")
+	}
+	var req provisionRequest
+	if err := c.BodyParser(&req); err != nil {
+		logger.Warn(c.Context(), "device provision invalid body", map[string]interface{}{
+			"lockerId": lockerID.String(),
+			"error":    err.Error(),
+		}, requestURL)
+		return writeError(c, fiber.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+	}
+	d, err := h.uc.Provision(c.UserContext(), deviceusecase.ProvisionInput{
+		LockerID: lockerID,
+		Name:     req.Name,
+	})
+	if err != nil {
+		logger.Warn(c.Context(), "device provision failed", map[string]interface{}{
+			"lockerId": lockerID.String(),
+			"error":    err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(response.APIResponse{Success: true, Data: deviceToResponse(d, true)})
+}
+
+// List returns the devices of the locker, revoked ones included.
+func (h *Handler) List(c *fiber.Ctx) error {
+	lockerID, err := uuid.Parse(c.Params("locker_id"))
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid locker_id")
+	}
+	items, err := h.uc.List(c.UserContext(), lockerID)
+	if err != nil {
+		logger.Error(c.Context(), "device list failed unexpectedly", map[string]interface{}{
+			"lockerId": lockerID.String(),
+			"error":    err.Error(),
+		}, c.OriginalURL())
+		return mapError(c, err)
+	}
+	data := make([]map[string]interface{}, 0, len(items))
+	for _, d := range items {
+		data = append(data, deviceToResponse(d, false))
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: data})
+}
+
+func (h *Handler) Get(c *fiber.Ctx) error {
+	lockerID, id, ok, err := parseIDs(c)
+	if !ok {
+		return err
+	}
+	d, err := h.uc.Get(c.UserContext(), lockerID, id)
+	if err != nil {
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: deviceToResponse(d, false)})
+}
+
+// RotateSecret issues a new signing secret and returns it once.
+func (h *Handler) RotateSecret(c *fiber.Ctx) error {
+	lockerID, id, ok, err := parseIDs(c)
+	if !ok {
+		return err
+	}
+	d, err := h.uc.RotateSecret(c.UserContext(), lockerID, id)
+	if err != nil {
+		logger.Warn(c.Context(), "device rotate secret failed", map[string]interface{}{
+			"deviceId": id.String(),
+			"error":    err.Error(),
+		}, c.OriginalURL())
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: deviceToResponse(d, true)})
+}
+
+// Revoke stops the device from signing requests.
+func (h *Handler) Revoke(c *fiber.Ctx) error {
+	lockerID, id, ok, err := parseIDs(c)
+	if !ok {
+		return err
+	}
+	d, err := h.uc.Revoke(c.UserContext(), lockerID, id)
+	if err != nil {
+		logger.Warn(c.Context(), "device revoke failed", map[string]interface{}{
+			"deviceId": id.String(),
+			"error":    err.Error(),
+		}, c.OriginalURL())
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: deviceToResponse(d, false)})
+}
+
+// parseIDs reads the :locker_id and :id parameters. When ok is false the error response has
+// been written and err is what the handler returns.
+func parseIDs(c *fiber.Ctx) (lockerID, id uuid.UUID, ok bool, err error) {
+	lockerID, perr := uuid.Parse(c.Params("locker_id"))
+	if perr != nil {
+		return uuid.Nil, uuid.Nil, false, writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid locker_id")
+	}
+	id, perr = uuid.Parse(c.Params("id"))
+	if perr != nil {
+		return uuid.Nil, uuid.Nil, false, writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid id")
+	}
+	return lockerID, id, true, nil
+}
+
+func mapError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return writeError(c, fiber.StatusNotFound, "NOT_FOUND", "locker not found")
+	}
+	var appErr errorx.Error
+	if errors.As(err, &appErr) {
+		return writeError(c, statusFromCode(appErr.Code), appErr.Code, appErr.Message)
+	}
+	return writeError(c, fiber.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+}
+
+func statusFromCode(code string) int {
+	switch code {
+	case "INVALID_REQUEST", "INVALID_DEVICE":
+		return fiber.StatusBadRequest
+	case "DEVICE_NOT_FOUND":
+		return fiber.StatusNotFound
+	case "DEVICE_REVOKED":
+		return fiber.StatusConflict
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+func writeError(c *fiber.Ctx, status int, code, msg string) error {
+	return c.Status(status).JSON(response.Error(code, msg))
+}
+
+func deviceToResponse(d *device.Device, withSecret bool) map[string]interface{} {
+	data := map[string]interface{}{
+		"id":                d.ID,
+		"locker_id":         d.LockerID,
+		"name":              d.Name,
+		"is_active":         d.Active(),
+		"secret_rotated_at": d.SecretRotatedAt,
+		"last_seen_at":      d.LastSeenAt,
+		"revoked_at":        d.RevokedAt,
+		"created_at":        d.CreatedAt,
+		"updated_at":        d.UpdatedAt,
+	}
+	if withSecret {
+		data["secret"] = d.Secret
+	}
+	return data
+}
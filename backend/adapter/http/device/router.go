@@ -0,0 +1,22 @@
+package device
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"smart-parcel-locker/backend/adapter/http/middleware"
+	"smart-parcel-locker/backend/domain/admin"
+)
+
+// RegisterRoutes wires kiosk device credential endpoints under their locker, so location-scoped
+// admins only reach devices at their own locations.
+func RegisterRoutes(router fiber.Router, handler *Handler, lockerLocation middleware.LocationResolver) {
+	read := middleware.RequirePermission(admin.PermInventoryRead)
+	write := middleware.RequirePermission(admin.PermLockersWrite)
+	lockerScope := middleware.RequireLocation(lockerLocation)
+
+	router.Post("/lockers/:locker_id/devices", write, lockerScope, handler.Provision)
+	router.Get("/lockers/:locker_id/devices", read, lockerScope, handler.List)
+	router.Get("/lockers/:locker_id/devices/:id", read, lockerScope, handler.Get)
+	router.Post("/lockers/:locker_id/devices/:id/rotate-secret", write, lockerScope, handler.RotateSecret)
+	router.Post("/lockers/:locker_id/devices/:id/revoke", write, lockerScope, handler.Revoke)
+}
@@ -0,0 +1,107 @@
+package heartbeat
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"smart-parcel-locker/backend/adapter/http/middleware"
+	"smart-parcel-locker/backend/domain/locker"
+	"smart-parcel-locker/backend/pkg/errorx"
+	"smart-parcel-locker/backend/pkg/logger"
+	"smart-parcel-locker/backend/pkg/response"
+	heartbeatusecase "smart-parcel-locker/backend/usecase/heartbeat"
+)
+
+// Handler receives kiosk heartbeats and shows the latest one to admins.
+type Handler struct {
+	uc *heartbeatusecase.UseCase
+}
+
+func NewHandler(uc *heartbeatusecase.UseCase) *Handler {
+	return &Handler{uc: uc}
+}
+
+type heartbeatRequest struct {
+	FirmwareVersion  string   `json:"firmware_version"`
+	TemperatureC     *float64 `json:"temperature_c"`
+	ControllerHealth string   `json:"controller_health"`
+}
+
+// Record stores a heartbeat for the signing device's locker.
+func (h *Handler) Record(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	d := middleware.Device(c)
+	var req heartbeatRequest
+	if err := c.BodyParser(&req); err != nil {
+		logger.Warn(c.Context(), "heartbeat invalid body", map[string]interface{}{
+			"deviceId": d.ID.String(),
+			"error":    err.Error(),
+		}, requestURL)
+		return writeError(c, fiber.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+	}
+	hb, status, err := h.uc.Record(c.UserContext(), d.LockerID, heartbeatusecase.Input{
+		FirmwareVersion:  req.FirmwareVersion,
+		TemperatureC:     req.TemperatureC,
+		ControllerHealth: req.ControllerHealth,
+	})
+	if err != nil {
+		logger.Warn(c.Context(), "heartbeat rejected", map[string]interface{}{
+			"deviceId": d.ID.String(),
+			"error":    err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	data := heartbeatToResponse(hb)
+	data["locker_status"] = status
+	return c.JSON(response.APIResponse{Success: true, Data: data})
+}
+
+// Latest returns the latest heartbeat of the locker.
+func (h *Handler) Latest(c *fiber.Ctx) error {
+	lockerID, err := uuid.Parse(c.Params("locker_id"))
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid locker_id")
+	}
+	hb, err := h.uc.Latest(c.UserContext(), lockerID)
+	if err != nil {
+		return mapError(c, err)
+	}
+	if hb == nil {
+		return writeError(c, fiber.StatusNotFound, "HEARTBEAT_NOT_FOUND", "locker has not sent a heartbeat")
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: heartbeatToResponse(hb)})
+}
+
+func mapError(c *fiber.Ctx, err error) error {
+	var appErr errorx.Error
+	if errors.As(err, &appErr) {
+		return writeError(c, statusFromCode(appErr.Code), appErr.Code, appErr.Message)
+	}
+	return writeError(c, fiber.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+}
+
+func statusFromCode(code string) int {
+	switch code {
+	case "INVALID_REQUEST", locker.ErrInvalidHeartbeat.Code:
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+func writeError(c *fiber.Ctx, status int, code, msg string) error {
+	return c.Status(status).JSON(response.Error(code, msg))
+}
+
+func heartbeatToResponse(hb *locker.Heartbeat) map[string]interface{} {
+	return map[string]interface{}{
+		"locker_id":         hb.LockerID,
+		"device_id":         hb.DeviceID,
+		"firmware_version":  hb.FirmwareVersion,
+		"temperature_c":     hb.TemperatureC,
+		"controller_health": hb.ControllerHealth,
+		"received_at":       hb.ReceivedAt,
+	}
+}
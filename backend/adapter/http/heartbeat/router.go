@@ -0,0 +1,19 @@
+package heartbeat
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"smart-parcel-locker/backend/adapter/http/middleware"
+	"smart-parcel-locker/backend/domain/admin"
+)
+
+// RegisterDeviceRoutes wires the heartbeat endpoint. The group must require a signed device
+// request.
+func RegisterDeviceRoutes(router fiber.Router, handler *Handler) {
+	router.Post("/heartbeat", handler.Record)
+}
+
+// RegisterRoutes wires the admin view of a locker's latest heartbeat.
+func RegisterRoutes(router fiber.Router, handler *Handler, lockerLocation middleware.LocationResolver) {
+	router.Get("/lockers/:locker_id/heartbeat", middleware.RequirePermission(admin.PermInventoryRead), middleware.RequireLocation(lockerLocation), handler.Latest)
+}
@@ -0,0 +1,241 @@
+package incident
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"smart-parcel-locker/backend/adapter/http/middleware"
+	"smart-parcel-locker/backend/domain/compartment"
+	"smart-parcel-locker/backend/domain/incident"
+	"smart-parcel-locker/backend/pkg/errorx"
+	"smart-parcel-locker/backend/pkg/logger"
+	"smart-parcel-locker/backend/pkg/response"
+	incidentusecase "smart-parcel-locker/backend/usecase/incident"
+)
+
+// Handler receives door events from kiosks and exposes incidents to admins.
+type Handler struct {
+	uc *incidentusecase.UseCase
+}
+
+func NewHandler(uc *incidentusecase.UseCase) *Handler {
+	return &Handler{uc: uc}
+}
+
+type doorEventRequest struct {
+	Events []struct {
+		CompartmentNo int        `json:"compartment_no"`
+		Type          string     `json:"type"`
+		OccurredAt    *time.Time `json:"occurred_at"`
+	} `json:"events"`
+}
+
+// RecordDoorEvents stores door and sensor events of the signing device's locker.
+func (h *Handler) RecordDoorEvents(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	d := middleware.Device(c)
+	var req doorEventRequest
+	if err := c.BodyParser(&req); err != nil {
+		logger.Warn(c.Context(), "door events invalid body", map[string]interface{}{
+			"deviceId": d.ID.String(),
+			"error":    err.Error(),
+		}, requestURL)
+		return writeError(c, fiber.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+	}
+	inputs := make([]incidentusecase.DoorEventInput, 0, len(req.Events))
+	for _, e := range req.Events {
+		inputs = append(inputs, incidentusecase.DoorEventInput{
+			CompartmentNo: e.CompartmentNo,
+			Type:          e.Type,
+			OccurredAt:    e.OccurredAt,
+		})
+	}
+	events, err := h.uc.RecordDoorEvents(c.UserContext(), d.LockerID, inputs)
+	if err != nil {
+		logger.Warn(c.Context(), "door events rejected", map[string]interface{}{
+			"deviceId": d.ID.String(),
+			"lockerId": d.LockerID.String(),
+			"error":    err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	data := make([]map[string]interface{}, 0, len(events))
+	for _, e := range events {
+		data = append(data, eventToResponse(e))
+	}
+	return c.Status(fiber.StatusCreated).JSON(response.APIResponse{Success: true, Data: data})
+}
+
+// List returns incidents newest first. Admins scoped to locations only see incidents at lockers
+// of those locations.
+func (h *Handler) List(c *fiber.Ctx) error {
+	filter := incident.Filter{
+		Status:      c.Query("status"),
+		Type:        c.Query("type"),
+		LocationIDs: middleware.AdminPrincipal(c).LocationIDs,
+		Limit:       c.QueryInt("limit", 50),
+		Offset:      c.QueryInt("offset", 0),
+	}
+	if raw := c.Query("locker_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid locker_id")
+		}
+		filter.LockerID = &id
+	}
+	items, total, err := h.uc.List(c.UserContext(), filter)
+	if err != nil {
+		logger.Warn(c.Context(), "incident list failed", map[string]interface{}{
+			"error": err.Error(),
+		}, c.OriginalURL())
+		return mapError(c, err)
+	}
+	data := make([]map[string]interface{}, 0, len(items))
+	for _, inc := range items {
+		data = append(data, incidentToResponse(inc))
+	}
+	return c.JSON(response.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"items":  data,
+			"total":  total,
+			"limit":  filter.Limit,
+			"offset": filter.Offset,
+		},
+	})
+}
+
+// Get returns one incident.
+func (h *Handler) Get(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid id")
+	}
+	inc, err := h.uc.Get(c.UserContext(), id)
+	if err != nil {
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: incidentToResponse(inc)})
+}
+
+type resolveRequest struct {
+	Note string `json:"note"`
+}
+
+// Resolve closes an open incident with a note of what staff did about it.
+func (h *Handler) Resolve(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid id")
+	}
+	var req resolveRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return writeError(c, fiber.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		}
+	}
+	inc, err := h.uc.Resolve(c.UserContext(), id, req.Note)
+	if err != nil {
+		logger.Warn(c.Context(), "incident resolve failed", map[string]interface{}{
+			"incidentId": id.String(),
+			"error":      err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: incidentToResponse(inc)})
+}
+
+// CompartmentEvents returns the door and sensor events of a compartment, newest first.
+func (h *Handler) CompartmentEvents(c *fiber.Ctx) error {
+	lockerID, err := uuid.Parse(c.Params("locker_id"))
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid locker_id")
+	}
+	compartmentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid id")
+	}
+	events, err := h.uc.CompartmentEvents(c.UserContext(), lockerID, compartmentID, c.QueryInt("limit", 50))
+	if err != nil {
+		return mapError(c, err)
+	}
+	data := make([]map[string]interface{}, 0, len(events))
+	for _, e := range events {
+		data = append(data, eventToResponse(e))
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: data})
+}
+
+// IncidentLocation resolves the location of the incident in the :id route parameter.
+func (h *Handler) IncidentLocation(c *fiber.Ctx) (uuid.UUID, bool, error) {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, false, nil
+	}
+	locationID, err := h.uc.Location(c.UserContext(), id)
+	if err != nil {
+		if errors.Is(err, incident.ErrIncidentNotFound) {
+			return uuid.Nil, false, nil
+		}
+		return uuid.Nil, false, err
+	}
+	return locationID, true, nil
+}
+
+func mapError(c *fiber.Ctx, err error) error {
+	var appErr errorx.Error
+	if errors.As(err, &appErr) {
+		return writeError(c, statusFromCode(appErr.Code), appErr.Code, appErr.Message)
+	}
+	return writeError(c, fiber.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+}
+
+func statusFromCode(code string) int {
+	switch code {
+	case "INVALID_REQUEST", "INVALID_DOOR_EVENT":
+		return fiber.StatusBadRequest
+	case "INCIDENT_NOT_FOUND", compartment.ErrCompartmentNotFound.Code:
+		return fiber.StatusNotFound
+	case "INCIDENT_ALREADY_RESOLVED":
+		return fiber.StatusConflict
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+func writeError(c *fiber.Ctx, status int, code, msg string) error {
+	return c.Status(status).JSON(response.Error(code, msg))
+}
+
+func eventToResponse(e compartment.Event) map[string]interface{} {
+	return map[string]interface{}{
+		"id":             e.ID,
+		"compartment_id": e.CompartmentID,
+		"locker_id":      e.LockerID,
+		"parcel_id":      e.ParcelID,
+		"device_id":      e.DeviceID,
+		"type":           e.Type,
+		"occurred_at":    e.OccurredAt,
+		"created_at":     e.CreatedAt,
+	}
+}
+
+func incidentToResponse(inc *incident.Incident) map[string]interface{} {
+	return map[string]interface{}{
+		"id":             inc.ID,
+		"type":           inc.Type,
+		"status":         inc.Status,
+		"locker_id":      inc.LockerID,
+		"compartment_id": inc.CompartmentID,
+		"parcel_id":      inc.ParcelID,
+		"detail":         inc.Detail,
+		"opened_at":      inc.OpenedAt,
+		"resolved_at":    inc.ResolvedAt,
+		"resolved_by":    inc.ResolvedBy,
+		"resolution":     inc.Resolution,
+	}
+}
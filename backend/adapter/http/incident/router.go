@@ -0,0 +1,25 @@
+package incident
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"smart-parcel-locker/backend/adapter/http/middleware"
+	"smart-parcel-locker/backend/domain/admin"
+)
+
+// RegisterDeviceRoutes wires the endpoint kiosks post door and sensor events to. The group must
+// require a signed device request.
+func RegisterDeviceRoutes(router fiber.Router, handler *Handler) {
+	router.Post("/door-events", handler.RecordDoorEvents)
+}
+
+// RegisterRoutes wires the admin incidents API and compartment event history.
+func RegisterRoutes(router fiber.Router, handler *Handler, lockerLocation middleware.LocationResolver) {
+	read := middleware.RequirePermission(admin.PermInventoryRead)
+	incidentScope := middleware.RequireLocation(handler.IncidentLocation)
+
+	router.Get("/incidents", read, handler.List)
+	router.Get("/incidents/:id", read, incidentScope, handler.Get)
+	router.Post("/incidents/:id/resolve", middleware.RequirePermission(admin.PermLockersWrite), incidentScope, handler.Resolve)
+	router.Get("/lockers/:locker_id/compartments/:id/events", read, middleware.RequireLocation(lockerLocation), handler.CompartmentEvents)
+}
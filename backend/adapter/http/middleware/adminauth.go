@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/google/uuid"
+
+	admindomain "smart-parcel-locker/backend/domain/admin"
+	auditdomain "smart-parcel-locker/backend/domain/audit"
+	"smart-parcel-locker/backend/pkg/errorx"
+	"smart-parcel-locker/backend/pkg/logger"
+	"smart-parcel-locker/backend/pkg/response"
+)
+
+const adminPrincipalKey = "adminPrincipal"
+
+// AdminAuthenticator resolves an admin access token.
+type AdminAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (*admindomain.Principal, error)
+}
+
+// RequireAdmin rejects requests without a valid "Authorization: Bearer <access token>" header
+// and stores the authenticated admin for AdminPrincipal. The admin, client IP and request ID are
+// also attached to c.UserContext() so use cases can record who made a change.
+func RequireAdmin(auth AdminAuthenticator) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, err := auth.Authenticate(c.Context(), BearerToken(c))
+		if err != nil {
+			var appErr errorx.Error
+			if errors.As(err, &appErr) {
+				logger.Warn(c.Context(), "admin request unauthenticated", map[string]interface{}{
+					"code": appErr.Code,
+				}, c.OriginalURL())
+				return writeAuthError(c, fiber.StatusUnauthorized, appErr)
+			}
+			logger.Error(c.Context(), "admin authentication failed unexpectedly", map[string]interface{}{
+				"error": err.Error(),
+			}, c.OriginalURL())
+			return c.Status(fiber.StatusInternalServerError).JSON(response.Error("INTERNAL_ERROR", "internal error"))
+		}
+		c.Locals(adminPrincipalKey, principal)
+		c.SetUserContext(auditdomain.WithActor(c.UserContext(), auditdomain.Actor{
+			AdminID:   principal.AdminID,
+			Username:  principal.Username,
+			IP:        c.IP(),
+			RequestID: RequestID(c),
+		}))
+		return c.Next()
+	}
+}
+
+// RequirePermission rejects admins whose role lacks any of perms, and admins who still have to
+// enrol the two-factor authentication their role requires. It must follow RequireAdmin.
+func RequirePermission(perms ...admindomain.Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal := AdminPrincipal(c)
+		if principal == nil {
+			return writeAuthError(c, fiber.StatusUnauthorized, admindomain.ErrUnauthorized)
+		}
+		if principal.MFAEnrollmentRequired {
+			return writeAuthError(c, fiber.StatusForbidden, admindomain.ErrMFAEnrollRequired)
+		}
+		for _, perm := range perms {
+			if !principal.Can(perm) {
+				logger.Warn(c.Context(), "admin request forbidden", map[string]interface{}{
+					"adminId":    principal.AdminID.String(),
+					"role":       principal.Role,
+					"permission": string(perm),
+				}, c.OriginalURL())
+				return writeAuthError(c, fiber.StatusForbidden, admindomain.ErrForbidden)
+			}
+		}
+		return c.Next()
+	}
+}
+
+// LocationResolver returns the location a request acts on. ok=false lets the handler report
+// malformed or unknown ids itself.
+type LocationResolver func(c *fiber.Ctx) (id uuid.UUID, ok bool, err error)
+
+// RequireLocation rejects admins scoped to other locations than the one the request acts on.
+// Unscoped admins pass without resolving the location. It must follow RequireAdmin.
+func RequireLocation(resolve LocationResolver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal := AdminPrincipal(c)
+		if principal == nil {
+			return writeAuthError(c, fiber.StatusUnauthorized, admindomain.ErrUnauthorized)
+		}
+		if !principal.Scoped() {
+			return c.Next()
+		}
+		locationID, ok, err := resolve(c)
+		if err != nil {
+			logger.Error(c.Context(), "admin location scope failed unexpectedly", map[string]interface{}{
+				"error": err.Error(),
+			}, c.OriginalURL())
+			return c.Status(fiber.StatusInternalServerError).JSON(response.Error("INTERNAL_ERROR", "internal error"))
+		}
+		if ok && !principal.CanAccessLocation(locationID) {
+			logger.Warn(c.Context(), "admin request outside location scope", map[string]interface{}{
+				"adminId":    principal.AdminID.String(),
+				"locationId": locationID.String(),
+			}, c.OriginalURL())
+			return writeAuthError(c, fiber.StatusForbidden, admindomain.ErrLocationForbidden)
+		}
+		return c.Next()
+	}
+}
+
+// RequireAllLocations rejects admins scoped to specific locations, for actions that span
+// every location. It must follow RequireAdmin.
+func RequireAllLocations() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal := AdminPrincipal(c)
+		if principal == nil {
+			return writeAuthError(c, fiber.StatusUnauthorized, admindomain.ErrUnauthorized)
+		}
+		if principal.Scoped() {
+			return writeAuthError(c, fiber.StatusForbidden, admindomain.ErrLocationForbidden)
+		}
+		return c.Next()
+	}
+}
+
+// LocationParam resolves the location from a route parameter.
+func LocationParam(name string) LocationResolver {
+	return func(c *fiber.Ctx) (uuid.UUID, bool, error) {
+		id, err := uuid.Parse(c.Params(name))
+		return id, err == nil, nil
+	}
+}
+
+func writeAuthError(c *fiber.Ctx, status int, err errorx.Error) error {
+	return c.Status(status).JSON(response.Error(err.Code, err.Message))
+}
+
+// AdminPrincipal returns the admin authenticated by RequireAdmin, or nil.
+func AdminPrincipal(c *fiber.Ctx) *admindomain.Principal {
+	principal, _ := c.Locals(adminPrincipalKey).(*admindomain.Principal)
+	return principal
+}
+
+// RequestID returns the request ID assigned by the requestid middleware, cut to 64 characters
+// because clients may supply their own X-Request-ID.
+func RequestID(c *fiber.Ctx) string {
+	id, _ := c.Locals(requestid.ConfigDefault.ContextKey).(string)
+	if len(id) > 64 {
+		id = id[:64]
+	}
+	return id
+}
+
+// BearerToken extracts the token from an "Authorization: Bearer" header.
+func BearerToken(c *fiber.Ctx) string {
+	header := strings.TrimSpace(c.Get(fiber.HeaderAuthorization))
+	if len(header) < 7 || !strings.EqualFold(header[:7], "bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(header[7:])
+}
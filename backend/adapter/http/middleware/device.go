@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	devicedomain "smart-parcel-locker/backend/domain/device"
+	"smart-parcel-locker/backend/pkg/errorx"
+	"smart-parcel-locker/backend/pkg/logger"
+	"smart-parcel-locker/backend/pkg/response"
+	deviceusecase "smart-parcel-locker/backend/usecase/device"
+)
+
+const devicePrincipalKey = "device"
+
+// DeviceAuthenticator verifies a signed kiosk request.
+type DeviceAuthenticator interface {
+	Authenticate(ctx context.Context, req deviceusecase.SignedRequest) (*devicedomain.Device, error)
+}
+
+// OptionalDevice verifies requests that carry an X-Device-Id header and attaches the device to
+// c.UserContext() so use cases can record which kiosk acted. Requests without the header pass
+// through unchanged, so browsers keep using the same endpoints.
+func OptionalDevice(auth DeviceAuthenticator) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Get(devicedomain.HeaderDeviceID) == "" {
+			return c.Next()
+		}
+		return authenticateDevice(c, auth)
+	}
+}
+
+// RequireDevice rejects requests that are not signed by a registered, active device.
+func RequireDevice(auth DeviceAuthenticator) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Get(devicedomain.HeaderDeviceID) == "" {
+			return writeAuthError(c, fiber.StatusUnauthorized, devicedomain.ErrUnauthorized)
+		}
+		return authenticateDevice(c, auth)
+	}
+}
+
+func authenticateDevice(c *fiber.Ctx, auth DeviceAuthenticator) error {
+	d, err := auth.Authenticate(c.Context(), deviceusecase.SignedRequest{
+		DeviceID:  c.Get(devicedomain.HeaderDeviceID),
+		Timestamp: c.Get(devicedomain.HeaderTimestamp),
+		Nonce:     c.Get(devicedomain.HeaderNonce),
+		Signature: c.Get(devicedomain.HeaderSignature),
+		Method:    c.Method(),
+		Path:      c.OriginalURL(),
+		Body:      c.Body(),
+	})
+	if err != nil {
+		var appErr errorx.Error
+		if errors.As(err, &appErr) {
+			return writeAuthError(c, fiber.StatusUnauthorized, appErr)
+		}
+		logger.Error(c.Context(), "device authentication failed unexpectedly", map[string]interface{}{
+			"error": err.Error(),
+		}, c.OriginalURL())
+		return c.Status(fiber.StatusInternalServerError).JSON(response.Error("INTERNAL_ERROR", "internal error"))
+	}
+	c.Locals(devicePrincipalKey, d)
+	c.SetUserContext(devicedomain.WithDevice(c.UserContext(), d))
+	return c.Next()
+}
+
+// Device returns the device authenticated by OptionalDevice or RequireDevice, or nil.
+func Device(c *fiber.Ctx) *devicedomain.Device {
+	d, _ := c.Locals(devicePrincipalKey).(*devicedomain.Device)
+	return d
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	ratelimitdomain "smart-parcel-locker/backend/domain/ratelimit"
+	"smart-parcel-locker/backend/pkg/logger"
+	"smart-parcel-locker/backend/pkg/phone"
+	"smart-parcel-locker/backend/pkg/response"
+	ratelimitusecase "smart-parcel-locker/backend/usecase/ratelimit"
+)
+
+// SubjectFunc extracts the value a rate limit dimension is keyed by.
+type SubjectFunc func(c *fiber.Ctx) string
+
+// RateLimit enforces the limiter policy for endpoint and writes X-RateLimit-* headers.
+func RateLimit(limiter *ratelimitusecase.Limiter, endpoint string, subjects map[ratelimitdomain.Dimension]SubjectFunc) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if limiter == nil {
+			return c.Next()
+		}
+		values := make(map[ratelimitdomain.Dimension]string, len(subjects))
+		for dimension, extract := range subjects {
+			values[dimension] = extract(c)
+		}
+		decision, ok := limiter.Check(c.Context(), endpoint, values)
+		if !ok {
+			return c.Next()
+		}
+		now := time.Now()
+		c.Set("X-RateLimit-Limit", strconv.Itoa(decision.Limit))
+		c.Set("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		c.Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.ResetAt.Sub(now))))
+		if !decision.Allowed {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(ceilSeconds(decision.RetryAfter(now))))
+			logger.Warn(c.Context(), "request rate limited", map[string]interface{}{
+				"endpoint": endpoint,
+			}, c.OriginalURL())
+			return c.Status(fiber.StatusTooManyRequests).JSON(response.Error(
+				ratelimitdomain.ErrTooManyRequests.Code,
+				ratelimitdomain.ErrTooManyRequests.Message,
+			))
+		}
+		return c.Next()
+	}
+}
+
+// ClientIP keys a limit by the caller's address (honouring the configured proxy header).
+func ClientIP() SubjectFunc {
+	return func(c *fiber.Ctx) string {
+		return c.IP()
+	}
+}
+
+// BodyField keys a limit by a top-level string field of the JSON body.
+func BodyField(name string) SubjectFunc {
+	return func(c *fiber.Ctx) string {
+		var body map[string]interface{}
+		if err := json.Unmarshal(c.Body(), &body); err != nil {
+			return ""
+		}
+		value, _ := body[name].(string)
+		return strings.TrimSpace(value)
+	}
+}
+
+// PhoneField keys a limit by a JSON body phone field in E.164 form, so format
+// variations of the same number share one budget.
+func PhoneField(name string, phones phone.Normalizer) SubjectFunc {
+	raw := BodyField(name)
+	return func(c *fiber.Ctx) string {
+		value := raw(c)
+		if normalized, err := phones.Normalize(value); err == nil {
+			return normalized
+		}
+		return value
+	}
+}
+
+// Param keys a limit by a route parameter.
+func Param(name string) SubjectFunc {
+	return func(c *fiber.Ctx) string {
+		return c.Params(name)
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,350 @@
+package notification
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	notificationdomain "smart-parcel-locker/backend/domain/notification"
+	"smart-parcel-locker/backend/pkg/errorx"
+	"smart-parcel-locker/backend/pkg/logger"
+	"smart-parcel-locker/backend/pkg/response"
+	notificationusecase "smart-parcel-locker/backend/usecase/notification"
+)
+
+// Handler exposes notification settings and the outbox for admins.
+type Handler struct {
+	uc         *notificationusecase.UseCase
+	dispatcher *notificationusecase.Dispatcher
+}
+
+func NewHandler(uc *notificationusecase.UseCase, dispatcher *notificationusecase.Dispatcher) *Handler {
+	return &Handler{uc: uc, dispatcher: dispatcher}
+}
+
+type preferenceRequest struct {
+	Channel string `json:"channel"`
+	Address string `json:"address"`
+	Locale  string `json:"locale"`
+}
+
+// ListChannels returns the channels enabled in this deployment.
+func (h *Handler) ListChannels(c *fiber.Ctx) error {
+	return c.JSON(response.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"channels": h.uc.Channels(),
+		},
+	})
+}
+
+func (h *Handler) GetPreference(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	phone := c.Params("phone")
+	pref, err := h.uc.GetPreference(c.UserContext(), phone)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Warn(c.Context(), "notification preference not found", map[string]interface{}{
+				"receiverPhone": phone,
+			}, requestURL)
+			return writeError(c, fiber.StatusNotFound, "NOT_FOUND", "not found")
+		}
+		logger.Warn(c.Context(), "notification preference get failed", map[string]interface{}{
+			"receiverPhone": phone,
+			"error":         err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: preferenceToResponse(pref)})
+}
+
+func (h *Handler) SetPreference(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	phone := c.Params("phone")
+	var req preferenceRequest
+	if err := c.BodyParser(&req); err != nil {
+		logger.Warn(c.Context(), "notification preference invalid body", map[string]interface{}{
+			"error": err.Error(),
+		}, requestURL)
+		return writeError(c, fiber.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+	}
+	logger.Info(c.Context(), "notification preference update request received", map[string]interface{}{
+		"receiverPhone": phone,
+		"channel":       req.Channel,
+	}, requestURL)
+	pref, err := h.uc.SetPreference(c.UserContext(), notificationusecase.SetPreferenceInput{
+		Phone:   phone,
+		Channel: notificationdomain.Channel(req.Channel),
+		Address: req.Address,
+		Locale:  req.Locale,
+	})
+	if err != nil {
+		logger.Warn(c.Context(), "notification preference update failed", map[string]interface{}{
+			"receiverPhone": phone,
+			"channel":       req.Channel,
+			"error":         err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: preferenceToResponse(pref)})
+}
+
+type templateRequest struct {
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+type previewRequest struct {
+	Subject *string           `json:"subject"`
+	Body    *string           `json:"body"`
+	Data    map[string]string `json:"data"`
+}
+
+// ListTemplates returns every message template, marking those edited by an admin.
+func (h *Handler) ListTemplates(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	views, err := h.uc.ListTemplates(c.UserContext())
+	if err != nil {
+		logger.Warn(c.Context(), "notification template list failed", map[string]interface{}{
+			"error": err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	items := make([]map[string]interface{}, 0, len(views))
+	for i := range views {
+		items = append(items, templateToResponse(&views[i]))
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: items})
+}
+
+func (h *Handler) SetTemplate(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	messageType, locale := c.Params("type"), c.Params("locale")
+	var req templateRequest
+	if err := c.BodyParser(&req); err != nil {
+		logger.Warn(c.Context(), "notification template invalid body", map[string]interface{}{
+			"error": err.Error(),
+		}, requestURL)
+		return writeError(c, fiber.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+	}
+	logger.Info(c.Context(), "notification template update request received", map[string]interface{}{
+		"messageType": messageType,
+		"locale":      locale,
+	}, requestURL)
+	view, err := h.uc.SetTemplate(c.UserContext(), notificationusecase.SetTemplateInput{
+		Type:    messageType,
+		Locale:  locale,
+		Subject: req.Subject,
+		Body:    req.Body,
+	})
+	if err != nil {
+		logger.Warn(c.Context(), "notification template update failed", map[string]interface{}{
+			"messageType": messageType,
+			"locale":      locale,
+			"error":       err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: templateToResponse(view)})
+}
+
+// ResetTemplate discards an admin edit and restores the built-in template.
+func (h *Handler) ResetTemplate(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	messageType, locale := c.Params("type"), c.Params("locale")
+	logger.Info(c.Context(), "notification template reset request received", map[string]interface{}{
+		"messageType": messageType,
+		"locale":      locale,
+	}, requestURL)
+	if err := h.uc.ResetTemplate(c.UserContext(), messageType, locale); err != nil {
+		logger.Warn(c.Context(), "notification template reset failed", map[string]interface{}{
+			"messageType": messageType,
+			"locale":      locale,
+			"error":       err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true})
+}
+
+// PreviewTemplate renders the stored template, or a draft from the body, with sample data.
+func (h *Handler) PreviewTemplate(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	var req previewRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			logger.Warn(c.Context(), "notification template preview invalid body", map[string]interface{}{
+				"error": err.Error(),
+			}, requestURL)
+			return writeError(c, fiber.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		}
+	}
+	rendered, err := h.uc.Preview(c.UserContext(), notificationusecase.PreviewInput{
+		Type:    c.Params("type"),
+		Locale:  c.Params("locale"),
+		Subject: req.Subject,
+		Body:    req.Body,
+		Data:    req.Data,
+	})
+	if err != nil {
+		logger.Warn(c.Context(), "notification template preview failed", map[string]interface{}{
+			"messageType": c.Params("type"),
+			"locale":      c.Params("locale"),
+			"error":       err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"subject": rendered.Subject,
+			"body":    rendered.Body,
+		},
+	})
+}
+
+// ListOutbox lists outbox messages, optionally filtered by status and phone.
+func (h *Handler) ListOutbox(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	filter := notificationdomain.OutboxFilter{
+		Status: notificationdomain.OutboxStatus(strings.ToUpper(c.Query("status"))),
+		Phone:  c.Query("phone"),
+		Limit:  c.QueryInt("limit", 50),
+		Offset: c.QueryInt("offset", 0),
+	}
+	items, total, err := h.dispatcher.ListOutbox(c.UserContext(), filter)
+	if err != nil {
+		logger.Warn(c.Context(), "notification outbox list failed", map[string]interface{}{
+			"status": filter.Status,
+			"error":  err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	data := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		data = append(data, outboxToResponse(item))
+	}
+	return c.JSON(response.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"items":  data,
+			"total":  total,
+			"limit":  filter.Limit,
+			"offset": filter.Offset,
+		},
+	})
+}
+
+func (h *Handler) GetOutbox(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid id")
+	}
+	item, err := h.dispatcher.GetOutbox(c.UserContext(), id)
+	if err != nil {
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: outboxToResponse(item)})
+}
+
+// RedriveOutbox returns a dead-lettered message to the delivery queue.
+func (h *Handler) RedriveOutbox(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid id")
+	}
+	logger.Info(c.Context(), "notification outbox redrive request received", map[string]interface{}{
+		"outboxId": id.String(),
+	}, requestURL)
+	item, err := h.dispatcher.Redrive(c.UserContext(), id)
+	if err != nil {
+		logger.Warn(c.Context(), "notification outbox redrive failed", map[string]interface{}{
+			"outboxId": id.String(),
+			"error":    err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: outboxToResponse(item)})
+}
+
+func mapError(c *fiber.Ctx, err error) error {
+	var appErr errorx.Error
+	if errors.As(err, &appErr) {
+		return writeError(c, statusFromCode(appErr.Code), appErr.Code, appErr.Message)
+	}
+	return writeError(c, fiber.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+}
+
+func statusFromCode(code string) int {
+	switch code {
+	case "INVALID_REQUEST", "INVALID_TEMPLATE":
+		return fiber.StatusBadRequest
+	case "CHANNEL_UNAVAILABLE":
+		return fiber.StatusUnprocessableEntity
+	case "NOT_FOUND", "TEMPLATE_NOT_FOUND":
+		return fiber.StatusNotFound
+	case "INVALID_STATUS_TRANSITION":
+		return fiber.StatusConflict
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+func writeError(c *fiber.Ctx, status int, code, msg string) error {
+	return c.Status(status).JSON(response.Error(code, msg))
+}
+
+func preferenceToResponse(pref *notificationdomain.Preference) map[string]interface{} {
+	if pref == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"phone":      pref.Phone,
+		"channel":    pref.Channel,
+		"address":    pref.Address,
+		"locale":     pref.Locale,
+		"created_at": pref.CreatedAt,
+		"updated_at": pref.UpdatedAt,
+	}
+}
+
+func templateToResponse(v *notificationusecase.TemplateView) map[string]interface{} {
+	return map[string]interface{}{
+		"type":       v.Type,
+		"locale":     v.Locale,
+		"subject":    v.Subject,
+		"body":       v.Body,
+		"customized": v.Customized,
+		"updated_at": v.UpdatedAt,
+	}
+}
+
+// outboxToResponse hides the body and data of sensitive messages such as OTP codes.
+func outboxToResponse(m *notificationdomain.OutboxMessage) map[string]interface{} {
+	body, data := m.Body, m.Data
+	if m.Sensitive() {
+		body, data = "[redacted]", nil
+	}
+	return map[string]interface{}{
+		"id":              m.ID,
+		"type":            m.Type,
+		"phone":           m.Phone,
+		"subject":         m.Subject,
+		"body":            body,
+		"locale":          m.Locale,
+		"data":            data,
+		"status":          m.Status,
+		"attempts":        m.Attempts,
+		"next_attempt_at": m.NextAttemptAt,
+		"expires_at":      m.ExpiresAt,
+		"last_error":      m.LastError,
+		"channel":         m.Channel,
+		"sent_at":         m.SentAt,
+		"created_at":      m.CreatedAt,
+		"updated_at":      m.UpdatedAt,
+	}
+}
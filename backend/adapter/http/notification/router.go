@@ -0,0 +1,31 @@
+package notification
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"smart-parcel-locker/backend/adapter/http/middleware"
+	"smart-parcel-locker/backend/domain/admin"
+)
+
+// RegisterRoutes wires notification admin endpoints. Templates and preferences apply to every
+// location, so changing them is not open to location-scoped admins. Receiver preferences and
+// outbox messages carry phone numbers from any location, so location-scoped admins cannot read
+// them either.
+func RegisterRoutes(router fiber.Router, handler *Handler) {
+	read := middleware.RequirePermission(admin.PermNotificationsRead)
+	readAll := []fiber.Handler{read, middleware.RequireAllLocations()}
+	write := []fiber.Handler{middleware.RequirePermission(admin.PermNotificationsWrite), middleware.RequireAllLocations()}
+
+	router.Get("/channels", read, handler.ListChannels)
+	router.Get("/preferences/:phone", append(readAll, handler.GetPreference)...)
+	router.Put("/preferences/:phone", append(write, handler.SetPreference)...)
+
+	router.Get("/templates", read, handler.ListTemplates)
+	router.Put("/templates/:type/:locale", append(write, handler.SetTemplate)...)
+	router.Delete("/templates/:type/:locale", append(write, handler.ResetTemplate)...)
+	router.Post("/templates/:type/:locale/preview", append(write, handler.PreviewTemplate)...)
+
+	router.Get("/outbox", append(readAll, handler.ListOutbox)...)
+	router.Get("/outbox/:id", append(readAll, handler.GetOutbox)...)
+	router.Post("/outbox/:id/redrive", append(write, handler.RedriveOutbox)...)
+}
@@ -28,6 +28,7 @@ type depositRequest struct {
 	Size          string `json:"size"`
 	ReceiverPhone string `json:"receiver_phone"`
 	SenderPhone   string `json:"sender_phone"`
+	CarrierCode   string `json:"carrier_code"`
 }
 
 func (h *Handler) Deposit(c *fiber.Ctx) error {
@@ -43,7 +44,7 @@ func (h *Handler) Deposit(c *fiber.Ctx) error {
 		return writeError(c, fiber.StatusBadRequest, "INVALID_REQUEST", "invalid locker_id")
 	}
 	requestURL := c.OriginalURL()
-	requestCtx := logger.WithTransactionID(c.Context(), uuid.New().String())
+	requestCtx := logger.WithTransactionID(c.UserContext(), uuid.New().String())
 	logger.Info(requestCtx, "deposit request received", map[string]interface{}{
 		"lockerId":      lockerID.String(),
 		"requestedSize": req.Size,
@@ -54,6 +55,7 @@ func (h *Handler) Deposit(c *fiber.Ctx) error {
 		Size:          req.Size,
 		ReceiverPhone: req.ReceiverPhone,
 		SenderPhone:   req.SenderPhone,
+		CarrierCode:   req.CarrierCode,
 		RequestURL:    requestURL,
 	})
 	if err != nil {
@@ -98,6 +100,7 @@ func (h *Handler) GetByID(c *fiber.Ctx) error {
 			"size":           p.Size,
 			"receiver_phone": p.ReceiverPhone,
 			"sender_phone":   p.SenderPhone,
+			"carrier_code":   p.CarrierCode,
 			"pickup_code":    p.PickupCode,
 			"deposited_at":   p.DepositedAt,
 			"picked_up_at":   p.PickedUpAt,
@@ -134,10 +137,14 @@ func statusFromCode(code string) int {
 	switch code {
 	case "INVALID_UUID", "INVALID_REQUEST":
 		return fiber.StatusBadRequest
+	case "DEVICE_LOCKER_MISMATCH":
+		return fiber.StatusForbidden
 	case "NOT_FOUND", parcel.ErrParcelNotFound.Code:
 		return fiber.StatusNotFound
-	case "NO_AVAILABLE_COMPARTMENT", "LOCKER_INACTIVE", "INVALID_STATUS_TRANSITION":
+	case "NO_AVAILABLE_COMPARTMENT", "LOCKER_INACTIVE", "LOCKER_OFFLINE", "INVALID_STATUS_TRANSITION":
 		return fiber.StatusConflict
+	case "DOOR_OPEN_FAILED", "DOOR_OUTCOME_UNKNOWN", "LOCK_CONTROLLER_UNAVAILABLE":
+		return fiber.StatusServiceUnavailable
 	default:
 		return fiber.StatusInternalServerError
 	}
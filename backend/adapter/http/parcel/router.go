@@ -1,9 +1,20 @@
 package parcel
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"smart-parcel-locker/backend/adapter/http/middleware"
+	ratelimitdomain "smart-parcel-locker/backend/domain/ratelimit"
+	ratelimitusecase "smart-parcel-locker/backend/usecase/ratelimit"
+)
 
 // RegisterRoutes wires parcel endpoints.
-func RegisterRoutes(router fiber.Router, handler *Handler) {
-	router.Post("/deposit", handler.Deposit)
-	router.Get("/:parcel_id", handler.GetByID)
+func RegisterRoutes(router fiber.Router, handler *Handler, limiter *ratelimitusecase.Limiter) {
+	router.Post("/deposit", middleware.RateLimit(limiter, ratelimitusecase.EndpointDeposit, map[ratelimitdomain.Dimension]middleware.SubjectFunc{
+		ratelimitdomain.DimensionIP:     middleware.ClientIP(),
+		ratelimitdomain.DimensionLocker: middleware.BodyField("locker_id"),
+	}), handler.Deposit)
+	router.Get("/:parcel_id", middleware.RateLimit(limiter, ratelimitusecase.EndpointTracking, map[ratelimitdomain.Dimension]middleware.SubjectFunc{
+		ratelimitdomain.DimensionIP: middleware.ClientIP(),
+	}), handler.GetByID)
 }
@@ -7,6 +7,8 @@ import (
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"smart-parcel-locker/backend/adapter/http/middleware"
+	"smart-parcel-locker/backend/domain/device"
 	"smart-parcel-locker/backend/domain/otp"
 	"smart-parcel-locker/backend/domain/parcel"
 	pickupdomain "smart-parcel-locker/backend/domain/pickup"
@@ -44,7 +46,7 @@ func (h *Handler) RequestOTP(c *fiber.Ctx) error {
 		"receiverPhone": req.Phone,
 	}, requestURL)
 
-	result, err := h.otpUC.RequestOTP(c.Context(), req.Phone)
+	result, err := h.otpUC.RequestOTP(c.UserContext(), req.Phone)
 	if err != nil {
 		code, msg := extractError(err)
 		if code == "INTERNAL_ERROR" {
@@ -75,9 +77,10 @@ func (h *Handler) RequestOTP(c *fiber.Ctx) error {
 }
 
 type verifyOTPRequest struct {
-	Phone  string `json:"phone"`
-	OtpRef string `json:"otp_ref"`
-	Otp    string `json:"otp_code"`
+	Phone    string `json:"phone"`
+	OtpRef   string `json:"otp_ref"`
+	Otp      string `json:"otp_code"`
+	LockerID string `json:"locker_id"`
 }
 
 func (h *Handler) VerifyOTP(c *fiber.Ctx) error {
@@ -89,12 +92,37 @@ func (h *Handler) VerifyOTP(c *fiber.Ctx) error {
 		}, requestURL)
 		return writeError(c, fiber.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
 	}
+	var lockerID *uuid.UUID
+	if req.LockerID != "" {
+		parsed, err := uuid.Parse(req.LockerID)
+		if err != nil {
+			logger.Warn(c.Context(), "pickup otp verify invalid locker_id", map[string]interface{}{
+				"lockerId": req.LockerID,
+				"error":    err.Error(),
+			}, requestURL)
+			return writeError(c, fiber.StatusBadRequest, "INVALID_REQUEST", "invalid locker_id")
+		}
+		lockerID = &parsed
+	}
+	// A kiosk always scopes the token to its own locker, whether or not it names one.
+	if d := middleware.Device(c); d != nil {
+		if lockerID != nil && *lockerID != d.LockerID {
+			logger.Warn(c.Context(), "pickup otp verify device locker mismatch", map[string]interface{}{
+				"deviceId": d.ID.String(),
+				"lockerId": req.LockerID,
+			}, requestURL)
+			return mapError(c, device.ErrLockerMismatch)
+		}
+		deviceLocker := d.LockerID
+		lockerID = &deviceLocker
+	}
 	logger.Info(c.Context(), "pickup otp verify request received", map[string]interface{}{
 		"receiverPhone": req.Phone,
 		"otpRef":        req.OtpRef,
+		"lockerId":      lockerID,
 	}, requestURL)
 
-	result, err := h.otpUC.VerifyOTP(c.Context(), req.Phone, req.OtpRef, req.Otp)
+	result, err := h.otpUC.VerifyOTP(c.UserContext(), req.Phone, req.OtpRef, req.Otp, lockerID)
 	if err != nil {
 		code, msg := extractError(err)
 		if code == "INTERNAL_ERROR" {
@@ -132,7 +160,7 @@ func (h *Handler) ListParcels(c *fiber.Ctx) error {
 	logger.Info(c.Context(), "pickup parcel list request received", map[string]interface{}{
 		"tokenPresent": token != "",
 	}, requestURL)
-	result, err := h.pickupUC.ListParcels(c.Context(), token)
+	result, err := h.pickupUC.ListParcels(c.UserContext(), token)
 	if err != nil {
 		code, msg := extractError(err)
 		if code == "INTERNAL_ERROR" {
@@ -197,7 +225,7 @@ func (h *Handler) ConfirmPickup(c *fiber.Ctx) error {
 	logger.Info(c.Context(), "pickup confirm request received", map[string]interface{}{
 		"parcelId": parcelID.String(),
 	}, requestURL)
-	result, err := h.pickupUC.ConfirmPickup(c.Context(), token, parcelID)
+	result, err := h.pickupUC.ConfirmPickup(c.UserContext(), token, parcelID)
 	if err != nil {
 		code, msg := extractError(err)
 		if code == "INTERNAL_ERROR" {
@@ -222,15 +250,39 @@ func (h *Handler) ConfirmPickup(c *fiber.Ctx) error {
 	return c.JSON(response.APIResponse{
 		Success: true,
 		Data: map[string]interface{}{
-			"parcel_id":    result.ParcelID,
-			"status":       result.Status,
-			"picked_up_at": result.PickedUpAt,
-			"overdue_days": result.OverdueDays,
-			"overdue_fee":  result.OverdueFee,
+			"parcel_id":     result.ParcelID,
+			"status":        result.Status,
+			"picked_up_at":  result.PickedUpAt,
+			"overdue_days":  result.OverdueDays,
+			"overdue_fee":   result.OverdueFee,
+			"token_revoked": result.TokenRevoked,
 		},
 	})
 }
 
+func (h *Handler) Logout(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	token := c.Get("X-Pickup-Token")
+	logger.Info(c.Context(), "pickup logout request received", map[string]interface{}{
+		"tokenPresent": token != "",
+	}, requestURL)
+	if err := h.pickupUC.Logout(c.UserContext(), token); err != nil {
+		code, msg := extractError(err)
+		if code == "INTERNAL_ERROR" {
+			logger.Error(c.Context(), "pickup logout failed unexpectedly", map[string]interface{}{
+				"error": msg,
+			}, requestURL)
+		} else {
+			logger.Warn(c.Context(), "pickup logout failed", map[string]interface{}{
+				"error": msg,
+			}, requestURL)
+		}
+		return mapError(c, err)
+	}
+	logger.Info(c.Context(), "pickup logout succeeded", map[string]interface{}{}, requestURL)
+	return c.JSON(response.APIResponse{Success: true})
+}
+
 func mapError(c *fiber.Ctx, err error) error {
 	if err == nil {
 		return nil
@@ -264,13 +316,13 @@ func statusFromCode(code string) int {
 	switch code {
 	case "INVALID_REQUEST", "INVALID_OTP":
 		return fiber.StatusBadRequest
-	case "FORBIDDEN":
+	case "FORBIDDEN", "DEVICE_LOCKER_MISMATCH":
 		return fiber.StatusForbidden
 	case "INVALID_TOKEN":
 		return fiber.StatusUnauthorized
 	case "OTP_ALREADY_USED":
 		return fiber.StatusConflict
-	case "CONFLICT", "INVALID_STATUS_TRANSITION":
+	case "CONFLICT", "INVALID_STATUS_TRANSITION", "LOCKER_DISABLED":
 		return fiber.StatusConflict
 	case "OTP_EXPIRED":
 		return fiber.StatusGone
@@ -282,6 +334,8 @@ func statusFromCode(code string) int {
 		return fiber.StatusTooManyRequests
 	case "TOKEN_EXPIRED":
 		return fiber.StatusGone
+	case "DOOR_OPEN_FAILED", "DOOR_OUTCOME_UNKNOWN", "LOCK_CONTROLLER_UNAVAILABLE":
+		return fiber.StatusServiceUnavailable
 	default:
 		return fiber.StatusInternalServerError
 	}
@@ -1,11 +1,25 @@
 package pickup
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"smart-parcel-locker/backend/adapter/http/middleware"
+	ratelimitdomain "smart-parcel-locker/backend/domain/ratelimit"
+	"smart-parcel-locker/backend/pkg/phone"
+	ratelimitusecase "smart-parcel-locker/backend/usecase/ratelimit"
+)
 
 // RegisterRoutes attaches pickup endpoints.
-func RegisterRoutes(router fiber.Router, handler *Handler) {
-	router.Post("/otp/request", handler.RequestOTP)
-	router.Post("/otp/verify", handler.VerifyOTP)
+func RegisterRoutes(router fiber.Router, handler *Handler, limiter *ratelimitusecase.Limiter, phones phone.Normalizer) {
+	router.Post("/otp/request", middleware.RateLimit(limiter, ratelimitusecase.EndpointOTPRequest, map[ratelimitdomain.Dimension]middleware.SubjectFunc{
+		ratelimitdomain.DimensionPhone: middleware.PhoneField("phone", phones),
+		ratelimitdomain.DimensionIP:    middleware.ClientIP(),
+	}), handler.RequestOTP)
+	router.Post("/otp/verify", middleware.RateLimit(limiter, ratelimitusecase.EndpointOTPVerify, map[ratelimitdomain.Dimension]middleware.SubjectFunc{
+		ratelimitdomain.DimensionPhone: middleware.PhoneField("phone", phones),
+		ratelimitdomain.DimensionIP:    middleware.ClientIP(),
+	}), handler.VerifyOTP)
 	router.Get("/parcels", handler.ListParcels)
 	router.Post("/confirm", handler.ConfirmPickup)
+	router.Post("/logout", handler.Logout)
 }
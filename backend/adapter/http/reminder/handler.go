@@ -0,0 +1,127 @@
+package reminder
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/pkg/errorx"
+	"smart-parcel-locker/backend/pkg/logger"
+	"smart-parcel-locker/backend/pkg/response"
+	reminderusecase "smart-parcel-locker/backend/usecase/reminder"
+)
+
+// Handler exposes per-location reminder policy endpoints.
+type Handler struct {
+	uc *reminderusecase.UseCase
+}
+
+func NewHandler(uc *reminderusecase.UseCase) *Handler {
+	return &Handler{uc: uc}
+}
+
+type policyRequest struct {
+	Rules []string `json:"rules"`
+}
+
+func (h *Handler) GetPolicy(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	locationID, err := uuid.Parse(c.Params("location_id"))
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid location_id")
+	}
+	policy, err := h.uc.GetPolicy(c.UserContext(), locationID)
+	if err != nil {
+		logger.Warn(c.Context(), "reminder policy get failed", map[string]interface{}{
+			"locationId": locationID.String(),
+			"error":      err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: policyToResponse(policy)})
+}
+
+func (h *Handler) SetPolicy(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	locationID, err := uuid.Parse(c.Params("location_id"))
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid location_id")
+	}
+	var req policyRequest
+	if err := c.BodyParser(&req); err != nil {
+		logger.Warn(c.Context(), "reminder policy invalid body", map[string]interface{}{
+			"locationId": locationID.String(),
+			"error":      err.Error(),
+		}, requestURL)
+		return writeError(c, fiber.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+	}
+	logger.Info(c.Context(), "reminder policy update request received", map[string]interface{}{
+		"locationId": locationID.String(),
+		"rules":      req.Rules,
+	}, requestURL)
+	policy, err := h.uc.SetPolicy(c.UserContext(), locationID, strings.Join(req.Rules, ","))
+	if err != nil {
+		logger.Warn(c.Context(), "reminder policy update failed", map[string]interface{}{
+			"locationId": locationID.String(),
+			"error":      err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: policyToResponse(policy)})
+}
+
+func (h *Handler) DeletePolicy(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	locationID, err := uuid.Parse(c.Params("location_id"))
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid location_id")
+	}
+	if err := h.uc.DeletePolicy(c.UserContext(), locationID); err != nil {
+		logger.Warn(c.Context(), "reminder policy delete failed", map[string]interface{}{
+			"locationId": locationID.String(),
+			"error":      err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true})
+}
+
+func mapError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return writeError(c, fiber.StatusNotFound, "NOT_FOUND", "not found")
+	}
+	var appErr errorx.Error
+	if errors.As(err, &appErr) {
+		return writeError(c, statusFromCode(appErr.Code), appErr.Code, appErr.Message)
+	}
+	return writeError(c, fiber.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+}
+
+func statusFromCode(code string) int {
+	switch code {
+	case "INVALID_REQUEST", "INVALID_POLICY":
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+func writeError(c *fiber.Ctx, status int, code, msg string) error {
+	return c.Status(status).JSON(response.Error(code, msg))
+}
+
+func policyToResponse(policy *reminderusecase.EffectivePolicy) map[string]interface{} {
+	rules := make([]string, 0, len(policy.Rules))
+	for _, r := range policy.Rules {
+		rules = append(rules, r.Key())
+	}
+	return map[string]interface{}{
+		"location_id": policy.LocationID,
+		"rules":       rules,
+		"default":     policy.Default,
+		"updated_at":  policy.UpdatedAt,
+	}
+}
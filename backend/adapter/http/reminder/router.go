@@ -0,0 +1,19 @@
+package reminder
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"smart-parcel-locker/backend/adapter/http/middleware"
+	"smart-parcel-locker/backend/domain/admin"
+)
+
+// RegisterRoutes wires reminder policy endpoints under /admin.
+func RegisterRoutes(router fiber.Router, handler *Handler) {
+	scope := middleware.RequireLocation(middleware.LocationParam("location_id"))
+	read := middleware.RequirePermission(admin.PermInventoryRead)
+	write := middleware.RequirePermission(admin.PermLocationsWrite)
+
+	router.Get("/locations/:location_id/reminder-policy", read, scope, handler.GetPolicy)
+	router.Put("/locations/:location_id/reminder-policy", write, scope, handler.SetPolicy)
+	router.Delete("/locations/:location_id/reminder-policy", write, scope, handler.DeletePolicy)
+}
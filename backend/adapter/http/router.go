@@ -5,9 +5,18 @@ import (
 
 	adminadapter "smart-parcel-locker/backend/adapter/http/admin"
 	adminopsadapter "smart-parcel-locker/backend/adapter/http/adminops"
+	auditadapter "smart-parcel-locker/backend/adapter/http/audit"
+	deviceadapter "smart-parcel-locker/backend/adapter/http/device"
+	heartbeatadapter "smart-parcel-locker/backend/adapter/http/heartbeat"
+	incidentadapter "smart-parcel-locker/backend/adapter/http/incident"
 	lockeradapter "smart-parcel-locker/backend/adapter/http/locker"
+	notificationadapter "smart-parcel-locker/backend/adapter/http/notification"
 	parceladapter "smart-parcel-locker/backend/adapter/http/parcel"
 	pickupadapter "smart-parcel-locker/backend/adapter/http/pickup"
+	reminderadapter "smart-parcel-locker/backend/adapter/http/reminder"
+	webhookadapter "smart-parcel-locker/backend/adapter/http/webhook"
+	"smart-parcel-locker/backend/pkg/phone"
+	ratelimitusecase "smart-parcel-locker/backend/usecase/ratelimit"
 )
 
 // Register attaches all HTTP routes to the Fiber app.
@@ -18,21 +27,55 @@ func Register(
 	adminOpsHandler *adminopsadapter.Handler,
 	lockerHandler *lockeradapter.Handler,
 	pickupHandler *pickupadapter.Handler,
+	notificationHandler *notificationadapter.Handler,
+	reminderHandler *reminderadapter.Handler,
+	webhookHandler *webhookadapter.Handler,
+	auditHandler *auditadapter.Handler,
+	deviceHandler *deviceadapter.Handler,
+	incidentHandler *incidentadapter.Handler,
+	heartbeatHandler *heartbeatadapter.Handler,
+	requireAdmin fiber.Handler,
+	deviceAuth fiber.Handler,
+	requireDevice fiber.Handler,
+	limiter *ratelimitusecase.Limiter,
+	phones phone.Normalizer,
 ) {
 	api := app.Group("/api/v1")
 
-	parcelGroup := api.Group("/parcels")
-	parceladapter.RegisterRoutes(parcelGroup, parcelHandler)
+	// Kiosks share the public endpoints with browsers; deviceAuth only checks requests that carry
+	// device signature headers.
+	parcelGroup := api.Group("/parcels", deviceAuth)
+	parceladapter.RegisterRoutes(parcelGroup, parcelHandler, limiter)
 
 	lockerGroup := api.Group("/lockers")
 	lockeradapter.RegisterRoutes(lockerGroup, lockerHandler)
 
-	pickupGroup := api.Group("/pickup")
-	pickupadapter.RegisterRoutes(pickupGroup, pickupHandler)
+	pickupGroup := api.Group("/pickup", deviceAuth)
+	pickupadapter.RegisterRoutes(pickupGroup, pickupHandler, limiter, phones)
 
-	adminGroup := api.Group("/admins")
+	// Endpoints only kiosks call; every request must be signed by a device.
+	deviceGroup := api.Group("/devices", requireDevice)
+	incidentadapter.RegisterDeviceRoutes(deviceGroup, incidentHandler)
+	heartbeatadapter.RegisterDeviceRoutes(deviceGroup, heartbeatHandler)
+
+	// Auth routes are registered before the protected /admin group so login stays public.
+	authGroup := api.Group("/admin/auth")
+	adminadapter.RegisterAuthRoutes(authGroup, adminHandler, requireAdmin, limiter)
+
+	adminGroup := api.Group("/admins", requireAdmin)
 	adminadapter.RegisterRoutes(adminGroup, adminHandler)
 
-	adminOpsGroup := api.Group("/admin")
+	adminOpsGroup := api.Group("/admin", requireAdmin)
 	adminopsadapter.RegisterRoutes(adminOpsGroup, adminOpsHandler)
+	reminderadapter.RegisterRoutes(adminOpsGroup, reminderHandler)
+	auditadapter.RegisterRoutes(adminOpsGroup, auditHandler)
+	deviceadapter.RegisterRoutes(adminOpsGroup, deviceHandler, adminOpsHandler.LockerLocation)
+	incidentadapter.RegisterRoutes(adminOpsGroup, incidentHandler, adminOpsHandler.LockerLocation)
+	heartbeatadapter.RegisterRoutes(adminOpsGroup, heartbeatHandler, adminOpsHandler.LockerLocation)
+
+	notificationGroup := adminOpsGroup.Group("/notifications")
+	notificationadapter.RegisterRoutes(notificationGroup, notificationHandler)
+
+	webhookGroup := adminOpsGroup.Group("/webhooks")
+	webhookadapter.RegisterRoutes(webhookGroup, webhookHandler)
 }
@@ -0,0 +1,317 @@
+package webhook
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"smart-parcel-locker/backend/domain/webhook"
+	"smart-parcel-locker/backend/pkg/errorx"
+	"smart-parcel-locker/backend/pkg/logger"
+	"smart-parcel-locker/backend/pkg/response"
+	webhookusecase "smart-parcel-locker/backend/usecase/webhook"
+)
+
+// Handler exposes carrier webhook subscriptions and the delivery log for admins.
+type Handler struct {
+	uc *webhookusecase.UseCase
+}
+
+func NewHandler(uc *webhookusecase.UseCase) *Handler {
+	return &Handler{uc: uc}
+}
+
+type createSubscriptionRequest struct {
+	CarrierCode string   `json:"carrier_code"`
+	URL         string   `json:"url"`
+	EventTypes  []string `json:"event_types"`
+	Secret      string   `json:"secret"`
+	IsActive    *bool    `json:"is_active"`
+}
+
+type updateSubscriptionRequest struct {
+	URL        *string  `json:"url"`
+	EventTypes []string `json:"event_types"`
+	IsActive   *bool    `json:"is_active"`
+}
+
+func (h *Handler) ListSubscriptions(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	subs, err := h.uc.ListSubscriptions(c.UserContext(), c.Query("carrier_code"))
+	if err != nil {
+		logger.Error(c.Context(), "webhook subscription list failed unexpectedly", map[string]interface{}{
+			"error": err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	items := make([]map[string]interface{}, 0, len(subs))
+	for _, sub := range subs {
+		items = append(items, subscriptionToResponse(sub, false))
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: items})
+}
+
+// CreateSubscription registers an endpoint; the response is the only place the secret is shown.
+func (h *Handler) CreateSubscription(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	var req createSubscriptionRequest
+	if err := c.BodyParser(&req); err != nil {
+		logger.Warn(c.Context(), "webhook subscription create invalid body", map[string]interface{}{
+			"error": err.Error(),
+		}, requestURL)
+		return writeError(c, fiber.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+	}
+	logger.Info(c.Context(), "webhook subscription create request received", map[string]interface{}{
+		"carrierCode": req.CarrierCode,
+		"eventTypes":  strings.Join(req.EventTypes, ","),
+	}, requestURL)
+	sub, err := h.uc.CreateSubscription(c.UserContext(), webhookusecase.CreateSubscriptionInput{
+		CarrierCode: req.CarrierCode,
+		URL:         req.URL,
+		EventTypes:  req.EventTypes,
+		Secret:      req.Secret,
+		IsActive:    req.IsActive,
+	})
+	if err != nil {
+		logger.Warn(c.Context(), "webhook subscription create failed", map[string]interface{}{
+			"carrierCode": req.CarrierCode,
+			"error":       err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(response.APIResponse{Success: true, Data: subscriptionToResponse(sub, true)})
+}
+
+func (h *Handler) GetSubscription(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid id")
+	}
+	sub, err := h.uc.GetSubscription(c.UserContext(), id)
+	if err != nil {
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: subscriptionToResponse(sub, false)})
+}
+
+func (h *Handler) UpdateSubscription(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid id")
+	}
+	var req updateSubscriptionRequest
+	if err := c.BodyParser(&req); err != nil {
+		logger.Warn(c.Context(), "webhook subscription update invalid body", map[string]interface{}{
+			"error": err.Error(),
+		}, requestURL)
+		return writeError(c, fiber.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+	}
+	sub, err := h.uc.UpdateSubscription(c.UserContext(), webhookusecase.UpdateSubscriptionInput{
+		ID:         id,
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		IsActive:   req.IsActive,
+	})
+	if err != nil {
+		logger.Warn(c.Context(), "webhook subscription update failed", map[string]interface{}{
+			"subscriptionId": id.String(),
+			"error":          err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: subscriptionToResponse(sub, false)})
+}
+
+func (h *Handler) DeleteSubscription(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid id")
+	}
+	if err := h.uc.DeleteSubscription(c.UserContext(), id); err != nil {
+		logger.Warn(c.Context(), "webhook subscription delete failed", map[string]interface{}{
+			"subscriptionId": id.String(),
+			"error":          err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true})
+}
+
+// RotateSecret issues a new signing secret and returns it once.
+func (h *Handler) RotateSecret(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid id")
+	}
+	sub, err := h.uc.RotateSecret(c.UserContext(), id)
+	if err != nil {
+		logger.Warn(c.Context(), "webhook subscription rotate secret failed", map[string]interface{}{
+			"subscriptionId": id.String(),
+			"error":          err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: subscriptionToResponse(sub, true)})
+}
+
+// Ping queues a signed ping event to the subscription endpoint.
+func (h *Handler) Ping(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid id")
+	}
+	delivery, err := h.uc.Ping(c.UserContext(), id)
+	if err != nil {
+		logger.Warn(c.Context(), "webhook subscription ping failed", map[string]interface{}{
+			"subscriptionId": id.String(),
+			"error":          err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	return c.Status(fiber.StatusAccepted).JSON(response.APIResponse{Success: true, Data: deliveryToResponse(delivery, false)})
+}
+
+// ListDeliveries returns the delivery log, optionally filtered by subscription, event and status.
+func (h *Handler) ListDeliveries(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	filter := webhook.DeliveryFilter{
+		Status: webhook.DeliveryStatus(strings.ToUpper(c.Query("status"))),
+		Limit:  c.QueryInt("limit", 50),
+		Offset: c.QueryInt("offset", 0),
+	}
+	if raw := c.Query("subscription_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid subscription_id")
+		}
+		filter.SubscriptionID = &id
+	}
+	if raw := c.Query("event_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid event_id")
+		}
+		filter.EventID = &id
+	}
+	items, total, err := h.uc.ListDeliveries(c.UserContext(), filter)
+	if err != nil {
+		logger.Warn(c.Context(), "webhook delivery list failed", map[string]interface{}{
+			"status": filter.Status,
+			"error":  err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	data := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		data = append(data, deliveryToResponse(item, false))
+	}
+	return c.JSON(response.APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"items":  data,
+			"total":  total,
+			"limit":  filter.Limit,
+			"offset": filter.Offset,
+		},
+	})
+}
+
+func (h *Handler) GetDelivery(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid id")
+	}
+	delivery, err := h.uc.GetDelivery(c.UserContext(), id)
+	if err != nil {
+		return mapError(c, err)
+	}
+	return c.JSON(response.APIResponse{Success: true, Data: deliveryToResponse(delivery, true)})
+}
+
+// ReplayDelivery queues the payload again as a new delivery.
+func (h *Handler) ReplayDelivery(c *fiber.Ctx) error {
+	requestURL := c.OriginalURL()
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, "INVALID_UUID", "invalid id")
+	}
+	logger.Info(c.Context(), "webhook delivery replay request received", map[string]interface{}{
+		"deliveryId": id.String(),
+	}, requestURL)
+	delivery, err := h.uc.Replay(c.UserContext(), id)
+	if err != nil {
+		logger.Warn(c.Context(), "webhook delivery replay failed", map[string]interface{}{
+			"deliveryId": id.String(),
+			"error":      err.Error(),
+		}, requestURL)
+		return mapError(c, err)
+	}
+	return c.Status(fiber.StatusAccepted).JSON(response.APIResponse{Success: true, Data: deliveryToResponse(delivery, false)})
+}
+
+func mapError(c *fiber.Ctx, err error) error {
+	var appErr errorx.Error
+	if errors.As(err, &appErr) {
+		return writeError(c, statusFromCode(appErr.Code), appErr.Code, appErr.Message)
+	}
+	return writeError(c, fiber.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+}
+
+func statusFromCode(code string) int {
+	switch code {
+	case "INVALID_REQUEST", "INVALID_SUBSCRIPTION":
+		return fiber.StatusBadRequest
+	case "SUBSCRIPTION_NOT_FOUND", "DELIVERY_NOT_FOUND":
+		return fiber.StatusNotFound
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+func writeError(c *fiber.Ctx, status int, code, msg string) error {
+	return c.Status(status).JSON(response.Error(code, msg))
+}
+
+func subscriptionToResponse(sub *webhook.Subscription, withSecret bool) map[string]interface{} {
+	data := map[string]interface{}{
+		"id":           sub.ID,
+		"carrier_code": sub.CarrierCode,
+		"url":          sub.URL,
+		"event_types":  sub.EventTypes,
+		"is_active":    sub.IsActive,
+		"created_at":   sub.CreatedAt,
+		"updated_at":   sub.UpdatedAt,
+	}
+	if withSecret {
+		data["secret"] = sub.Secret
+	}
+	return data
+}
+
+func deliveryToResponse(d *webhook.Delivery, withPayload bool) map[string]interface{} {
+	data := map[string]interface{}{
+		"id":               d.ID,
+		"subscription_id":  d.SubscriptionID,
+		"event_id":         d.EventID,
+		"event_type":       d.EventType,
+		"status":           d.Status,
+		"attempts":         d.Attempts,
+		"next_attempt_at":  d.NextAttemptAt,
+		"last_status_code": d.LastStatusCode,
+		"last_error":       d.LastError,
+		"delivered_at":     d.DeliveredAt,
+		"replay_of":        d.ReplayOf,
+		"created_at":       d.CreatedAt,
+		"updated_at":       d.UpdatedAt,
+	}
+	if withPayload {
+		data["payload"] = string(d.Payload)
+	}
+	return data
+}
@@ -0,0 +1,26 @@
+package webhook
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"smart-parcel-locker/backend/adapter/http/middleware"
+	"smart-parcel-locker/backend/domain/admin"
+)
+
+// RegisterRoutes wires carrier webhook admin endpoints. Subscriptions span every location, so
+// they need webhooks:manage and an admin not scoped to specific locations.
+func RegisterRoutes(router fiber.Router, handler *Handler) {
+	manage := []fiber.Handler{middleware.RequirePermission(admin.PermWebhooksManage), middleware.RequireAllLocations()}
+
+	router.Get("/subscriptions", append(manage, handler.ListSubscriptions)...)
+	router.Post("/subscriptions", append(manage, handler.CreateSubscription)...)
+	router.Get("/subscriptions/:id", append(manage, handler.GetSubscription)...)
+	router.Put("/subscriptions/:id", append(manage, handler.UpdateSubscription)...)
+	router.Delete("/subscriptions/:id", append(manage, handler.DeleteSubscription)...)
+	router.Post("/subscriptions/:id/rotate-secret", append(manage, handler.RotateSecret)...)
+	router.Post("/subscriptions/:id/ping", append(manage, handler.Ping)...)
+
+	router.Get("/deliveries", append(manage, handler.ListDeliveries)...)
+	router.Get("/deliveries/:id", append(manage, handler.GetDelivery)...)
+	router.Post("/deliveries/:id/replay", append(manage, handler.ReplayDelivery)...)
+}
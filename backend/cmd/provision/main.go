@@ -0,0 +1,216 @@
+// Command provision exports and imports locker manifests through the admin API, so a rollout can
+// be kept as a versioned file. It needs an admin access token with the locations, lockers and
+// fees write permissions to import.
+//
+//	ADMIN_TOKEN=... go run ./cmd/provision -url http://localhost:8080 export -format csv > lockers.csv
+//	ADMIN_TOKEN=... go run ./cmd/provision -url http://localhost:8080 import -dry-run lockers.csv
+//	go run ./cmd/provision validate lockers.csv
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"smart-parcel-locker/backend/domain/provisioning"
+)
+
+const manifestPath = "/api/v1/admin/provisioning/manifest"
+
+func main() {
+	baseURL := flag.String("url", "http://localhost:8080", "API base URL")
+	timeout := flag.Duration("timeout", 2*time.Minute, "request timeout")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: provision [-url URL] export [-format json|csv] | import [-dry-run] FILE | validate FILE")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	log.SetFlags(0)
+
+	client := &apiClient{baseURL: strings.TrimRight(*baseURL, "/"), token: os.Getenv("ADMIN_TOKEN"), http: &http.Client{Timeout: *timeout}}
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	switch args[0] {
+	case "export":
+		fs := flag.NewFlagSet("export", flag.ExitOnError)
+		format := fs.String("format", provisioning.FormatJSON, "json or csv")
+		_ = fs.Parse(args[1:])
+		body, err := client.do(http.MethodGet, manifestPath+"?format="+*format, "", nil)
+		if err != nil {
+			log.Fatal(err)
+		}
+		_, _ = os.Stdout.Write(body)
+	case "import":
+		fs := flag.NewFlagSet("import", flag.ExitOnError)
+		dryRun := fs.Bool("dry-run", false, "only show what would change")
+		_ = fs.Parse(args[1:])
+		format, data := readManifest(fs.Args())
+		contentType := contentTypeFor(format)
+		body, err := client.do(http.MethodPost, fmt.Sprintf("%s?format=%s&dry_run=%t", manifestPath, format, *dryRun), contentType, data)
+		if err != nil {
+			log.Fatal(err)
+		}
+		printPlan(body, *dryRun)
+	case "validate":
+		format, data := readManifest(args[1:])
+		m, err := provisioning.Decode(format, data)
+		if err == nil {
+			if problems := m.Validate(); len(problems) > 0 {
+				err = &provisioning.ManifestError{Problems: problems}
+			}
+		}
+		if err != nil {
+			fatalManifest(err)
+		}
+		fmt.Printf("manifest is valid: %d location(s)\n", len(m.Locations))
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+type apiClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// apiError is the error body of the admin API; Data carries the problems of a refused manifest.
+type apiError struct {
+	ErrorCode string `json:"error_code"`
+	Error     string `json:"error"`
+	Data      struct {
+		Problems []provisioning.Problem `json:"problems"`
+	} `json:"data"`
+}
+
+func (c *apiClient) do(method, path, contentType string, body []byte) ([]byte, error) {
+	if c.token == "" {
+		return nil, errors.New("ADMIN_TOKEN is required")
+	}
+	req, err := http.NewRequest(method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr apiError
+		if json.Unmarshal(data, &apiErr) != nil || apiErr.ErrorCode == "" {
+			return nil, fmt.Errorf("%s %s: %s", method, path, resp.Status)
+		}
+		if len(apiErr.Data.Problems) > 0 {
+			fatalManifest(&provisioning.ManifestError{Problems: apiErr.Data.Problems})
+		}
+		return nil, fmt.Errorf("%s: %s", apiErr.ErrorCode, apiErr.Error)
+	}
+	return data, nil
+}
+
+// readManifest reads the single file argument and picks the format from its extension.
+func readManifest(args []string) (string, []byte) {
+	if len(args) != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	format := provisioning.FormatJSON
+	if strings.EqualFold(filepath.Ext(args[0]), ".csv") {
+		format = provisioning.FormatCSV
+	}
+	return format, data
+}
+
+func contentTypeFor(format string) string {
+	if format == provisioning.FormatCSV {
+		return "text/csv"
+	}
+	return "application/json"
+}
+
+func fatalManifest(err error) {
+	var manifestErr *provisioning.ManifestError
+	if errors.As(err, &manifestErr) {
+		for _, p := range manifestErr.Problems {
+			log.Printf("%s: %s", p.Path, p.Message)
+		}
+	}
+	log.Fatal(err)
+}
+
+func printPlan(body []byte, dryRun bool) {
+	var resp struct {
+		Data struct {
+			Applied      bool                  `json:"applied"`
+			Locations    provisioning.Counts   `json:"locations"`
+			Lockers      provisioning.Counts   `json:"lockers"`
+			Compartments provisioning.Counts   `json:"compartments"`
+			Changes      []provisioning.Change `json:"changes"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		log.Fatalf("unexpected response: %v", err)
+	}
+	for _, ch := range resp.Data.Changes {
+		mark := "+"
+		if ch.Action == provisioning.ActionUpdate {
+			mark = "~"
+		}
+		names := make([]string, 0, len(ch.Fields))
+		for name := range ch.Fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		parts := make([]string, 0, len(names))
+		for _, name := range names {
+			f := ch.Fields[name]
+			if ch.Action == provisioning.ActionCreate {
+				parts = append(parts, fmt.Sprintf("%s=%v", name, f.To))
+			} else {
+				parts = append(parts, fmt.Sprintf("%s: %v -> %v", name, f.From, f.To))
+			}
+		}
+		fmt.Printf("%s %s %s %s\n", mark, ch.EntityType, ch.Path, strings.Join(parts, ", "))
+	}
+	for _, row := range []struct {
+		name string
+		c    provisioning.Counts
+	}{{"locations", resp.Data.Locations}, {"lockers", resp.Data.Lockers}, {"compartments", resp.Data.Compartments}} {
+		fmt.Printf("%s: %d create, %d update, %d unchanged\n", row.name, row.c.Create, row.c.Update, row.c.Unchanged)
+	}
+	switch {
+	case dryRun:
+		fmt.Println("dry run: nothing was changed")
+	case resp.Data.Applied:
+		fmt.Println("applied")
+	default:
+		fmt.Println("nothing to apply")
+	}
+}
@@ -4,34 +4,69 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
+	"time"
+	_ "time/tzdata" // the runtime image ships without a zoneinfo database
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 
+	admindomain "smart-parcel-locker/backend/domain/admin"
+	hardwaredomain "smart-parcel-locker/backend/domain/hardware"
+	notificationdomain "smart-parcel-locker/backend/domain/notification"
+	pickupdomain "smart-parcel-locker/backend/domain/pickup"
+	ratelimitdomain "smart-parcel-locker/backend/domain/ratelimit"
+	reminderdomain "smart-parcel-locker/backend/domain/reminder"
+
 	"smart-parcel-locker/backend/adapter/http"
 	adminadapter "smart-parcel-locker/backend/adapter/http/admin"
 	adminopsadapter "smart-parcel-locker/backend/adapter/http/adminops"
+	auditadapter "smart-parcel-locker/backend/adapter/http/audit"
+	deviceadapter "smart-parcel-locker/backend/adapter/http/device"
+	heartbeatadapter "smart-parcel-locker/backend/adapter/http/heartbeat"
+	incidentadapter "smart-parcel-locker/backend/adapter/http/incident"
 	lockeradapter "smart-parcel-locker/backend/adapter/http/locker"
+	"smart-parcel-locker/backend/adapter/http/middleware"
+	notificationadapter "smart-parcel-locker/backend/adapter/http/notification"
 	parceladapter "smart-parcel-locker/backend/adapter/http/parcel"
 	pickupadapter "smart-parcel-locker/backend/adapter/http/pickup"
+	reminderadapter "smart-parcel-locker/backend/adapter/http/reminder"
+	webhookadapter "smart-parcel-locker/backend/adapter/http/webhook"
 	admininfra "smart-parcel-locker/backend/infrastructure/admin"
+	auditinfra "smart-parcel-locker/backend/infrastructure/audit"
 	compartmentinfra "smart-parcel-locker/backend/infrastructure/compartment"
 	"smart-parcel-locker/backend/infrastructure/database"
+	deviceinfra "smart-parcel-locker/backend/infrastructure/device"
+	hardwareinfra "smart-parcel-locker/backend/infrastructure/hardware"
 	httpserver "smart-parcel-locker/backend/infrastructure/http"
+	incidentinfra "smart-parcel-locker/backend/infrastructure/incident"
 	locationinfra "smart-parcel-locker/backend/infrastructure/location"
 	lockerinfra "smart-parcel-locker/backend/infrastructure/locker"
-	"smart-parcel-locker/backend/infrastructure/notification"
+	notificationinfra "smart-parcel-locker/backend/infrastructure/notification"
 	otpinfra "smart-parcel-locker/backend/infrastructure/otp"
 	parcelinfra "smart-parcel-locker/backend/infrastructure/parcel"
 	pickupinfra "smart-parcel-locker/backend/infrastructure/pickup"
+	ratelimitinfra "smart-parcel-locker/backend/infrastructure/ratelimit"
+	reminderinfra "smart-parcel-locker/backend/infrastructure/reminder"
+	webhookinfra "smart-parcel-locker/backend/infrastructure/webhook"
+	"smart-parcel-locker/backend/infrastructure/worker"
 	"smart-parcel-locker/backend/pkg/config"
 	"smart-parcel-locker/backend/pkg/logger"
+	"smart-parcel-locker/backend/pkg/phone"
 	adminusecase "smart-parcel-locker/backend/usecase/admin"
 	adminopsusecase "smart-parcel-locker/backend/usecase/adminops"
+	auditusecase "smart-parcel-locker/backend/usecase/audit"
+	deviceusecase "smart-parcel-locker/backend/usecase/device"
+	heartbeatusecase "smart-parcel-locker/backend/usecase/heartbeat"
+	incidentusecase "smart-parcel-locker/backend/usecase/incident"
 	lockerqueryusecase "smart-parcel-locker/backend/usecase/lockerquery"
+	notificationusecase "smart-parcel-locker/backend/usecase/notification"
 	otpusecase "smart-parcel-locker/backend/usecase/otp"
 	parcelusecase "smart-parcel-locker/backend/usecase/parcel"
 	pickupusecase "smart-parcel-locker/backend/usecase/pickup"
+	ratelimitusecase "smart-parcel-locker/backend/usecase/ratelimit"
+	reminderusecase "smart-parcel-locker/backend/usecase/reminder"
+	webhookusecase "smart-parcel-locker/backend/usecase/webhook"
 )
 
 func main() {
@@ -43,6 +78,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	phones, err := phone.NewNormalizer(cfg.Phone.DefaultCountry)
+	if err != nil {
+		logger.Error(context.Background(), "server phone config invalid", map[string]interface{}{
+			"error": err.Error(),
+		}, "")
+		os.Exit(1)
+	}
+
 	db, err := database.NewPostgres(cfg.Database)
 	if err != nil {
 		logger.Error(context.Background(), "server database init failed", map[string]interface{}{
@@ -50,15 +93,23 @@ func main() {
 		}, "")
 		os.Exit(1)
 	}
-	if err := database.Prepare(db); err != nil {
+	if err := database.Prepare(db, phones); err != nil {
 		logger.Error(context.Background(), "server database prepare failed", map[string]interface{}{
 			"error": err.Error(),
 		}, "")
 		os.Exit(1)
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	app := httpserver.NewFiberApp(cfg)
-	wireModules(app, db)
+	if err := wireModules(ctx, app, db, cfg, phones); err != nil {
+		logger.Error(context.Background(), "server wiring failed", map[string]interface{}{
+			"error": err.Error(),
+		}, "")
+		os.Exit(1)
+	}
 
 	addr := fmt.Sprintf(":%d", cfg.HTTP.Port)
 	logger.Info(context.Background(), "server starting", map[string]interface{}{
@@ -72,36 +123,349 @@ func main() {
 	}
 }
 
-func wireModules(app *fiber.App, db *gorm.DB) {
+func wireModules(ctx context.Context, app *fiber.App, db *gorm.DB, cfg *config.Config, phones phone.Normalizer) error {
 	txManager := database.NewTransactionManager(db)
 
+	// Audit log; admin mutations record into it inside their own transaction.
+	auditRepo := auditinfra.NewGormRepository(db)
+	auditRecorder := auditusecase.NewRecorder(auditRepo)
+	auditHandler := auditadapter.NewHandler(auditusecase.NewUseCase(auditRepo))
+
+	// Notifications
+	timezone, err := time.LoadLocation(cfg.Notify.Timezone)
+	if err != nil {
+		return fmt.Errorf("NOTIFY_TIMEZONE: %w", err)
+	}
+	notifyUC, err := buildNotifier(cfg.Notify, cfg.App.Env, db, auditRecorder, txManager, phones)
+	if err != nil {
+		return err
+	}
+	outboxRepo := notificationinfra.NewGormOutboxRepository(db)
+	dispatcher := notificationusecase.NewDispatcher(outboxRepo, notifyUC, auditRecorder, txManager, notificationusecase.DispatcherConfig{
+		BatchSize:   cfg.Notify.OutboxBatchSize,
+		MaxAttempts: cfg.Notify.OutboxMaxAttempts,
+		BaseBackoff: cfg.Notify.OutboxBaseBackoff,
+		MaxBackoff:  cfg.Notify.OutboxMaxBackoff,
+		Lease:       cfg.Notify.OutboxLease,
+		Phones:      phones,
+	})
+	notifyHandler := notificationadapter.NewHandler(notifyUC, dispatcher)
+	go worker.RunPeriodic(ctx, "notification_outbox", cfg.Notify.OutboxInterval, dispatcher.Run)
+
+	// Carrier webhooks
+	webhookRepo := webhookinfra.NewGormRepository(db)
+	webhookPublisher := webhookusecase.NewPublisher(webhookRepo)
+	webhookUC := webhookusecase.NewUseCase(webhookRepo, auditRecorder, txManager)
+	webhookDispatcher := webhookusecase.NewDispatcher(webhookRepo, webhookinfra.NewHTTPSender(cfg.Webhook.Timeout), webhookusecase.DispatcherConfig{
+		BatchSize:   cfg.Webhook.BatchSize,
+		MaxAttempts: cfg.Webhook.MaxAttempts,
+		BaseBackoff: cfg.Webhook.BaseBackoff,
+		MaxBackoff:  cfg.Webhook.MaxBackoff,
+		Lease:       cfg.Webhook.Lease,
+	})
+	webhookHandler := webhookadapter.NewHandler(webhookUC)
+	go worker.RunPeriodic(ctx, "carrier_webhooks", cfg.Webhook.Interval, webhookDispatcher.Run)
+
 	// Locker & parcel modules
 	lockerRepo := lockerinfra.NewGormRepository(db)
 	parcelRepo := parcelinfra.NewGormRepository(db)
 	compRepo := compartmentinfra.NewGormRepository(db)
-	parcelUC := parcelusecase.NewUseCase(parcelRepo, lockerRepo, compRepo, txManager)
+	locationRepo := locationinfra.NewGormRepository(db)
+	doors, err := buildLockController(cfg.Hardware)
+	if err != nil {
+		return err
+	}
+	parcelUC := parcelusecase.NewUseCase(parcelRepo, lockerRepo, compRepo, locationRepo, outboxRepo, webhookPublisher, doors, txManager, parcelusecase.Config{
+		StoragePeriod: cfg.Parcel.StoragePeriod,
+		PickupURL:     cfg.Parcel.PickupURL,
+		Timezone:      timezone,
+		Phones:        phones,
+	})
 	parcelHandler := parceladapter.NewHandler(parcelUC)
 
+	// Kiosk devices; signed requests identify the locker hardware that made them.
+	deviceUC := deviceusecase.NewUseCase(deviceinfra.NewGormRepository(db), lockerRepo, auditRecorder, txManager, deviceusecase.Config{
+		MaxSkew: cfg.Device.SignatureMaxSkew,
+	})
+	deviceHandler := deviceadapter.NewHandler(deviceUC)
+	deviceAuth := middleware.OptionalDevice(deviceUC)
+	go worker.RunPeriodic(ctx, "device_nonce_cleanup", cfg.Device.NonceCleanupInterval, deviceUC.PurgeNonces)
+
+	// Door events and incidents
+	incidentUC := incidentusecase.NewUseCase(incidentinfra.NewGormRepository(db), compRepo, lockerRepo, auditRecorder, txManager, incidentusecase.Config{
+		DoorLeftOpenAfter: cfg.Incident.DoorLeftOpenAfter,
+	})
+	incidentHandler := incidentadapter.NewHandler(incidentUC)
+	go worker.RunPeriodic(ctx, "door_left_open_check", cfg.Incident.CheckInterval, incidentUC.CheckDoors)
+
+	// Heartbeats; lockers whose kiosk goes silent are taken offline until it reports again.
+	heartbeatUC := heartbeatusecase.NewUseCase(lockerRepo, txManager, heartbeatusecase.Config{
+		OfflineAfter: cfg.Heartbeat.OfflineAfter,
+	})
+	heartbeatHandler := heartbeatadapter.NewHandler(heartbeatUC)
+	go worker.RunPeriodic(ctx, "locker_heartbeat_monitor", cfg.Heartbeat.CheckInterval, heartbeatUC.Monitor)
+
 	// Admin module
 	adminRepo := admininfra.NewGormRepository(db)
-	adminUC := adminusecase.NewUseCase(adminRepo)
+	mfaRoles, err := parseAdminRoles(cfg.Admin.MFARequiredRoles)
+	if err != nil {
+		return fmt.Errorf("admin mfa required roles: %w", err)
+	}
+	adminUC := adminusecase.NewUseCase(adminRepo, locationRepo, auditRecorder, txManager, adminusecase.Config{
+		SessionTTL:       cfg.Admin.SessionTTL,
+		RefreshTTL:       cfg.Admin.RefreshTTL,
+		SetupTokenTTL:    cfg.Admin.SetupTokenTTL,
+		SetupURL:         cfg.Admin.SetupURL,
+		MFARequiredRoles: mfaRoles,
+		TOTPIssuer:       cfg.Admin.TOTPIssuer,
+	})
+	if cfg.Admin.BootstrapUsername != "" {
+		if err := adminUC.Bootstrap(ctx, cfg.Admin.BootstrapUsername, cfg.Admin.BootstrapPassword); err != nil {
+			return fmt.Errorf("bootstrap admin: %w", err)
+		}
+	}
 	adminHandler := adminadapter.NewHandler(adminUC)
+	requireAdmin := middleware.RequireAdmin(adminUC)
+	go worker.RunPeriodic(ctx, "admin_session_cleanup", cfg.Admin.SessionCleanupInterval, adminUC.PurgeSessions)
 
 	// Admin operations module
-	locationRepo := locationinfra.NewGormRepository(db)
-	adminOpsUC := adminopsusecase.NewUseCase(locationRepo, lockerRepo, compRepo, parcelRepo, txManager)
+	adminOpsUC := adminopsusecase.NewUseCase(locationRepo, lockerRepo, compRepo, parcelRepo, auditRecorder, doors, txManager)
 	adminOpsHandler := adminopsadapter.NewHandler(adminOpsUC)
 
+	// Reminders
+	reminderRules, err := reminderdomain.ParseRules(cfg.Reminder.DefaultRules)
+	if err != nil {
+		return fmt.Errorf("reminder default rules: %w", err)
+	}
+	reminderUC := reminderusecase.NewUseCase(reminderinfra.NewGormRepository(db), locationRepo, outboxRepo, auditRecorder, txManager, reminderRules, timezone)
+	reminderHandler := reminderadapter.NewHandler(reminderUC)
+	go worker.RunPeriodic(ctx, "pickup_reminders", cfg.Reminder.Interval, reminderUC.Run)
+
 	// Locker query module
 	lockerQueryUC := lockerqueryusecase.NewUseCase(lockerRepo, locationRepo)
 	lockerHandler := lockeradapter.NewHandler(lockerQueryUC)
 
-	tokenStore := pickupinfra.NewTokenStore()
+	tokenStore, err := buildTokenStore(cfg.Pickup, db)
+	if err != nil {
+		return err
+	}
+	go worker.RunPeriodic(ctx, "pickup_token_cleanup", cfg.Pickup.TokenCleanupInterval, func(ctx context.Context) error {
+		removed, err := tokenStore.PurgeExpired(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+		logger.Info(ctx, "pickup token cleanup completed", map[string]interface{}{
+			"removed": removed,
+		}, "")
+		return nil
+	})
 	otpRepo := otpinfra.NewGormRepository(db)
-	otpNotifier := notification.NewDiscordNotifier()
-	otpUC := otpusecase.NewUseCase(otpRepo, otpNotifier, tokenStore, txManager)
-	pickupUC := pickupusecase.NewUseCase(parcelRepo, compRepo, tokenStore, txManager)
+	otpUC := otpusecase.NewUseCase(otpRepo, outboxRepo, tokenStore, txManager, phones)
+	pickupUC := pickupusecase.NewUseCase(parcelRepo, compRepo, lockerRepo, tokenStore, webhookPublisher, doors, txManager, pickupusecase.Config{
+		RevokeTokenWhenCollected: cfg.Pickup.RevokeTokenWhenCollected,
+		Phones:                   phones,
+	})
 	pickupHandler := pickupadapter.NewHandler(otpUC, pickupUC)
 
-	http.Register(app, parcelHandler, adminHandler, adminOpsHandler, lockerHandler, pickupHandler)
+	otpHousekeeping, err := otpusecase.NewHousekeeping(otpRepo, otpusecase.HousekeepingConfig{
+		Retention: cfg.OTP.Retention,
+		Mode:      cfg.OTP.RetentionMode,
+	})
+	if err != nil {
+		return err
+	}
+	go worker.RunPeriodic(ctx, "otp_housekeeping", cfg.OTP.HousekeepingInterval, otpHousekeeping.Run)
+
+	// Rate limiting
+	limiter, err := buildRateLimiter(cfg.RateLimit, db)
+	if err != nil {
+		return err
+	}
+	go worker.RunPeriodic(ctx, "rate_limit_purge", cfg.RateLimit.PurgeInterval, func(ctx context.Context) error {
+		return limiter.Purge(ctx, limiter.MaxWindow())
+	})
+
+	http.Register(app, parcelHandler, adminHandler, adminOpsHandler, lockerHandler, pickupHandler, notifyHandler, reminderHandler, webhookHandler, auditHandler, deviceHandler, incidentHandler, heartbeatHandler, requireAdmin, deviceAuth, middleware.RequireDevice(deviceUC), limiter, phones)
+	return nil
+}
+
+// pickupTokenStore is a token store that can also drop expired tokens.
+type pickupTokenStore interface {
+	pickupdomain.TokenStore
+	PurgeExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+func buildTokenStore(cfg config.PickupConfig, db *gorm.DB) (pickupTokenStore, error) {
+	switch cfg.TokenStore {
+	case "", "memory":
+		return pickupinfra.NewTokenStore(), nil
+	case "postgres":
+		return pickupinfra.NewGormTokenStore(db), nil
+	case "signed":
+		var denylist pickupinfra.Denylist
+		switch cfg.Denylist {
+		case "", "memory":
+			denylist = pickupinfra.NewMemoryDenylist()
+		case "postgres":
+			denylist = pickupinfra.NewGormDenylist(db)
+		default:
+			return nil, fmt.Errorf("unknown pickup token denylist %q", cfg.Denylist)
+		}
+		return pickupinfra.NewSignedTokenStore(cfg.SigningKeys, cfg.ActiveKeyID, denylist)
+	default:
+		return nil, fmt.Errorf("unknown pickup token store %q", cfg.TokenStore)
+	}
+}
+
+func buildLockController(cfg config.HardwareConfig) (hardwaredomain.LockController, error) {
+	switch cfg.Driver {
+	case "", "simulator":
+		return hardwareinfra.NewSimulator(cfg.SimulatorAutoClose), nil
+	case "tcp":
+		if cfg.Address == "" {
+			return nil, fmt.Errorf("LOCK_CONTROLLER_ADDRESS is required for the tcp lock controller")
+		}
+		return hardwareinfra.NewTCPController(cfg.Address, cfg.Timeout), nil
+	case "serial":
+		if cfg.SerialDevice == "" {
+			return nil, fmt.Errorf("LOCK_CONTROLLER_SERIAL_DEVICE is required for the serial lock controller")
+		}
+		return hardwareinfra.NewSerialController(cfg.SerialDevice, cfg.Timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown lock controller driver %q", cfg.Driver)
+	}
+}
+
+// buildNotifier enables every channel whose settings are present.
+func buildNotifier(cfg config.NotificationConfig, appEnv string, db *gorm.DB, recorder *auditusecase.Recorder, tx *database.TransactionManager, phones phone.Normalizer) (*notificationusecase.UseCase, error) {
+	senders := []notificationdomain.Sender{notificationinfra.NewLogSender()}
+	if cfg.SMSProviderURL != "" {
+		s, err := notificationinfra.NewSMSSender(notificationinfra.SMSConfig{
+			URL:        cfg.SMSProviderURL,
+			APIKey:     cfg.SMSAPIKey,
+			AuthHeader: cfg.SMSAuthHeader,
+			SenderName: cfg.SMSSenderName,
+			Timeout:    cfg.Timeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+		senders = append(senders, s)
+	}
+	if cfg.SMTPHost != "" {
+		s, err := notificationinfra.NewEmailSender(notificationinfra.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+			Timeout:  cfg.Timeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+		senders = append(senders, s)
+	}
+	if cfg.LineChannelAccessToken != "" {
+		s, err := notificationinfra.NewLineSender(notificationinfra.LineConfig{
+			ChannelAccessToken: cfg.LineChannelAccessToken,
+			Timeout:            cfg.Timeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+		senders = append(senders, s)
+	}
+	if cfg.DiscordWebhookURL != "" {
+		s, err := notificationinfra.NewDiscordSender(cfg.DiscordWebhookURL, cfg.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		senders = append(senders, s)
+	}
+
+	channel := notificationdomain.Channel(strings.ToUpper(cfg.DefaultChannel))
+	if !channel.Valid() {
+		return nil, fmt.Errorf("unknown notification channel %q", cfg.DefaultChannel)
+	}
+	// The log channel never reaches receivers, so it must not be the production default.
+	if channel == notificationdomain.ChannelLog && strings.EqualFold(appEnv, "production") {
+		return nil, fmt.Errorf("NOTIFY_DEFAULT_CHANNEL=LOG is not allowed when APP_ENV=production")
+	}
+	return notificationusecase.NewUseCase(
+		notificationinfra.NewGormPreferenceRepository(db),
+		notificationinfra.NewGormTemplateRepository(db),
+		recorder,
+		tx,
+		notificationusecase.Config{
+			DefaultChannel: channel,
+			DefaultLocale:  notificationdomain.Locale(strings.ToLower(cfg.DefaultLocale)),
+			Phones:         phones,
+		},
+		senders...,
+	)
+}
+
+func buildRateLimiter(cfg config.RateLimitConfig, db *gorm.DB) (*ratelimitusecase.Limiter, error) {
+	var store ratelimitdomain.Store
+	switch cfg.Store {
+	case "", "memory":
+		store = ratelimitinfra.NewMemoryStore()
+	case "postgres":
+		store = ratelimitinfra.NewGormStore(db)
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", cfg.Store)
+	}
+
+	specs := []struct {
+		endpoint  string
+		dimension ratelimitdomain.Dimension
+		spec      string
+	}{
+		{ratelimitusecase.EndpointOTPRequest, ratelimitdomain.DimensionPhone, cfg.OTPRequestPhone},
+		{ratelimitusecase.EndpointOTPRequest, ratelimitdomain.DimensionIP, cfg.OTPRequestIP},
+		{ratelimitusecase.EndpointOTPVerify, ratelimitdomain.DimensionPhone, cfg.OTPVerifyPhone},
+		{ratelimitusecase.EndpointOTPVerify, ratelimitdomain.DimensionIP, cfg.OTPVerifyIP},
+		{ratelimitusecase.EndpointDeposit, ratelimitdomain.DimensionIP, cfg.DepositIP},
+		{ratelimitusecase.EndpointDeposit, ratelimitdomain.DimensionLocker, cfg.DepositLocker},
+		{ratelimitusecase.EndpointTracking, ratelimitdomain.DimensionIP, cfg.TrackingIP},
+		{ratelimitusecase.EndpointAdminLogin, ratelimitdomain.DimensionIP, cfg.AdminLoginIP},
+	}
+	byEndpoint := map[string]ratelimitusecase.Policy{}
+	for _, s := range specs {
+		rule, err := ratelimitdomain.ParseRule(s.spec)
+		if err != nil {
+			return nil, fmt.Errorf("rate limit %s/%s: %w", s.endpoint, s.dimension, err)
+		}
+		policy, ok := byEndpoint[s.endpoint]
+		if !ok {
+			policy = ratelimitusecase.Policy{
+				Endpoint: s.endpoint,
+				Rules:    map[ratelimitdomain.Dimension]ratelimitdomain.Rule{},
+			}
+		}
+		policy.Rules[s.dimension] = rule
+		byEndpoint[s.endpoint] = policy
+	}
+	policies := make([]ratelimitusecase.Policy, 0, len(byEndpoint))
+	for _, p := range byEndpoint {
+		policies = append(policies, p)
+	}
+	return ratelimitusecase.NewLimiter(store, policies), nil
+}
+
+// parseAdminRoles upper-cases the configured role names and rejects unknown ones.
+func parseAdminRoles(raw []string) ([]string, error) {
+	roles := make([]string, 0, len(raw))
+	for _, r := range raw {
+		role := strings.ToUpper(strings.TrimSpace(r))
+		if role == "" {
+			continue
+		}
+		if !admindomain.ValidRole(role) {
+			return nil, fmt.Errorf("unknown role %q", r)
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
 }
@@ -0,0 +1,53 @@
+// Command webhook-receiver is a local endpoint for testing carrier webhooks. It verifies the
+// signature of every request with the subscription secret and logs the event.
+//
+//	WEBHOOK_SECRET=whsec_... go run ./cmd/webhook-receiver -addr :9090
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"smart-parcel-locker/backend/domain/webhook"
+)
+
+func main() {
+	addr := flag.String("addr", ":9090", "listen address")
+	status := flag.Int("status", http.StatusNoContent, "status returned for valid requests; use 5xx to exercise retries")
+	tolerance := flag.Duration("tolerance", 5*time.Minute, "maximum age of the signed timestamp")
+	flag.Parse()
+
+	secret := os.Getenv("WEBHOOK_SECRET")
+	if secret == "" {
+		log.Fatal("WEBHOOK_SECRET is required")
+	}
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, "read body", http.StatusBadRequest)
+			return
+		}
+		ts, err := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+		if err != nil || time.Since(time.Unix(ts, 0)).Abs() > *tolerance {
+			log.Printf("rejected delivery %s: stale or missing timestamp", r.Header.Get(webhook.HeaderDelivery))
+			http.Error(w, "invalid timestamp", http.StatusUnauthorized)
+			return
+		}
+		if !webhook.Verify(secret, ts, body, r.Header.Get(webhook.HeaderSignature)) {
+			log.Printf("rejected delivery %s: bad signature", r.Header.Get(webhook.HeaderDelivery))
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+		log.Printf("event=%s delivery=%s body=%s", r.Header.Get(webhook.HeaderEvent), r.Header.Get(webhook.HeaderDelivery), body)
+		w.WriteHeader(*status)
+	})
+
+	log.Printf("webhook receiver listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
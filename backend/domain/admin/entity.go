@@ -6,12 +6,38 @@ import (
 	"github.com/google/uuid"
 )
 
-// Admin represents a minimal administrative user.
+// Admin represents an administrative user. PasswordHash is always produced by
+// pkg/password and is empty until the admin completes the password setup flow.
+// LocationIDs limits the admin to those locations; empty means every location.
+// DisabledAt is set while the account is disabled. TOTPSecret is the base32 authenticator
+// secret; it only takes effect once TOTPEnabledAt is set by a confirmed enrolment.
+// TOTPLastStep is the last accepted time step, so a code cannot be used twice.
 type Admin struct {
-	ID           uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	Username     string    `gorm:"size:255;uniqueIndex;not null"`
-	PasswordHash string    `gorm:"size:255;not null"`
-	Role         string    `gorm:"size:255;not null"`
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	ID            uuid.UUID
+	Username      string
+	PasswordHash  string
+	Role          string
+	LocationIDs   []uuid.UUID
+	PasswordSetAt *time.Time
+	DisabledAt    *time.Time
+	TOTPSecret    string
+	TOTPEnabledAt *time.Time
+	TOTPLastStep  int64
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// HasPassword reports whether the admin has completed password setup and can log in.
+func (a *Admin) HasPassword() bool {
+	return a.PasswordHash != ""
+}
+
+// MFAEnabled reports whether login requires a TOTP or recovery code.
+func (a *Admin) MFAEnabled() bool {
+	return a.TOTPEnabledAt != nil
+}
+
+// Active reports whether the admin may log in and use open sessions.
+func (a *Admin) Active() bool {
+	return a.DisabledAt == nil
 }
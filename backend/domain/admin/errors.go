@@ -0,0 +1,30 @@
+package admin
+
+import "smart-parcel-locker/backend/pkg/errorx"
+
+var (
+	ErrInvalidRequest     = errorx.Error{Code: "INVALID_REQUEST", Message: "invalid request"}
+	ErrInvalidCredentials = errorx.Error{Code: "INVALID_CREDENTIALS", Message: "invalid username or password"}
+	ErrUnauthorized       = errorx.Error{Code: "UNAUTHORIZED", Message: "a valid admin session is required"}
+	ErrInvalidSession     = errorx.Error{Code: "INVALID_SESSION", Message: "session is invalid or expired"}
+	ErrInvalidSetupToken  = errorx.Error{Code: "INVALID_SETUP_TOKEN", Message: "setup token is invalid, used or expired"}
+	ErrWeakPassword       = errorx.Error{Code: "WEAK_PASSWORD", Message: "password must be 12 to 72 characters"}
+	ErrUsernameTaken      = errorx.Error{Code: "USERNAME_TAKEN", Message: "username is already in use"}
+	ErrForbidden          = errorx.Error{Code: "FORBIDDEN", Message: "your role does not allow this action"}
+	ErrLocationForbidden  = errorx.Error{Code: "FORBIDDEN", Message: "your role is not assigned to this location"}
+	ErrInvalidRole        = errorx.Error{Code: "INVALID_ROLE", Message: "role must be SUPER_ADMIN, OPERATOR, SUPPORT or VIEWER"}
+	ErrRoleNotScopeable   = errorx.Error{Code: "INVALID_ROLE", Message: "SUPER_ADMIN cannot be limited to locations"}
+	ErrUnknownLocation    = errorx.Error{Code: "INVALID_LOCATION", Message: "one or more location_ids do not exist"}
+	ErrLastSuperAdmin     = errorx.Error{Code: "LAST_SUPER_ADMIN", Message: "at least one active SUPER_ADMIN must remain"}
+	ErrSelfRoleChange     = errorx.Error{Code: "SELF_ROLE_CHANGE", Message: "admins cannot change their own role or delete themselves"}
+	ErrSelfAccountChange  = errorx.Error{Code: "SELF_ACCOUNT_CHANGE", Message: "admins cannot disable or reset themselves; use change password"}
+	ErrAccountDisabled    = errorx.Error{Code: "ACCOUNT_DISABLED", Message: "admin account is disabled"}
+	ErrWrongPassword      = errorx.Error{Code: "INVALID_PASSWORD", Message: "current password is incorrect"}
+	ErrMFARequired        = errorx.Error{Code: "MFA_REQUIRED", Message: "a TOTP code or recovery code is required"}
+	ErrInvalidMFACode     = errorx.Error{Code: "INVALID_MFA_CODE", Message: "TOTP or recovery code is invalid or already used"}
+	ErrMFAEnrollRequired  = errorx.Error{Code: "MFA_ENROLLMENT_REQUIRED", Message: "your role requires two-factor authentication; enrol a TOTP authenticator first"}
+	ErrMFAAlreadyEnabled  = errorx.Error{Code: "MFA_ALREADY_ENABLED", Message: "two-factor authentication is already enabled"}
+	ErrMFANotEnrolling    = errorx.Error{Code: "MFA_NOT_ENROLLING", Message: "start TOTP enrolment first"}
+	ErrMFANotEnabled      = errorx.Error{Code: "MFA_NOT_ENABLED", Message: "two-factor authentication is not enabled"}
+	ErrMFAMandatory       = errorx.Error{Code: "MFA_MANDATORY", Message: "your role requires two-factor authentication"}
+)
@@ -0,0 +1,29 @@
+package admin
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+)
+
+// RecoveryCodeCount is how many one-time recovery codes are issued at a time.
+const RecoveryCodeCount = 10
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewRecoveryCode returns a random code of the form "abcd-efgh-ijkl-mnop" (80 bits).
+func NewRecoveryCode() (string, error) {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	raw := strings.ToLower(recoveryEncoding.EncodeToString(buf))
+	return raw[0:4] + "-" + raw[4:8] + "-" + raw[8:12] + "-" + raw[12:16], nil
+}
+
+// HashRecoveryCode returns the stored hash of a recovery code. Dashes, spaces and case are
+// ignored so codes can be typed back loosely.
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+	return HashToken(normalized)
+}
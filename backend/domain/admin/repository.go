@@ -2,14 +2,72 @@ package admin
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
-// Repository defines CRUD operations for Admin.
+// ListFilter narrows an admin listing. Query matches usernames case-insensitively; Active
+// selects enabled (true) or disabled (false) admins when set.
+type ListFilter struct {
+	Query      string
+	Role       string
+	Active     *bool
+	LocationID *uuid.UUID
+	Limit      int
+	Offset     int
+}
+
+// Repository defines persistence for admins, their sessions and password setup tokens.
 type Repository interface {
 	Create(ctx context.Context, admin *Admin) (*Admin, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*Admin, error)
+	GetByUsername(ctx context.Context, username string) (*Admin, error)
+	// List returns admins ordered by username, with the total before paging.
+	List(ctx context.Context, filter ListFilter) ([]*Admin, int64, error)
+	Count(ctx context.Context) (int64, error)
+	// CountByRoleForUpdate counts the active admins holding role and locks their rows until the
+	// transaction ends, so concurrent demotions cannot both see the other admin.
+	CountByRoleForUpdate(ctx context.Context, role string) (int64, error)
 	Update(ctx context.Context, admin *Admin) (*Admin, error)
+	// SetRole replaces the role and location assignments of an admin.
+	SetRole(ctx context.Context, id uuid.UUID, role string, locationIDs []uuid.UUID) error
+	SetPasswordHash(ctx context.Context, id uuid.UUID, hash string, at time.Time) error
+	// ClearPassword removes the password so the admin can only log in after a new setup.
+	ClearPassword(ctx context.Context, id uuid.UUID, at time.Time) error
+	// SetDisabledAt disables the admin at the given time, or enables it when at is nil.
+	SetDisabledAt(ctx context.Context, id uuid.UUID, at *time.Time) error
+
+	// SetTOTPSecret stores a pending TOTP secret and turns two-factor authentication off until
+	// EnableTOTP confirms it. An empty secret removes TOTP altogether.
+	SetTOTPSecret(ctx context.Context, id uuid.UUID, secret string) error
+	EnableTOTP(ctx context.Context, id uuid.UUID, at time.Time, step int64) error
+	// UseTOTPStep records step as used, reporting false if it is not newer than the last one.
+	UseTOTPStep(ctx context.Context, id uuid.UUID, step int64) (bool, error)
+	// ReplaceRecoveryCodes swaps every recovery code of the admin for the given hashes.
+	ReplaceRecoveryCodes(ctx context.Context, adminID uuid.UUID, hashes []string, at time.Time) error
+	// UseRecoveryCode marks an unused code as used, reporting false if none matched.
+	UseRecoveryCode(ctx context.Context, adminID uuid.UUID, hash string, at time.Time) (bool, error)
+	CountRecoveryCodes(ctx context.Context, adminID uuid.UUID) (int64, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+
+	CreateSession(ctx context.Context, session *Session) error
+	GetSessionByTokenHash(ctx context.Context, hash string) (*Session, error)
+	GetSessionByRefreshHash(ctx context.Context, hash string) (*Session, error)
+	// RotateSession replaces both token hashes and expiries of a session that is not revoked.
+	// It reports false when the session was revoked or rotated concurrently.
+	RotateSession(ctx context.Context, session *Session, previousRefreshHash string) (bool, error)
+	RevokeSession(ctx context.Context, id uuid.UUID, at time.Time) error
+	RevokeSessions(ctx context.Context, adminID uuid.UUID, at time.Time) (int64, error)
+	// RevokeOtherSessions revokes every open session of the admin except keep.
+	RevokeOtherSessions(ctx context.Context, adminID, keep uuid.UUID, at time.Time) (int64, error)
+	// PurgeSessions deletes sessions whose refresh token expired before the given time.
+	PurgeSessions(ctx context.Context, before time.Time) (int64, error)
+
+	CreateSetupToken(ctx context.Context, token *SetupToken) error
+	GetSetupToken(ctx context.Context, hash string) (*SetupToken, error)
+	// UseSetupToken marks an unused token as used, reporting false if it was already used.
+	UseSetupToken(ctx context.Context, id uuid.UUID, at time.Time) (bool, error)
+	// ExpireSetupTokens marks every unused setup token of the admin as used.
+	ExpireSetupTokens(ctx context.Context, adminID uuid.UUID, at time.Time) error
 }
@@ -0,0 +1,115 @@
+package admin
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// Roles an admin can hold. RoleSuperAdmin is the only role that may manage other admins.
+const (
+	RoleSuperAdmin = "SUPER_ADMIN"
+	RoleOperator   = "OPERATOR"
+	RoleSupport    = "SUPPORT"
+	RoleViewer     = "VIEWER"
+)
+
+// Permission is a single action checked per route.
+type Permission string
+
+const (
+	// PermAdminsManage covers admin accounts and their role assignments.
+	PermAdminsManage Permission = "admins:manage"
+	// PermInventoryRead covers reading locations, lockers, compartments and the overview.
+	PermInventoryRead Permission = "inventory:read"
+	// PermLocationsWrite covers creating locations and their reminder policies.
+	PermLocationsWrite Permission = "locations:write"
+	// PermLockersWrite covers registering lockers, compartments and changing locker status.
+	PermLockersWrite Permission = "lockers:write"
+	// PermFeesWrite covers setting compartment overdue fees.
+	PermFeesWrite Permission = "fees:write"
+	// PermParcelsRead covers searching parcels, including receiver phone numbers.
+	PermParcelsRead Permission = "parcels:read"
+	// PermNotificationsRead covers channels, receiver preferences, templates and the outbox.
+	PermNotificationsRead Permission = "notifications:read"
+	// PermNotificationsWrite covers preferences, templates and outbox redrive.
+	PermNotificationsWrite Permission = "notifications:write"
+	// PermWebhooksManage covers carrier webhook subscriptions and deliveries.
+	PermWebhooksManage Permission = "webhooks:manage"
+	// PermAuditRead covers reading the admin audit log.
+	PermAuditRead Permission = "audit:read"
+)
+
+var rolePermissions = map[string][]Permission{
+	RoleSuperAdmin: {
+		PermAdminsManage, PermInventoryRead, PermLocationsWrite, PermLockersWrite, PermFeesWrite,
+		PermParcelsRead, PermNotificationsRead, PermNotificationsWrite, PermWebhooksManage,
+		PermAuditRead,
+	},
+	RoleOperator: {
+		PermInventoryRead, PermLocationsWrite, PermLockersWrite, PermFeesWrite,
+		PermParcelsRead, PermNotificationsRead, PermNotificationsWrite, PermWebhooksManage,
+	},
+	RoleSupport: {
+		PermInventoryRead, PermParcelsRead, PermNotificationsRead,
+	},
+	RoleViewer: {
+		PermInventoryRead,
+	},
+}
+
+// Roles lists every role in order of decreasing privilege.
+func Roles() []string {
+	return []string{RoleSuperAdmin, RoleOperator, RoleSupport, RoleViewer}
+}
+
+// ValidRole reports whether role is a known role.
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// PermissionsFor returns the permissions granted to role, sorted.
+func PermissionsFor(role string) []Permission {
+	perms := append([]Permission(nil), rolePermissions[role]...)
+	sort.Slice(perms, func(i, j int) bool { return perms[i] < perms[j] })
+	return perms
+}
+
+// RoleHas reports whether role grants perm.
+func RoleHas(role string, perm Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// Scopeable reports whether a role may be limited to specific locations.
+func Scopeable(role string) bool {
+	return role != RoleSuperAdmin
+}
+
+// Can reports whether the principal's role grants perm.
+func (p *Principal) Can(perm Permission) bool {
+	return RoleHas(p.Role, perm)
+}
+
+// Scoped reports whether the principal is limited to specific locations.
+func (p *Principal) Scoped() bool {
+	return len(p.LocationIDs) > 0
+}
+
+// CanAccessLocation reports whether the principal may act on the location.
+func (p *Principal) CanAccessLocation(id uuid.UUID) bool {
+	if !p.Scoped() {
+		return true
+	}
+	for _, allowed := range p.LocationIDs {
+		if allowed == id {
+			return true
+		}
+	}
+	return false
+}
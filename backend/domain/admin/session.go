@@ -0,0 +1,64 @@
+package admin
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is a logged-in admin. The access and refresh tokens are opaque random strings;
+// only their SHA-256 hashes are stored.
+type Session struct {
+	ID               uuid.UUID
+	AdminID          uuid.UUID
+	TokenHash        string
+	RefreshTokenHash string
+	ExpiresAt        time.Time
+	RefreshExpiresAt time.Time
+	IP               string
+	UserAgent        string
+	RevokedAt        *time.Time
+	CreatedAt        time.Time
+	RefreshedAt      *time.Time
+}
+
+// Active reports whether the access token of the session may be used at now.
+func (s *Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// Refreshable reports whether the refresh token of the session may be used at now.
+func (s *Session) Refreshable(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.RefreshExpiresAt)
+}
+
+// Principal is the authenticated admin attached to a request. MFAEnrollmentRequired is set when
+// the role requires two-factor authentication the admin has not enrolled yet; such a principal
+// is refused every permission-checked route.
+type Principal struct {
+	AdminID               uuid.UUID
+	Username              string
+	Role                  string
+	LocationIDs           []uuid.UUID
+	SessionID             uuid.UUID
+	MFAEnrollmentRequired bool
+}
+
+// NewToken returns a random URL-safe token with 256 bits of entropy.
+func NewToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// HashToken returns the hex SHA-256 of a session or setup token as stored in the database.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,23 @@
+package admin
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SetupToken is a single-use token that lets an admin choose their own password.
+// Only its SHA-256 hash is stored.
+type SetupToken struct {
+	ID        uuid.UUID
+	AdminID   uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// Usable reports whether the token can still set a password at now.
+func (t *SetupToken) Usable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,83 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Entity types recorded in the audit log.
+const (
+	EntityAdmin                  = "admin"
+	EntityLocation               = "location"
+	EntityLocker                 = "locker"
+	EntityReminderPolicy         = "reminder_policy"
+	EntityNotificationTemplate   = "notification_template"
+	EntityNotificationPreference = "notification_preference"
+	EntityOutboxMessage          = "outbox_message"
+	EntityWebhookSubscription    = "webhook_subscription"
+	EntityWebhookDelivery        = "webhook_delivery"
+	EntityDevice                 = "device"
+	EntityCompartment            = "compartment"
+	EntityIncident               = "incident"
+)
+
+// Entry is one admin change. Before is empty for creations and After for deletions.
+type Entry struct {
+	ID            uuid.UUID
+	ActorID       *uuid.UUID
+	ActorUsername string
+	Action        string
+	EntityType    string
+	EntityID      string
+	Before        json.RawMessage
+	After         json.RawMessage
+	IP            string
+	RequestID     string
+	CreatedAt     time.Time
+}
+
+// Change describes a mutation to record. Before and After are JSON-encoded snapshots and must
+// not contain secrets or password hashes.
+type Change struct {
+	Action     string
+	EntityType string
+	EntityID   string
+	Before     interface{}
+	After      interface{}
+}
+
+// Actor identifies who made a change and from where.
+type Actor struct {
+	AdminID   uuid.UUID
+	Username  string
+	IP        string
+	RequestID string
+}
+
+type actorKey struct{}
+
+// WithActor attaches the acting admin to a request context.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the acting admin attached by WithActor.
+func ActorFrom(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
+
+// Filter narrows an audit log query. From is inclusive and To exclusive.
+type Filter struct {
+	ActorID    *uuid.UUID
+	EntityType string
+	EntityID   string
+	Action     string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
+}
@@ -0,0 +1,7 @@
+package audit
+
+import "smart-parcel-locker/backend/pkg/errorx"
+
+var (
+	ErrInvalidFilter = errorx.Error{Code: "INVALID_REQUEST", Message: "invalid audit filter"}
+)
@@ -0,0 +1,14 @@
+package audit
+
+import "context"
+
+// Repository stores audit entries. Entries are never updated or deleted.
+type Repository interface {
+	Create(ctx context.Context, entry *Entry) error
+	List(ctx context.Context, filter Filter) ([]*Entry, int64, error)
+}
+
+// Recorder records a change, in the caller's transaction when bound to one.
+type Recorder interface {
+	Record(ctx context.Context, change Change) error
+}
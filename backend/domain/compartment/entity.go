@@ -46,8 +46,13 @@ func (c *Compartment) Occupy(parcelID uuid.UUID) error {
 	return nil
 }
 
-// Release frees the compartment back to available.
+// Release frees the compartment back to available. A compartment forced out of service with a
+// parcel inside stays out of service once the parcel is collected.
 func (c *Compartment) Release() error {
+	if c.Status == StatusOutOfService {
+		c.ParcelID = nil
+		return nil
+	}
 	if c.Status != StatusReserved && c.Status != StatusOccupied {
 		return ErrInvalidCompartmentStatus
 	}
@@ -55,3 +60,32 @@ func (c *Compartment) Release() error {
 	c.ParcelID = nil
 	return nil
 }
+
+// TakeOutOfService stops the compartment from being allocated. A compartment holding a parcel
+// is only taken out of service when force is set.
+func (c *Compartment) TakeOutOfService(force bool) error {
+	switch c.Status {
+	case StatusAvailable:
+	case StatusReserved, StatusOccupied:
+		if !force {
+			return ErrCompartmentOccupied
+		}
+	default:
+		return ErrInvalidCompartmentStatus
+	}
+	c.Status = StatusOutOfService
+	return nil
+}
+
+// ReturnToService makes an out-of-service compartment usable again. holdsParcel reports whether
+// a parcel is still waiting inside, in which case it goes back to occupied.
+func (c *Compartment) ReturnToService(holdsParcel bool) error {
+	if c.Status != StatusOutOfService {
+		return ErrInvalidCompartmentStatus
+	}
+	c.Status = StatusAvailable
+	if holdsParcel {
+		c.Status = StatusOccupied
+	}
+	return nil
+}
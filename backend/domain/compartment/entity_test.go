@@ -0,0 +1,48 @@
+package compartment
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestTakeOutOfServiceOccupiedNeedsForce(t *testing.T) {
+	c := &Compartment{Status: StatusOccupied}
+	if err := c.TakeOutOfService(false); !errors.Is(err, ErrCompartmentOccupied) {
+		t.Fatalf("expected ErrCompartmentOccupied, got %v", err)
+	}
+	if c.Status != StatusOccupied {
+		t.Fatalf("status changed without force: %s", c.Status)
+	}
+	if err := c.TakeOutOfService(true); err != nil {
+		t.Fatalf("forced take out of service: %v", err)
+	}
+	if c.Status != StatusOutOfService {
+		t.Fatalf("expected %s, got %s", StatusOutOfService, c.Status)
+	}
+}
+
+func TestReturnToServiceRestoresOccupancy(t *testing.T) {
+	c := &Compartment{Status: StatusOutOfService}
+	if err := c.ReturnToService(true); err != nil || c.Status != StatusOccupied {
+		t.Fatalf("expected OCCUPIED, got %s (%v)", c.Status, err)
+	}
+	c = &Compartment{Status: StatusOutOfService}
+	if err := c.ReturnToService(false); err != nil || c.Status != StatusAvailable {
+		t.Fatalf("expected AVAILABLE, got %s (%v)", c.Status, err)
+	}
+	if err := (&Compartment{Status: StatusAvailable}).ReturnToService(false); !errors.Is(err, ErrInvalidCompartmentStatus) {
+		t.Fatalf("expected ErrInvalidCompartmentStatus, got %v", err)
+	}
+}
+
+func TestOutOfServiceCompartmentIsNotAllocated(t *testing.T) {
+	c := &Compartment{Status: StatusOutOfService}
+	if err := c.Reserve(uuid.New()); !errors.Is(err, ErrInvalidCompartmentStatus) {
+		t.Fatalf("expected ErrInvalidCompartmentStatus, got %v", err)
+	}
+	if err := c.Release(); err != nil || c.Status != StatusOutOfService {
+		t.Fatalf("release should keep the compartment out of service, got %s (%v)", c.Status, err)
+	}
+}
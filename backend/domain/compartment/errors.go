@@ -4,4 +4,9 @@ import "smart-parcel-locker/backend/pkg/errorx"
 
 var (
 	ErrInvalidCompartmentStatus = errorx.Error{Code: "INVALID_STATUS_TRANSITION", Message: "invalid compartment status"}
+	ErrCompartmentNotFound      = errorx.Error{Code: "COMPARTMENT_NOT_FOUND", Message: "compartment not found"}
+	ErrCompartmentOccupied      = errorx.Error{Code: "COMPARTMENT_OCCUPIED", Message: "compartment holds a parcel; set force and give a reason"}
+	ErrReasonRequired           = errorx.Error{Code: "INVALID_INPUT", Message: "reason is required when forcing a status change"}
+	ErrCompartmentResize        = errorx.Error{Code: "COMPARTMENT_OCCUPIED", Message: "size cannot change while the compartment holds a parcel"}
+	ErrCompartmentNoTaken       = errorx.Error{Code: "COMPARTMENT_NO_TAKEN", Message: "compartment number is already used in this locker"}
 )
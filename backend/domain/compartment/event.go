@@ -0,0 +1,51 @@
+package compartment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Door and presence sensor events reported by a kiosk device.
+const (
+	EventDoorOpened     = "DOOR_OPENED"
+	EventDoorClosed     = "DOOR_CLOSED"
+	EventDoorForced     = "DOOR_FORCED"
+	EventSensorEmpty    = "SENSOR_EMPTY"
+	EventSensorOccupied = "SENSOR_OCCUPIED"
+)
+
+// Event is a door or sensor report for a compartment. ParcelID is the parcel the compartment
+// held when the event was received, and DeviceID the kiosk that reported it.
+type Event struct {
+	ID            uuid.UUID
+	CompartmentID uuid.UUID
+	LockerID      uuid.UUID
+	ParcelID      *uuid.UUID
+	DeviceID      *uuid.UUID
+	Type          string
+	OccurredAt    time.Time
+	CreatedAt     time.Time
+}
+
+// ValidEventType reports whether t is a known door or sensor event.
+func ValidEventType(t string) bool {
+	switch t {
+	case EventDoorOpened, EventDoorClosed, EventDoorForced, EventSensorEmpty, EventSensorOccupied:
+		return true
+	}
+	return false
+}
+
+// ExpectsParcel reports whether the sensor of a compartment in this state should see a parcel.
+// ok is false for reserved and out-of-service compartments, whose contents are not predictable.
+func (c *Compartment) ExpectsParcel() (expected bool, ok bool) {
+	switch c.Status {
+	case StatusOccupied:
+		return true, true
+	case StatusAvailable:
+		return false, true
+	default:
+		return false, false
+	}
+}
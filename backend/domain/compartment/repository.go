@@ -2,6 +2,7 @@ package compartment
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -11,8 +12,22 @@ type Repository interface {
 	CreateBulk(ctx context.Context, compartments []Compartment) (int, error)
 	FindAvailableByLockerSizesForUpdate(ctx context.Context, lockerID uuid.UUID, sizes []string) (*Compartment, error)
 	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*Compartment, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*Compartment, error)
 	Update(ctx context.Context, compartment *Compartment) (*Compartment, error)
+	// UpdateDetails saves the number, size and overdue fee, including a fee of zero.
+	UpdateDetails(ctx context.Context, compartment *Compartment) (*Compartment, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	DeleteByLocker(ctx context.Context, lockerID uuid.UUID) error
 	ListByLocker(ctx context.Context, lockerID uuid.UUID) ([]Compartment, error)
 	CountAll(ctx context.Context) (int64, error)
 	CountByStatus(ctx context.Context, status string) (int64, error)
+	CreateStatusChange(ctx context.Context, change *StatusChange) error
+	// ListStatusChanges returns the status history of a compartment, newest first.
+	ListStatusChanges(ctx context.Context, compartmentID uuid.UUID, limit int) ([]StatusChange, error)
+	CreateEvent(ctx context.Context, event *Event) error
+	// ListEvents returns the door and sensor events of a compartment, newest first.
+	ListEvents(ctx context.Context, compartmentID uuid.UUID, limit int) ([]Event, error)
+	// ListDoorsOpenSince returns, per compartment, the latest door event when it opened the door
+	// at or before the given time and no later door event closed it.
+	ListDoorsOpenSince(ctx context.Context, before time.Time) ([]Event, error)
 }
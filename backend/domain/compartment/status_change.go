@@ -0,0 +1,24 @@
+package compartment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReasonUnrecordedDoorOpen is recorded when a door opened for a deposit or pickup that then failed
+// to commit, so the contents may no longer match the parcel records.
+const ReasonUnrecordedDoorOpen = "door opened but the operation was not recorded; check the contents"
+
+// StatusChange is one administrative status change of a compartment. ActorID is nil when the
+// change was not made by an admin.
+type StatusChange struct {
+	ID            uuid.UUID
+	CompartmentID uuid.UUID
+	FromStatus    string
+	ToStatus      string
+	Reason        string
+	Forced        bool
+	ActorID       *uuid.UUID
+	CreatedAt     time.Time
+}
@@ -0,0 +1,50 @@
+package device
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Device is a kiosk registered to a locker. It signs its requests with Secret; the secret is
+// only returned when the device is provisioned or its secret is rotated.
+type Device struct {
+	ID              uuid.UUID
+	LockerID        uuid.UUID
+	Name            string
+	Secret          string
+	SecretRotatedAt *time.Time
+	LastSeenAt      *time.Time
+	RevokedAt       *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       *time.Time
+}
+
+// Active reports whether the device may still sign requests.
+func (d *Device) Active() bool {
+	return d.RevokedAt == nil
+}
+
+type deviceKey struct{}
+
+// WithDevice attaches the device that signed a request to its context.
+func WithDevice(ctx context.Context, d *Device) context.Context {
+	return context.WithValue(ctx, deviceKey{}, d)
+}
+
+// FromContext returns the device attached by WithDevice.
+func FromContext(ctx context.Context) (*Device, bool) {
+	d, ok := ctx.Value(deviceKey{}).(*Device)
+	return d, ok && d != nil
+}
+
+// IDFrom returns the id of the device attached to ctx, or nil for requests without one.
+func IDFrom(ctx context.Context) *uuid.UUID {
+	d, ok := FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	id := d.ID
+	return &id
+}
@@ -0,0 +1,15 @@
+package device
+
+import "smart-parcel-locker/backend/pkg/errorx"
+
+var (
+	ErrInvalidDevice  = errorx.Error{Code: "INVALID_DEVICE", Message: "name is required and must be at most 100 characters"}
+	ErrDeviceNotFound = errorx.Error{Code: "DEVICE_NOT_FOUND", Message: "device not found"}
+	ErrDeviceRevoked  = errorx.Error{Code: "DEVICE_REVOKED", Message: "device has been revoked"}
+	// ErrUnauthorized covers unknown and revoked devices and bad signatures alike, so a caller
+	// cannot tell which check failed.
+	ErrUnauthorized   = errorx.Error{Code: "DEVICE_UNAUTHORIZED", Message: "device signature invalid"}
+	ErrRequestExpired = errorx.Error{Code: "DEVICE_REQUEST_EXPIRED", Message: "device request timestamp outside the allowed window"}
+	ErrNonceReused    = errorx.Error{Code: "DEVICE_NONCE_REUSED", Message: "device request nonce already used"}
+	ErrLockerMismatch = errorx.Error{Code: "DEVICE_LOCKER_MISMATCH", Message: "device is not registered to this locker"}
+)
@@ -0,0 +1,24 @@
+package device
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository stores devices and the nonces of their recent requests.
+type Repository interface {
+	Create(ctx context.Context, d *Device) (*Device, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*Device, error)
+	// ListByLocker returns the devices of a locker, revoked ones included, oldest first.
+	ListByLocker(ctx context.Context, lockerID uuid.UUID) ([]*Device, error)
+	UpdateSecret(ctx context.Context, id uuid.UUID, secret string, at time.Time) error
+	Revoke(ctx context.Context, id uuid.UUID, at time.Time) error
+	TouchLastSeen(ctx context.Context, id uuid.UUID, at time.Time) error
+
+	// UseNonce records a request nonce until expiresAt, reporting false if the device already
+	// used it.
+	UseNonce(ctx context.Context, deviceID uuid.UUID, nonce string, expiresAt time.Time) (bool, error)
+	PurgeNonces(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,50 @@
+package device
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// Request headers a device sends with every signed request.
+const (
+	HeaderDeviceID  = "X-Device-Id"
+	HeaderTimestamp = "X-Device-Timestamp"
+	HeaderNonce     = "X-Device-Nonce"
+	HeaderSignature = "X-Device-Signature"
+)
+
+// Nonce length limits. Nonces are random strings chosen by the device, unique per request.
+const (
+	MinNonceLength = 16
+	MaxNonceLength = 64
+)
+
+const signaturePrefix = "sha256="
+
+// Sign returns the signature header value: HMAC-SHA256 keyed with the device secret over
+// "<timestamp>.<nonce>.<METHOD>.<path>.<body>", hex encoded and prefixed with "sha256=". path
+// is the request path including the query string.
+func Sign(secret string, timestamp int64, nonce, method, path string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write([]byte(nonce))
+	mac.Write([]byte("."))
+	mac.Write([]byte(strings.ToUpper(method)))
+	mac.Write([]byte("."))
+	mac.Write([]byte(path))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature header value in constant time.
+func Verify(secret string, timestamp int64, nonce, method, path string, body []byte, signature string) bool {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, timestamp, nonce, method, path, body)), []byte(signature))
+}
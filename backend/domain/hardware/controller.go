@@ -0,0 +1,43 @@
+package hardware
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// DoorState is the latch state a controller board reports for a compartment door.
+type DoorState string
+
+const (
+	DoorOpen    DoorState = "OPEN"
+	DoorClosed  DoorState = "CLOSED"
+	DoorUnknown DoorState = "UNKNOWN"
+)
+
+// Occupancy is what a compartment's presence sensor reports.
+type Occupancy string
+
+const (
+	OccupancyEmpty    Occupancy = "EMPTY"
+	OccupancyOccupied Occupancy = "OCCUPIED"
+	OccupancyUnknown  Occupancy = "UNKNOWN"
+)
+
+// CompartmentRef addresses one compartment door. Controllers wired to a single locker bank use
+// CompartmentNo; CompartmentID identifies the compartment in logs and the simulator.
+type CompartmentRef struct {
+	LockerID      uuid.UUID
+	CompartmentID uuid.UUID
+	CompartmentNo int
+}
+
+// LockController drives the physical locker: it unlatches compartment doors and reads their
+// door and presence sensors.
+type LockController interface {
+	// Open unlatches the door and returns once the board confirms it opened. ErrDoorOutcomeUnknown
+	// means the command was sent but not answered; callers must treat the door as possibly open.
+	Open(ctx context.Context, ref CompartmentRef) error
+	DoorState(ctx context.Context, ref CompartmentRef) (DoorState, error)
+	Occupancy(ctx context.Context, ref CompartmentRef) (Occupancy, error)
+}
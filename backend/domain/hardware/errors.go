@@ -0,0 +1,11 @@
+package hardware
+
+import "smart-parcel-locker/backend/pkg/errorx"
+
+var (
+	ErrDoorOpenFailed        = errorx.Error{Code: "DOOR_OPEN_FAILED", Message: "compartment door did not open"}
+	ErrControllerUnavailable = errorx.Error{Code: "LOCK_CONTROLLER_UNAVAILABLE", Message: "locker controller is not reachable"}
+	// ErrDoorOutcomeUnknown means the open command reached the board but no answer came back, so
+	// the door may be open.
+	ErrDoorOutcomeUnknown = errorx.Error{Code: "DOOR_OUTCOME_UNKNOWN", Message: "locker controller did not confirm whether the door opened"}
+)
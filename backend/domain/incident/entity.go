@@ -0,0 +1,46 @@
+package incident
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Incident types raised from door and sensor events.
+const (
+	TypeDoorLeftOpen   = "DOOR_LEFT_OPEN"
+	TypeDoorForced     = "DOOR_FORCED"
+	TypeSensorMismatch = "SENSOR_MISMATCH"
+)
+
+const (
+	StatusOpen     = "OPEN"
+	StatusResolved = "RESOLVED"
+)
+
+// Incident is an alert about a compartment that needs staff attention. A compartment has at
+// most one open incident of each type. ResolvedBy is nil when the incident cleared itself, e.g.
+// when a door left open was closed.
+type Incident struct {
+	ID            uuid.UUID
+	Type          string
+	Status        string
+	LockerID      uuid.UUID
+	CompartmentID uuid.UUID
+	ParcelID      *uuid.UUID
+	Detail        string
+	OpenedAt      time.Time
+	ResolvedAt    *time.Time
+	ResolvedBy    *uuid.UUID
+	Resolution    string
+}
+
+// Filter narrows an incident query. LocationIDs limits results to lockers at those locations.
+type Filter struct {
+	Status      string
+	Type        string
+	LockerID    *uuid.UUID
+	LocationIDs []uuid.UUID
+	Limit       int
+	Offset      int
+}
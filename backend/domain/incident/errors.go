@@ -0,0 +1,10 @@
+package incident
+
+import "smart-parcel-locker/backend/pkg/errorx"
+
+var (
+	ErrIncidentNotFound = errorx.Error{Code: "INCIDENT_NOT_FOUND", Message: "incident not found"}
+	ErrAlreadyResolved  = errorx.Error{Code: "INCIDENT_ALREADY_RESOLVED", Message: "incident is already resolved"}
+	ErrInvalidFilter    = errorx.Error{Code: "INVALID_REQUEST", Message: "invalid incident status or type"}
+	ErrInvalidDoorEvent = errorx.Error{Code: "INVALID_DOOR_EVENT", Message: "events need a known type, a compartment_no and at most 100 entries"}
+)
@@ -0,0 +1,20 @@
+package incident
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository stores incidents.
+type Repository interface {
+	// Open records inc unless its compartment already has an open incident of the same type,
+	// reporting whether it was recorded.
+	Open(ctx context.Context, inc *Incident) (bool, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*Incident, error)
+	List(ctx context.Context, filter Filter) ([]*Incident, int64, error)
+	Resolve(ctx context.Context, id uuid.UUID, at time.Time, by *uuid.UUID, resolution string) error
+	// ResolveOpen resolves the open incident of a type on a compartment, if there is one.
+	ResolveOpen(ctx context.Context, compartmentID uuid.UUID, incidentType string, at time.Time, resolution string) (int64, error)
+}
@@ -8,11 +8,12 @@ import (
 
 // Location represents a locker location.
 type Location struct {
-	ID        uuid.UUID
-	Code      string
-	Name      string
-	Address   *string
-	IsActive  bool
-	CreatedAt time.Time
-	UpdatedAt *time.Time
+	ID            uuid.UUID
+	Code          string
+	Name          string
+	Address       *string
+	IsActive      bool
+	DefaultLocale string // notification locale for receivers without a preference; empty uses the system default
+	CreatedAt     time.Time
+	UpdatedAt     *time.Time
 }
@@ -0,0 +1,7 @@
+package location
+
+import "smart-parcel-locker/backend/pkg/errorx"
+
+var (
+	ErrLocationCodeTaken = errorx.Error{Code: "LOCATION_CODE_TAKEN", Message: "location code is already in use"}
+)
@@ -10,6 +10,9 @@ import (
 type Repository interface {
 	Create(ctx context.Context, loc *Location) (*Location, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*Location, error)
+	GetByCode(ctx context.Context, code string) (*Location, error)
+	Update(ctx context.Context, loc *Location) (*Location, error)
+	Delete(ctx context.Context, id uuid.UUID) error
 	List(ctx context.Context) ([]Location, error)
 	Count(ctx context.Context) (int64, error)
 }
@@ -25,6 +25,9 @@ const (
 	StatusActive      = "ACTIVE"
 	StatusMaintenance = "MAINTENANCE"
 	StatusDisabled    = "DISABLED"
+	// StatusOffline is set by the heartbeat monitor, never by admins, and cleared when
+	// heartbeats resume.
+	StatusOffline = "OFFLINE"
 )
 
 // LockerService encapsulates locker business rules using receiver methods.
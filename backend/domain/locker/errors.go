@@ -3,9 +3,17 @@ package locker
 import "smart-parcel-locker/backend/pkg/errorx"
 
 var (
-	ErrNoAvailableSlot        = errorx.Error{Code: "NO_AVAILABLE_COMPARTMENT", Message: "no available compartment"}
-	ErrInvalidDeposit         = errorx.Error{Code: "INVALID_INPUT", Message: "invalid deposit"}
-	ErrParcelNotFound         = errorx.Error{Code: "PARCEL_NOT_FOUND", Message: "parcel not found in locker"}
-	ErrLockerInactive         = errorx.Error{Code: "LOCKER_INACTIVE", Message: "locker is not active"}
-	ErrCompartmentInvalidSize = errorx.Error{Code: "INVALID_INPUT", Message: "invalid compartment size"}
+	ErrNoAvailableSlot         = errorx.Error{Code: "NO_AVAILABLE_COMPARTMENT", Message: "no available compartment"}
+	ErrInvalidDeposit          = errorx.Error{Code: "INVALID_INPUT", Message: "invalid deposit"}
+	ErrParcelNotFound          = errorx.Error{Code: "PARCEL_NOT_FOUND", Message: "parcel not found in locker"}
+	ErrLockerInactive          = errorx.Error{Code: "LOCKER_INACTIVE", Message: "locker is not active"}
+	ErrCompartmentInvalidSize  = errorx.Error{Code: "INVALID_INPUT", Message: "invalid compartment size"}
+	ErrLockerCodeTaken         = errorx.Error{Code: "LOCKER_CODE_TAKEN", Message: "locker code is already in use"}
+	ErrLockerOffline           = errorx.Error{Code: "LOCKER_OFFLINE", Message: "locker is offline"}
+	ErrLockerDisabled          = errorx.Error{Code: "LOCKER_DISABLED", Message: "locker is disabled"}
+	ErrInvalidLockerStatus     = errorx.Error{Code: "INVALID_INPUT", Message: "status must be ACTIVE, MAINTENANCE or DISABLED"}
+	ErrInvalidLockerTransition = errorx.Error{Code: "INVALID_STATUS_TRANSITION", Message: "locker cannot move to this status from its current status"}
+	ErrStatusReasonRequired    = errorx.Error{Code: "INVALID_INPUT", Message: "reason is required to change locker status"}
+	ErrLockerHasParcels        = errorx.Error{Code: "LOCKER_HAS_PARCELS", Message: "locker still holds parcels; set force to disable it anyway"}
+	ErrInvalidHeartbeat        = errorx.Error{Code: "INVALID_HEARTBEAT", Message: "controller_health must be OK, DEGRADED or FAULT and firmware_version at most 50 characters"}
 )
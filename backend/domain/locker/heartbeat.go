@@ -0,0 +1,24 @@
+package locker
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Door controller health reported with each heartbeat.
+const (
+	ControllerOK       = "OK"
+	ControllerDegraded = "DEGRADED"
+	ControllerFault    = "FAULT"
+)
+
+// Heartbeat is the latest sign of life from a locker's kiosk.
+type Heartbeat struct {
+	LockerID         uuid.UUID
+	DeviceID         *uuid.UUID
+	FirmwareVersion  string
+	TemperatureC     *float64
+	ControllerHealth string
+	ReceivedAt       time.Time
+}
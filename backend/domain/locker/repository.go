@@ -2,6 +2,7 @@ package locker
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -14,7 +15,31 @@ type Repository interface {
 	UpdateCompartment(ctx context.Context, c *compartment.Compartment) (*compartment.Compartment, error)
 	Create(ctx context.Context, locker *Locker) (*Locker, error)
 	GetByID(ctx context.Context, lockerID uuid.UUID) (*Locker, error)
+	// GetByIDForShare loads a locker and keeps its status from changing until the transaction
+	// ends. Deposits and pickups use it so a status change waits for them to commit.
+	GetByIDForShare(ctx context.Context, lockerID uuid.UUID) (*Locker, error)
+	// GetByIDForUpdate loads a locker and blocks deposits and pickups into it until the
+	// transaction ends, so its active parcels can be counted reliably.
+	GetByIDForUpdate(ctx context.Context, lockerID uuid.UUID) (*Locker, error)
+	GetByCode(ctx context.Context, lockerCode string) (*Locker, error)
 	List(ctx context.Context) ([]Locker, error)
+	ListByLocation(ctx context.Context, locationID uuid.UUID) ([]Locker, error)
+	// Update saves the locker code and name.
+	Update(ctx context.Context, locker *Locker) (*Locker, error)
+	Delete(ctx context.Context, lockerID uuid.UUID) error
 	UpdateStatus(ctx context.Context, lockerID uuid.UUID, status string) (*Locker, error)
 	Count(ctx context.Context) (int64, error)
+	// TransitionStatus changes the status only while it is still from, reporting whether it did.
+	TransitionStatus(ctx context.Context, lockerID uuid.UUID, from, to string) (bool, error)
+	CreateStatusChange(ctx context.Context, change *StatusChange) error
+	// ListStatusChanges returns the status history of a locker, newest first.
+	ListStatusChanges(ctx context.Context, lockerID uuid.UUID, limit int) ([]StatusChange, error)
+
+	// SaveHeartbeat replaces the latest heartbeat of a locker.
+	SaveHeartbeat(ctx context.Context, hb *Heartbeat) error
+	// GetHeartbeat returns the latest heartbeat of a locker, or nil if it never sent one.
+	GetHeartbeat(ctx context.Context, lockerID uuid.UUID) (*Heartbeat, error)
+	// ListSilentSince returns ACTIVE lockers whose latest heartbeat is older than before.
+	// Lockers that never sent a heartbeat are not monitored.
+	ListSilentSince(ctx context.Context, before time.Time) ([]Locker, error)
 }
@@ -0,0 +1,83 @@
+package locker
+
+import "strings"
+
+// adminTransitions lists where an admin may move a locker from each status. OFFLINE is only
+// entered and left by the heartbeat monitor, see monitorTransitions; an admin can still take an
+// offline locker into maintenance or disable it.
+var adminTransitions = map[string][]string{
+	StatusActive:      {StatusMaintenance, StatusDisabled},
+	StatusMaintenance: {StatusActive, StatusDisabled},
+	StatusDisabled:    {StatusActive, StatusMaintenance},
+	StatusOffline:     {StatusMaintenance, StatusDisabled},
+}
+
+var monitorTransitions = map[string]string{
+	StatusActive:  StatusOffline,
+	StatusOffline: StatusActive,
+}
+
+// StatusChangeRequest is an admin's request to move a locker to another status.
+// ActiveParcels is the number of parcels still waiting in the locker.
+type StatusChangeRequest struct {
+	To            string
+	Reason        string
+	Force         bool
+	ActiveParcels int64
+}
+
+// ChangeStatus applies an admin status change. Every change needs a reason. A locker holding
+// parcels is only disabled when forced, since its parcels can no longer be picked up.
+func (l *Locker) ChangeStatus(req StatusChangeRequest) error {
+	if _, ok := adminTransitions[req.To]; !ok {
+		return ErrInvalidLockerStatus
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return ErrStatusReasonRequired
+	}
+	if !contains(adminTransitions[l.Status], req.To) {
+		return ErrInvalidLockerTransition
+	}
+	if req.To == StatusDisabled && req.ActiveParcels > 0 && !req.Force {
+		return ErrLockerHasParcels
+	}
+	l.Status = req.To
+	return nil
+}
+
+// CanMonitorTransition reports whether the heartbeat monitor may move a locker from one status
+// to another.
+func CanMonitorTransition(from, to string) bool {
+	return monitorTransitions[from] == to
+}
+
+// CheckDeposit reports why the locker cannot take a new parcel, if it cannot. Only ACTIVE
+// lockers accept deposits; MAINTENANCE drains the locker.
+func (l *Locker) CheckDeposit() error {
+	switch l.Status {
+	case StatusActive:
+		return nil
+	case StatusOffline:
+		return ErrLockerOffline
+	default:
+		return ErrLockerInactive
+	}
+}
+
+// CheckPickup reports why parcels cannot be collected from the locker, if they cannot. Pickups
+// continue in MAINTENANCE and while the kiosk is OFFLINE; only DISABLED stops them.
+func (l *Locker) CheckPickup() error {
+	if l.Status == StatusDisabled {
+		return ErrLockerDisabled
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,19 @@
+package locker
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatusChange is one status change of a locker. ActorID is nil for changes made by the
+// heartbeat monitor.
+type StatusChange struct {
+	ID         uuid.UUID
+	LockerID   uuid.UUID
+	FromStatus string
+	ToStatus   string
+	Reason     string
+	ActorID    *uuid.UUID
+	CreatedAt  time.Time
+}
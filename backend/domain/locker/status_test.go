@@ -0,0 +1,65 @@
+package locker
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestChangeStatusFollowsTransitions(t *testing.T) {
+	l := &Locker{Status: StatusOffline}
+	if err := l.ChangeStatus(StatusChangeRequest{To: StatusActive, Reason: "back"}); !errors.Is(err, ErrInvalidLockerTransition) {
+		t.Fatalf("expected ErrInvalidLockerTransition, got %v", err)
+	}
+	if err := l.ChangeStatus(StatusChangeRequest{To: StatusOffline, Reason: "down"}); !errors.Is(err, ErrInvalidLockerTransition) {
+		t.Fatalf("admin moved a locker to OFFLINE: %v", err)
+	}
+	if err := l.ChangeStatus(StatusChangeRequest{To: "BROKEN", Reason: "x"}); !errors.Is(err, ErrInvalidLockerStatus) {
+		t.Fatalf("expected ErrInvalidLockerStatus, got %v", err)
+	}
+	if err := l.ChangeStatus(StatusChangeRequest{To: StatusMaintenance, Reason: " "}); !errors.Is(err, ErrStatusReasonRequired) {
+		t.Fatalf("expected ErrStatusReasonRequired, got %v", err)
+	}
+	if l.Status != StatusOffline {
+		t.Fatalf("refused change moved the locker to %s", l.Status)
+	}
+	if err := l.ChangeStatus(StatusChangeRequest{To: StatusMaintenance, Reason: "technician on site"}); err != nil || l.Status != StatusMaintenance {
+		t.Fatalf("expected MAINTENANCE, got %s (%v)", l.Status, err)
+	}
+}
+
+func TestDisableWithParcelsNeedsForce(t *testing.T) {
+	l := &Locker{Status: StatusMaintenance}
+	req := StatusChangeRequest{To: StatusDisabled, Reason: "decommissioned", ActiveParcels: 2}
+	if err := l.ChangeStatus(req); !errors.Is(err, ErrLockerHasParcels) {
+		t.Fatalf("expected ErrLockerHasParcels, got %v", err)
+	}
+	req.Force = true
+	if err := l.ChangeStatus(req); err != nil || l.Status != StatusDisabled {
+		t.Fatalf("expected forced DISABLED, got %s (%v)", l.Status, err)
+	}
+}
+
+func TestMaintenanceDrainsLocker(t *testing.T) {
+	l := &Locker{Status: StatusMaintenance}
+	if err := l.CheckDeposit(); !errors.Is(err, ErrLockerInactive) {
+		t.Fatalf("expected deposits refused in maintenance, got %v", err)
+	}
+	if err := l.CheckPickup(); err != nil {
+		t.Fatalf("expected pickups allowed in maintenance, got %v", err)
+	}
+	if err := (&Locker{Status: StatusOffline}).CheckDeposit(); !errors.Is(err, ErrLockerOffline) {
+		t.Fatalf("expected ErrLockerOffline, got %v", err)
+	}
+	if err := (&Locker{Status: StatusDisabled}).CheckPickup(); !errors.Is(err, ErrLockerDisabled) {
+		t.Fatalf("expected ErrLockerDisabled, got %v", err)
+	}
+}
+
+func TestMonitorOnlyTogglesOffline(t *testing.T) {
+	if !CanMonitorTransition(StatusActive, StatusOffline) || !CanMonitorTransition(StatusOffline, StatusActive) {
+		t.Fatalf("monitor cannot toggle ACTIVE and OFFLINE")
+	}
+	if CanMonitorTransition(StatusMaintenance, StatusOffline) || CanMonitorTransition(StatusOffline, StatusDisabled) {
+		t.Fatalf("monitor may only toggle ACTIVE and OFFLINE")
+	}
+}
@@ -0,0 +1,110 @@
+package notification
+
+// MessageTypes lists every message type that has a template.
+func MessageTypes() []string {
+	return []string{TypeOTP, TypeParcelDeposited, TypeParcelReminder, TypeParcelExpired, TypeParcelRelocated}
+}
+
+// DefaultTemplate returns the built-in template used until an admin edits it.
+func DefaultTemplate(messageType string, locale Locale) (Template, bool) {
+	byLocale, ok := defaultTemplates[messageType]
+	if !ok {
+		return Template{}, false
+	}
+	t, ok := byLocale[locale]
+	if !ok {
+		return Template{}, false
+	}
+	t.Type = messageType
+	t.Locale = locale
+	return t, true
+}
+
+// SampleData returns example fields for previewing a message type.
+func SampleData(messageType string) map[string]string {
+	base := map[string]string{
+		"parcel_code":   "PR-0123456789",
+		"locker_name":   "Central Lobby A",
+		"location_name": "Central World",
+		"size":          "M",
+		"pickup_code":   "PU-482913",
+		"deadline":      "2025-01-12 18:00 +07",
+		"pickup_link":   "https://locker.example.com/pickup?locker_id=00000000-0000-0000-0000-000000000000",
+	}
+	switch messageType {
+	case TypeOTP:
+		return map[string]string{"otp_code": "123456", "ttl_minutes": "5"}
+	case TypeParcelReminder:
+		base["fees_from"] = "2025-01-10 18:00 +07"
+	case TypeParcelRelocated:
+		base["previous_locker_name"] = "Central Lobby B"
+	}
+	return base
+}
+
+var defaultTemplates = map[string]map[Locale]Template{
+	TypeOTP: {
+		LocaleEN: {
+			Subject: "Your pickup code",
+			Body:    "Your pickup OTP is {{.otp_code}}. It expires in {{.ttl_minutes}} minutes.",
+		},
+		LocaleTH: {
+			Subject: "รหัสรับพัสดุของคุณ",
+			Body:    "รหัส OTP สำหรับรับพัสดุคือ {{.otp_code}} หมดอายุใน {{.ttl_minutes}} นาที",
+		},
+	},
+	TypeParcelDeposited: {
+		LocaleEN: {
+			Subject: "Your parcel has arrived",
+			Body: "Your parcel {{.parcel_code}} is ready for pickup at {{.locker_name}}{{if .location_name}}, {{.location_name}}{{end}} (size {{.size}})." +
+				"\nPickup code: {{.pickup_code}}" +
+				"{{if .deadline}}\nPlease collect it by {{.deadline}}.{{end}}" +
+				"{{if .pickup_link}}\nPickup: {{.pickup_link}}{{end}}",
+		},
+		LocaleTH: {
+			Subject: "พัสดุของคุณมาถึงแล้ว",
+			Body: "พัสดุ {{.parcel_code}} พร้อมให้รับที่ {{.locker_name}}{{if .location_name}} {{.location_name}}{{end}} (ขนาด {{.size}})" +
+				"\nรหัสรับพัสดุ: {{.pickup_code}}" +
+				"{{if .deadline}}\nกรุณารับภายใน {{.deadline}}{{end}}" +
+				"{{if .pickup_link}}\nรับพัสดุ: {{.pickup_link}}{{end}}",
+		},
+	},
+	TypeParcelReminder: {
+		LocaleEN: {
+			Subject: "Reminder: your parcel is waiting",
+			Body: "Your parcel {{.parcel_code}} is still waiting at {{.locker_name}}." +
+				"\nPickup code: {{.pickup_code}}" +
+				"{{if .fees_from}}\nOverdue fees apply from {{.fees_from}}.{{end}}" +
+				"{{if .deadline}}\nPlease collect it by {{.deadline}}.{{end}}",
+		},
+		LocaleTH: {
+			Subject: "แจ้งเตือน: พัสดุของคุณรอการรับอยู่",
+			Body: "พัสดุ {{.parcel_code}} ยังรอให้รับที่ {{.locker_name}}" +
+				"\nรหัสรับพัสดุ: {{.pickup_code}}" +
+				"{{if .fees_from}}\nเริ่มคิดค่าฝากเกินกำหนดตั้งแต่ {{.fees_from}}{{end}}" +
+				"{{if .deadline}}\nกรุณารับภายใน {{.deadline}}{{end}}",
+		},
+	},
+	TypeParcelExpired: {
+		LocaleEN: {
+			Subject: "Your parcel has expired",
+			Body:    "Your parcel {{.parcel_code}} at {{.locker_name}} was not collected by {{.deadline}} and has expired. Please contact support.",
+		},
+		LocaleTH: {
+			Subject: "พัสดุของคุณหมดเวลารับแล้ว",
+			Body:    "พัสดุ {{.parcel_code}} ที่ {{.locker_name}} ไม่ได้รับภายใน {{.deadline}} และหมดเวลารับแล้ว กรุณาติดต่อฝ่ายบริการ",
+		},
+	},
+	TypeParcelRelocated: {
+		LocaleEN: {
+			Subject: "Your parcel has moved",
+			Body: "Your parcel {{.parcel_code}} has moved from {{.previous_locker_name}} to {{.locker_name}}{{if .location_name}}, {{.location_name}}{{end}}." +
+				"\nPickup code: {{.pickup_code}}",
+		},
+		LocaleTH: {
+			Subject: "พัสดุของคุณถูกย้ายตำแหน่ง",
+			Body: "พัสดุ {{.parcel_code}} ถูกย้ายจาก {{.previous_locker_name}} ไปที่ {{.locker_name}}{{if .location_name}} {{.location_name}}{{end}}" +
+				"\nรหัสรับพัสดุ: {{.pickup_code}}",
+		},
+	},
+}
@@ -0,0 +1,65 @@
+package notification
+
+import "time"
+
+// Channel identifies a delivery mechanism.
+type Channel string
+
+const (
+	ChannelSMS     Channel = "SMS"
+	ChannelEmail   Channel = "EMAIL"
+	ChannelLine    Channel = "LINE"
+	ChannelDiscord Channel = "DISCORD"
+	ChannelLog     Channel = "LOG"
+)
+
+// Valid reports whether the channel is a known value.
+func (c Channel) Valid() bool {
+	switch c {
+	case ChannelSMS, ChannelEmail, ChannelLine, ChannelDiscord, ChannelLog:
+		return true
+	default:
+		return false
+	}
+}
+
+// Message types sent by the system.
+const (
+	TypeOTP             = "OTP"
+	TypeParcelDeposited = "PARCEL_DEPOSITED"
+	TypeParcelReminder  = "PARCEL_REMINDER"
+	TypeParcelExpired   = "PARCEL_EXPIRED"
+	TypeParcelRelocated = "PARCEL_RELOCATED"
+)
+
+// Message is a notification addressed to a receiver phone. The channel and locale are
+// resolved at send time; Data fills the template for Type. Subject and Body are used
+// as-is when no Data is given.
+type Message struct {
+	Type  string
+	Phone string
+	// Locale is the fallback when the receiver has no preferred locale, e.g. the location default.
+	Locale  Locale
+	Data    map[string]string
+	Subject string
+	Body    string
+}
+
+// Delivery is a message bound to a concrete channel and address.
+type Delivery struct {
+	Channel     Channel
+	Address     string
+	MessageType string
+	Subject     string
+	Body        string
+}
+
+// Preference records how a receiver wants to be contacted.
+type Preference struct {
+	Phone     string
+	Channel   Channel
+	Address   string // e-mail address or LINE user ID; unused for SMS
+	Locale    Locale // empty uses the location or system default
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+}
@@ -0,0 +1,12 @@
+package notification
+
+import "smart-parcel-locker/backend/pkg/errorx"
+
+var (
+	ErrInvalidRequest     = errorx.Error{Code: "INVALID_REQUEST", Message: "invalid request"}
+	ErrChannelUnavailable = errorx.Error{Code: "CHANNEL_UNAVAILABLE", Message: "notification channel is not configured"}
+	ErrOutboxNotFound     = errorx.Error{Code: "NOT_FOUND", Message: "outbox message not found"}
+	ErrTemplateNotFound   = errorx.Error{Code: "TEMPLATE_NOT_FOUND", Message: "no template for message type and locale"}
+	ErrInvalidTemplate    = errorx.Error{Code: "INVALID_TEMPLATE", Message: "template does not parse or render"}
+	ErrNotRedrivable      = errorx.Error{Code: "INVALID_STATUS_TRANSITION", Message: "only DEAD messages without a one-time password can be re-driven"}
+)
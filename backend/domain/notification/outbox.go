@@ -0,0 +1,90 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxStatus is the delivery state of an outbox message.
+type OutboxStatus string
+
+const (
+	OutboxPending OutboxStatus = "PENDING"
+	OutboxSent    OutboxStatus = "SENT"
+	OutboxDead    OutboxStatus = "DEAD"
+)
+
+// OutboxMessage is a notification written in the same transaction as the business
+// change that produced it and delivered later by the dispatcher.
+type OutboxMessage struct {
+	ID            uuid.UUID
+	Type          string
+	Phone         string
+	Locale        Locale
+	Data          map[string]string
+	Subject       string
+	Body          string
+	Status        OutboxStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	// ExpiresAt drops messages that are useless once late, such as OTP codes.
+	ExpiresAt *time.Time
+	LastError *string
+	Channel   *Channel
+	SentAt    *time.Time
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+}
+
+// NewOutboxMessage prepares msg for immediate delivery.
+func NewOutboxMessage(msg Message, now time.Time) *OutboxMessage {
+	return &OutboxMessage{
+		ID:            uuid.New(),
+		Type:          msg.Type,
+		Phone:         msg.Phone,
+		Locale:        msg.Locale,
+		Data:          msg.Data,
+		Subject:       msg.Subject,
+		Body:          msg.Body,
+		Status:        OutboxPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
+
+// Message returns the notification carried by the outbox entry.
+func (m *OutboxMessage) Message() Message {
+	return Message{Type: m.Type, Phone: m.Phone, Locale: m.Locale, Data: m.Data, Subject: m.Subject, Body: m.Body}
+}
+
+// Sensitive reports whether the data or body holds a secret that must not be shown, kept once the
+// message is sent or dead-lettered, or sent again.
+func (m *OutboxMessage) Sensitive() bool {
+	return m.Type == TypeOTP
+}
+
+// OutboxFilter narrows outbox listings.
+type OutboxFilter struct {
+	Status OutboxStatus
+	Phone  string
+	Limit  int
+	Offset int
+}
+
+// OutboxRepository stores pending notifications.
+type OutboxRepository interface {
+	Enqueue(ctx context.Context, msg *OutboxMessage) error
+	// ClaimDue leases up to limit due PENDING messages by pushing their next attempt to leaseUntil,
+	// so concurrent dispatchers never pick the same message.
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*OutboxMessage, error)
+	// MarkSent and MarkDead clear the body and data when redact is set.
+	MarkSent(ctx context.Context, id uuid.UUID, channel Channel, at time.Time, redact bool) error
+	MarkFailed(ctx context.Context, id uuid.UUID, attempts int, nextAttemptAt time.Time, lastError string) error
+	MarkDead(ctx context.Context, id uuid.UUID, attempts int, lastError string, redact bool) error
+	GetByID(ctx context.Context, id uuid.UUID) (*OutboxMessage, error)
+	List(ctx context.Context, filter OutboxFilter) ([]*OutboxMessage, int64, error)
+	// Redrive resets a DEAD message to PENDING for immediate delivery.
+	Redrive(ctx context.Context, id uuid.UUID, now time.Time) (*OutboxMessage, error)
+}
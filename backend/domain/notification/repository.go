@@ -0,0 +1,9 @@
+package notification
+
+import "context"
+
+// PreferenceRepository provides receiver channel preferences.
+type PreferenceRepository interface {
+	GetByPhone(ctx context.Context, phone string) (*Preference, error)
+	Upsert(ctx context.Context, pref *Preference) (*Preference, error)
+}
@@ -0,0 +1,9 @@
+package notification
+
+import "context"
+
+// Sender delivers messages over one channel.
+type Sender interface {
+	Channel() Channel
+	Send(ctx context.Context, delivery Delivery) error
+}
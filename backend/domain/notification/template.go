@@ -0,0 +1,116 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Locale selects the language of a notification.
+type Locale string
+
+const (
+	LocaleTH Locale = "th"
+	LocaleEN Locale = "en"
+)
+
+// Valid reports whether the locale has templates.
+func (l Locale) Valid() bool {
+	return l == LocaleTH || l == LocaleEN
+}
+
+// ParseLocale normalises "TH"/"th-TH" style input; it returns "" for unknown values.
+func ParseLocale(raw string) Locale {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if i := strings.IndexAny(raw, "-_"); i > 0 {
+		raw = raw[:i]
+	}
+	if l := Locale(raw); l.Valid() {
+		return l
+	}
+	return ""
+}
+
+// Locales lists every supported locale.
+func Locales() []Locale {
+	return []Locale{LocaleTH, LocaleEN}
+}
+
+// TimeLayout formats timestamps placed in message data.
+const TimeLayout = "2006-01-02 15:04 MST"
+
+// FormatTime formats t for message data in loc, the timezone receivers read times in. Times come
+// from the database in UTC, so callers pass the configured timezone; nil keeps t's own zone.
+func FormatTime(t time.Time, loc *time.Location) string {
+	if loc != nil {
+		t = t.In(loc)
+	}
+	return t.Format(TimeLayout)
+}
+
+// Template is the subject and body for one message type in one locale. Both use Go
+// text/template syntax with message data fields, e.g. {{.pickup_code}}.
+type Template struct {
+	Type      string
+	Locale    Locale
+	Subject   string
+	Body      string
+	UpdatedAt *time.Time
+}
+
+// Rendered is a template applied to message data.
+type Rendered struct {
+	Subject string
+	Body    string
+}
+
+// Validate parses the template without rendering it.
+func (t Template) Validate() error {
+	if _, err := parseText(t.Subject); err != nil {
+		return err
+	}
+	_, err := parseText(t.Body)
+	return err
+}
+
+// Render applies data to the template; missing fields render as empty text.
+func (t Template) Render(data map[string]string) (Rendered, error) {
+	subject, err := renderText(t.Subject, data)
+	if err != nil {
+		return Rendered{}, err
+	}
+	body, err := renderText(t.Body, data)
+	if err != nil {
+		return Rendered{}, err
+	}
+	return Rendered{Subject: subject, Body: body}, nil
+}
+
+func parseText(text string) (*template.Template, error) {
+	return template.New("notification").Option("missingkey=zero").Parse(text)
+}
+
+func renderText(text string, data map[string]string) (string, error) {
+	tmpl, err := parseText(text)
+	if err != nil {
+		return "", err
+	}
+	if data == nil {
+		data = map[string]string{}
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// TemplateRepository stores admin-edited templates; built-in defaults fill the gaps.
+type TemplateRepository interface {
+	Get(ctx context.Context, messageType string, locale Locale) (*Template, error)
+	List(ctx context.Context) ([]Template, error)
+	Upsert(ctx context.Context, tmpl *Template) (*Template, error)
+	Delete(ctx context.Context, messageType string, locale Locale) error
+}
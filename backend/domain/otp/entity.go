@@ -24,4 +24,7 @@ type OTP struct {
 	ExpiresAt  time.Time
 	VerifiedAt *time.Time
 	CreatedAt  time.Time
+	// RequestDeviceID and VerifyDeviceID record the kiosk that requested and verified the OTP.
+	RequestDeviceID *uuid.UUID
+	VerifyDeviceID  *uuid.UUID
 }
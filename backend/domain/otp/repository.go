@@ -1,10 +1,16 @@
 package otp
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Repository provides OTP persistence.
 type Repository interface {
 	Create(ctx context.Context, otp *OTP) (*OTP, error)
 	GetByRefAndPhone(ctx context.Context, otpRef string, phone string) (*OTP, error)
 	Update(ctx context.Context, otp *OTP) (*OTP, error)
+	ExpireActive(ctx context.Context, now time.Time) (int64, error)
+	DeleteExpiredBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	AnonymizeExpiredBefore(ctx context.Context, cutoff time.Time) (int64, error)
 }
@@ -16,6 +16,11 @@ const (
 	StatusExpired        Status = "EXPIRED"
 )
 
+// ActiveStatuses returns the statuses of parcels that still occupy their compartment.
+func ActiveStatuses() []Status {
+	return []Status{StatusDepositing, StatusReadyForPickup}
+}
+
 // Parcel represents a package stored in a locker compartment.
 type Parcel struct {
 	ID            uuid.UUID
@@ -25,6 +30,7 @@ type Parcel struct {
 	Size          string // S | M | L
 	ReceiverPhone string
 	SenderPhone   string
+	CarrierCode   *string // set when a carrier deposits; selects webhook subscribers
 	PickupCode    *string
 	Status        Status
 	DepositedAt   *time.Time
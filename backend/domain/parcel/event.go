@@ -6,10 +6,12 @@ import (
 	"github.com/google/uuid"
 )
 
-// Event captures parcel timeline transitions.
+// Event captures parcel timeline transitions. DeviceID is the kiosk that performed the
+// transition, if a registered device signed the request.
 type Event struct {
 	ID        uuid.UUID
 	ParcelID  uuid.UUID
 	EventType string
+	DeviceID  *uuid.UUID
 	CreatedAt time.Time
 }
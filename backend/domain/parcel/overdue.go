@@ -5,13 +5,16 @@ import (
 	"time"
 )
 
+// GracePeriod is the free storage time after deposit before overdue fees start.
+const GracePeriod = 24 * time.Hour
+
 // CalculateOverdue computes overdue days and fee based on deposited time and fee per day.
 func CalculateOverdue(depositedAt *time.Time, now time.Time, overdueFeePerDay int) (int, int) {
 	if depositedAt == nil {
 		return 0, 0
 	}
 	overdueDuration := now.Sub(*depositedAt)
-	if overdueDuration <= 24*time.Hour {
+	if overdueDuration <= GracePeriod {
 		return 0, 0
 	}
 	overdueHours := overdueDuration.Hours()
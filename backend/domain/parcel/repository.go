@@ -6,6 +6,20 @@ import (
 	"github.com/google/uuid"
 )
 
+// SearchFilter narrows an admin parcel search. Phone matches the receiver or the sender; an
+// empty LocationIDs means every location. Statuses matches any of the listed statuses.
+type SearchFilter struct {
+	Phone         string
+	ParcelCode    string
+	Status        Status
+	Statuses      []Status
+	LockerID      *uuid.UUID
+	CompartmentID *uuid.UUID
+	LocationIDs   []uuid.UUID
+	Limit         int
+	Offset        int
+}
+
 // Repository defines data access for parcels.
 type Repository interface {
 	Create(ctx context.Context, parcel *Parcel) (*Parcel, error)
@@ -15,4 +29,7 @@ type Repository interface {
 	CreateEvent(ctx context.Context, event *Event) error
 	CountByStatus(ctx context.Context, statuses []Status) (int64, error)
 	ListReadyForPickupByPhone(ctx context.Context, phone string) ([]*Parcel, error)
+	Search(ctx context.Context, filter SearchFilter) ([]*Parcel, int64, error)
+	// CountActiveInCompartment counts parcels depositing into or waiting in a compartment.
+	CountActiveInCompartment(ctx context.Context, compartmentID uuid.UUID) (int64, error)
 }
@@ -1,14 +1,30 @@
 package pickup
 
-import "time"
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type TokenInfo struct {
 	Phone     string
 	ExpiresAt time.Time
+	// OtpRef identifies the OTP verification that issued the token.
+	OtpRef string
+	// LockerID optionally restricts the token to a single locker.
+	LockerID *uuid.UUID
+}
+
+// AllowsLocker reports whether the token may be used at the given locker.
+func (i TokenInfo) AllowsLocker(lockerID uuid.UUID) bool {
+	return i.LockerID == nil || *i.LockerID == lockerID
 }
 
-// TokenStore keeps pickup tokens in memory for now.
+// TokenStore issues, resolves and revokes pickup tokens after OTP verification.
+// Get reports ok=false for unknown, forged or revoked tokens; expiry is checked by the caller.
 type TokenStore interface {
-	Store(token string, phone string, expiresAt time.Time)
-	Get(token string) (TokenInfo, bool)
+	Issue(ctx context.Context, info TokenInfo) (string, error)
+	Get(ctx context.Context, token string) (TokenInfo, bool, error)
+	Revoke(ctx context.Context, token string) error
 }
@@ -0,0 +1,27 @@
+package provisioning
+
+import (
+	"fmt"
+
+	"smart-parcel-locker/backend/pkg/errorx"
+)
+
+var (
+	ErrUnsupportedFormat = errorx.Error{Code: "INVALID_REQUEST", Message: "manifest format must be json or csv"}
+)
+
+// Problem is one reason a manifest cannot be applied. Path names the entry, e.g.
+// "LOC-1/LK-1/3" for compartment 3 of locker LK-1, or a CSV line.
+type Problem struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// ManifestError refuses a manifest and lists every problem found in it.
+type ManifestError struct {
+	Problems []Problem
+}
+
+func (e *ManifestError) Error() string {
+	return fmt.Sprintf("manifest has %d problem(s)", len(e.Problems))
+}
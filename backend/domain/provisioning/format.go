@@ -0,0 +1,219 @@
+package provisioning
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// csvColumns is the CSV layout: one row per compartment, repeating its locker and location. A
+// row without compartment_no declares a locker without compartments, and a row without
+// locker_code a location without lockers.
+var csvColumns = []string{
+	"location_code", "location_name", "location_address", "location_active", "location_default_locale",
+	"locker_code", "locker_name", "compartment_no", "size", "overdue_fee_per_day",
+}
+
+// Decode parses a manifest in the given format. Syntax problems are returned as a ManifestError
+// naming the CSV line or JSON offset.
+func Decode(format string, data []byte) (*Manifest, error) {
+	var m *Manifest
+	var err error
+	switch format {
+	case FormatJSON:
+		m, err = decodeJSON(data)
+	case FormatCSV:
+		m, err = decodeCSV(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	m.Normalize()
+	return m, nil
+}
+
+// Encode writes a manifest in the given format.
+func Encode(format string, m *Manifest) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(m, "", "  ")
+	case FormatCSV:
+		return encodeCSV(m)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+func decodeJSON(data []byte) (*Manifest, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var m Manifest
+	if err := dec.Decode(&m); err != nil {
+		path := "json"
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr):
+			path = fmt.Sprintf("json offset %d", syntaxErr.Offset)
+		case errors.As(err, &typeErr):
+			path = typeErr.Field
+		}
+		return nil, &ManifestError{Problems: []Problem{{Path: path, Message: err.Error()}}}
+	}
+	return &m, nil
+}
+
+func decodeCSV(data []byte) (*Manifest, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = len(csvColumns)
+	r.TrimLeadingSpace = true
+	header, err := r.Read()
+	if err != nil {
+		return nil, csvError(1, err)
+	}
+	for i, name := range csvColumns {
+		if strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")) != name {
+			return nil, &ManifestError{Problems: []Problem{{
+				Path:    "line 1",
+				Message: "header must be " + strings.Join(csvColumns, ","),
+			}}}
+		}
+	}
+
+	var m Manifest
+	var problems []Problem
+	locations := map[string]int{}
+	lockers := map[string]int{}
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			problems = append(problems, csvError(0, err).Problems...)
+			break
+		}
+		line, _ := r.FieldPos(0)
+		lp := fmt.Sprintf("line %d", line)
+		field := func(i int) string { return strings.TrimSpace(row[i]) }
+		fail := func(msg string) { problems = append(problems, Problem{Path: lp, Message: msg}) }
+
+		loc := Location{Code: field(0), Name: field(1), Address: field(2), DefaultLocale: field(4)}
+		if raw := field(3); raw != "" {
+			active, err := strconv.ParseBool(raw)
+			if err != nil {
+				fail("location_active must be true or false")
+				continue
+			}
+			loc.IsActive = &active
+		}
+		li, seen := locations[loc.Code]
+		if !seen {
+			li = len(m.Locations)
+			locations[loc.Code] = li
+			m.Locations = append(m.Locations, loc)
+		} else if !sameLocation(&m.Locations[li], &loc) {
+			fail("location " + loc.Code + " has different details than on an earlier line")
+			continue
+		}
+
+		lockerCode, lockerName := field(5), field(6)
+		if lockerCode == "" {
+			if field(6) != "" || field(7) != "" || field(8) != "" || field(9) != "" {
+				fail("locker_code is required for locker and compartment columns")
+			}
+			continue
+		}
+		key := loc.Code + "\x00" + lockerCode
+		ki, seen := lockers[key]
+		if !seen {
+			ki = len(m.Locations[li].Lockers)
+			lockers[key] = ki
+			m.Locations[li].Lockers = append(m.Locations[li].Lockers, Locker{Code: lockerCode, Name: lockerName})
+		} else if m.Locations[li].Lockers[ki].Name != lockerName {
+			fail("locker " + lockerCode + " has a different name than on an earlier line")
+			continue
+		}
+
+		if field(7) == "" {
+			if field(8) != "" || field(9) != "" {
+				fail("compartment_no is required for size and overdue_fee_per_day")
+			}
+			continue
+		}
+		no, err := strconv.Atoi(field(7))
+		if err != nil {
+			fail("compartment_no must be a number")
+			continue
+		}
+		fee := 0
+		if raw := field(9); raw != "" {
+			if fee, err = strconv.Atoi(raw); err != nil {
+				fail("overdue_fee_per_day must be a number")
+				continue
+			}
+		}
+		l := &m.Locations[li].Lockers[ki]
+		l.Compartments = append(l.Compartments, Compartment{No: no, Size: field(8), OverdueFeePerDay: fee})
+	}
+	if len(problems) > 0 {
+		return nil, &ManifestError{Problems: problems}
+	}
+	return &m, nil
+}
+
+func sameLocation(a, b *Location) bool {
+	return a.Name == b.Name && a.Address == b.Address && a.DefaultLocale == b.DefaultLocale && a.Active() == b.Active()
+}
+
+func csvError(line int, err error) *ManifestError {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		line = parseErr.Line
+		err = parseErr.Err
+	}
+	return &ManifestError{Problems: []Problem{{Path: fmt.Sprintf("line %d", line), Message: err.Error()}}}
+}
+
+func encodeCSV(m *Manifest) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(csvColumns); err != nil {
+		return nil, err
+	}
+	for _, loc := range m.Locations {
+		location := []string{loc.Code, loc.Name, loc.Address, strconv.FormatBool(loc.Active()), loc.DefaultLocale}
+		if len(loc.Lockers) == 0 {
+			if err := w.Write(append(location, "", "", "", "", "")); err != nil {
+				return nil, err
+			}
+		}
+		for _, l := range loc.Lockers {
+			row := append(append([]string{}, location...), l.Code, l.Name)
+			if len(l.Compartments) == 0 {
+				if err := w.Write(append(row, "", "", "")); err != nil {
+					return nil, err
+				}
+			}
+			for _, c := range l.Compartments {
+				if err := w.Write(append(row, strconv.Itoa(c.No), c.Size, strconv.Itoa(c.OverdueFeePerDay))); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
@@ -0,0 +1,86 @@
+package provisioning
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleCSV = `location_code,location_name,location_address,location_active,location_default_locale,locker_code,locker_name,compartment_no,size,overdue_fee_per_day
+LOC-1,Central,1 Main St,true,en,LK-1,Lobby,1,s,10
+LOC-1,Central,1 Main St,true,en,LK-1,Lobby,2,M,15
+LOC-1,Central,1 Main St,true,en,LK-2,Garage,,,
+LOC-2,North,,false,,,,,,
+`
+
+func TestCSVRoundTrip(t *testing.T) {
+	m, err := Decode(FormatCSV, []byte(sampleCSV))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if problems := m.Validate(); len(problems) != 0 {
+		t.Fatalf("unexpected problems: %+v", problems)
+	}
+	if len(m.Locations) != 2 || len(m.Locations[0].Lockers) != 2 || len(m.Locations[0].Lockers[0].Compartments) != 2 {
+		t.Fatalf("unexpected manifest: %+v", m)
+	}
+	if got := m.Locations[0].Lockers[0].Compartments[0]; got.Size != "S" || got.OverdueFeePerDay != 10 {
+		t.Fatalf("compartment not normalized: %+v", got)
+	}
+	if m.Locations[1].Active() {
+		t.Fatalf("location_active=false was ignored")
+	}
+
+	out, err := Encode(FormatCSV, m)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	again, err := Decode(FormatCSV, out)
+	if err != nil {
+		t.Fatalf("decode encoded manifest: %v", err)
+	}
+	if !reflect.DeepEqual(m, again) {
+		t.Fatalf("round trip changed the manifest:\n%+v\n%+v", m, again)
+	}
+}
+
+func TestDecodeCSVReportsLines(t *testing.T) {
+	data := strings.Replace(sampleCSV, "LK-1,Lobby,2,M,15", "LK-1,Lobby,two,M,15", 1)
+	data = strings.Replace(data, "LOC-2,North,,false", "LOC-2,North,,maybe", 1)
+	_, err := Decode(FormatCSV, []byte(data))
+	var manifestErr *ManifestError
+	if !errors.As(err, &manifestErr) {
+		t.Fatalf("expected ManifestError, got %v", err)
+	}
+	if len(manifestErr.Problems) != 2 || manifestErr.Problems[0].Path != "line 3" || manifestErr.Problems[1].Path != "line 5" {
+		t.Fatalf("unexpected problems: %+v", manifestErr.Problems)
+	}
+}
+
+func TestValidateCollectsEveryProblem(t *testing.T) {
+	m := &Manifest{Locations: []Location{
+		{Code: "LOC-1", Name: "Central", DefaultLocale: "xx", Lockers: []Locker{
+			{Code: "LK-1", Compartments: []Compartment{{No: 1, Size: "S"}, {No: 1, Size: "XL", OverdueFeePerDay: -1}}},
+		}},
+		{Code: "LOC-1", Lockers: []Locker{{Code: "LK-1"}}},
+	}}
+	want := []string{
+		"unsupported default_locale",
+		"compartment 1 is listed twice",
+		"size must be S, M or L",
+		"overdue_fee_per_day must be",
+		"location LOC-1 is listed twice",
+		"location name is required",
+		"locker LK-1 is already listed",
+	}
+	problems := m.Validate()
+	if len(problems) != len(want) {
+		t.Fatalf("expected %d problems, got %+v", len(want), problems)
+	}
+	for i, p := range problems {
+		if !strings.Contains(p.Message, want[i]) {
+			t.Fatalf("problem %d: expected %q, got %q", i, want[i], p.Message)
+		}
+	}
+}
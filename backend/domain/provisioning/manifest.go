@@ -0,0 +1,138 @@
+package provisioning
+
+import (
+	"fmt"
+	"strings"
+
+	"smart-parcel-locker/backend/domain/notification"
+)
+
+// Manifest describes locations, their lockers and compartments as a file that can be kept under
+// version control. Locations are keyed by code, lockers by their globally unique code and
+// compartments by number within their locker.
+type Manifest struct {
+	Locations []Location `json:"locations"`
+}
+
+// Location is a location entry of a manifest. IsActive defaults to true when omitted.
+type Location struct {
+	Code          string   `json:"code"`
+	Name          string   `json:"name"`
+	Address       string   `json:"address,omitempty"`
+	IsActive      *bool    `json:"is_active,omitempty"`
+	DefaultLocale string   `json:"default_locale,omitempty"`
+	Lockers       []Locker `json:"lockers"`
+}
+
+// Locker is a locker entry of a manifest.
+type Locker struct {
+	Code         string        `json:"code"`
+	Name         string        `json:"name"`
+	Compartments []Compartment `json:"compartments"`
+}
+
+// Compartment is a compartment entry of a manifest.
+type Compartment struct {
+	No               int    `json:"no"`
+	Size             string `json:"size"`
+	OverdueFeePerDay int    `json:"overdue_fee_per_day"`
+}
+
+// Active reports whether the location should be active.
+func (l *Location) Active() bool {
+	return l.IsActive == nil || *l.IsActive
+}
+
+// Normalize trims codes, names and sizes and canonicalises locales, so that comparing a manifest
+// with stored values does not report whitespace as a change.
+func (m *Manifest) Normalize() {
+	for i := range m.Locations {
+		loc := &m.Locations[i]
+		loc.Code = strings.TrimSpace(loc.Code)
+		loc.Name = strings.TrimSpace(loc.Name)
+		loc.Address = strings.TrimSpace(loc.Address)
+		if locale := notification.ParseLocale(loc.DefaultLocale); locale != "" {
+			loc.DefaultLocale = string(locale)
+		}
+		for j := range loc.Lockers {
+			l := &loc.Lockers[j]
+			l.Code = strings.TrimSpace(l.Code)
+			l.Name = strings.TrimSpace(l.Name)
+			for k := range l.Compartments {
+				c := &l.Compartments[k]
+				c.Size = strings.ToUpper(strings.TrimSpace(c.Size))
+			}
+		}
+	}
+}
+
+// Validate checks the manifest on its own, without looking at stored state, and returns every
+// problem found rather than only the first.
+func (m *Manifest) Validate() []Problem {
+	var problems []Problem
+	add := func(path, format string, args ...interface{}) {
+		problems = append(problems, Problem{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	if len(m.Locations) == 0 {
+		add("locations", "manifest has no locations")
+	}
+	locationCodes := map[string]bool{}
+	lockerCodes := map[string]string{}
+	for _, loc := range m.Locations {
+		path := LocationPath(loc.Code)
+		if loc.Code == "" {
+			add(path, "location code is required")
+		} else if locationCodes[loc.Code] {
+			add(path, "location %s is listed twice", loc.Code)
+		}
+		locationCodes[loc.Code] = true
+		if loc.Name == "" {
+			add(path, "location name is required")
+		}
+		if loc.DefaultLocale != "" && notification.ParseLocale(loc.DefaultLocale) == "" {
+			add(path, "unsupported default_locale %q", loc.DefaultLocale)
+		}
+		for _, l := range loc.Lockers {
+			path := LockerPath(loc.Code, l.Code)
+			if l.Code == "" {
+				add(path, "locker code is required")
+			} else if other, ok := lockerCodes[l.Code]; ok {
+				add(path, "locker %s is already listed under location %s", l.Code, other)
+			}
+			lockerCodes[l.Code] = loc.Code
+			numbers := map[int]bool{}
+			for _, c := range l.Compartments {
+				path := CompartmentPath(loc.Code, l.Code, c.No)
+				if c.No <= 0 {
+					add(path, "compartment number must be greater than 0")
+				} else if numbers[c.No] {
+					add(path, "compartment %d is listed twice", c.No)
+				}
+				numbers[c.No] = true
+				if !ValidSize(c.Size) {
+					add(path, "size must be S, M or L")
+				}
+				if c.OverdueFeePerDay < 0 {
+					add(path, "overdue_fee_per_day must be greater than or equal to 0")
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// ValidSize reports whether size is a compartment size.
+func ValidSize(size string) bool {
+	return size == "S" || size == "M" || size == "L"
+}
+
+// LocationPath, LockerPath and CompartmentPath name manifest entries in problems and changes.
+func LocationPath(code string) string { return code }
+
+func LockerPath(locationCode, lockerCode string) string {
+	return locationCode + "/" + lockerCode
+}
+
+func CompartmentPath(locationCode, lockerCode string, no int) string {
+	return fmt.Sprintf("%s/%s/%d", locationCode, lockerCode, no)
+}
@@ -0,0 +1,63 @@
+package provisioning
+
+const (
+	ActionCreate = "CREATE"
+	ActionUpdate = "UPDATE"
+)
+
+// FieldChange is the stored and the manifest value of one field. From is nil for new entries.
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// Change is one entry the manifest creates or updates. EntityType uses the audit entity names.
+type Change struct {
+	Action     string                 `json:"action"`
+	EntityType string                 `json:"entity_type"`
+	Path       string                 `json:"path"`
+	Fields     map[string]FieldChange `json:"fields"`
+}
+
+// Counts tallies the entries of one entity type.
+type Counts struct {
+	Create    int `json:"create"`
+	Update    int `json:"update"`
+	Unchanged int `json:"unchanged"`
+}
+
+// Plan is the diff between a manifest and the stored configuration. Entries that exist but are
+// not in the manifest are never touched and do not appear in it.
+type Plan struct {
+	Changes      []Change `json:"changes"`
+	Locations    Counts   `json:"locations"`
+	Lockers      Counts   `json:"lockers"`
+	Compartments Counts   `json:"compartments"`
+}
+
+// Empty reports whether applying the plan would change nothing.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Diff records the differing fields between stored values and manifest values. It returns nil
+// when every field matches.
+func Diff(pairs ...FieldPair) map[string]FieldChange {
+	var fields map[string]FieldChange
+	for _, p := range pairs {
+		if p.From == p.To {
+			continue
+		}
+		if fields == nil {
+			fields = map[string]FieldChange{}
+		}
+		fields[p.Name] = FieldChange{From: p.From, To: p.To}
+	}
+	return fields
+}
+
+// FieldPair names a field with its stored and manifest values. Values must be comparable.
+type FieldPair struct {
+	Name     string
+	From, To interface{}
+}
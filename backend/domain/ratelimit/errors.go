@@ -0,0 +1,7 @@
+package ratelimit
+
+import "smart-parcel-locker/backend/pkg/errorx"
+
+var (
+	ErrTooManyRequests = errorx.Error{Code: "TOO_MANY_REQUESTS", Message: "too many requests"}
+)
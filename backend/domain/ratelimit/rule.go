@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dimension identifies what a limit is keyed by.
+type Dimension string
+
+const (
+	DimensionPhone  Dimension = "phone"
+	DimensionIP     Dimension = "ip"
+	DimensionLocker Dimension = "locker"
+)
+
+// Rule allows Limit hits inside a sliding Window.
+type Rule struct {
+	Limit  int
+	Window time.Duration
+}
+
+// Enabled reports whether the rule should be enforced.
+func (r Rule) Enabled() bool {
+	return r.Limit > 0 && r.Window > 0
+}
+
+// ParseRule parses a "<limit>/<window>" spec such as "5/10m".
+// An empty spec or "off" yields a disabled rule.
+func ParseRule(spec string) (Rule, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" || strings.EqualFold(spec, "off") {
+		return Rule{}, nil
+	}
+	parts := strings.SplitN(spec, "/", 2)
+	if len(parts) != 2 {
+		return Rule{}, fmt.Errorf("invalid rate limit %q: expected <limit>/<window>", spec)
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || limit <= 0 {
+		return Rule{}, fmt.Errorf("invalid rate limit %q: limit must be a positive integer", spec)
+	}
+	window, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+	if err != nil || window <= 0 {
+		return Rule{}, fmt.Errorf("invalid rate limit %q: window must be a positive duration", spec)
+	}
+	return Rule{Limit: limit, Window: window}, nil
+}
+
+// Decision is the outcome of taking one hit against a rule.
+type Decision struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	ResetAt   time.Time
+}
+
+// RetryAfter returns how long the caller should wait before retrying.
+func (d Decision) RetryAfter(now time.Time) time.Duration {
+	if d.Allowed || !d.ResetAt.After(now) {
+		return 0
+	}
+	return d.ResetAt.Sub(now)
+}
+
+// Evaluate applies a sliding-log window to the hits recorded inside the window.
+// hits must only contain timestamps after now-rule.Window, oldest first.
+func Evaluate(rule Rule, hits []time.Time, now time.Time) Decision {
+	count := len(hits)
+	resetAt := now.Add(rule.Window)
+	if count > 0 {
+		resetAt = hits[0].Add(rule.Window)
+	}
+	if count >= rule.Limit {
+		return Decision{
+			Allowed:   false,
+			Limit:     rule.Limit,
+			Remaining: 0,
+			ResetAt:   resetAt,
+		}
+	}
+	return Decision{
+		Allowed:   true,
+		Limit:     rule.Limit,
+		Remaining: rule.Limit - count - 1,
+		ResetAt:   resetAt,
+	}
+}
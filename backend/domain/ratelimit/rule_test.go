@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRule(t *testing.T) {
+	rule, err := ParseRule("5/10m")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rule.Limit != 5 || rule.Window != 10*time.Minute {
+		t.Fatalf("expected 5/10m; got %d/%s", rule.Limit, rule.Window)
+	}
+}
+
+func TestParseRuleOff(t *testing.T) {
+	for _, spec := range []string{"", "off", "OFF"} {
+		rule, err := ParseRule(spec)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", spec, err)
+		}
+		if rule.Enabled() {
+			t.Fatalf("expected %q to disable the rule", spec)
+		}
+	}
+}
+
+func TestParseRuleInvalid(t *testing.T) {
+	for _, spec := range []string{"5", "0/1m", "x/1m", "5/abc", "5/-1s"} {
+		if _, err := ParseRule(spec); err == nil {
+			t.Fatalf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestEvaluateAllowsUntilLimit(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	rule := Rule{Limit: 2, Window: time.Minute}
+	first := now.Add(-30 * time.Second)
+
+	d := Evaluate(rule, []time.Time{first}, now)
+	if !d.Allowed || d.Remaining != 0 {
+		t.Fatalf("expected allowed with 0 remaining; got %+v", d)
+	}
+
+	d = Evaluate(rule, []time.Time{first, now.Add(-time.Second)}, now)
+	if d.Allowed {
+		t.Fatalf("expected denied; got %+v", d)
+	}
+	if !d.ResetAt.Equal(first.Add(time.Minute)) {
+		t.Fatalf("expected reset when oldest hit leaves the window; got %s", d.ResetAt)
+	}
+	if d.RetryAfter(now) != 30*time.Second {
+		t.Fatalf("expected retry after 30s; got %s", d.RetryAfter(now))
+	}
+}
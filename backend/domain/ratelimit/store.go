@@ -0,0 +1,22 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Hit is one key checked against its rule.
+type Hit struct {
+	Key  string
+	Rule Rule
+}
+
+// Store records hits for keys and decides whether another hit is allowed.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Take evaluates all hits together and records them only when every one is allowed, so a
+	// request denied on one key does not use up the budget of the others. Decisions are returned
+	// in the order of hits.
+	Take(ctx context.Context, hits []Hit, now time.Time) ([]Decision, error)
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
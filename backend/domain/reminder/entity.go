@@ -0,0 +1,39 @@
+package reminder
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"smart-parcel-locker/backend/domain/notification"
+	"smart-parcel-locker/backend/domain/parcel"
+)
+
+// Policy overrides the default reminder rules for one location.
+type Policy struct {
+	LocationID uuid.UUID
+	Rules      []Rule
+	CreatedAt  time.Time
+	UpdatedAt  *time.Time
+}
+
+// Candidate is a parcel awaiting pickup together with what a reminder needs.
+type Candidate struct {
+	Parcel     *parcel.Parcel
+	LocationID uuid.UUID
+	LockerName string
+	// Locale is the location's default notification locale.
+	Locale notification.Locale
+}
+
+// DueQuery selects the parcels awaiting pickup for which Rule is due at Now and not yet sent.
+type DueQuery struct {
+	Rule Rule
+	Now  time.Time
+	// LocationIDs are the locations whose own policy contains Rule.
+	LocationIDs []uuid.UUID
+	// Default also selects locations without a policy, i.e. those not in PolicyLocationIDs,
+	// because Rule is one of the default rules.
+	Default           bool
+	PolicyLocationIDs []uuid.UUID
+}
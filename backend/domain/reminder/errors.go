@@ -0,0 +1,8 @@
+package reminder
+
+import "smart-parcel-locker/backend/pkg/errorx"
+
+var (
+	ErrInvalidRequest = errorx.Error{Code: "INVALID_REQUEST", Message: "invalid request"}
+	ErrInvalidPolicy  = errorx.Error{Code: "INVALID_POLICY", Message: "invalid reminder policy"}
+)
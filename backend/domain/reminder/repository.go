@@ -0,0 +1,21 @@
+package reminder
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository stores reminder policies and sent-reminder records.
+type Repository interface {
+	// ListDue returns the parcels selected by the query, oldest deposit first.
+	ListDue(ctx context.Context, query DueQuery) ([]Candidate, error)
+	// Claim records the reminder as sent; it returns false if it was already recorded.
+	Claim(ctx context.Context, parcelID uuid.UUID, key string, at time.Time) (bool, error)
+
+	ListPolicies(ctx context.Context) ([]Policy, error)
+	GetPolicy(ctx context.Context, locationID uuid.UUID) (*Policy, error)
+	UpsertPolicy(ctx context.Context, policy *Policy) (*Policy, error)
+	DeletePolicy(ctx context.Context, locationID uuid.UUID) error
+}
@@ -0,0 +1,146 @@
+package reminder
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"smart-parcel-locker/backend/domain/parcel"
+)
+
+// Anchor is the parcel moment a reminder offset is measured from.
+type Anchor string
+
+const (
+	AnchorDeposited Anchor = "DEPOSITED" // parcel deposited_at
+	AnchorGraceEnd  Anchor = "GRACE_END" // deposited_at + parcel.GracePeriod, when overdue fees start
+	AnchorExpiry    Anchor = "EXPIRY"    // parcel expires_at
+)
+
+// Rule sends one reminder at Anchor+Offset. A negative offset fires before the anchor.
+type Rule struct {
+	Anchor Anchor
+	Offset time.Duration
+}
+
+// Key identifies the rule in sent-reminder records, e.g. "EXPIRY-24h".
+func (r Rule) Key() string {
+	if r.Offset < 0 {
+		return fmt.Sprintf("%s-%s", r.Anchor, formatOffset(-r.Offset))
+	}
+	return fmt.Sprintf("%s+%s", r.Anchor, formatOffset(r.Offset))
+}
+
+// formatOffset renders durations without trailing zero units ("24h" rather than "24h0m0s").
+func formatOffset(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// anchorTime returns the anchor instant for the parcel, or false when the parcel lacks it.
+func (r Rule) anchorTime(p *parcel.Parcel) (time.Time, bool) {
+	switch r.Anchor {
+	case AnchorDeposited:
+		if p.DepositedAt == nil {
+			return time.Time{}, false
+		}
+		return *p.DepositedAt, true
+	case AnchorGraceEnd:
+		if p.DepositedAt == nil {
+			return time.Time{}, false
+		}
+		return p.DepositedAt.Add(parcel.GracePeriod), true
+	case AnchorExpiry:
+		if p.ExpiresAt == nil {
+			return time.Time{}, false
+		}
+		return *p.ExpiresAt, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// Due reports whether the reminder should be sent now. Reminders that lead up to a
+// moment (negative offsets) are skipped once that moment has passed.
+func (r Rule) Due(p *parcel.Parcel, now time.Time) bool {
+	if p.Status != parcel.StatusReadyForPickup {
+		return false
+	}
+	anchor, ok := r.anchorTime(p)
+	if !ok {
+		return false
+	}
+	if now.Before(anchor.Add(r.Offset)) {
+		return false
+	}
+	if r.Offset < 0 && !now.Before(anchor) {
+		return false
+	}
+	return true
+}
+
+// DueRange is the filter behind Due for a store: the rule is due at now for parcels whose anchor
+// field (deposited_at for DEPOSITED and GRACE_END, expires_at for EXPIRY) is at or before until
+// and, when since is set, after since.
+func (r Rule) DueRange(now time.Time) (until time.Time, since *time.Time) {
+	shift := time.Duration(0)
+	if r.Anchor == AnchorGraceEnd {
+		shift = parcel.GracePeriod
+	}
+	until = now.Add(-r.Offset - shift)
+	if r.Offset < 0 {
+		after := now.Add(-shift)
+		since = &after
+	}
+	return until, since
+}
+
+// ParseRules parses a comma separated list such as "DEPOSITED+24h,GRACE_END-2h,EXPIRY-24h".
+// An empty spec or "off" yields no rules.
+func ParseRules(spec string) ([]Rule, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" || strings.EqualFold(spec, "off") {
+		return nil, nil
+	}
+	var rules []Rule
+	seen := map[string]bool{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		idx := strings.IndexAny(part, "+-")
+		if idx <= 0 {
+			return nil, fmt.Errorf("invalid reminder rule %q", part)
+		}
+		anchor := Anchor(strings.ToUpper(part[:idx]))
+		switch anchor {
+		case AnchorDeposited, AnchorGraceEnd, AnchorExpiry:
+		default:
+			return nil, fmt.Errorf("invalid reminder anchor %q", part[:idx])
+		}
+		offset, err := time.ParseDuration(part[idx:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid reminder offset %q: %w", part, err)
+		}
+		rule := Rule{Anchor: anchor, Offset: offset}
+		if seen[rule.Key()] {
+			continue
+		}
+		seen[rule.Key()] = true
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// FormatRules renders rules back into the spec format accepted by ParseRules.
+func FormatRules(rules []Rule) string {
+	parts := make([]string, 0, len(rules))
+	for _, r := range rules {
+		parts = append(parts, r.Key())
+	}
+	return strings.Join(parts, ",")
+}
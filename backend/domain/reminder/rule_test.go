@@ -0,0 +1,77 @@
+package reminder
+
+import (
+	"testing"
+	"time"
+
+	"smart-parcel-locker/backend/domain/parcel"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("DEPOSITED+24h, grace_end-2h,EXPIRY-24h,EXPIRY-1440m")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := FormatRules(rules); got != "DEPOSITED+24h,GRACE_END-2h,EXPIRY-24h" {
+		t.Fatalf("unexpected rules %q", got)
+	}
+}
+
+func TestParseRulesInvalid(t *testing.T) {
+	for _, spec := range []string{"PICKUP+1h", "EXPIRY", "EXPIRY-abc"} {
+		if _, err := ParseRules(spec); err == nil {
+			t.Fatalf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestRuleDue(t *testing.T) {
+	deposited := time.Date(2025, 1, 9, 12, 0, 0, 0, time.UTC)
+	expires := deposited.Add(72 * time.Hour)
+	p := &parcel.Parcel{Status: parcel.StatusReadyForPickup, DepositedAt: &deposited, ExpiresAt: &expires}
+
+	afterDeposit := Rule{Anchor: AnchorDeposited, Offset: 24 * time.Hour}
+	if afterDeposit.Due(p, deposited.Add(23*time.Hour)) {
+		t.Fatal("after-deposit reminder fired early")
+	}
+	if !afterDeposit.Due(p, deposited.Add(25*time.Hour)) {
+		t.Fatal("after-deposit reminder not due")
+	}
+
+	beforeGrace := Rule{Anchor: AnchorGraceEnd, Offset: -2 * time.Hour}
+	if !beforeGrace.Due(p, deposited.Add(23*time.Hour)) {
+		t.Fatal("grace reminder not due")
+	}
+	if beforeGrace.Due(p, deposited.Add(25*time.Hour)) {
+		t.Fatal("grace reminder fired after grace period ended")
+	}
+
+	p.Status = parcel.StatusPickedUp
+	if afterDeposit.Due(p, deposited.Add(25*time.Hour)) {
+		t.Fatal("reminder fired for collected parcel")
+	}
+}
+
+func TestDueRangeMatchesDue(t *testing.T) {
+	deposited := time.Date(2025, 1, 9, 12, 0, 0, 0, time.UTC)
+	expires := deposited.Add(72 * time.Hour)
+	p := &parcel.Parcel{Status: parcel.StatusReadyForPickup, DepositedAt: &deposited, ExpiresAt: &expires}
+	rules, err := ParseRules("DEPOSITED+24h,GRACE_END-2h,GRACE_END+1h,EXPIRY-24h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range rules {
+		field := deposited
+		if r.Anchor == AnchorExpiry {
+			field = expires
+		}
+		for h := 0; h <= 96; h++ {
+			now := deposited.Add(time.Duration(h) * time.Hour)
+			until, since := r.DueRange(now)
+			inRange := !field.After(until) && (since == nil || field.After(*since))
+			if inRange != r.Due(p, now) {
+				t.Fatalf("%s at +%dh: range says %t, Due says %t", r.Key(), h, inRange, r.Due(p, now))
+			}
+		}
+	}
+}
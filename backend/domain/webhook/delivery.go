@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "PENDING"
+	DeliverySucceeded DeliveryStatus = "SUCCEEDED"
+	DeliveryDead      DeliveryStatus = "DEAD"
+)
+
+// Delivery is one event sent to one subscription; the rows form the delivery log.
+type Delivery struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	EventID        uuid.UUID
+	EventType      EventType
+	Payload        []byte
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode *int
+	LastError      *string
+	DeliveredAt    *time.Time
+	// ReplayOf links a replayed delivery to the original.
+	ReplayOf  *uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+}
+
+// NewDelivery queues payload for sub, due immediately.
+func NewDelivery(sub *Subscription, eventID uuid.UUID, eventType EventType, payload []byte, now time.Time) *Delivery {
+	return &Delivery{
+		ID:             uuid.New(),
+		SubscriptionID: sub.ID,
+		EventID:        eventID,
+		EventType:      eventType,
+		Payload:        payload,
+		Status:         DeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}
+}
+
+// Attempt is the outcome of one HTTP call to a subscriber.
+type Attempt struct {
+	StatusCode int
+	Duration   time.Duration
+}
+
+// DeliveryFilter narrows the delivery log.
+type DeliveryFilter struct {
+	SubscriptionID *uuid.UUID
+	EventID        *uuid.UUID
+	Status         DeliveryStatus
+	Limit          int
+	Offset         int
+}
@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"smart-parcel-locker/backend/domain/parcel"
+)
+
+// EventType names a parcel lifecycle event a carrier can subscribe to.
+type EventType string
+
+const (
+	EventParcelDeposited EventType = "parcel.deposited"
+	EventParcelPickedUp  EventType = "parcel.picked_up"
+	EventParcelExpired   EventType = "parcel.expired"
+	EventParcelReturned  EventType = "parcel.returned"
+	// EventPing is sent on demand to check an endpoint; every subscription receives it.
+	EventPing EventType = "ping"
+)
+
+// EventTypes lists the subscribable event types.
+func EventTypes() []EventType {
+	return []EventType{EventParcelDeposited, EventParcelPickedUp, EventParcelExpired, EventParcelReturned}
+}
+
+func (t EventType) Valid() bool {
+	for _, known := range EventTypes() {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// EventTypeFor maps a parcel event (recorded as the status entered) to its webhook event type.
+func EventTypeFor(parcelEventType string) (EventType, bool) {
+	switch parcel.Status(parcelEventType) {
+	case parcel.StatusReadyForPickup:
+		return EventParcelDeposited, true
+	case parcel.StatusPickedUp:
+		return EventParcelPickedUp, true
+	case parcel.StatusExpired:
+		return EventParcelExpired, true
+	case parcel.StatusCancelled:
+		return EventParcelReturned, true
+	default:
+		return "", false
+	}
+}
+
+// Subscription is a carrier endpoint that receives signed parcel events.
+type Subscription struct {
+	ID          uuid.UUID
+	CarrierCode string
+	URL         string
+	EventTypes  []EventType
+	Secret      string
+	IsActive    bool
+	CreatedAt   time.Time
+	UpdatedAt   *time.Time
+}
+
+// Wants reports whether the subscription receives events of type t.
+func (s *Subscription) Wants(t EventType) bool {
+	if t == EventPing {
+		return true
+	}
+	for _, et := range s.EventTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,10 @@
+package webhook
+
+import "smart-parcel-locker/backend/pkg/errorx"
+
+var (
+	ErrInvalidRequest       = errorx.Error{Code: "INVALID_REQUEST", Message: "invalid request"}
+	ErrInvalidSubscription  = errorx.Error{Code: "INVALID_SUBSCRIPTION", Message: "carrier_code, an http(s) url and at least one known event type are required"}
+	ErrSubscriptionNotFound = errorx.Error{Code: "SUBSCRIPTION_NOT_FOUND", Message: "webhook subscription not found"}
+	ErrDeliveryNotFound     = errorx.Error{Code: "DELIVERY_NOT_FOUND", Message: "webhook delivery not found"}
+)
@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"smart-parcel-locker/backend/domain/parcel"
+)
+
+// Event is the JSON body posted to subscribers.
+type Event struct {
+	ID         uuid.UUID  `json:"id"`
+	Type       EventType  `json:"type"`
+	OccurredAt time.Time  `json:"occurred_at"`
+	Data       *EventData `json:"data,omitempty"`
+}
+
+// EventData describes the parcel; receiver contact details are never included.
+type EventData struct {
+	ParcelID    uuid.UUID  `json:"parcel_id"`
+	ParcelCode  string     `json:"parcel_code"`
+	CarrierCode string     `json:"carrier_code"`
+	LockerID    uuid.UUID  `json:"locker_id"`
+	Size        string     `json:"size"`
+	Status      string     `json:"status"`
+	DepositedAt *time.Time `json:"deposited_at,omitempty"`
+	PickedUpAt  *time.Time `json:"picked_up_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+// NewParcelEvent builds the webhook event for a recorded parcel event. It returns false when the
+// parcel has no carrier or the event type is not published.
+func NewParcelEvent(p *parcel.Parcel, e *parcel.Event) (Event, bool) {
+	eventType, ok := EventTypeFor(e.EventType)
+	if !ok || p.CarrierCode == nil || *p.CarrierCode == "" {
+		return Event{}, false
+	}
+	return Event{
+		ID:         e.ID,
+		Type:       eventType,
+		OccurredAt: e.CreatedAt,
+		Data: &EventData{
+			ParcelID:    p.ID,
+			ParcelCode:  p.ParcelCode,
+			CarrierCode: *p.CarrierCode,
+			LockerID:    p.LockerID,
+			Size:        p.Size,
+			Status:      string(p.Status),
+			DepositedAt: p.DepositedAt,
+			PickedUpAt:  p.PickedUpAt,
+			ExpiresAt:   p.ExpiresAt,
+		},
+	}, true
+}
+
+// Payload encodes the event for delivery.
+func (e Event) Payload() ([]byte, error) {
+	return json.Marshal(e)
+}
@@ -0,0 +1,12 @@
+package webhook
+
+import (
+	"context"
+
+	"smart-parcel-locker/backend/domain/parcel"
+)
+
+// Publisher fans a recorded parcel event out to the carrier's subscriptions.
+type Publisher interface {
+	Publish(ctx context.Context, p *parcel.Parcel, e *parcel.Event) error
+}
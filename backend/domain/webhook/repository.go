@@ -0,0 +1,28 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository stores subscriptions and the delivery log.
+type Repository interface {
+	CreateSubscription(ctx context.Context, sub *Subscription) (*Subscription, error)
+	UpdateSubscription(ctx context.Context, sub *Subscription) (*Subscription, error)
+	GetSubscription(ctx context.Context, id uuid.UUID) (*Subscription, error)
+	ListSubscriptions(ctx context.Context, carrierCode string) ([]*Subscription, error)
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+	// ListActiveByCarrier returns the active subscriptions of a carrier.
+	ListActiveByCarrier(ctx context.Context, carrierCode string) ([]*Subscription, error)
+
+	EnqueueDeliveries(ctx context.Context, deliveries []*Delivery) error
+	// ClaimDue leases up to limit pending deliveries due at now until leaseUntil.
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*Delivery, error)
+	MarkSucceeded(ctx context.Context, id uuid.UUID, attempts int, statusCode int, at time.Time) error
+	MarkFailed(ctx context.Context, id uuid.UUID, attempts int, statusCode *int, nextAttemptAt time.Time, lastError string) error
+	MarkDead(ctx context.Context, id uuid.UUID, attempts int, statusCode *int, lastError string) error
+	GetDelivery(ctx context.Context, id uuid.UUID) (*Delivery, error)
+	ListDeliveries(ctx context.Context, filter DeliveryFilter) ([]*Delivery, int64, error)
+}
@@ -0,0 +1,9 @@
+package webhook
+
+import "context"
+
+// Sender posts a signed delivery to a subscription endpoint. A non-2xx response is an error;
+// the returned Attempt carries the status code whenever a response was received.
+type Sender interface {
+	Send(ctx context.Context, sub *Subscription, d *Delivery) (Attempt, error)
+}
@@ -2,14 +2,19 @@ package admin
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"smart-parcel-locker/backend/domain/admin"
+	gormmodels "smart-parcel-locker/backend/infrastructure/persistence/gorm/models"
 )
 
-// GormRepository provides data access for Admin entities.
+// GormRepository provides data access for admins, admin sessions and setup tokens.
 type GormRepository struct {
 	db *gorm.DB
 }
@@ -23,27 +28,487 @@ func (r *GormRepository) WithDB(db *gorm.DB) admin.Repository {
 }
 
 func (r *GormRepository) Create(ctx context.Context, a *admin.Admin) (*admin.Admin, error) {
-	if err := r.db.WithContext(ctx).Create(a).Error; err != nil {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+	}
+	model := mapAdminToModel(a)
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
 		return nil, err
 	}
-	return a, nil
+	if err := r.replaceLocations(ctx, a.ID, a.LocationIDs); err != nil {
+		return nil, err
+	}
+	created := mapAdminModelToDomain(model)
+	created.LocationIDs = a.LocationIDs
+	return created, nil
 }
 
 func (r *GormRepository) GetByID(ctx context.Context, id uuid.UUID) (*admin.Admin, error) {
-	var entity admin.Admin
-	if err := r.db.WithContext(ctx).First(&entity, "id = ?", id).Error; err != nil {
+	var model gormmodels.Admin
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return r.withLocations(ctx, mapAdminModelToDomain(model))
+}
+
+func (r *GormRepository) GetByUsername(ctx context.Context, username string) (*admin.Admin, error) {
+	var model gormmodels.Admin
+	if err := r.db.WithContext(ctx).First(&model, "username = ?", username).Error; err != nil {
 		return nil, err
 	}
-	return &entity, nil
+	return r.withLocations(ctx, mapAdminModelToDomain(model))
 }
 
+func (r *GormRepository) List(ctx context.Context, filter admin.ListFilter) ([]*admin.Admin, int64, error) {
+	query := r.db.WithContext(ctx).Model(&gormmodels.Admin{})
+	if q := strings.TrimSpace(filter.Query); q != "" {
+		query = query.Where("username ILIKE ?", "%"+escapeLike(q)+"%")
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Active != nil {
+		if *filter.Active {
+			query = query.Where("disabled_at IS NULL")
+		} else {
+			query = query.Where("disabled_at IS NOT NULL")
+		}
+	}
+	if filter.LocationID != nil {
+		query = query.Where("id IN (?)", r.db.Model(&gormmodels.AdminLocation{}).
+			Select("admin_id").
+			Where("location_id = ?", *filter.LocationID))
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var models []gormmodels.Admin
+	if err := query.Order("username ASC").Limit(filter.Limit).Offset(filter.Offset).Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+	items := make([]*admin.Admin, 0, len(models))
+	ids := make([]uuid.UUID, 0, len(models))
+	for _, m := range models {
+		items = append(items, mapAdminModelToDomain(m))
+		ids = append(ids, m.ID)
+	}
+	if len(ids) == 0 {
+		return items, total, nil
+	}
+	var links []gormmodels.AdminLocation
+	if err := r.db.WithContext(ctx).
+		Where("admin_id IN ?", ids).
+		Order("created_at ASC, location_id ASC").
+		Find(&links).Error; err != nil {
+		return nil, 0, err
+	}
+	byAdmin := make(map[uuid.UUID][]uuid.UUID, len(ids))
+	for _, link := range links {
+		byAdmin[link.AdminID] = append(byAdmin[link.AdminID], link.LocationID)
+	}
+	for _, item := range items {
+		item.LocationIDs = byAdmin[item.ID]
+	}
+	return items, total, nil
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+func (r *GormRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&gormmodels.Admin{}).Count(&count).Error
+	return count, err
+}
+
+func (r *GormRepository) CountByRoleForUpdate(ctx context.Context, role string) (int64, error) {
+	// Postgres cannot lock rows under an aggregate, so the ids are locked and counted here.
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Model(&gormmodels.Admin{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("role = ? AND disabled_at IS NULL", role).
+		Order("id").
+		Pluck("id", &ids).Error
+	return int64(len(ids)), err
+}
+
+// Update changes the username. The role is written by SetRole and the password hash by SetPasswordHash.
 func (r *GormRepository) Update(ctx context.Context, a *admin.Admin) (*admin.Admin, error) {
-	if err := r.db.WithContext(ctx).Save(a).Error; err != nil {
+	res := r.db.WithContext(ctx).Model(&gormmodels.Admin{}).
+		Where("id = ?", a.ID).
+		Updates(map[string]interface{}{
+			"username":   a.Username,
+			"updated_at": time.Now(),
+		})
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.GetByID(ctx, a.ID)
+}
+
+func (r *GormRepository) SetRole(ctx context.Context, id uuid.UUID, role string, locationIDs []uuid.UUID) error {
+	res := r.db.WithContext(ctx).Model(&gormmodels.Admin{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"role":       role,
+			"updated_at": time.Now(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return r.replaceLocations(ctx, id, locationIDs)
+}
+
+func (r *GormRepository) replaceLocations(ctx context.Context, adminID uuid.UUID, locationIDs []uuid.UUID) error {
+	if err := r.db.WithContext(ctx).Where("admin_id = ?", adminID).Delete(&gormmodels.AdminLocation{}).Error; err != nil {
+		return err
+	}
+	if len(locationIDs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	rows := make([]gormmodels.AdminLocation, 0, len(locationIDs))
+	for _, id := range locationIDs {
+		rows = append(rows, gormmodels.AdminLocation{AdminID: adminID, LocationID: id, CreatedAt: now})
+	}
+	return r.db.WithContext(ctx).Create(&rows).Error
+}
+
+func (r *GormRepository) withLocations(ctx context.Context, a *admin.Admin) (*admin.Admin, error) {
+	var ids []uuid.UUID
+	if err := r.db.WithContext(ctx).Model(&gormmodels.AdminLocation{}).
+		Where("admin_id = ?", a.ID).
+		Order("created_at ASC, location_id ASC").
+		Pluck("location_id", &ids).Error; err != nil {
 		return nil, err
 	}
+	a.LocationIDs = ids
 	return a, nil
 }
 
+func (r *GormRepository) SetPasswordHash(ctx context.Context, id uuid.UUID, hash string, at time.Time) error {
+	res := r.db.WithContext(ctx).Model(&gormmodels.Admin{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"password_hash":   hash,
+			"password_set_at": at,
+			"updated_at":      at,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *GormRepository) ClearPassword(ctx context.Context, id uuid.UUID, at time.Time) error {
+	res := r.db.WithContext(ctx).Model(&gormmodels.Admin{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"password_hash":   "",
+			"password_set_at": nil,
+			"updated_at":      at,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *GormRepository) SetDisabledAt(ctx context.Context, id uuid.UUID, at *time.Time) error {
+	res := r.db.WithContext(ctx).Model(&gormmodels.Admin{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"disabled_at": at,
+			"updated_at":  time.Now(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *GormRepository) SetTOTPSecret(ctx context.Context, id uuid.UUID, secret string) error {
+	res := r.db.WithContext(ctx).Model(&gormmodels.Admin{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"totp_secret":     secret,
+			"totp_enabled_at": nil,
+			"totp_last_step":  0,
+			"updated_at":      time.Now(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *GormRepository) EnableTOTP(ctx context.Context, id uuid.UUID, at time.Time, step int64) error {
+	res := r.db.WithContext(ctx).Model(&gormmodels.Admin{}).
+		Where("id = ? AND totp_secret <> ''", id).
+		Updates(map[string]interface{}{
+			"totp_enabled_at": at,
+			"totp_last_step":  step,
+			"updated_at":      at,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *GormRepository) UseTOTPStep(ctx context.Context, id uuid.UUID, step int64) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&gormmodels.Admin{}).
+		Where("id = ? AND totp_last_step < ?", id, step).
+		Update("totp_last_step", step)
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *GormRepository) ReplaceRecoveryCodes(ctx context.Context, adminID uuid.UUID, hashes []string, at time.Time) error {
+	if err := r.db.WithContext(ctx).Where("admin_id = ?", adminID).Delete(&gormmodels.AdminRecoveryCode{}).Error; err != nil {
+		return err
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+	rows := make([]gormmodels.AdminRecoveryCode, 0, len(hashes))
+	for _, hash := range hashes {
+		rows = append(rows, gormmodels.AdminRecoveryCode{ID: uuid.New(), AdminID: adminID, CodeHash: hash, CreatedAt: at})
+	}
+	return r.db.WithContext(ctx).Create(&rows).Error
+}
+
+func (r *GormRepository) UseRecoveryCode(ctx context.Context, adminID uuid.UUID, hash string, at time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&gormmodels.AdminRecoveryCode{}).
+		Where("admin_id = ? AND code_hash = ? AND used_at IS NULL", adminID, hash).
+		Update("used_at", at)
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *GormRepository) CountRecoveryCodes(ctx context.Context, adminID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&gormmodels.AdminRecoveryCode{}).
+		Where("admin_id = ? AND used_at IS NULL", adminID).
+		Count(&count).Error
+	return count, err
+}
+
 func (r *GormRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&admin.Admin{}, "id = ?", id).Error
+	res := r.db.WithContext(ctx).Delete(&gormmodels.Admin{}, "id = ?", id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *GormRepository) CreateSession(ctx context.Context, s *admin.Session) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	model := mapSessionToModel(s)
+	return r.db.WithContext(ctx).Create(&model).Error
+}
+
+func (r *GormRepository) GetSessionByTokenHash(ctx context.Context, hash string) (*admin.Session, error) {
+	return r.getSession(ctx, "token_hash = ?", hash)
+}
+
+func (r *GormRepository) GetSessionByRefreshHash(ctx context.Context, hash string) (*admin.Session, error) {
+	return r.getSession(ctx, "refresh_token_hash = ?", hash)
+}
+
+func (r *GormRepository) getSession(ctx context.Context, query string, hash string) (*admin.Session, error) {
+	var model gormmodels.AdminSession
+	if err := r.db.WithContext(ctx).Where(query, hash).Take(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, admin.ErrInvalidSession
+		}
+		return nil, err
+	}
+	return mapSessionModelToDomain(model), nil
+}
+
+func (r *GormRepository) RotateSession(ctx context.Context, s *admin.Session, previousRefreshHash string) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&gormmodels.AdminSession{}).
+		Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", s.ID, previousRefreshHash).
+		Updates(map[string]interface{}{
+			"token_hash":         s.TokenHash,
+			"refresh_token_hash": s.RefreshTokenHash,
+			"expires_at":         s.ExpiresAt,
+			"refresh_expires_at": s.RefreshExpiresAt,
+			"refreshed_at":       s.RefreshedAt,
+		})
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *GormRepository) RevokeSession(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&gormmodels.AdminSession{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+}
+
+func (r *GormRepository) RevokeSessions(ctx context.Context, adminID uuid.UUID, at time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Model(&gormmodels.AdminSession{}).
+		Where("admin_id = ? AND revoked_at IS NULL", adminID).
+		Update("revoked_at", at)
+	return res.RowsAffected, res.Error
+}
+
+func (r *GormRepository) RevokeOtherSessions(ctx context.Context, adminID, keep uuid.UUID, at time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Model(&gormmodels.AdminSession{}).
+		Where("admin_id = ? AND id <> ? AND revoked_at IS NULL", adminID, keep).
+		Update("revoked_at", at)
+	return res.RowsAffected, res.Error
+}
+
+func (r *GormRepository) PurgeSessions(ctx context.Context, before time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Where("refresh_expires_at < ?", before).
+		Delete(&gormmodels.AdminSession{})
+	return res.RowsAffected, res.Error
+}
+
+func (r *GormRepository) CreateSetupToken(ctx context.Context, t *admin.SetupToken) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	model := gormmodels.AdminSetupToken{
+		ID:        t.ID,
+		AdminID:   t.AdminID,
+		TokenHash: t.TokenHash,
+		ExpiresAt: t.ExpiresAt,
+		UsedAt:    t.UsedAt,
+		CreatedAt: t.CreatedAt,
+	}
+	return r.db.WithContext(ctx).Create(&model).Error
+}
+
+func (r *GormRepository) GetSetupToken(ctx context.Context, hash string) (*admin.SetupToken, error) {
+	var model gormmodels.AdminSetupToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).Take(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, admin.ErrInvalidSetupToken
+		}
+		return nil, err
+	}
+	return &admin.SetupToken{
+		ID:        model.ID,
+		AdminID:   model.AdminID,
+		TokenHash: model.TokenHash,
+		ExpiresAt: model.ExpiresAt,
+		UsedAt:    model.UsedAt,
+		CreatedAt: model.CreatedAt,
+	}, nil
+}
+
+func (r *GormRepository) UseSetupToken(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&gormmodels.AdminSetupToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *GormRepository) ExpireSetupTokens(ctx context.Context, adminID uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&gormmodels.AdminSetupToken{}).
+		Where("admin_id = ? AND used_at IS NULL", adminID).
+		Update("used_at", at).Error
+}
+
+func mapAdminToModel(a *admin.Admin) gormmodels.Admin {
+	model := gormmodels.Admin{
+		ID:            a.ID,
+		Username:      a.Username,
+		PasswordHash:  a.PasswordHash,
+		Role:          a.Role,
+		PasswordSetAt: a.PasswordSetAt,
+		DisabledAt:    a.DisabledAt,
+		TOTPSecret:    a.TOTPSecret,
+		TOTPEnabledAt: a.TOTPEnabledAt,
+		TOTPLastStep:  a.TOTPLastStep,
+		CreatedAt:     a.CreatedAt,
+	}
+	if !a.UpdatedAt.IsZero() {
+		updatedAt := a.UpdatedAt
+		model.UpdatedAt = &updatedAt
+	}
+	return model
+}
+
+func mapAdminModelToDomain(m gormmodels.Admin) *admin.Admin {
+	a := &admin.Admin{
+		ID:            m.ID,
+		Username:      m.Username,
+		PasswordHash:  m.PasswordHash,
+		Role:          m.Role,
+		PasswordSetAt: m.PasswordSetAt,
+		DisabledAt:    m.DisabledAt,
+		TOTPSecret:    m.TOTPSecret,
+		TOTPEnabledAt: m.TOTPEnabledAt,
+		TOTPLastStep:  m.TOTPLastStep,
+		CreatedAt:     m.CreatedAt,
+	}
+	if m.UpdatedAt != nil {
+		a.UpdatedAt = *m.UpdatedAt
+	}
+	return a
+}
+
+func mapSessionToModel(s *admin.Session) gormmodels.AdminSession {
+	return gormmodels.AdminSession{
+		ID:               s.ID,
+		AdminID:          s.AdminID,
+		TokenHash:        s.TokenHash,
+		RefreshTokenHash: s.RefreshTokenHash,
+		ExpiresAt:        s.ExpiresAt,
+		RefreshExpiresAt: s.RefreshExpiresAt,
+		IP:               s.IP,
+		UserAgent:        s.UserAgent,
+		RevokedAt:        s.RevokedAt,
+		CreatedAt:        s.CreatedAt,
+		RefreshedAt:      s.RefreshedAt,
+	}
+}
+
+func mapSessionModelToDomain(m gormmodels.AdminSession) *admin.Session {
+	return &admin.Session{
+		ID:               m.ID,
+		AdminID:          m.AdminID,
+		TokenHash:        m.TokenHash,
+		RefreshTokenHash: m.RefreshTokenHash,
+		ExpiresAt:        m.ExpiresAt,
+		RefreshExpiresAt: m.RefreshExpiresAt,
+		IP:               m.IP,
+		UserAgent:        m.UserAgent,
+		RevokedAt:        m.RevokedAt,
+		CreatedAt:        m.CreatedAt,
+		RefreshedAt:      m.RefreshedAt,
+	}
 }
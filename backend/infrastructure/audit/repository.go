@@ -0,0 +1,115 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/audit"
+	gormmodels "smart-parcel-locker/backend/infrastructure/persistence/gorm/models"
+)
+
+// GormRepository stores audit entries in the audit_log table.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) WithDB(db *gorm.DB) audit.Repository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) Create(ctx context.Context, entry *audit.Entry) error {
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	model := gormmodels.AuditLog{
+		ID:            entry.ID,
+		ActorID:       entry.ActorID,
+		ActorUsername: entry.ActorUsername,
+		Action:        entry.Action,
+		EntityType:    entry.EntityType,
+		EntityID:      entry.EntityID,
+		Before:        rawToString(entry.Before),
+		After:         rawToString(entry.After),
+		IP:            entry.IP,
+		RequestID:     entry.RequestID,
+		CreatedAt:     entry.CreatedAt,
+	}
+	return r.db.WithContext(ctx).Create(&model).Error
+}
+
+func (r *GormRepository) List(ctx context.Context, filter audit.Filter) ([]*audit.Entry, int64, error) {
+	query := r.db.WithContext(ctx).Model(&gormmodels.AuditLog{})
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var models []gormmodels.AuditLog
+	if err := query.
+		Order("created_at DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+	entries := make([]*audit.Entry, 0, len(models))
+	for _, model := range models {
+		entries = append(entries, &audit.Entry{
+			ID:            model.ID,
+			ActorID:       model.ActorID,
+			ActorUsername: model.ActorUsername,
+			Action:        model.Action,
+			EntityType:    model.EntityType,
+			EntityID:      model.EntityID,
+			Before:        stringToRaw(model.Before),
+			After:         stringToRaw(model.After),
+			IP:            model.IP,
+			RequestID:     model.RequestID,
+			CreatedAt:     model.CreatedAt,
+		})
+	}
+	return entries, total, nil
+}
+
+func rawToString(raw json.RawMessage) *string {
+	if len(raw) == 0 {
+		return nil
+	}
+	s := string(raw)
+	return &s
+}
+
+func stringToRaw(s *string) json.RawMessage {
+	if s == nil {
+		return nil
+	}
+	return json.RawMessage(*s)
+}
@@ -116,6 +116,23 @@ func (r *GormRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*c
 	}, nil
 }
 
+func (r *GormRepository) GetByID(ctx context.Context, id uuid.UUID) (*compartment.Compartment, error) {
+	var model gormmodels.Compartment
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &compartment.Compartment{
+		ID:               model.ID,
+		LockerID:         model.LockerID,
+		CompartmentNo:    model.CompartmentNo,
+		Size:             model.Size,
+		Status:           model.Status,
+		OverdueFeePerDay: model.OverdueFeePerDay,
+		CreatedAt:        model.CreatedAt,
+		UpdatedAt:        model.UpdatedAt,
+	}, nil
+}
+
 func (r *GormRepository) Update(ctx context.Context, comp *compartment.Compartment) (*compartment.Compartment, error) {
 	if comp == nil {
 		return nil, nil
@@ -139,6 +156,43 @@ func (r *GormRepository) Update(ctx context.Context, comp *compartment.Compartme
 	return comp, nil
 }
 
+func (r *GormRepository) UpdateDetails(ctx context.Context, comp *compartment.Compartment) (*compartment.Compartment, error) {
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&gormmodels.Compartment{}).
+		Where("id = ?", comp.ID).
+		Select("compartment_no", "size", "overdue_fee_per_day", "updated_at").
+		Updates(gormmodels.Compartment{
+			CompartmentNo:    comp.CompartmentNo,
+			Size:             comp.Size,
+			OverdueFeePerDay: comp.OverdueFeePerDay,
+			UpdatedAt:        &now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	comp.UpdatedAt = &now
+	return comp, nil
+}
+
+func (r *GormRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&gormmodels.Compartment{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *GormRepository) DeleteByLocker(ctx context.Context, lockerID uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&gormmodels.Compartment{}, "locker_id = ?", lockerID).Error
+}
+
 func (r *GormRepository) ListByLocker(ctx context.Context, lockerID uuid.UUID) ([]compartment.Compartment, error) {
 	var models []gormmodels.Compartment
 	if err := r.db.WithContext(ctx).
@@ -182,3 +236,103 @@ func (r *GormRepository) CountByStatus(ctx context.Context, status string) (int6
 	}
 	return count, nil
 }
+
+func (r *GormRepository) CreateStatusChange(ctx context.Context, change *compartment.StatusChange) error {
+	model := gormmodels.CompartmentStatusChange{
+		ID:            change.ID,
+		CompartmentID: change.CompartmentID,
+		FromStatus:    change.FromStatus,
+		ToStatus:      change.ToStatus,
+		Reason:        change.Reason,
+		Forced:        change.Forced,
+		ActorID:       change.ActorID,
+		CreatedAt:     change.CreatedAt,
+	}
+	return r.db.WithContext(ctx).Create(&model).Error
+}
+
+func (r *GormRepository) ListStatusChanges(ctx context.Context, compartmentID uuid.UUID, limit int) ([]compartment.StatusChange, error) {
+	var models []gormmodels.CompartmentStatusChange
+	if err := r.db.WithContext(ctx).
+		Where("compartment_id = ?", compartmentID).
+		Order("created_at desc").
+		Limit(limit).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	result := make([]compartment.StatusChange, 0, len(models))
+	for _, m := range models {
+		result = append(result, compartment.StatusChange{
+			ID:            m.ID,
+			CompartmentID: m.CompartmentID,
+			FromStatus:    m.FromStatus,
+			ToStatus:      m.ToStatus,
+			Reason:        m.Reason,
+			Forced:        m.Forced,
+			ActorID:       m.ActorID,
+			CreatedAt:     m.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (r *GormRepository) CreateEvent(ctx context.Context, event *compartment.Event) error {
+	model := gormmodels.CompartmentEvent{
+		ID:            event.ID,
+		CompartmentID: event.CompartmentID,
+		LockerID:      event.LockerID,
+		ParcelID:      event.ParcelID,
+		DeviceID:      event.DeviceID,
+		EventType:     event.Type,
+		OccurredAt:    event.OccurredAt,
+		CreatedAt:     event.CreatedAt,
+	}
+	return r.db.WithContext(ctx).Create(&model).Error
+}
+
+func (r *GormRepository) ListEvents(ctx context.Context, compartmentID uuid.UUID, limit int) ([]compartment.Event, error) {
+	var models []gormmodels.CompartmentEvent
+	if err := r.db.WithContext(ctx).
+		Where("compartment_id = ?", compartmentID).
+		Order("occurred_at desc").
+		Limit(limit).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return mapEventModels(models), nil
+}
+
+func (r *GormRepository) ListDoorsOpenSince(ctx context.Context, before time.Time) ([]compartment.Event, error) {
+	doorEvents := []string{compartment.EventDoorOpened, compartment.EventDoorClosed, compartment.EventDoorForced}
+	latest := r.db.
+		Table("compartment_events").
+		Select("DISTINCT ON (compartment_id) *").
+		Where("event_type IN ?", doorEvents).
+		Order("compartment_id, occurred_at desc, created_at desc")
+	var models []gormmodels.CompartmentEvent
+	if err := r.db.WithContext(ctx).
+		Table("(?) AS latest", latest).
+		Where("event_type <> ?", compartment.EventDoorClosed).
+		Where("occurred_at <= ?", before).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return mapEventModels(models), nil
+}
+
+func mapEventModels(models []gormmodels.CompartmentEvent) []compartment.Event {
+	result := make([]compartment.Event, 0, len(models))
+	for _, m := range models {
+		result = append(result, compartment.Event{
+			ID:            m.ID,
+			CompartmentID: m.CompartmentID,
+			LockerID:      m.LockerID,
+			ParcelID:      m.ParcelID,
+			DeviceID:      m.DeviceID,
+			Type:          m.EventType,
+			OccurredAt:    m.OccurredAt,
+			CreatedAt:     m.CreatedAt,
+		})
+	}
+	return result
+}
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	gormmodels "smart-parcel-locker/backend/infrastructure/persistence/gorm/models"
+	"smart-parcel-locker/backend/pkg/logger"
+	"smart-parcel-locker/backend/pkg/phone"
+)
+
+// dataMigration is a one-off data change applied after schema auto-migration.
+type dataMigration struct {
+	ID  string
+	Run func(tx *gorm.DB) error
+}
+
+// dataMigrations lists the migrations in the order they run; each is recorded in
+// schema_migrations and never re-applied.
+func dataMigrations(phones phone.Normalizer) []dataMigration {
+	return []dataMigration{
+		{ID: "0001_normalize_phone_e164", Run: normalizeStoredPhones(phones)},
+		{ID: "0002_admin_role_super_admin", Run: promoteLegacyAdmins},
+	}
+}
+
+// ApplyDataMigrations runs pending data migrations, each in its own transaction.
+func ApplyDataMigrations(db *gorm.DB, phones phone.Normalizer) error {
+	for _, m := range dataMigrations(phones) {
+		var count int64
+		if err := db.Model(&gormmodels.SchemaMigration{}).Where("id = ?", m.ID).Count(&count).Error; err != nil {
+			return fmt.Errorf("check migration %s: %w", m.ID, err)
+		}
+		if count > 0 {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Run(tx); err != nil {
+				return err
+			}
+			return tx.Create(&gormmodels.SchemaMigration{ID: m.ID, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("apply migration %s: %w", m.ID, err)
+		}
+		logger.Info(context.Background(), "data migration applied", map[string]interface{}{
+			"migration": m.ID,
+		}, "")
+	}
+	return nil
+}
+
+// normalizeStoredPhones rewrites stored phone numbers to E.164 so lookups match across input formats.
+func normalizeStoredPhones(phones phone.Normalizer) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		columns := []struct {
+			table  string
+			column string
+		}{
+			{"parcels", "receiver_phone"},
+			{"parcels", "sender_phone"},
+			{"parcel_otps", "phone"},
+			{"users", "phone"},
+		}
+		for _, c := range columns {
+			var values []string
+			if err := tx.Table(c.table).
+				Distinct(c.column).
+				Where(c.column+" NOT LIKE ?", "+%").
+				Pluck(c.column, &values).Error; err != nil {
+				return fmt.Errorf("load %s.%s: %w", c.table, c.column, err)
+			}
+			var updated, skipped int
+			for _, raw := range values {
+				normalized, err := phones.Normalize(raw)
+				if err != nil {
+					skipped++
+					continue
+				}
+				if err := tx.Table(c.table).
+					Where(c.column+" = ?", raw).
+					Update(c.column, normalized).Error; err != nil {
+					return fmt.Errorf("update %s.%s: %w", c.table, c.column, err)
+				}
+				updated++
+			}
+			logger.Info(context.Background(), "phone normalisation migrated column", map[string]interface{}{
+				"table":   c.table,
+				"column":  c.column,
+				"updated": updated,
+				"skipped": skipped,
+			}, "")
+		}
+		return nil
+	}
+}
+
+// promoteLegacyAdmins maps the single legacy ADMIN role to SUPER_ADMIN, which keeps every
+// existing admin's access unchanged under role-based access control.
+func promoteLegacyAdmins(tx *gorm.DB) error {
+	res := tx.Model(&gormmodels.Admin{}).Where("role = ?", "ADMIN").Update("role", "SUPER_ADMIN")
+	if res.Error != nil {
+		return fmt.Errorf("update admins.role: %w", res.Error)
+	}
+	logger.Info(context.Background(), "legacy admin roles migrated", map[string]interface{}{
+		"updated": res.RowsAffected,
+	}, "")
+	return nil
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ClaimDue leases up to limit rows of T in pendingStatus whose next_attempt_at is due, oldest
+// first, by pushing next_attempt_at to leaseUntil. Rows locked by another claim are skipped, so
+// concurrent dispatchers never pick the same row. T's table needs id, status, next_attempt_at and
+// updated_at columns; id returns a row's id.
+func ClaimDue[T any](ctx context.Context, db *gorm.DB, pendingStatus string, now, leaseUntil time.Time, limit int, id func(T) uuid.UUID) ([]T, error) {
+	var models []T
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", pendingStatus, now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&models).Error; err != nil {
+			return err
+		}
+		if len(models) == 0 {
+			return nil
+		}
+		ids := make([]uuid.UUID, 0, len(models))
+		for _, m := range models {
+			ids = append(ids, id(m))
+		}
+		return tx.Model(new(T)).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"next_attempt_at": leaseUntil,
+				"updated_at":      now,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return models, nil
+}
@@ -3,16 +3,21 @@ package database
 import (
 	"fmt"
 	gormmodels "smart-parcel-locker/backend/infrastructure/persistence/gorm/models"
+	"smart-parcel-locker/backend/pkg/phone"
 
 	"gorm.io/gorm"
 )
 
-// Prepare applies DB-level setup (extensions + migrations).
-func Prepare(db *gorm.DB) error {
+// Prepare applies DB-level setup (extensions + migrations). phones normalises stored phone
+// numbers during the data migrations.
+func Prepare(db *gorm.DB, phones phone.Normalizer) error {
 	if err := ensureUUIDExtension(db); err != nil {
 		return err
 	}
-	return AutoMigrateSchema(db)
+	if err := AutoMigrateSchema(db); err != nil {
+		return err
+	}
+	return ApplyDataMigrations(db, phones)
 }
 
 // AutoMigrateSchema runs schema migration for core entities using schema models only.
@@ -25,6 +30,30 @@ func AutoMigrateSchema(db *gorm.DB) error {
 		&gormmodels.Parcel{},
 		&gormmodels.ParcelEvent{},
 		&gormmodels.ParcelOTP{},
+		&gormmodels.RateLimitHit{},
+		&gormmodels.SchemaMigration{},
+		&gormmodels.PickupToken{},
+		&gormmodels.PickupTokenDenylist{},
+		&gormmodels.NotificationPreference{},
+		&gormmodels.ParcelReminder{},
+		&gormmodels.ReminderPolicy{},
+		&gormmodels.NotificationOutbox{},
+		&gormmodels.NotificationTemplate{},
+		&gormmodels.WebhookSubscription{},
+		&gormmodels.WebhookDelivery{},
+		&gormmodels.Admin{},
+		&gormmodels.AdminSession{},
+		&gormmodels.AdminSetupToken{},
+		&gormmodels.AdminLocation{},
+		&gormmodels.AdminRecoveryCode{},
+		&gormmodels.AuditLog{},
+		&gormmodels.LockerDevice{},
+		&gormmodels.DeviceNonce{},
+		&gormmodels.CompartmentStatusChange{},
+		&gormmodels.CompartmentEvent{},
+		&gormmodels.Incident{},
+		&gormmodels.LockerHeartbeat{},
+		&gormmodels.LockerStatusChange{},
 	); err != nil {
 		return fmt.Errorf("auto migrate: %w", err)
 	}
@@ -0,0 +1,148 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"smart-parcel-locker/backend/domain/device"
+	gormmodels "smart-parcel-locker/backend/infrastructure/persistence/gorm/models"
+)
+
+// GormRepository stores kiosk devices and their request nonces.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) WithDB(db *gorm.DB) device.Repository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) Create(ctx context.Context, d *device.Device) (*device.Device, error) {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = time.Now()
+	}
+	model := mapDeviceToModel(d)
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return nil, err
+	}
+	return mapDeviceModelToDomain(model), nil
+}
+
+func (r *GormRepository) GetByID(ctx context.Context, id uuid.UUID) (*device.Device, error) {
+	var model gormmodels.LockerDevice
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, device.ErrDeviceNotFound
+		}
+		return nil, err
+	}
+	return mapDeviceModelToDomain(model), nil
+}
+
+func (r *GormRepository) ListByLocker(ctx context.Context, lockerID uuid.UUID) ([]*device.Device, error) {
+	var models []gormmodels.LockerDevice
+	if err := r.db.WithContext(ctx).
+		Where("locker_id = ?", lockerID).
+		Order("created_at ASC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	items := make([]*device.Device, 0, len(models))
+	for _, m := range models {
+		items = append(items, mapDeviceModelToDomain(m))
+	}
+	return items, nil
+}
+
+func (r *GormRepository) UpdateSecret(ctx context.Context, id uuid.UUID, secret string, at time.Time) error {
+	return r.update(ctx, id, map[string]interface{}{
+		"secret":            secret,
+		"secret_rotated_at": at,
+		"updated_at":        at,
+	})
+}
+
+func (r *GormRepository) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.update(ctx, id, map[string]interface{}{
+		"revoked_at": at,
+		"updated_at": at,
+	})
+}
+
+func (r *GormRepository) TouchLastSeen(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&gormmodels.LockerDevice{}).
+		Where("id = ?", id).
+		Update("last_seen_at", at).Error
+}
+
+func (r *GormRepository) update(ctx context.Context, id uuid.UUID, values map[string]interface{}) error {
+	res := r.db.WithContext(ctx).Model(&gormmodels.LockerDevice{}).
+		Where("id = ?", id).
+		Updates(values)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return device.ErrDeviceNotFound
+	}
+	return nil
+}
+
+func (r *GormRepository) UseNonce(ctx context.Context, deviceID uuid.UUID, nonce string, expiresAt time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&gormmodels.DeviceNonce{
+			DeviceID:  deviceID,
+			Nonce:     nonce,
+			ExpiresAt: expiresAt,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *GormRepository) PurgeNonces(ctx context.Context, before time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Delete(&gormmodels.DeviceNonce{}, "expires_at < ?", before)
+	return res.RowsAffected, res.Error
+}
+
+func mapDeviceToModel(d *device.Device) gormmodels.LockerDevice {
+	return gormmodels.LockerDevice{
+		ID:              d.ID,
+		LockerID:        d.LockerID,
+		Name:            d.Name,
+		Secret:          d.Secret,
+		SecretRotatedAt: d.SecretRotatedAt,
+		LastSeenAt:      d.LastSeenAt,
+		RevokedAt:       d.RevokedAt,
+		CreatedAt:       d.CreatedAt,
+		UpdatedAt:       d.UpdatedAt,
+	}
+}
+
+func mapDeviceModelToDomain(m gormmodels.LockerDevice) *device.Device {
+	return &device.Device{
+		ID:              m.ID,
+		LockerID:        m.LockerID,
+		Name:            m.Name,
+		Secret:          m.Secret,
+		SecretRotatedAt: m.SecretRotatedAt,
+		LastSeenAt:      m.LastSeenAt,
+		RevokedAt:       m.RevokedAt,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+	}
+}
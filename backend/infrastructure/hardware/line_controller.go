@@ -0,0 +1,159 @@
+package hardware
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"smart-parcel-locker/backend/domain/hardware"
+	"smart-parcel-locker/backend/pkg/logger"
+)
+
+// MaxTimeout caps the time one command may take. Deposits and pickups call Open while they hold
+// row locks on the compartment, parcel and locker, and the board answers one command at a time,
+// so a board that stops answering stalls every deposit and pickup on it for up to this long.
+const MaxTimeout = 5 * time.Second
+
+// LineController talks to a controller board over TCP or a serial port with a line-based
+// command protocol. One command is sent per connection and answered with one line:
+//
+//	OPEN <locker_id> <compartment_no>   -> OK | ERR <reason>
+//	DOOR <locker_id> <compartment_no>   -> OPEN | CLOSED
+//	SENSE <locker_id> <compartment_no>  -> OCCUPIED | EMPTY
+//
+// The command set is a stub to be replaced by the vendor's board protocol; the connection
+// handling, timeouts and error mapping are meant to stay.
+type LineController struct {
+	name    string
+	dial    func(ctx context.Context) (io.ReadWriteCloser, error)
+	timeout time.Duration
+	// Boards answer one request per link at a time, so commands for different compartments queue
+	// here behind a slow one.
+	mu sync.Mutex
+}
+
+// NewTCPController returns a controller for a board, or a serial-to-Ethernet bridge, listening
+// at address.
+func NewTCPController(address string, timeout time.Duration) *LineController {
+	return &LineController{
+		name:    "tcp",
+		timeout: capTimeout(timeout),
+		dial: func(ctx context.Context) (io.ReadWriteCloser, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "tcp", address)
+		},
+	}
+}
+
+// NewSerialController returns a controller for a board on a serial device such as /dev/ttyUSB0.
+// Baud rate and framing must be configured on the port beforehand, e.g. with stty.
+func NewSerialController(device string, timeout time.Duration) *LineController {
+	return &LineController{
+		name:    "serial",
+		timeout: capTimeout(timeout),
+		dial: func(ctx context.Context) (io.ReadWriteCloser, error) {
+			return os.OpenFile(device, os.O_RDWR, 0)
+		},
+	}
+}
+
+// capTimeout bounds timeout to MaxTimeout; zero or negative means MaxTimeout.
+func capTimeout(timeout time.Duration) time.Duration {
+	if timeout <= 0 || timeout > MaxTimeout {
+		return MaxTimeout
+	}
+	return timeout
+}
+
+func (c *LineController) Open(ctx context.Context, ref hardware.CompartmentRef) error {
+	reply, err := c.command(ctx, "OPEN", ref)
+	if err != nil {
+		return err
+	}
+	if reply == "OK" {
+		return nil
+	}
+	logger.Warn(ctx, "lock controller refused open", map[string]interface{}{
+		"driver":        c.name,
+		"lockerId":      ref.LockerID.String(),
+		"compartmentNo": ref.CompartmentNo,
+		"reply":         reply,
+	}, "")
+	return hardware.ErrDoorOpenFailed
+}
+
+func (c *LineController) DoorState(ctx context.Context, ref hardware.CompartmentRef) (hardware.DoorState, error) {
+	reply, err := c.command(ctx, "DOOR", ref)
+	if err != nil {
+		return hardware.DoorUnknown, err
+	}
+	switch state := hardware.DoorState(reply); state {
+	case hardware.DoorOpen, hardware.DoorClosed:
+		return state, nil
+	default:
+		return hardware.DoorUnknown, fmt.Errorf("lock controller: unexpected door reply %q", reply)
+	}
+}
+
+func (c *LineController) Occupancy(ctx context.Context, ref hardware.CompartmentRef) (hardware.Occupancy, error) {
+	reply, err := c.command(ctx, "SENSE", ref)
+	if err != nil {
+		return hardware.OccupancyUnknown, err
+	}
+	switch occupancy := hardware.Occupancy(reply); occupancy {
+	case hardware.OccupancyEmpty, hardware.OccupancyOccupied:
+		return occupancy, nil
+	default:
+		return hardware.OccupancyUnknown, fmt.Errorf("lock controller: unexpected sensor reply %q", reply)
+	}
+}
+
+// command sends one command and returns the trimmed reply line. Connection failures and
+// timeouts are reported as ErrControllerUnavailable, except a missing answer to OPEN, which is
+// ErrDoorOutcomeUnknown because the board may have acted on it.
+func (c *LineController) command(ctx context.Context, verb string, ref hardware.CompartmentRef) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return "", c.fail(ctx, verb, ref, err, hardware.ErrControllerUnavailable)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if d, ok := conn.(interface{ SetDeadline(time.Time) error }); ok {
+			_ = d.SetDeadline(deadline)
+		}
+	}
+
+	if _, err := fmt.Fprintf(conn, "%s %s %d\r\n", verb, ref.LockerID, ref.CompartmentNo); err != nil {
+		return "", c.fail(ctx, verb, ref, err, hardware.ErrControllerUnavailable)
+	}
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil && line == "" {
+		if verb == "OPEN" {
+			return "", c.fail(ctx, verb, ref, err, hardware.ErrDoorOutcomeUnknown)
+		}
+		return "", c.fail(ctx, verb, ref, err, hardware.ErrControllerUnavailable)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func (c *LineController) fail(ctx context.Context, verb string, ref hardware.CompartmentRef, err, result error) error {
+	logger.Error(ctx, "lock controller command failed", map[string]interface{}{
+		"driver":        c.name,
+		"command":       verb,
+		"lockerId":      ref.LockerID.String(),
+		"compartmentNo": ref.CompartmentNo,
+		"error":         err.Error(),
+	}, "")
+	return result
+}
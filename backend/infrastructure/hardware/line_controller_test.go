@@ -0,0 +1,121 @@
+package hardware
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"smart-parcel-locker/backend/domain/hardware"
+)
+
+// serveBoard answers each command with reply(command) until the listener closes.
+func serveBoard(t *testing.T, reply func(command string) string) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			line, _ := bufio.NewReader(conn).ReadString('\n')
+			conn.Write([]byte(reply(strings.TrimSpace(line)) + "\r\n"))
+			conn.Close()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestLineControllerOpen(t *testing.T) {
+	ref := hardware.CompartmentRef{LockerID: uuid.New(), CompartmentNo: 7}
+	commands := make(chan string, 1)
+	addr := serveBoard(t, func(command string) string {
+		commands <- command
+		return "OK"
+	})
+	c := NewTCPController(addr, time.Second)
+	if err := c.Open(context.Background(), ref); err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if want, got := "OPEN "+ref.LockerID.String()+" 7", <-commands; got != want {
+		t.Fatalf("expected command %q, got %q", want, got)
+	}
+}
+
+func TestLineControllerOpenRefused(t *testing.T) {
+	addr := serveBoard(t, func(string) string { return "ERR jammed" })
+	err := NewTCPController(addr, time.Second).Open(context.Background(), hardware.CompartmentRef{CompartmentNo: 1})
+	if !errors.Is(err, hardware.ErrDoorOpenFailed) {
+		t.Fatalf("expected ErrDoorOpenFailed, got %v", err)
+	}
+}
+
+func TestLineControllerOpenUnanswered(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		// Take the command, then hang up without answering.
+		bufio.NewReader(conn).ReadString('\n')
+		conn.Close()
+	}()
+	err = NewTCPController(ln.Addr().String(), time.Second).Open(context.Background(), hardware.CompartmentRef{CompartmentNo: 1})
+	if !errors.Is(err, hardware.ErrDoorOutcomeUnknown) {
+		t.Fatalf("expected ErrDoorOutcomeUnknown, got %v", err)
+	}
+}
+
+func TestLineControllerUnreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	_, err = NewTCPController(addr, time.Second).DoorState(context.Background(), hardware.CompartmentRef{CompartmentNo: 1})
+	if !errors.Is(err, hardware.ErrControllerUnavailable) {
+		t.Fatalf("expected ErrControllerUnavailable, got %v", err)
+	}
+}
+
+func TestLineControllerCapsTimeout(t *testing.T) {
+	for _, timeout := range []time.Duration{0, time.Minute} {
+		if got := NewTCPController("127.0.0.1:0", timeout).timeout; got != MaxTimeout {
+			t.Fatalf("timeout %s: expected %s, got %s", timeout, MaxTimeout, got)
+		}
+	}
+	if got := NewSerialController("/dev/null", time.Second).timeout; got != time.Second {
+		t.Fatalf("expected a timeout under the cap to be kept, got %s", got)
+	}
+}
+
+func TestSimulatorFailOpen(t *testing.T) {
+	sim := NewSimulator(0)
+	ref := hardware.CompartmentRef{CompartmentID: uuid.New(), CompartmentNo: 3}
+	sim.FailOpen(ref.CompartmentID, 1)
+	if err := sim.Open(context.Background(), ref); !errors.Is(err, hardware.ErrDoorOpenFailed) {
+		t.Fatalf("expected ErrDoorOpenFailed, got %v", err)
+	}
+	if err := sim.Open(context.Background(), ref); err != nil {
+		t.Fatalf("second open: %v", err)
+	}
+	if state, _ := sim.DoorState(context.Background(), ref); state != hardware.DoorOpen {
+		t.Fatalf("expected door OPEN, got %s", state)
+	}
+}
@@ -0,0 +1,95 @@
+package hardware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"smart-parcel-locker/backend/domain/hardware"
+	"smart-parcel-locker/backend/pkg/logger"
+)
+
+// Simulator is an in-process LockController for development and tests. Doors open instantly
+// and close again after AutoClose; occupancy is whatever SetOccupancy last reported.
+type Simulator struct {
+	mu        sync.Mutex
+	autoClose time.Duration
+	doors     map[uuid.UUID]hardware.DoorState
+	occupancy map[uuid.UUID]hardware.Occupancy
+	failures  map[uuid.UUID]int
+}
+
+// NewSimulator returns a simulator whose doors close autoClose after opening; zero leaves them
+// open until Close is called.
+func NewSimulator(autoClose time.Duration) *Simulator {
+	return &Simulator{
+		autoClose: autoClose,
+		doors:     make(map[uuid.UUID]hardware.DoorState),
+		occupancy: make(map[uuid.UUID]hardware.Occupancy),
+		failures:  make(map[uuid.UUID]int),
+	}
+}
+
+func (s *Simulator) Open(ctx context.Context, ref hardware.CompartmentRef) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures[ref.CompartmentID] > 0 {
+		s.failures[ref.CompartmentID]--
+		logger.Warn(ctx, "lock simulator door jammed", map[string]interface{}{
+			"lockerId":      ref.LockerID.String(),
+			"compartmentNo": ref.CompartmentNo,
+		}, "")
+		return hardware.ErrDoorOpenFailed
+	}
+	s.doors[ref.CompartmentID] = hardware.DoorOpen
+	logger.Info(ctx, "lock simulator door opened", map[string]interface{}{
+		"lockerId":      ref.LockerID.String(),
+		"compartmentNo": ref.CompartmentNo,
+	}, "")
+	if s.autoClose > 0 {
+		id := ref.CompartmentID
+		time.AfterFunc(s.autoClose, func() { s.Close(id) })
+	}
+	return nil
+}
+
+func (s *Simulator) DoorState(ctx context.Context, ref hardware.CompartmentRef) (hardware.DoorState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if state, ok := s.doors[ref.CompartmentID]; ok {
+		return state, nil
+	}
+	return hardware.DoorClosed, nil
+}
+
+func (s *Simulator) Occupancy(ctx context.Context, ref hardware.CompartmentRef) (hardware.Occupancy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if occupancy, ok := s.occupancy[ref.CompartmentID]; ok {
+		return occupancy, nil
+	}
+	return hardware.OccupancyUnknown, nil
+}
+
+// Close shuts a door, as if someone pushed it to.
+func (s *Simulator) Close(compartmentID uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.doors[compartmentID] = hardware.DoorClosed
+}
+
+// SetOccupancy sets what the presence sensor of a compartment reports.
+func (s *Simulator) SetOccupancy(compartmentID uuid.UUID, occupancy hardware.Occupancy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.occupancy[compartmentID] = occupancy
+}
+
+// FailOpen makes the next times opens of a compartment fail with ErrDoorOpenFailed.
+func (s *Simulator) FailOpen(compartmentID uuid.UUID, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[compartmentID] = times
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 
 	"smart-parcel-locker/backend/pkg/config"
 )
@@ -12,12 +13,15 @@ import (
 // NewFiberApp configures the Fiber application.
 func NewFiberApp(cfg *config.Config) *fiber.App {
 	app := fiber.New(fiber.Config{
-		AppName: fmt.Sprintf("%s-%s", cfg.App.Name, cfg.App.Env),
+		AppName:     fmt.Sprintf("%s-%s", cfg.App.Name, cfg.App.Env),
+		ProxyHeader: cfg.HTTP.ProxyHeader,
 	})
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
 		AllowHeaders: "*",
 		AllowMethods: "GET,POST,PATCH,PUT,DELETE,OPTIONS",
 	}))
+	// Every response carries an X-Request-ID; admin changes store it in the audit log.
+	app.Use(requestid.New())
 	return app
 }
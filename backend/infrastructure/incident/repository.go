@@ -0,0 +1,154 @@
+package incident
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"smart-parcel-locker/backend/domain/incident"
+	gormmodels "smart-parcel-locker/backend/infrastructure/persistence/gorm/models"
+)
+
+// GormRepository stores compartment incidents.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) WithDB(db *gorm.DB) incident.Repository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) Open(ctx context.Context, inc *incident.Incident) (bool, error) {
+	if inc.ID == uuid.Nil {
+		inc.ID = uuid.New()
+	}
+	model := mapIncidentToModel(inc)
+	// uidx_incidents_open only covers open incidents, so the conflict target repeats its predicate.
+	res := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "compartment_id"}, {Name: "incident_type"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "status", Value: incident.StatusOpen}}},
+			DoNothing:   true,
+		}).
+		Create(&model)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *GormRepository) GetByID(ctx context.Context, id uuid.UUID) (*incident.Incident, error) {
+	var model gormmodels.Incident
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, incident.ErrIncidentNotFound
+		}
+		return nil, err
+	}
+	return mapIncidentModelToDomain(model), nil
+}
+
+func (r *GormRepository) List(ctx context.Context, filter incident.Filter) ([]*incident.Incident, int64, error) {
+	query := r.db.WithContext(ctx).Model(&gormmodels.Incident{})
+	if filter.Status != "" {
+		query = query.Where("incidents.status = ?", filter.Status)
+	}
+	if filter.Type != "" {
+		query = query.Where("incidents.incident_type = ?", filter.Type)
+	}
+	if filter.LockerID != nil {
+		query = query.Where("incidents.locker_id = ?", *filter.LockerID)
+	}
+	if len(filter.LocationIDs) > 0 {
+		query = query.
+			Joins("JOIN lockers ON lockers.id = incidents.locker_id").
+			Where("lockers.location_id IN ?", filter.LocationIDs)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var models []gormmodels.Incident
+	if err := query.
+		Select("incidents.*").
+		Order("incidents.opened_at DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+	items := make([]*incident.Incident, 0, len(models))
+	for _, m := range models {
+		items = append(items, mapIncidentModelToDomain(m))
+	}
+	return items, total, nil
+}
+
+func (r *GormRepository) Resolve(ctx context.Context, id uuid.UUID, at time.Time, by *uuid.UUID, resolution string) error {
+	res := r.db.WithContext(ctx).Model(&gormmodels.Incident{}).
+		Where("id = ? AND status = ?", id, incident.StatusOpen).
+		Updates(map[string]interface{}{
+			"status":      incident.StatusResolved,
+			"resolved_at": at,
+			"resolved_by": by,
+			"resolution":  resolution,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return incident.ErrAlreadyResolved
+	}
+	return nil
+}
+
+func (r *GormRepository) ResolveOpen(ctx context.Context, compartmentID uuid.UUID, incidentType string, at time.Time, resolution string) (int64, error) {
+	res := r.db.WithContext(ctx).Model(&gormmodels.Incident{}).
+		Where("compartment_id = ? AND incident_type = ? AND status = ?", compartmentID, incidentType, incident.StatusOpen).
+		Updates(map[string]interface{}{
+			"status":      incident.StatusResolved,
+			"resolved_at": at,
+			"resolution":  resolution,
+		})
+	return res.RowsAffected, res.Error
+}
+
+func mapIncidentToModel(inc *incident.Incident) gormmodels.Incident {
+	return gormmodels.Incident{
+		ID:            inc.ID,
+		IncidentType:  inc.Type,
+		Status:        inc.Status,
+		LockerID:      inc.LockerID,
+		CompartmentID: inc.CompartmentID,
+		ParcelID:      inc.ParcelID,
+		Detail:        inc.Detail,
+		OpenedAt:      inc.OpenedAt,
+		ResolvedAt:    inc.ResolvedAt,
+		ResolvedBy:    inc.ResolvedBy,
+		Resolution:    inc.Resolution,
+	}
+}
+
+func mapIncidentModelToDomain(m gormmodels.Incident) *incident.Incident {
+	return &incident.Incident{
+		ID:            m.ID,
+		Type:          m.IncidentType,
+		Status:        m.Status,
+		LockerID:      m.LockerID,
+		CompartmentID: m.CompartmentID,
+		ParcelID:      m.ParcelID,
+		Detail:        m.Detail,
+		OpenedAt:      m.OpenedAt,
+		ResolvedAt:    m.ResolvedAt,
+		ResolvedBy:    m.ResolvedBy,
+		Resolution:    m.Resolution,
+	}
+}
@@ -26,12 +26,13 @@ func (r *GormRepository) WithDB(db *gorm.DB) location.Repository {
 
 func (r *GormRepository) Create(ctx context.Context, loc *location.Location) (*location.Location, error) {
 	model := gormmodels.Location{
-		ID:        loc.ID,
-		Code:      loc.Code,
-		Name:      loc.Name,
-		Address:   loc.Address,
-		IsActive:  loc.IsActive,
-		CreatedAt: time.Now(),
+		ID:            loc.ID,
+		Code:          loc.Code,
+		Name:          loc.Name,
+		Address:       loc.Address,
+		IsActive:      loc.IsActive,
+		DefaultLocale: loc.DefaultLocale,
+		CreatedAt:     time.Now(),
 	}
 	if model.ID == uuid.Nil {
 		model.ID = uuid.New()
@@ -50,6 +51,48 @@ func (r *GormRepository) GetByID(ctx context.Context, id uuid.UUID) (*location.L
 	return mapLocationModelToDomain(model), nil
 }
 
+func (r *GormRepository) GetByCode(ctx context.Context, code string) (*location.Location, error) {
+	var model gormmodels.Location
+	if err := r.db.WithContext(ctx).First(&model, "code = ?", code).Error; err != nil {
+		return nil, err
+	}
+	return mapLocationModelToDomain(model), nil
+}
+
+func (r *GormRepository) Update(ctx context.Context, loc *location.Location) (*location.Location, error) {
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&gormmodels.Location{}).
+		Where("id = ?", loc.ID).
+		Select("code", "name", "address", "is_active", "default_locale", "updated_at").
+		Updates(gormmodels.Location{
+			Code:          loc.Code,
+			Name:          loc.Name,
+			Address:       loc.Address,
+			IsActive:      loc.IsActive,
+			DefaultLocale: loc.DefaultLocale,
+			UpdatedAt:     &now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.GetByID(ctx, loc.ID)
+}
+
+func (r *GormRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&gormmodels.Location{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 func (r *GormRepository) List(ctx context.Context) ([]location.Location, error) {
 	var models []gormmodels.Location
 	if err := r.db.WithContext(ctx).Find(&models).Error; err != nil {
@@ -72,12 +115,13 @@ func (r *GormRepository) Count(ctx context.Context) (int64, error) {
 
 func mapLocationModelToDomain(model gormmodels.Location) *location.Location {
 	return &location.Location{
-		ID:        model.ID,
-		Code:      model.Code,
-		Name:      model.Name,
-		Address:   model.Address,
-		IsActive:  model.IsActive,
-		CreatedAt: model.CreatedAt,
-		UpdatedAt: model.UpdatedAt,
+		ID:            model.ID,
+		Code:          model.Code,
+		Name:          model.Name,
+		Address:       model.Address,
+		IsActive:      model.IsActive,
+		DefaultLocale: model.DefaultLocale,
+		CreatedAt:     model.CreatedAt,
+		UpdatedAt:     model.UpdatedAt,
 	}
 }
@@ -2,10 +2,12 @@ package locker
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"smart-parcel-locker/backend/domain/compartment"
 	"smart-parcel-locker/backend/domain/locker"
@@ -51,6 +53,73 @@ func (r *GormRepository) GetByID(ctx context.Context, lockerID uuid.UUID) (*lock
 	return mapLockerModelToDomain(lockerModel, nil, nil), nil
 }
 
+func (r *GormRepository) GetByIDForShare(ctx context.Context, lockerID uuid.UUID) (*locker.Locker, error) {
+	return r.getLocked(ctx, lockerID, clause.Locking{Strength: "SHARE"})
+}
+
+func (r *GormRepository) GetByIDForUpdate(ctx context.Context, lockerID uuid.UUID) (*locker.Locker, error) {
+	return r.getLocked(ctx, lockerID, clause.Locking{Strength: "UPDATE"})
+}
+
+func (r *GormRepository) getLocked(ctx context.Context, lockerID uuid.UUID, locking clause.Locking) (*locker.Locker, error) {
+	var lockerModel gormmodels.Locker
+	if err := r.db.WithContext(ctx).Clauses(locking).First(&lockerModel, "id = ?", lockerID).Error; err != nil {
+		return nil, err
+	}
+	return mapLockerModelToDomain(lockerModel, nil, nil), nil
+}
+
+func (r *GormRepository) GetByCode(ctx context.Context, lockerCode string) (*locker.Locker, error) {
+	var lockerModel gormmodels.Locker
+	if err := r.db.WithContext(ctx).First(&lockerModel, "locker_code = ?", lockerCode).Error; err != nil {
+		return nil, err
+	}
+	return mapLockerModelToDomain(lockerModel, nil, nil), nil
+}
+
+func (r *GormRepository) ListByLocation(ctx context.Context, locationID uuid.UUID) ([]locker.Locker, error) {
+	var models []gormmodels.Locker
+	if err := r.db.WithContext(ctx).Where("location_id = ?", locationID).Order("created_at asc").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	result := make([]locker.Locker, 0, len(models))
+	for _, m := range models {
+		result = append(result, *mapLockerModelToDomain(m, nil, nil))
+	}
+	return result, nil
+}
+
+func (r *GormRepository) Update(ctx context.Context, l *locker.Locker) (*locker.Locker, error) {
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&gormmodels.Locker{}).
+		Where("id = ?", l.ID).
+		Select("locker_code", "name", "updated_at").
+		Updates(gormmodels.Locker{
+			LockerCode: l.LockerCode,
+			Name:       ptrString(l.Name),
+			UpdatedAt:  &now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.GetByID(ctx, l.ID)
+}
+
+func (r *GormRepository) Delete(ctx context.Context, lockerID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&gormmodels.Locker{}, "id = ?", lockerID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 func (r *GormRepository) List(ctx context.Context) ([]locker.Locker, error) {
 	var models []gormmodels.Locker
 	if err := r.db.WithContext(ctx).Order("created_at asc").Find(&models).Error; err != nil {
@@ -183,3 +252,103 @@ func ptrString(s string) *string {
 	}
 	return &s
 }
+
+func (r *GormRepository) TransitionStatus(ctx context.Context, lockerID uuid.UUID, from, to string) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&gormmodels.Locker{}).
+		Where("id = ? AND status = ?", lockerID, from).
+		Updates(map[string]interface{}{
+			"status":     to,
+			"updated_at": time.Now(),
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *GormRepository) CreateStatusChange(ctx context.Context, change *locker.StatusChange) error {
+	model := gormmodels.LockerStatusChange{
+		ID:         change.ID,
+		LockerID:   change.LockerID,
+		FromStatus: change.FromStatus,
+		ToStatus:   change.ToStatus,
+		Reason:     change.Reason,
+		ActorID:    change.ActorID,
+		CreatedAt:  change.CreatedAt,
+	}
+	return r.db.WithContext(ctx).Create(&model).Error
+}
+
+func (r *GormRepository) ListStatusChanges(ctx context.Context, lockerID uuid.UUID, limit int) ([]locker.StatusChange, error) {
+	var models []gormmodels.LockerStatusChange
+	if err := r.db.WithContext(ctx).
+		Where("locker_id = ?", lockerID).
+		Order("created_at desc").
+		Limit(limit).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	result := make([]locker.StatusChange, 0, len(models))
+	for _, m := range models {
+		result = append(result, locker.StatusChange{
+			ID:         m.ID,
+			LockerID:   m.LockerID,
+			FromStatus: m.FromStatus,
+			ToStatus:   m.ToStatus,
+			Reason:     m.Reason,
+			ActorID:    m.ActorID,
+			CreatedAt:  m.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (r *GormRepository) SaveHeartbeat(ctx context.Context, hb *locker.Heartbeat) error {
+	model := gormmodels.LockerHeartbeat{
+		LockerID:         hb.LockerID,
+		DeviceID:         hb.DeviceID,
+		FirmwareVersion:  hb.FirmwareVersion,
+		TemperatureC:     hb.TemperatureC,
+		ControllerHealth: hb.ControllerHealth,
+		ReceivedAt:       hb.ReceivedAt,
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "locker_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"device_id", "firmware_version", "temperature_c", "controller_health", "received_at"}),
+		}).
+		Create(&model).Error
+}
+
+func (r *GormRepository) GetHeartbeat(ctx context.Context, lockerID uuid.UUID) (*locker.Heartbeat, error) {
+	var model gormmodels.LockerHeartbeat
+	if err := r.db.WithContext(ctx).First(&model, "locker_id = ?", lockerID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &locker.Heartbeat{
+		LockerID:         model.LockerID,
+		DeviceID:         model.DeviceID,
+		FirmwareVersion:  model.FirmwareVersion,
+		TemperatureC:     model.TemperatureC,
+		ControllerHealth: model.ControllerHealth,
+		ReceivedAt:       model.ReceivedAt,
+	}, nil
+}
+
+func (r *GormRepository) ListSilentSince(ctx context.Context, before time.Time) ([]locker.Locker, error) {
+	var models []gormmodels.Locker
+	if err := r.db.WithContext(ctx).
+		Joins("JOIN locker_heartbeats ON locker_heartbeats.locker_id = lockers.id").
+		Where("lockers.status = ? AND locker_heartbeats.received_at < ?", locker.StatusActive, before).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	result := make([]locker.Locker, 0, len(models))
+	for _, m := range models {
+		result = append(result, *mapLockerModelToDomain(m, nil, nil))
+	}
+	return result, nil
+}
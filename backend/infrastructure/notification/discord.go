@@ -1,57 +1,46 @@
 package notification
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
-)
 
-const discordWebhookURL = "https://discord.com/api/webhooks/1450788972123914321/ySG_m2Nc-Gg0XIhYv9OVlxBswZMsG9v8i1myqSDjNGyJhcHOrSmWnlymIq5olMNVG1JT"
+	notificationdomain "smart-parcel-locker/backend/domain/notification"
+)
 
-// DiscordNotifier sends OTP messages to a Discord webhook.
-// TODO: Replace with SMS provider before production.
-type DiscordNotifier struct {
-	client *http.Client
+// DiscordSender posts messages to a Discord webhook. Intended for staging and ops channels.
+type DiscordSender struct {
+	webhookURL string
+	client     *http.Client
 }
 
-// NewDiscordNotifier creates a Discord notifier with a short timeout.
-func NewDiscordNotifier() *DiscordNotifier {
-	return &DiscordNotifier{
-		client: &http.Client{Timeout: 5 * time.Second},
+// NewDiscordSender creates a Discord sender for the given webhook URL.
+func NewDiscordSender(webhookURL string, timeout time.Duration) (*DiscordSender, error) {
+	if webhookURL == "" {
+		return nil, errors.New("discord webhook url is required")
 	}
+	return &DiscordSender{
+		webhookURL: webhookURL,
+		client:     &http.Client{Timeout: timeout},
+	}, nil
 }
 
-// SendOTP posts OTP payload to Discord webhook.
-func (d *DiscordNotifier) SendOTP(ctx context.Context, phone string, otpCode string) error {
-	payload := map[string]string{
-		"content": fmt.Sprintf(
-			"Pickup OTP\nPhone: %s\nOTP: %s\nExpires in 5 minutes",
-			phone,
-			otpCode,
-		),
-	}
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discordWebhookURL, bytes.NewReader(body))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
+func (d *DiscordSender) Channel() notificationdomain.Channel {
+	return notificationdomain.ChannelDiscord
+}
 
-	resp, err := d.client.Do(req)
-	if err != nil {
-		return err
+// Send posts the message content to the Discord webhook.
+func (d *DiscordSender) Send(ctx context.Context, delivery notificationdomain.Delivery) error {
+	content := delivery.Body
+	if delivery.Subject != "" {
+		content = delivery.Subject + "\n" + content
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
-		return fmt.Errorf("discord webhook returned status %d", resp.StatusCode)
+	if delivery.Address != "" {
+		content = fmt.Sprintf("%s\nTo: %s", content, delivery.Address)
 	}
-	return nil
+	return postJSON(ctx, d.client, d.webhookURL, nil, map[string]string{
+		"content": content,
+	})
 }
@@ -0,0 +1,139 @@
+package notification
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	notificationdomain "smart-parcel-locker/backend/domain/notification"
+)
+
+// SMTPConfig configures outbound e-mail.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	// Timeout bounds one delivery, from dial to the end of the message.
+	Timeout time.Duration
+}
+
+// EmailSender delivers messages through an SMTP relay.
+type EmailSender struct {
+	cfg SMTPConfig
+}
+
+func NewEmailSender(cfg SMTPConfig) (*EmailSender, error) {
+	if cfg.Host == "" || cfg.From == "" {
+		return nil, errors.New("smtp host and from address are required")
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &EmailSender{cfg: cfg}, nil
+}
+
+func (e *EmailSender) Channel() notificationdomain.Channel {
+	return notificationdomain.ChannelEmail
+}
+
+func (e *EmailSender) Send(ctx context.Context, delivery notificationdomain.Delivery) error {
+	if delivery.Address == "" || strings.ContainsAny(delivery.Address, "\r\n") {
+		return notificationdomain.ErrInvalidRequest
+	}
+	if err := e.send(ctx, delivery.Address, buildMessage(e.cfg.From, delivery, time.Now())); err != nil {
+		return fmt.Errorf("smtp send: %w", err)
+	}
+	return nil
+}
+
+// buildMessage renders an RFC 5322 message. The subject is MIME encoded because templates may
+// contain non-ASCII text such as Thai.
+func buildMessage(from string, delivery notificationdomain.Delivery, now time.Time) []byte {
+	subject := strings.NewReplacer("\r", " ", "\n", " ").Replace(delivery.Subject)
+	if subject == "" {
+		subject = "Smart Parcel Locker"
+	}
+	return []byte(strings.Join([]string{
+		"From: " + from,
+		"To: " + delivery.Address,
+		"Subject: " + mime.BEncoding.Encode("UTF-8", subject),
+		"Date: " + now.Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		delivery.Body,
+	}, "\r\n"))
+}
+
+// send runs one SMTP session like smtp.SendMail, but on a connection with a deadline that is
+// closed when ctx ends, so a cancelled delivery cannot still complete in the background and
+// be sent twice once the dispatcher retries it.
+func (e *EmailSender) send(ctx context.Context, to string, msg []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, e.cfg.Timeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(e.cfg.Host, strconv.Itoa(e.cfg.Port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, e.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: e.cfg.Host}); err != nil {
+			return err
+		}
+	}
+	if e.cfg.Username != "" {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("server does not support AUTH")
+		}
+		if err := c.Auth(smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.cfg.Host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(e.cfg.From); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	// The relay accepted the message; a failed QUIT must not trigger a second delivery.
+	_ = c.Quit()
+	return nil
+}
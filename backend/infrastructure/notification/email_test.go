@@ -0,0 +1,74 @@
+package notification
+
+import (
+	"context"
+	"mime"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	notificationdomain "smart-parcel-locker/backend/domain/notification"
+)
+
+func TestBuildMessageEncodesSubject(t *testing.T) {
+	subject := "พัสดุของคุณถึงตู้แล้ว"
+	msg := string(buildMessage("noreply@example.com", notificationdomain.Delivery{
+		Address: "receiver@example.com",
+		Subject: subject,
+		Body:    "body",
+	}, time.Now()))
+
+	var header string
+	for _, line := range strings.Split(msg, "\r\n") {
+		if strings.HasPrefix(line, "Subject: ") {
+			header = strings.TrimPrefix(line, "Subject: ")
+		}
+	}
+	for i := 0; i < len(header); i++ {
+		if header[i] >= 0x80 {
+			t.Fatalf("subject header is not ASCII: %q", header)
+		}
+	}
+	decoded, err := new(mime.WordDecoder).DecodeHeader(header)
+	if err != nil || decoded != subject {
+		t.Fatalf("expected the subject to decode to %q, got %q (%v)", subject, decoded, err)
+	}
+}
+
+func TestEmailSenderStopsWhenCancelled(t *testing.T) {
+	// A relay that accepts the connection but never greets.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	closed := make(chan struct{})
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		conn.Read(make([]byte, 1))
+		conn.Close()
+		close(closed)
+	}()
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	portNo, _ := strconv.Atoi(port)
+	sender, err := NewEmailSender(SMTPConfig{Host: host, Port: portNo, From: "noreply@example.com", Timeout: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := sender.Send(ctx, notificationdomain.Delivery{Address: "receiver@example.com"}); err == nil {
+		t.Fatal("expected the cancelled send to fail")
+	}
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("connection left open after the send was cancelled")
+	}
+}
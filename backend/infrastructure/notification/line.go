@@ -0,0 +1,56 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	notificationdomain "smart-parcel-locker/backend/domain/notification"
+)
+
+const defaultLinePushURL = "https://api.line.me/v2/bot/message/push"
+
+// LineConfig configures the LINE Messaging API.
+type LineConfig struct {
+	ChannelAccessToken string
+	PushURL            string
+	Timeout            time.Duration
+}
+
+// LineSender pushes text messages to a LINE user ID.
+type LineSender struct {
+	cfg    LineConfig
+	client *http.Client
+}
+
+func NewLineSender(cfg LineConfig) (*LineSender, error) {
+	if cfg.ChannelAccessToken == "" {
+		return nil, errors.New("line channel access token is required")
+	}
+	if cfg.PushURL == "" {
+		cfg.PushURL = defaultLinePushURL
+	}
+	return &LineSender{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+func (l *LineSender) Channel() notificationdomain.Channel {
+	return notificationdomain.ChannelLine
+}
+
+func (l *LineSender) Send(ctx context.Context, delivery notificationdomain.Delivery) error {
+	if delivery.Address == "" {
+		return notificationdomain.ErrInvalidRequest
+	}
+	return postJSON(ctx, l.client, l.cfg.PushURL, map[string]string{
+		"Authorization": "Bearer " + l.cfg.ChannelAccessToken,
+	}, map[string]interface{}{
+		"to": delivery.Address,
+		"messages": []map[string]string{
+			{"type": "text", "text": delivery.Body},
+		},
+	})
+}
@@ -0,0 +1,34 @@
+package notification
+
+import (
+	"context"
+
+	notificationdomain "smart-parcel-locker/backend/domain/notification"
+	"smart-parcel-locker/backend/pkg/logger"
+)
+
+// LogSender writes messages to the application log. Intended for local development; the body of
+// an OTP message is never logged.
+type LogSender struct{}
+
+func NewLogSender() *LogSender {
+	return &LogSender{}
+}
+
+func (LogSender) Channel() notificationdomain.Channel {
+	return notificationdomain.ChannelLog
+}
+
+func (LogSender) Send(ctx context.Context, delivery notificationdomain.Delivery) error {
+	body := delivery.Body
+	if delivery.MessageType == notificationdomain.TypeOTP {
+		body = "[redacted]"
+	}
+	logger.Info(ctx, "notification log channel delivery", map[string]interface{}{
+		"messageType": delivery.MessageType,
+		"address":     delivery.Address,
+		"subject":     delivery.Subject,
+		"body":        body,
+	}, "")
+	return nil
+}
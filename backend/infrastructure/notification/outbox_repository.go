@@ -0,0 +1,220 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	notificationdomain "smart-parcel-locker/backend/domain/notification"
+	"smart-parcel-locker/backend/infrastructure/database"
+	gormmodels "smart-parcel-locker/backend/infrastructure/persistence/gorm/models"
+)
+
+// GormOutboxRepository stores outbox messages in notification_outbox.
+type GormOutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewGormOutboxRepository(db *gorm.DB) *GormOutboxRepository {
+	return &GormOutboxRepository{db: db}
+}
+
+func (r *GormOutboxRepository) WithDB(db *gorm.DB) notificationdomain.OutboxRepository {
+	return &GormOutboxRepository{db: db}
+}
+
+func (r *GormOutboxRepository) Enqueue(ctx context.Context, msg *notificationdomain.OutboxMessage) error {
+	if msg.ID == uuid.Nil {
+		msg.ID = uuid.New()
+	}
+	data, err := encodeData(msg.Data)
+	if err != nil {
+		return err
+	}
+	model := gormmodels.NotificationOutbox{
+		ID:            msg.ID,
+		Type:          msg.Type,
+		Phone:         msg.Phone,
+		Locale:        string(msg.Locale),
+		Data:          data,
+		Subject:       msg.Subject,
+		Body:          msg.Body,
+		Status:        string(msg.Status),
+		Attempts:      msg.Attempts,
+		NextAttemptAt: msg.NextAttemptAt,
+		ExpiresAt:     msg.ExpiresAt,
+		CreatedAt:     msg.CreatedAt,
+	}
+	return r.db.WithContext(ctx).Create(&model).Error
+}
+
+func (r *GormOutboxRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*notificationdomain.OutboxMessage, error) {
+	models, err := database.ClaimDue(ctx, r.db, string(notificationdomain.OutboxPending), now, leaseUntil, limit,
+		func(m gormmodels.NotificationOutbox) uuid.UUID { return m.ID })
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*notificationdomain.OutboxMessage, 0, len(models))
+	for _, m := range models {
+		results = append(results, mapOutboxModelToDomain(m))
+	}
+	return results, nil
+}
+
+func (r *GormOutboxRepository) MarkSent(ctx context.Context, id uuid.UUID, channel notificationdomain.Channel, at time.Time, redact bool) error {
+	updates := map[string]interface{}{
+		"status":     string(notificationdomain.OutboxSent),
+		"channel":    string(channel),
+		"sent_at":    at,
+		"last_error": nil,
+		"updated_at": at,
+	}
+	if redact {
+		updates["body"] = ""
+		updates["data"] = "{}"
+	}
+	return r.db.WithContext(ctx).Model(&gormmodels.NotificationOutbox{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+func (r *GormOutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, attempts int, nextAttemptAt time.Time, lastError string) error {
+	return r.db.WithContext(ctx).Model(&gormmodels.NotificationOutbox{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        attempts,
+			"next_attempt_at": nextAttemptAt,
+			"last_error":      lastError,
+			"updated_at":      time.Now(),
+		}).Error
+}
+
+func (r *GormOutboxRepository) MarkDead(ctx context.Context, id uuid.UUID, attempts int, lastError string, redact bool) error {
+	updates := map[string]interface{}{
+		"status":     string(notificationdomain.OutboxDead),
+		"attempts":   attempts,
+		"last_error": lastError,
+		"updated_at": time.Now(),
+	}
+	if redact {
+		updates["body"] = ""
+		updates["data"] = "{}"
+	}
+	return r.db.WithContext(ctx).Model(&gormmodels.NotificationOutbox{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+func (r *GormOutboxRepository) GetByID(ctx context.Context, id uuid.UUID) (*notificationdomain.OutboxMessage, error) {
+	var model gormmodels.NotificationOutbox
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, notificationdomain.ErrOutboxNotFound
+		}
+		return nil, err
+	}
+	return mapOutboxModelToDomain(model), nil
+}
+
+func (r *GormOutboxRepository) List(ctx context.Context, filter notificationdomain.OutboxFilter) ([]*notificationdomain.OutboxMessage, int64, error) {
+	query := r.db.WithContext(ctx).Model(&gormmodels.NotificationOutbox{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+	if filter.Phone != "" {
+		query = query.Where("phone = ?", filter.Phone)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var models []gormmodels.NotificationOutbox
+	if err := query.
+		Order("created_at DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+	results := make([]*notificationdomain.OutboxMessage, 0, len(models))
+	for _, m := range models {
+		results = append(results, mapOutboxModelToDomain(m))
+	}
+	return results, total, nil
+}
+
+func (r *GormOutboxRepository) Redrive(ctx context.Context, id uuid.UUID, now time.Time) (*notificationdomain.OutboxMessage, error) {
+	res := r.db.WithContext(ctx).Model(&gormmodels.NotificationOutbox{}).
+		Where("id = ? AND status = ?", id, string(notificationdomain.OutboxDead)).
+		Updates(map[string]interface{}{
+			"status":          string(notificationdomain.OutboxPending),
+			"attempts":        0,
+			"next_attempt_at": now,
+			"updated_at":      now,
+		})
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		existing, err := r.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if existing.Status != notificationdomain.OutboxDead {
+			return nil, notificationdomain.ErrNotRedrivable
+		}
+	}
+	return r.GetByID(ctx, id)
+}
+
+func mapOutboxModelToDomain(model gormmodels.NotificationOutbox) *notificationdomain.OutboxMessage {
+	var channel *notificationdomain.Channel
+	if model.Channel != nil {
+		c := notificationdomain.Channel(*model.Channel)
+		channel = &c
+	}
+	return &notificationdomain.OutboxMessage{
+		ID:            model.ID,
+		Type:          model.Type,
+		Phone:         model.Phone,
+		Locale:        notificationdomain.Locale(model.Locale),
+		Data:          decodeData(model.Data),
+		Subject:       model.Subject,
+		Body:          model.Body,
+		Status:        notificationdomain.OutboxStatus(model.Status),
+		Attempts:      model.Attempts,
+		NextAttemptAt: model.NextAttemptAt,
+		ExpiresAt:     model.ExpiresAt,
+		LastError:     model.LastError,
+		Channel:       channel,
+		SentAt:        model.SentAt,
+		CreatedAt:     model.CreatedAt,
+		UpdatedAt:     model.UpdatedAt,
+	}
+}
+
+func encodeData(data map[string]string) (string, error) {
+	if len(data) == 0 {
+		return "{}", nil
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func decodeData(raw string) map[string]string {
+	if raw == "" || raw == "{}" {
+		return nil
+	}
+	var data map[string]string
+	if err := json.Unmarshal([]byte(raw), &data); err != nil {
+		return nil
+	}
+	return data
+}
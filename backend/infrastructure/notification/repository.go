@@ -0,0 +1,65 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	notificationdomain "smart-parcel-locker/backend/domain/notification"
+	gormmodels "smart-parcel-locker/backend/infrastructure/persistence/gorm/models"
+)
+
+// GormPreferenceRepository provides data access for notification preferences.
+type GormPreferenceRepository struct {
+	db *gorm.DB
+}
+
+func NewGormPreferenceRepository(db *gorm.DB) *GormPreferenceRepository {
+	return &GormPreferenceRepository{db: db}
+}
+
+func (r *GormPreferenceRepository) WithDB(db *gorm.DB) notificationdomain.PreferenceRepository {
+	return &GormPreferenceRepository{db: db}
+}
+
+func (r *GormPreferenceRepository) GetByPhone(ctx context.Context, phone string) (*notificationdomain.Preference, error) {
+	var model gormmodels.NotificationPreference
+	if err := r.db.WithContext(ctx).First(&model, "phone = ?", phone).Error; err != nil {
+		return nil, err
+	}
+	return mapPreferenceModelToDomain(model), nil
+}
+
+func (r *GormPreferenceRepository) Upsert(ctx context.Context, pref *notificationdomain.Preference) (*notificationdomain.Preference, error) {
+	now := time.Now()
+	model := gormmodels.NotificationPreference{
+		Phone:     pref.Phone,
+		Channel:   string(pref.Channel),
+		Address:   pref.Address,
+		Locale:    string(pref.Locale),
+		CreatedAt: now,
+		UpdatedAt: &now,
+	}
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "phone"}},
+			DoUpdates: clause.AssignmentColumns([]string{"channel", "address", "locale", "updated_at"}),
+		}).
+		Create(&model).Error; err != nil {
+		return nil, err
+	}
+	return r.GetByPhone(ctx, pref.Phone)
+}
+
+func mapPreferenceModelToDomain(model gormmodels.NotificationPreference) *notificationdomain.Preference {
+	return &notificationdomain.Preference{
+		Phone:     model.Phone,
+		Channel:   notificationdomain.Channel(model.Channel),
+		Address:   model.Address,
+		Locale:    notificationdomain.Locale(model.Locale),
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+}
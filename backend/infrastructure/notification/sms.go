@@ -0,0 +1,58 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	notificationdomain "smart-parcel-locker/backend/domain/notification"
+)
+
+// SMSConfig configures a generic HTTP SMS gateway.
+type SMSConfig struct {
+	URL        string
+	APIKey     string
+	AuthHeader string // defaults to Authorization with a Bearer prefix
+	SenderName string
+	Timeout    time.Duration
+}
+
+// SMSSender posts {"to","from","message"} JSON to an HTTP SMS provider.
+type SMSSender struct {
+	cfg    SMSConfig
+	client *http.Client
+}
+
+func NewSMSSender(cfg SMSConfig) (*SMSSender, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("sms provider url is required")
+	}
+	return &SMSSender{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+func (s *SMSSender) Channel() notificationdomain.Channel {
+	return notificationdomain.ChannelSMS
+}
+
+func (s *SMSSender) Send(ctx context.Context, delivery notificationdomain.Delivery) error {
+	if delivery.Address == "" {
+		return notificationdomain.ErrInvalidRequest
+	}
+	headers := map[string]string{}
+	if s.cfg.APIKey != "" {
+		if s.cfg.AuthHeader == "" || s.cfg.AuthHeader == "Authorization" {
+			headers["Authorization"] = "Bearer " + s.cfg.APIKey
+		} else {
+			headers[s.cfg.AuthHeader] = s.cfg.APIKey
+		}
+	}
+	return postJSON(ctx, s.client, s.cfg.URL, headers, map[string]string{
+		"to":      delivery.Address,
+		"from":    s.cfg.SenderName,
+		"message": delivery.Body,
+	})
+}
@@ -0,0 +1,89 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	notificationdomain "smart-parcel-locker/backend/domain/notification"
+	gormmodels "smart-parcel-locker/backend/infrastructure/persistence/gorm/models"
+)
+
+// GormTemplateRepository stores admin-edited notification templates.
+type GormTemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewGormTemplateRepository(db *gorm.DB) *GormTemplateRepository {
+	return &GormTemplateRepository{db: db}
+}
+
+func (r *GormTemplateRepository) WithDB(db *gorm.DB) notificationdomain.TemplateRepository {
+	return &GormTemplateRepository{db: db}
+}
+
+func (r *GormTemplateRepository) Get(ctx context.Context, messageType string, locale notificationdomain.Locale) (*notificationdomain.Template, error) {
+	var model gormmodels.NotificationTemplate
+	if err := r.db.WithContext(ctx).
+		First(&model, "type = ? AND locale = ?", messageType, string(locale)).Error; err != nil {
+		return nil, err
+	}
+	return mapTemplateModelToDomain(model), nil
+}
+
+func (r *GormTemplateRepository) List(ctx context.Context) ([]notificationdomain.Template, error) {
+	var models []gormmodels.NotificationTemplate
+	if err := r.db.WithContext(ctx).Order("type ASC, locale ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	result := make([]notificationdomain.Template, 0, len(models))
+	for _, m := range models {
+		result = append(result, *mapTemplateModelToDomain(m))
+	}
+	return result, nil
+}
+
+func (r *GormTemplateRepository) Upsert(ctx context.Context, tmpl *notificationdomain.Template) (*notificationdomain.Template, error) {
+	now := time.Now()
+	model := gormmodels.NotificationTemplate{
+		Type:      tmpl.Type,
+		Locale:    string(tmpl.Locale),
+		Subject:   tmpl.Subject,
+		Body:      tmpl.Body,
+		CreatedAt: now,
+		UpdatedAt: &now,
+	}
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "type"}, {Name: "locale"}},
+			DoUpdates: clause.AssignmentColumns([]string{"subject", "body", "updated_at"}),
+		}).
+		Create(&model).Error; err != nil {
+		return nil, err
+	}
+	return r.Get(ctx, tmpl.Type, tmpl.Locale)
+}
+
+func (r *GormTemplateRepository) Delete(ctx context.Context, messageType string, locale notificationdomain.Locale) error {
+	res := r.db.WithContext(ctx).
+		Delete(&gormmodels.NotificationTemplate{}, "type = ? AND locale = ?", messageType, string(locale))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func mapTemplateModelToDomain(model gormmodels.NotificationTemplate) *notificationdomain.Template {
+	return &notificationdomain.Template{
+		Type:      model.Type,
+		Locale:    notificationdomain.Locale(model.Locale),
+		Subject:   model.Subject,
+		Body:      model.Body,
+		UpdatedAt: model.UpdatedAt,
+	}
+}
@@ -11,6 +11,8 @@ import (
 	gormmodels "smart-parcel-locker/backend/infrastructure/persistence/gorm/models"
 )
 
+const anonymizedPhone = "ANONYMIZED"
+
 // GormRepository provides data access for OTPs.
 type GormRepository struct {
 	db *gorm.DB
@@ -34,6 +36,9 @@ func (r *GormRepository) Create(ctx context.Context, entity *otp.OTP) (*otp.OTP,
 		ExpiresAt:  entity.ExpiresAt,
 		VerifiedAt: entity.VerifiedAt,
 		CreatedAt:  entity.CreatedAt,
+
+		RequestDeviceID: entity.RequestDeviceID,
+		VerifyDeviceID:  entity.VerifyDeviceID,
 	}
 	if model.ID == uuid.Nil {
 		model.ID = uuid.New()
@@ -70,6 +75,9 @@ func (r *GormRepository) Update(ctx context.Context, entity *otp.OTP) (*otp.OTP,
 		ExpiresAt:  entity.ExpiresAt,
 		VerifiedAt: entity.VerifiedAt,
 		CreatedAt:  entity.CreatedAt,
+
+		RequestDeviceID: entity.RequestDeviceID,
+		VerifyDeviceID:  entity.VerifyDeviceID,
 	}
 	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
 		return nil, err
@@ -77,6 +85,35 @@ func (r *GormRepository) Update(ctx context.Context, entity *otp.OTP) (*otp.OTP,
 	return mapModelToDomain(model), nil
 }
 
+// ExpireActive marks ACTIVE OTPs past expires_at as EXPIRED.
+func (r *GormRepository) ExpireActive(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&gormmodels.ParcelOTP{}).
+		Where("expires_at < ? AND status = ?", now, string(otp.StatusActive)).
+		Update("status", string(otp.StatusExpired))
+	return result.RowsAffected, result.Error
+}
+
+// DeleteExpiredBefore removes OTP records that expired before cutoff.
+func (r *GormRepository) DeleteExpiredBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at < ?", cutoff).
+		Delete(&gormmodels.ParcelOTP{})
+	return result.RowsAffected, result.Error
+}
+
+// AnonymizeExpiredBefore strips the phone number and hash from OTP records that expired before cutoff.
+func (r *GormRepository) AnonymizeExpiredBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&gormmodels.ParcelOTP{}).
+		Where("expires_at < ? AND phone <> ?", cutoff, anonymizedPhone).
+		Updates(map[string]interface{}{
+			"phone":    anonymizedPhone,
+			"otp_hash": "",
+		})
+	return result.RowsAffected, result.Error
+}
+
 func mapModelToDomain(model gormmodels.ParcelOTP) *otp.OTP {
 	return &otp.OTP{
 		ID:         model.ID,
@@ -87,5 +124,8 @@ func mapModelToDomain(model gormmodels.ParcelOTP) *otp.OTP {
 		ExpiresAt:  model.ExpiresAt,
 		VerifiedAt: model.VerifiedAt,
 		CreatedAt:  model.CreatedAt,
+
+		RequestDeviceID: model.RequestDeviceID,
+		VerifyDeviceID:  model.VerifyDeviceID,
 	}
 }
@@ -35,6 +35,7 @@ func (r *GormRepository) Create(ctx context.Context, p *parcel.Parcel) (*parcel.
 		Size:          p.Size,
 		ReceiverPhone: p.ReceiverPhone,
 		SenderPhone:   p.SenderPhone,
+		CarrierCode:   p.CarrierCode,
 		PickupCode:    p.PickupCode,
 		Status:        string(p.Status),
 		DepositedAt:   p.DepositedAt,
@@ -83,6 +84,7 @@ func (r *GormRepository) Update(ctx context.Context, p *parcel.Parcel) (*parcel.
 		Size:          p.Size,
 		ReceiverPhone: p.ReceiverPhone,
 		SenderPhone:   p.SenderPhone,
+		CarrierCode:   p.CarrierCode,
 		PickupCode:    p.PickupCode,
 		Status:        string(p.Status),
 		DepositedAt:   p.DepositedAt,
@@ -105,6 +107,7 @@ func (r *GormRepository) CreateEvent(ctx context.Context, event *parcel.Event) e
 		ID:        event.ID,
 		ParcelID:  event.ParcelID,
 		EventType: event.EventType,
+		DeviceID:  event.DeviceID,
 		CreatedAt: event.CreatedAt,
 	}
 	if model.ID == uuid.Nil {
@@ -150,6 +153,64 @@ func (r *GormRepository) ListReadyForPickupByPhone(ctx context.Context, phone st
 	return results, nil
 }
 
+func (r *GormRepository) Search(ctx context.Context, filter parcel.SearchFilter) ([]*parcel.Parcel, int64, error) {
+	query := r.db.WithContext(ctx).Model(&gormmodels.Parcel{})
+	if filter.Phone != "" {
+		query = query.Where("parcels.receiver_phone = ? OR parcels.sender_phone = ?", filter.Phone, filter.Phone)
+	}
+	if filter.ParcelCode != "" {
+		query = query.Where("parcels.parcel_code = ?", filter.ParcelCode)
+	}
+	if filter.Status != "" {
+		query = query.Where("parcels.status = ?", string(filter.Status))
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, 0, len(filter.Statuses))
+		for _, s := range filter.Statuses {
+			statuses = append(statuses, string(s))
+		}
+		query = query.Where("parcels.status IN ?", statuses)
+	}
+	if filter.LockerID != nil {
+		query = query.Where("parcels.locker_id = ?", *filter.LockerID)
+	}
+	if filter.CompartmentID != nil {
+		query = query.Where("parcels.compartment_id = ?", *filter.CompartmentID)
+	}
+	if len(filter.LocationIDs) > 0 {
+		query = query.Joins("JOIN lockers ON lockers.id = parcels.locker_id").
+			Where("lockers.location_id IN ?", filter.LocationIDs)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var models []gormmodels.Parcel
+	if err := query.
+		Order("parcels.created_at DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+	results := make([]*parcel.Parcel, 0, len(models))
+	for _, model := range models {
+		results = append(results, mapParcelModelToDomain(model))
+	}
+	return results, total, nil
+}
+
+func (r *GormRepository) CountActiveInCompartment(ctx context.Context, compartmentID uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&gormmodels.Parcel{}).
+		Where("compartment_id = ? AND status IN ?", compartmentID, []string{string(parcel.StatusDepositing), string(parcel.StatusReadyForPickup)}).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 func mapParcelModelToDomain(model gormmodels.Parcel) *parcel.Parcel {
 	return &parcel.Parcel{
 		ID:            model.ID,
@@ -159,6 +220,7 @@ func mapParcelModelToDomain(model gormmodels.Parcel) *parcel.Parcel {
 		Size:          model.Size,
 		ReceiverPhone: model.ReceiverPhone,
 		SenderPhone:   model.SenderPhone,
+		CarrierCode:   model.CarrierCode,
 		PickupCode:    model.PickupCode,
 		Status:        parcel.Status(model.Status),
 		DepositedAt:   model.DepositedAt,
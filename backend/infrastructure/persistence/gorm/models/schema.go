@@ -7,13 +7,14 @@ import (
 )
 
 type Location struct {
-	ID        uuid.UUID  `gorm:"column:id;type:uuid;primaryKey"`
-	Code      string     `gorm:"column:code;type:varchar(50);not null;uniqueIndex:uidx_locations_code"`
-	Name      string     `gorm:"column:name;type:varchar(200);not null"`
-	Address   *string    `gorm:"column:address;type:text"`
-	IsActive  bool       `gorm:"column:is_active;type:boolean;not null;default:true"`
-	CreatedAt time.Time  `gorm:"column:created_at;type:timestamptz;not null"`
-	UpdatedAt *time.Time `gorm:"column:updated_at;type:timestamptz"`
+	ID            uuid.UUID  `gorm:"column:id;type:uuid;primaryKey"`
+	Code          string     `gorm:"column:code;type:varchar(50);not null;uniqueIndex:uidx_locations_code"`
+	Name          string     `gorm:"column:name;type:varchar(200);not null"`
+	Address       *string    `gorm:"column:address;type:text"`
+	IsActive      bool       `gorm:"column:is_active;type:boolean;not null;default:true"`
+	DefaultLocale string     `gorm:"column:default_locale;type:varchar(5);not null;default:''"`
+	CreatedAt     time.Time  `gorm:"column:created_at;type:timestamptz;not null"`
+	UpdatedAt     *time.Time `gorm:"column:updated_at;type:timestamptz"`
 }
 
 func (Location) TableName() string {
@@ -73,6 +74,7 @@ type Parcel struct {
 	Size          string     `gorm:"column:size;type:varchar(2);not null"`
 	ReceiverPhone string     `gorm:"column:receiver_phone;type:varchar(30);not null;index:idx_parcels_receiver_status,priority:1"`
 	SenderPhone   string     `gorm:"column:sender_phone;type:varchar(30);not null;index:idx_parcels_sender_status,priority:1"`
+	CarrierCode   *string    `gorm:"column:carrier_code;type:varchar(40);index:idx_parcels_carrier_code"`
 	PickupCode    *string    `gorm:"column:pickup_code;type:varchar(40);uniqueIndex:uidx_parcels_pickup_code"`
 	Status        string     `gorm:"column:status;type:varchar(20);not null;index:idx_parcels_locker_status,priority:2;index:idx_parcels_status_expires_at,priority:1;index:idx_parcels_receiver_status,priority:2;index:idx_parcels_sender_status,priority:2"`
 	DepositedAt   *time.Time `gorm:"column:deposited_at;type:timestamptz"`
@@ -90,10 +92,11 @@ func (Parcel) TableName() string {
 }
 
 type ParcelEvent struct {
-	ID        uuid.UUID `gorm:"column:id;type:uuid;primaryKey"`
-	ParcelID  uuid.UUID `gorm:"column:parcel_id;type:uuid;not null;index:idx_parcel_events_parcel_id"`
-	EventType string    `gorm:"column:event_type;type:varchar(30);not null"`
-	CreatedAt time.Time `gorm:"column:created_at;type:timestamptz;not null"`
+	ID        uuid.UUID  `gorm:"column:id;type:uuid;primaryKey"`
+	ParcelID  uuid.UUID  `gorm:"column:parcel_id;type:uuid;not null;index:idx_parcel_events_parcel_id"`
+	EventType string     `gorm:"column:event_type;type:varchar(30);not null"`
+	DeviceID  *uuid.UUID `gorm:"column:device_id;type:uuid;index:idx_parcel_events_device_id"`
+	CreatedAt time.Time  `gorm:"column:created_at;type:timestamptz;not null"`
 
 	Parcel Parcel `gorm:"foreignKey:ParcelID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
 }
@@ -111,8 +114,380 @@ type ParcelOTP struct {
 	ExpiresAt  time.Time  `gorm:"column:expires_at;type:timestamptz;not null;index:idx_parcel_otps_expires_at"`
 	VerifiedAt *time.Time `gorm:"column:verified_at;type:timestamptz"`
 	CreatedAt  time.Time  `gorm:"column:created_at;type:timestamptz;not null"`
+
+	RequestDeviceID *uuid.UUID `gorm:"column:request_device_id;type:uuid"`
+	VerifyDeviceID  *uuid.UUID `gorm:"column:verify_device_id;type:uuid"`
 }
 
 func (ParcelOTP) TableName() string {
 	return "parcel_otps"
 }
+
+type RateLimitHit struct {
+	ID    uuid.UUID `gorm:"column:id;type:uuid;primaryKey"`
+	Key   string    `gorm:"column:key;type:varchar(200);not null;index:idx_rate_limit_hits_key_hit_at,priority:1"`
+	HitAt time.Time `gorm:"column:hit_at;type:timestamptz;not null;index:idx_rate_limit_hits_key_hit_at,priority:2;index:idx_rate_limit_hits_hit_at"`
+}
+
+func (RateLimitHit) TableName() string {
+	return "rate_limit_hits"
+}
+
+type SchemaMigration struct {
+	ID        string    `gorm:"column:id;type:varchar(100);primaryKey"`
+	AppliedAt time.Time `gorm:"column:applied_at;type:timestamptz;not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+type PickupToken struct {
+	ID        uuid.UUID  `gorm:"column:id;type:uuid;primaryKey"`
+	TokenHash string     `gorm:"column:token_hash;type:varchar(64);not null;uniqueIndex:uidx_pickup_tokens_hash"`
+	Phone     string     `gorm:"column:phone;type:varchar(30);not null"`
+	OtpRef    string     `gorm:"column:otp_ref;type:varchar(64)"`
+	LockerID  *uuid.UUID `gorm:"column:locker_id;type:uuid"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;type:timestamptz;not null;index:idx_pickup_tokens_expires_at"`
+	RevokedAt *time.Time `gorm:"column:revoked_at;type:timestamptz"`
+	CreatedAt time.Time  `gorm:"column:created_at;type:timestamptz;not null"`
+}
+
+func (PickupToken) TableName() string {
+	return "pickup_tokens"
+}
+
+type PickupTokenDenylist struct {
+	TokenID   string    `gorm:"column:token_id;type:varchar(64);primaryKey"`
+	ExpiresAt time.Time `gorm:"column:expires_at;type:timestamptz;not null;index:idx_pickup_token_denylist_expires_at"`
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamptz;not null"`
+}
+
+func (PickupTokenDenylist) TableName() string {
+	return "pickup_token_denylist"
+}
+
+type NotificationPreference struct {
+	Phone     string     `gorm:"column:phone;type:varchar(30);primaryKey"`
+	Channel   string     `gorm:"column:channel;type:varchar(20);not null"`
+	Address   string     `gorm:"column:address;type:varchar(255);not null;default:''"`
+	Locale    string     `gorm:"column:locale;type:varchar(5);not null;default:''"`
+	CreatedAt time.Time  `gorm:"column:created_at;type:timestamptz;not null"`
+	UpdatedAt *time.Time `gorm:"column:updated_at;type:timestamptz"`
+}
+
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+type ParcelReminder struct {
+	ParcelID    uuid.UUID `gorm:"column:parcel_id;type:uuid;primaryKey"`
+	ReminderKey string    `gorm:"column:reminder_key;type:varchar(50);primaryKey"`
+	SentAt      time.Time `gorm:"column:sent_at;type:timestamptz;not null"`
+
+	Parcel Parcel `gorm:"foreignKey:ParcelID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (ParcelReminder) TableName() string {
+	return "parcel_reminders"
+}
+
+type ReminderPolicy struct {
+	LocationID uuid.UUID  `gorm:"column:location_id;type:uuid;primaryKey"`
+	Rules      string     `gorm:"column:rules;type:text;not null"`
+	CreatedAt  time.Time  `gorm:"column:created_at;type:timestamptz;not null"`
+	UpdatedAt  *time.Time `gorm:"column:updated_at;type:timestamptz"`
+
+	Location Location `gorm:"foreignKey:LocationID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (ReminderPolicy) TableName() string {
+	return "reminder_policies"
+}
+
+type NotificationOutbox struct {
+	ID            uuid.UUID  `gorm:"column:id;type:uuid;primaryKey"`
+	Type          string     `gorm:"column:type;type:varchar(40);not null"`
+	Phone         string     `gorm:"column:phone;type:varchar(30);not null;index:idx_notification_outbox_phone"`
+	Locale        string     `gorm:"column:locale;type:varchar(5);not null;default:''"`
+	Data          string     `gorm:"column:data;type:jsonb;not null;default:'{}'"`
+	Subject       string     `gorm:"column:subject;type:varchar(255);not null;default:''"`
+	Body          string     `gorm:"column:body;type:text;not null"`
+	Status        string     `gorm:"column:status;type:varchar(20);not null;index:idx_notification_outbox_status_next,priority:1"`
+	Attempts      int        `gorm:"column:attempts;type:integer;not null;default:0"`
+	NextAttemptAt time.Time  `gorm:"column:next_attempt_at;type:timestamptz;not null;index:idx_notification_outbox_status_next,priority:2"`
+	ExpiresAt     *time.Time `gorm:"column:expires_at;type:timestamptz"`
+	LastError     *string    `gorm:"column:last_error;type:text"`
+	Channel       *string    `gorm:"column:channel;type:varchar(20)"`
+	SentAt        *time.Time `gorm:"column:sent_at;type:timestamptz"`
+	CreatedAt     time.Time  `gorm:"column:created_at;type:timestamptz;not null"`
+	UpdatedAt     *time.Time `gorm:"column:updated_at;type:timestamptz"`
+}
+
+func (NotificationOutbox) TableName() string {
+	return "notification_outbox"
+}
+
+type NotificationTemplate struct {
+	Type      string     `gorm:"column:type;type:varchar(40);primaryKey"`
+	Locale    string     `gorm:"column:locale;type:varchar(5);primaryKey"`
+	Subject   string     `gorm:"column:subject;type:varchar(255);not null;default:''"`
+	Body      string     `gorm:"column:body;type:text;not null"`
+	CreatedAt time.Time  `gorm:"column:created_at;type:timestamptz;not null"`
+	UpdatedAt *time.Time `gorm:"column:updated_at;type:timestamptz"`
+}
+
+func (NotificationTemplate) TableName() string {
+	return "notification_templates"
+}
+
+type WebhookSubscription struct {
+	ID          uuid.UUID  `gorm:"column:id;type:uuid;primaryKey"`
+	CarrierCode string     `gorm:"column:carrier_code;type:varchar(40);not null;index:idx_webhook_subscriptions_carrier_active,priority:1"`
+	URL         string     `gorm:"column:url;type:varchar(500);not null"`
+	EventTypes  string     `gorm:"column:event_types;type:text;not null"`
+	Secret      string     `gorm:"column:secret;type:varchar(128);not null"`
+	IsActive    bool       `gorm:"column:is_active;type:boolean;not null;default:true;index:idx_webhook_subscriptions_carrier_active,priority:2"`
+	CreatedAt   time.Time  `gorm:"column:created_at;type:timestamptz;not null"`
+	UpdatedAt   *time.Time `gorm:"column:updated_at;type:timestamptz"`
+}
+
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID  `gorm:"column:id;type:uuid;primaryKey"`
+	SubscriptionID uuid.UUID  `gorm:"column:subscription_id;type:uuid;not null;index:idx_webhook_deliveries_subscription_created,priority:1"`
+	EventID        uuid.UUID  `gorm:"column:event_id;type:uuid;not null;index:idx_webhook_deliveries_event_id"`
+	EventType      string     `gorm:"column:event_type;type:varchar(40);not null"`
+	Payload        string     `gorm:"column:payload;type:jsonb;not null"`
+	Status         string     `gorm:"column:status;type:varchar(20);not null;index:idx_webhook_deliveries_status_next,priority:1"`
+	Attempts       int        `gorm:"column:attempts;type:integer;not null;default:0"`
+	NextAttemptAt  time.Time  `gorm:"column:next_attempt_at;type:timestamptz;not null;index:idx_webhook_deliveries_status_next,priority:2"`
+	LastStatusCode *int       `gorm:"column:last_status_code;type:integer"`
+	LastError      *string    `gorm:"column:last_error;type:text"`
+	DeliveredAt    *time.Time `gorm:"column:delivered_at;type:timestamptz"`
+	ReplayOf       *uuid.UUID `gorm:"column:replay_of;type:uuid"`
+	CreatedAt      time.Time  `gorm:"column:created_at;type:timestamptz;not null;index:idx_webhook_deliveries_subscription_created,priority:2"`
+	UpdatedAt      *time.Time `gorm:"column:updated_at;type:timestamptz"`
+
+	Subscription WebhookSubscription `gorm:"foreignKey:SubscriptionID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+type Admin struct {
+	ID            uuid.UUID  `gorm:"column:id;type:uuid;primaryKey"`
+	Username      string     `gorm:"column:username;type:varchar(255);not null;uniqueIndex:idx_admins_username"`
+	PasswordHash  string     `gorm:"column:password_hash;type:varchar(255);not null;default:''"`
+	Role          string     `gorm:"column:role;type:varchar(255);not null"`
+	PasswordSetAt *time.Time `gorm:"column:password_set_at;type:timestamptz"`
+	DisabledAt    *time.Time `gorm:"column:disabled_at;type:timestamptz"`
+	TOTPSecret    string     `gorm:"column:totp_secret;type:varchar(64);not null;default:''"`
+	TOTPEnabledAt *time.Time `gorm:"column:totp_enabled_at;type:timestamptz"`
+	TOTPLastStep  int64      `gorm:"column:totp_last_step;type:bigint;not null;default:0"`
+	CreatedAt     time.Time  `gorm:"column:created_at;type:timestamptz;not null"`
+	UpdatedAt     *time.Time `gorm:"column:updated_at;type:timestamptz"`
+}
+
+func (Admin) TableName() string {
+	return "admins"
+}
+
+type AdminSession struct {
+	ID               uuid.UUID  `gorm:"column:id;type:uuid;primaryKey"`
+	AdminID          uuid.UUID  `gorm:"column:admin_id;type:uuid;not null;index:idx_admin_sessions_admin_id"`
+	TokenHash        string     `gorm:"column:token_hash;type:varchar(64);not null;uniqueIndex:uidx_admin_sessions_token_hash"`
+	RefreshTokenHash string     `gorm:"column:refresh_token_hash;type:varchar(64);not null;uniqueIndex:uidx_admin_sessions_refresh_token_hash"`
+	ExpiresAt        time.Time  `gorm:"column:expires_at;type:timestamptz;not null"`
+	RefreshExpiresAt time.Time  `gorm:"column:refresh_expires_at;type:timestamptz;not null;index:idx_admin_sessions_refresh_expires_at"`
+	IP               string     `gorm:"column:ip;type:varchar(64)"`
+	UserAgent        string     `gorm:"column:user_agent;type:varchar(255)"`
+	RevokedAt        *time.Time `gorm:"column:revoked_at;type:timestamptz"`
+	CreatedAt        time.Time  `gorm:"column:created_at;type:timestamptz;not null"`
+	RefreshedAt      *time.Time `gorm:"column:refreshed_at;type:timestamptz"`
+
+	Admin Admin `gorm:"foreignKey:AdminID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (AdminSession) TableName() string {
+	return "admin_sessions"
+}
+
+type AdminSetupToken struct {
+	ID        uuid.UUID  `gorm:"column:id;type:uuid;primaryKey"`
+	AdminID   uuid.UUID  `gorm:"column:admin_id;type:uuid;not null;index:idx_admin_setup_tokens_admin_id"`
+	TokenHash string     `gorm:"column:token_hash;type:varchar(64);not null;uniqueIndex:uidx_admin_setup_tokens_token_hash"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;type:timestamptz;not null"`
+	UsedAt    *time.Time `gorm:"column:used_at;type:timestamptz"`
+	CreatedAt time.Time  `gorm:"column:created_at;type:timestamptz;not null"`
+
+	Admin Admin `gorm:"foreignKey:AdminID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (AdminSetupToken) TableName() string {
+	return "admin_setup_tokens"
+}
+
+type AdminRecoveryCode struct {
+	ID        uuid.UUID  `gorm:"column:id;type:uuid;primaryKey"`
+	AdminID   uuid.UUID  `gorm:"column:admin_id;type:uuid;not null;uniqueIndex:uidx_admin_recovery_codes_admin_hash,priority:1"`
+	CodeHash  string     `gorm:"column:code_hash;type:varchar(64);not null;uniqueIndex:uidx_admin_recovery_codes_admin_hash,priority:2"`
+	UsedAt    *time.Time `gorm:"column:used_at;type:timestamptz"`
+	CreatedAt time.Time  `gorm:"column:created_at;type:timestamptz;not null"`
+
+	Admin Admin `gorm:"foreignKey:AdminID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (AdminRecoveryCode) TableName() string {
+	return "admin_recovery_codes"
+}
+
+type AdminLocation struct {
+	AdminID    uuid.UUID `gorm:"column:admin_id;type:uuid;primaryKey"`
+	LocationID uuid.UUID `gorm:"column:location_id;type:uuid;primaryKey;index:idx_admin_locations_location_id"`
+	CreatedAt  time.Time `gorm:"column:created_at;type:timestamptz;not null"`
+
+	Admin    Admin    `gorm:"foreignKey:AdminID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Location Location `gorm:"foreignKey:LocationID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (AdminLocation) TableName() string {
+	return "admin_locations"
+}
+
+type AuditLog struct {
+	ID            uuid.UUID  `gorm:"column:id;type:uuid;primaryKey"`
+	ActorID       *uuid.UUID `gorm:"column:actor_id;type:uuid;index:idx_audit_log_actor_created,priority:1"`
+	ActorUsername string     `gorm:"column:actor_username;type:varchar(100);not null;default:''"`
+	Action        string     `gorm:"column:action;type:varchar(60);not null"`
+	EntityType    string     `gorm:"column:entity_type;type:varchar(40);not null;index:idx_audit_log_entity,priority:1"`
+	EntityID      string     `gorm:"column:entity_id;type:varchar(100);not null;index:idx_audit_log_entity,priority:2"`
+	Before        *string    `gorm:"column:before;type:jsonb"`
+	After         *string    `gorm:"column:after;type:jsonb"`
+	IP            string     `gorm:"column:ip;type:varchar(64);not null;default:''"`
+	RequestID     string     `gorm:"column:request_id;type:varchar(64);not null;default:''"`
+	CreatedAt     time.Time  `gorm:"column:created_at;type:timestamptz;not null;index:idx_audit_log_actor_created,priority:2;index:idx_audit_log_created_at"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_log"
+}
+
+type LockerDevice struct {
+	ID              uuid.UUID  `gorm:"column:id;type:uuid;primaryKey"`
+	LockerID        uuid.UUID  `gorm:"column:locker_id;type:uuid;not null;index:idx_locker_devices_locker_id"`
+	Name            string     `gorm:"column:name;type:varchar(100);not null"`
+	Secret          string     `gorm:"column:secret;type:varchar(128);not null"`
+	SecretRotatedAt *time.Time `gorm:"column:secret_rotated_at;type:timestamptz"`
+	LastSeenAt      *time.Time `gorm:"column:last_seen_at;type:timestamptz"`
+	RevokedAt       *time.Time `gorm:"column:revoked_at;type:timestamptz"`
+	CreatedAt       time.Time  `gorm:"column:created_at;type:timestamptz;not null"`
+	UpdatedAt       *time.Time `gorm:"column:updated_at;type:timestamptz"`
+
+	Locker Locker `gorm:"foreignKey:LockerID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (LockerDevice) TableName() string {
+	return "locker_devices"
+}
+
+type DeviceNonce struct {
+	DeviceID  uuid.UUID `gorm:"column:device_id;type:uuid;primaryKey"`
+	Nonce     string    `gorm:"column:nonce;type:varchar(64);primaryKey"`
+	ExpiresAt time.Time `gorm:"column:expires_at;type:timestamptz;not null;index:idx_device_nonces_expires_at"`
+
+	Device LockerDevice `gorm:"foreignKey:DeviceID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (DeviceNonce) TableName() string {
+	return "device_nonces"
+}
+
+type CompartmentStatusChange struct {
+	ID            uuid.UUID  `gorm:"column:id;type:uuid;primaryKey"`
+	CompartmentID uuid.UUID  `gorm:"column:compartment_id;type:uuid;not null;index:idx_compartment_status_changes_compartment_id_created_at,priority:1"`
+	FromStatus    string     `gorm:"column:from_status;type:varchar(20);not null"`
+	ToStatus      string     `gorm:"column:to_status;type:varchar(20);not null"`
+	Reason        string     `gorm:"column:reason;type:text;not null;default:''"`
+	Forced        bool       `gorm:"column:forced;not null;default:false"`
+	ActorID       *uuid.UUID `gorm:"column:actor_id;type:uuid"`
+	CreatedAt     time.Time  `gorm:"column:created_at;type:timestamptz;not null;index:idx_compartment_status_changes_compartment_id_created_at,priority:2"`
+
+	Compartment Compartment `gorm:"foreignKey:CompartmentID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (CompartmentStatusChange) TableName() string {
+	return "compartment_status_changes"
+}
+
+type CompartmentEvent struct {
+	ID            uuid.UUID  `gorm:"column:id;type:uuid;primaryKey"`
+	CompartmentID uuid.UUID  `gorm:"column:compartment_id;type:uuid;not null;index:idx_compartment_events_compartment_id_occurred_at,priority:1"`
+	LockerID      uuid.UUID  `gorm:"column:locker_id;type:uuid;not null"`
+	ParcelID      *uuid.UUID `gorm:"column:parcel_id;type:uuid"`
+	DeviceID      *uuid.UUID `gorm:"column:device_id;type:uuid"`
+	EventType     string     `gorm:"column:event_type;type:varchar(30);not null"`
+	OccurredAt    time.Time  `gorm:"column:occurred_at;type:timestamptz;not null;index:idx_compartment_events_compartment_id_occurred_at,priority:2"`
+	CreatedAt     time.Time  `gorm:"column:created_at;type:timestamptz;not null"`
+
+	Compartment Compartment `gorm:"foreignKey:CompartmentID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (CompartmentEvent) TableName() string {
+	return "compartment_events"
+}
+
+type Incident struct {
+	ID            uuid.UUID  `gorm:"column:id;type:uuid;primaryKey"`
+	IncidentType  string     `gorm:"column:incident_type;type:varchar(30);not null;uniqueIndex:uidx_incidents_open,priority:2,where:status = 'OPEN'"`
+	Status        string     `gorm:"column:status;type:varchar(20);not null;index:idx_incidents_status_opened_at,priority:1"`
+	LockerID      uuid.UUID  `gorm:"column:locker_id;type:uuid;not null;index:idx_incidents_locker_id"`
+	CompartmentID uuid.UUID  `gorm:"column:compartment_id;type:uuid;not null;uniqueIndex:uidx_incidents_open,priority:1,where:status = 'OPEN'"`
+	ParcelID      *uuid.UUID `gorm:"column:parcel_id;type:uuid"`
+	Detail        string     `gorm:"column:detail;type:text;not null;default:''"`
+	OpenedAt      time.Time  `gorm:"column:opened_at;type:timestamptz;not null;index:idx_incidents_status_opened_at,priority:2"`
+	ResolvedAt    *time.Time `gorm:"column:resolved_at;type:timestamptz"`
+	ResolvedBy    *uuid.UUID `gorm:"column:resolved_by;type:uuid"`
+	Resolution    string     `gorm:"column:resolution;type:text;not null;default:''"`
+
+	Compartment Compartment `gorm:"foreignKey:CompartmentID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (Incident) TableName() string {
+	return "incidents"
+}
+
+type LockerHeartbeat struct {
+	LockerID         uuid.UUID  `gorm:"column:locker_id;type:uuid;primaryKey"`
+	DeviceID         *uuid.UUID `gorm:"column:device_id;type:uuid"`
+	FirmwareVersion  string     `gorm:"column:firmware_version;type:varchar(50);not null;default:''"`
+	TemperatureC     *float64   `gorm:"column:temperature_c;type:numeric(5,2)"`
+	ControllerHealth string     `gorm:"column:controller_health;type:varchar(20);not null"`
+	ReceivedAt       time.Time  `gorm:"column:received_at;type:timestamptz;not null;index:idx_locker_heartbeats_received_at"`
+
+	Locker Locker `gorm:"foreignKey:LockerID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (LockerHeartbeat) TableName() string {
+	return "locker_heartbeats"
+}
+
+type LockerStatusChange struct {
+	ID         uuid.UUID  `gorm:"column:id;type:uuid;primaryKey"`
+	LockerID   uuid.UUID  `gorm:"column:locker_id;type:uuid;not null;index:idx_locker_status_changes_locker_id_created_at,priority:1"`
+	FromStatus string     `gorm:"column:from_status;type:varchar(20);not null"`
+	ToStatus   string     `gorm:"column:to_status;type:varchar(20);not null"`
+	Reason     string     `gorm:"column:reason;type:text;not null;default:''"`
+	ActorID    *uuid.UUID `gorm:"column:actor_id;type:uuid"`
+	CreatedAt  time.Time  `gorm:"column:created_at;type:timestamptz;not null;index:idx_locker_status_changes_locker_id_created_at,priority:2"`
+
+	Locker Locker `gorm:"foreignKey:LockerID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (LockerStatusChange) TableName() string {
+	return "locker_status_changes"
+}
@@ -0,0 +1,89 @@
+package pickup
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	gormmodels "smart-parcel-locker/backend/infrastructure/persistence/gorm/models"
+)
+
+// MemoryDenylist keeps revoked token IDs in-process.
+type MemoryDenylist struct {
+	mu      sync.RWMutex
+	entries map[string]time.Time
+}
+
+func NewMemoryDenylist() *MemoryDenylist {
+	return &MemoryDenylist{
+		entries: make(map[string]time.Time),
+	}
+}
+
+func (d *MemoryDenylist) Add(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries[tokenID] = expiresAt
+	return nil
+}
+
+func (d *MemoryDenylist) Contains(ctx context.Context, tokenID string) (bool, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	_, ok := d.entries[tokenID]
+	return ok, nil
+}
+
+func (d *MemoryDenylist) PurgeExpired(ctx context.Context, now time.Time) (int64, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var removed int64
+	for tokenID, expiresAt := range d.entries {
+		if now.After(expiresAt) {
+			delete(d.entries, tokenID)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// GormDenylist keeps revoked token IDs in Postgres so revocation is shared across replicas.
+type GormDenylist struct {
+	db *gorm.DB
+}
+
+func NewGormDenylist(db *gorm.DB) *GormDenylist {
+	return &GormDenylist{db: db}
+}
+
+func (d *GormDenylist) Add(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	model := gormmodels.PickupTokenDenylist{
+		TokenID:   tokenID,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+	return d.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model).Error
+}
+
+func (d *GormDenylist) Contains(ctx context.Context, tokenID string) (bool, error) {
+	var count int64
+	if err := d.db.WithContext(ctx).
+		Model(&gormmodels.PickupTokenDenylist{}).
+		Where("token_id = ?", tokenID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (d *GormDenylist) PurgeExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := d.db.WithContext(ctx).
+		Where("expires_at < ?", now).
+		Delete(&gormmodels.PickupTokenDenylist{})
+	return result.RowsAffected, result.Error
+}
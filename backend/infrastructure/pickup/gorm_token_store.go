@@ -0,0 +1,92 @@
+package pickup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	pickupdomain "smart-parcel-locker/backend/domain/pickup"
+	gormmodels "smart-parcel-locker/backend/infrastructure/persistence/gorm/models"
+)
+
+// GormTokenStore keeps pickup tokens in Postgres so they survive restarts and are
+// shared across replicas. Only a SHA-256 hash of each token is stored.
+type GormTokenStore struct {
+	db *gorm.DB
+}
+
+func NewGormTokenStore(db *gorm.DB) *GormTokenStore {
+	return &GormTokenStore{db: db}
+}
+
+// WithDB returns a store bound to the caller's transaction, so a token is only kept when the
+// verification that issued it commits.
+func (s *GormTokenStore) WithDB(db *gorm.DB) pickupdomain.TokenStore {
+	return &GormTokenStore{db: db}
+}
+
+func (s *GormTokenStore) Issue(ctx context.Context, info pickupdomain.TokenInfo) (string, error) {
+	token := uuid.NewString()
+	model := gormmodels.PickupToken{
+		ID:        uuid.New(),
+		TokenHash: hashToken(token),
+		Phone:     info.Phone,
+		OtpRef:    info.OtpRef,
+		LockerID:  info.LockerID,
+		ExpiresAt: info.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+	if err := s.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *GormTokenStore) Get(ctx context.Context, token string) (pickupdomain.TokenInfo, bool, error) {
+	if token == "" {
+		return pickupdomain.TokenInfo{}, false, nil
+	}
+	var model gormmodels.PickupToken
+	err := s.db.WithContext(ctx).
+		Where("token_hash = ? AND revoked_at IS NULL", hashToken(token)).
+		Take(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return pickupdomain.TokenInfo{}, false, nil
+		}
+		return pickupdomain.TokenInfo{}, false, err
+	}
+	return pickupdomain.TokenInfo{
+		Phone:     model.Phone,
+		ExpiresAt: model.ExpiresAt,
+		OtpRef:    model.OtpRef,
+		LockerID:  model.LockerID,
+	}, true, nil
+}
+
+// Revoke marks a token as revoked so it can no longer be used.
+func (s *GormTokenStore) Revoke(ctx context.Context, token string) error {
+	now := time.Now()
+	return s.db.WithContext(ctx).
+		Model(&gormmodels.PickupToken{}).
+		Where("token_hash = ? AND revoked_at IS NULL", hashToken(token)).
+		Update("revoked_at", &now).Error
+}
+
+// PurgeExpired deletes tokens that expired before now.
+func (s *GormTokenStore) PurgeExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).
+		Where("expires_at < ?", now).
+		Delete(&gormmodels.PickupToken{})
+	return result.RowsAffected, result.Error
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
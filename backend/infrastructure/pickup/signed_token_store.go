@@ -0,0 +1,206 @@
+package pickup
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	pickupdomain "smart-parcel-locker/backend/domain/pickup"
+)
+
+const minSigningKeyLength = 32
+
+// Denylist records revoked token IDs until the token would have expired anyway.
+type Denylist interface {
+	Add(ctx context.Context, tokenID string, expiresAt time.Time) error
+	Contains(ctx context.Context, tokenID string) (bool, error)
+	PurgeExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// SignedTokenStore issues self-contained HS256 JWTs instead of storing tokens.
+// Keys are addressed by key ID so old keys can keep verifying during rotation.
+type SignedTokenStore struct {
+	keys        map[string][]byte
+	activeKeyID string
+	denylist    Denylist
+}
+
+type tokenHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+type tokenClaims struct {
+	Subject   string `json:"sub"`
+	TokenID   string `json:"jti"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+	OtpRef    string `json:"otp_ref,omitempty"`
+	LockerID  string `json:"locker_id,omitempty"`
+}
+
+// NewSignedTokenStore builds a signed token store. denylist may be nil.
+func NewSignedTokenStore(keys map[string]string, activeKeyID string, denylist Denylist) (*SignedTokenStore, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("signed pickup tokens require at least one signing key")
+	}
+	byID := make(map[string][]byte, len(keys))
+	for kid, secret := range keys {
+		if kid == "" {
+			return nil, errors.New("signing key id must not be empty")
+		}
+		if len(secret) < minSigningKeyLength {
+			return nil, fmt.Errorf("signing key %q must be at least %d bytes", kid, minSigningKeyLength)
+		}
+		byID[kid] = []byte(secret)
+	}
+	if _, ok := byID[activeKeyID]; !ok {
+		return nil, fmt.Errorf("active signing key %q is not configured", activeKeyID)
+	}
+	return &SignedTokenStore{
+		keys:        byID,
+		activeKeyID: activeKeyID,
+		denylist:    denylist,
+	}, nil
+}
+
+func (s *SignedTokenStore) Issue(ctx context.Context, info pickupdomain.TokenInfo) (string, error) {
+	tokenID, err := randomTokenID()
+	if err != nil {
+		return "", err
+	}
+	claims := tokenClaims{
+		Subject:   info.Phone,
+		TokenID:   tokenID,
+		IssuedAt:  time.Now().Unix(),
+		ExpiresAt: info.ExpiresAt.Unix(),
+		OtpRef:    info.OtpRef,
+	}
+	if info.LockerID != nil {
+		claims.LockerID = info.LockerID.String()
+	}
+	header, err := encodeSegment(tokenHeader{Alg: "HS256", Typ: "JWT", Kid: s.activeKeyID})
+	if err != nil {
+		return "", err
+	}
+	payload, err := encodeSegment(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := header + "." + payload
+	return signingInput + "." + sign(s.keys[s.activeKeyID], signingInput), nil
+}
+
+func (s *SignedTokenStore) Get(ctx context.Context, token string) (pickupdomain.TokenInfo, bool, error) {
+	claims, ok := s.verify(token)
+	if !ok {
+		return pickupdomain.TokenInfo{}, false, nil
+	}
+	if s.denylist != nil {
+		revoked, err := s.denylist.Contains(ctx, claims.TokenID)
+		if err != nil {
+			return pickupdomain.TokenInfo{}, false, err
+		}
+		if revoked {
+			return pickupdomain.TokenInfo{}, false, nil
+		}
+	}
+	info := pickupdomain.TokenInfo{
+		Phone:     claims.Subject,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+		OtpRef:    claims.OtpRef,
+	}
+	if claims.LockerID != "" {
+		lockerID, err := uuid.Parse(claims.LockerID)
+		if err != nil {
+			return pickupdomain.TokenInfo{}, false, nil
+		}
+		info.LockerID = &lockerID
+	}
+	return info, true, nil
+}
+
+// Revoke adds the token to the denylist until it expires.
+func (s *SignedTokenStore) Revoke(ctx context.Context, token string) error {
+	claims, ok := s.verify(token)
+	if !ok {
+		return nil
+	}
+	if s.denylist == nil {
+		return errors.New("signed pickup tokens cannot be revoked without a denylist")
+	}
+	return s.denylist.Add(ctx, claims.TokenID, time.Unix(claims.ExpiresAt, 0))
+}
+
+// PurgeExpired drops denylist entries for tokens that have expired.
+func (s *SignedTokenStore) PurgeExpired(ctx context.Context, now time.Time) (int64, error) {
+	if s.denylist == nil {
+		return 0, nil
+	}
+	return s.denylist.PurgeExpired(ctx, now)
+}
+
+func (s *SignedTokenStore) verify(token string) (tokenClaims, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return tokenClaims{}, false
+	}
+	var header tokenHeader
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return tokenClaims{}, false
+	}
+	key, ok := s.keys[header.Kid]
+	if !ok {
+		return tokenClaims{}, false
+	}
+	expected := sign(key, parts[0]+"."+parts[1])
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return tokenClaims{}, false
+	}
+	var claims tokenClaims
+	if err := decodeSegment(parts[1], &claims); err != nil || claims.Subject == "" || claims.TokenID == "" {
+		return tokenClaims{}, false
+	}
+	return claims, true
+}
+
+func sign(key []byte, input string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(input))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func encodeSegment(v interface{}) (string, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+func randomTokenID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
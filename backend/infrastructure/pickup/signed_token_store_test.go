@@ -0,0 +1,105 @@
+package pickup
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	pickupdomain "smart-parcel-locker/backend/domain/pickup"
+)
+
+const (
+	testKeyOld = "old-secret-0123456789abcdef0123456789"
+	testKeyNew = "new-secret-0123456789abcdef0123456789"
+)
+
+func TestSignedTokenStoreRoundTrip(t *testing.T) {
+	store, err := NewSignedTokenStore(map[string]string{"k1": testKeyOld}, "k1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lockerID := uuid.New()
+	expiresAt := time.Date(2025, 1, 10, 12, 15, 0, 0, time.UTC)
+	token, err := store.Issue(context.Background(), pickupdomain.TokenInfo{
+		Phone:     "+66812345678",
+		ExpiresAt: expiresAt,
+		OtpRef:    "ref-1",
+		LockerID:  &lockerID,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	info, ok, err := store.Get(context.Background(), token)
+	if err != nil || !ok {
+		t.Fatalf("expected valid token; got ok=%v err=%v", ok, err)
+	}
+	if info.Phone != "+66812345678" || info.OtpRef != "ref-1" || !info.ExpiresAt.Equal(expiresAt) {
+		t.Fatalf("unexpected claims: %+v", info)
+	}
+	if info.LockerID == nil || *info.LockerID != lockerID {
+		t.Fatalf("expected locker scope %s; got %v", lockerID, info.LockerID)
+	}
+}
+
+func TestSignedTokenStoreRejectsTampering(t *testing.T) {
+	store, _ := NewSignedTokenStore(map[string]string{"k1": testKeyOld}, "k1", nil)
+	token, _ := store.Issue(context.Background(), pickupdomain.TokenInfo{
+		Phone:     "+66812345678",
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+	parts := strings.Split(token, ".")
+	forged, _ := encodeSegment(tokenClaims{Subject: "+66899999999", TokenID: "x", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	for _, candidate := range []string{
+		parts[0] + "." + forged + "." + parts[2],
+		token + "x",
+		"not-a-token",
+	} {
+		if _, ok, _ := store.Get(context.Background(), candidate); ok {
+			t.Fatalf("expected %q to be rejected", candidate)
+		}
+	}
+}
+
+func TestSignedTokenStoreKeyRotation(t *testing.T) {
+	oldStore, _ := NewSignedTokenStore(map[string]string{"k1": testKeyOld}, "k1", nil)
+	token, _ := oldStore.Issue(context.Background(), pickupdomain.TokenInfo{
+		Phone:     "+66812345678",
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+
+	rotated, _ := NewSignedTokenStore(map[string]string{"k1": testKeyOld, "k2": testKeyNew}, "k2", nil)
+	if _, ok, _ := rotated.Get(context.Background(), token); !ok {
+		t.Fatal("expected token signed with previous key to remain valid")
+	}
+
+	retired, _ := NewSignedTokenStore(map[string]string{"k2": testKeyNew}, "k2", nil)
+	if _, ok, _ := retired.Get(context.Background(), token); ok {
+		t.Fatal("expected token signed with retired key to be rejected")
+	}
+}
+
+func TestSignedTokenStoreRevoke(t *testing.T) {
+	store, _ := NewSignedTokenStore(map[string]string{"k1": testKeyOld}, "k1", NewMemoryDenylist())
+	token, _ := store.Issue(context.Background(), pickupdomain.TokenInfo{
+		Phone:     "+66812345678",
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+	if err := store.Revoke(context.Background(), token); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok, _ := store.Get(context.Background(), token); ok {
+		t.Fatal("expected revoked token to be rejected")
+	}
+}
+
+func TestNewSignedTokenStoreValidatesKeys(t *testing.T) {
+	if _, err := NewSignedTokenStore(map[string]string{"k1": "short"}, "k1", nil); err == nil {
+		t.Fatal("expected short key to be rejected")
+	}
+	if _, err := NewSignedTokenStore(map[string]string{"k1": testKeyOld}, "k2", nil); err == nil {
+		t.Fatal("expected unknown active key to be rejected")
+	}
+}
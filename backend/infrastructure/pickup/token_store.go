@@ -1,9 +1,12 @@
 package pickup
 
 import (
+	"context"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
+
 	pickupdomain "smart-parcel-locker/backend/domain/pickup"
 )
 
@@ -19,24 +22,42 @@ func NewTokenStore() *TokenStore {
 	}
 }
 
-func (s *TokenStore) Store(token string, phone string, expiresAt time.Time) {
-	if token == "" {
-		return
-	}
+func (s *TokenStore) Issue(ctx context.Context, info pickupdomain.TokenInfo) (string, error) {
+	token := uuid.NewString()
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.tokens[token] = pickupdomain.TokenInfo{
-		Phone:     phone,
-		ExpiresAt: expiresAt,
-	}
+	s.tokens[token] = info
+	return token, nil
 }
 
-func (s *TokenStore) Get(token string) (pickupdomain.TokenInfo, bool) {
+func (s *TokenStore) Get(ctx context.Context, token string) (pickupdomain.TokenInfo, bool, error) {
 	if token == "" {
-		return pickupdomain.TokenInfo{}, false
+		return pickupdomain.TokenInfo{}, false, nil
 	}
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	info, ok := s.tokens[token]
-	return info, ok
+	return info, ok, nil
+}
+
+// Revoke removes a token so it can no longer be used.
+func (s *TokenStore) Revoke(ctx context.Context, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, token)
+	return nil
+}
+
+// PurgeExpired drops tokens that expired before now.
+func (s *TokenStore) PurgeExpired(ctx context.Context, now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var removed int64
+	for token, info := range s.tokens {
+		if now.After(info.ExpiresAt) {
+			delete(s.tokens, token)
+			removed++
+		}
+	}
+	return removed, nil
 }
@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	ratelimitdomain "smart-parcel-locker/backend/domain/ratelimit"
+	gormmodels "smart-parcel-locker/backend/infrastructure/persistence/gorm/models"
+)
+
+// GormStore keeps sliding-window hits in Postgres so limits are shared across replicas.
+type GormStore struct {
+	db *gorm.DB
+}
+
+func NewGormStore(db *gorm.DB) *GormStore {
+	return &GormStore{db: db}
+}
+
+func (s *GormStore) Take(ctx context.Context, hits []ratelimitdomain.Hit, now time.Time) ([]ratelimitdomain.Decision, error) {
+	decisions := make([]ratelimitdomain.Decision, len(hits))
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Serialize concurrent hits for the same keys across all instances; locking in key order
+		// keeps requests that share several keys from deadlocking.
+		keys := make([]string, 0, len(hits))
+		for _, h := range hits {
+			keys = append(keys, h.Key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", key).Error; err != nil {
+				return err
+			}
+		}
+
+		allowed := true
+		for i, h := range hits {
+			windowStart := now.Add(-h.Rule.Window)
+			if err := tx.
+				Where("key = ? AND hit_at <= ?", h.Key, windowStart).
+				Delete(&gormmodels.RateLimitHit{}).Error; err != nil {
+				return err
+			}
+
+			var times []time.Time
+			if err := tx.Model(&gormmodels.RateLimitHit{}).
+				Where("key = ?", h.Key).
+				Order("hit_at asc").
+				Pluck("hit_at", &times).Error; err != nil {
+				return err
+			}
+			decisions[i] = ratelimitdomain.Evaluate(h.Rule, times, now)
+			allowed = allowed && decisions[i].Allowed
+		}
+
+		if !allowed || len(hits) == 0 {
+			return nil
+		}
+		models := make([]gormmodels.RateLimitHit, 0, len(hits))
+		for _, h := range hits {
+			models = append(models, gormmodels.RateLimitHit{
+				ID:    uuid.New(),
+				Key:   h.Key,
+				HitAt: now,
+			})
+		}
+		return tx.Create(&models).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decisions, nil
+}
+
+// Purge removes hits older than before, covering keys that stopped receiving traffic.
+func (s *GormStore) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).
+		Where("hit_at < ?", before).
+		Delete(&gormmodels.RateLimitHit{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	ratelimitdomain "smart-parcel-locker/backend/domain/ratelimit"
+)
+
+// MemoryStore keeps sliding-window hits in-process. Suitable for a single node.
+type MemoryStore struct {
+	mu   sync.Mutex
+	hits map[string][]time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		hits: make(map[string][]time.Time),
+	}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, hits []ratelimitdomain.Hit, now time.Time) ([]ratelimitdomain.Decision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	decisions := make([]ratelimitdomain.Decision, len(hits))
+	allowed := true
+	for i, h := range hits {
+		windowStart := now.Add(-h.Rule.Window)
+		current := s.hits[h.Key]
+		kept := current[:0]
+		for _, hit := range current {
+			if hit.After(windowStart) {
+				kept = append(kept, hit)
+			}
+		}
+		if len(kept) == 0 {
+			delete(s.hits, h.Key)
+		} else {
+			s.hits[h.Key] = kept
+		}
+		decisions[i] = ratelimitdomain.Evaluate(h.Rule, kept, now)
+		allowed = allowed && decisions[i].Allowed
+	}
+
+	if allowed {
+		for _, h := range hits {
+			s.hits[h.Key] = append(s.hits[h.Key], now)
+		}
+	}
+	return decisions, nil
+}
+
+// Purge drops keys whose most recent hit is older than before.
+func (s *MemoryStore) Purge(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var removed int64
+	for key, hits := range s.hits {
+		if len(hits) == 0 || !hits[len(hits)-1].After(before) {
+			removed += int64(len(hits))
+			delete(s.hits, key)
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,37 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ratelimitdomain "smart-parcel-locker/backend/domain/ratelimit"
+)
+
+func TestMemoryStoreRecordsOnlyWhenEveryHitIsAllowed(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Date(2025, 1, 9, 12, 0, 0, 0, time.UTC)
+	phone := ratelimitdomain.Hit{Key: "otp_request:phone:+66812345678", Rule: ratelimitdomain.Rule{Limit: 1, Window: time.Hour}}
+	ip := ratelimitdomain.Hit{Key: "otp_request:ip:10.0.0.1", Rule: ratelimitdomain.Rule{Limit: 2, Window: time.Hour}}
+
+	if _, err := store.Take(context.Background(), []ratelimitdomain.Hit{phone}, now); err != nil {
+		t.Fatal(err)
+	}
+	// The phone is exhausted, so neither key may be charged for this request.
+	decisions, err := store.Take(context.Background(), []ratelimitdomain.Hit{phone, ip}, now.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decisions[0].Allowed || !decisions[1].Allowed {
+		t.Fatalf("expected the phone to be denied and the ip allowed, got %+v", decisions)
+	}
+	for i := 0; i < 2; i++ {
+		decisions, err = store.Take(context.Background(), []ratelimitdomain.Hit{ip}, now.Add(2*time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !decisions[0].Allowed {
+			t.Fatalf("ip hit %d denied: the denied request used up its budget", i+1)
+		}
+	}
+}
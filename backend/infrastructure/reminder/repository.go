@@ -0,0 +1,176 @@
+package reminder
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"smart-parcel-locker/backend/domain/notification"
+	"smart-parcel-locker/backend/domain/parcel"
+	"smart-parcel-locker/backend/domain/reminder"
+	gormmodels "smart-parcel-locker/backend/infrastructure/persistence/gorm/models"
+)
+
+// GormRepository provides data access for reminder policies and sent records.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) WithDB(db *gorm.DB) reminder.Repository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) ListDue(ctx context.Context, query reminder.DueQuery) ([]reminder.Candidate, error) {
+	db := r.db.WithContext(ctx).
+		Preload("Locker.Location").
+		Joins("JOIN lockers ON lockers.id = parcels.locker_id").
+		Where("parcels.status = ?", string(parcel.StatusReadyForPickup))
+
+	switch {
+	case query.Default && len(query.PolicyLocationIDs) == 0:
+	case query.Default && len(query.LocationIDs) > 0:
+		db = db.Where("(lockers.location_id IN ? OR lockers.location_id NOT IN ?)", query.LocationIDs, query.PolicyLocationIDs)
+	case query.Default:
+		db = db.Where("lockers.location_id NOT IN ?", query.PolicyLocationIDs)
+	case len(query.LocationIDs) > 0:
+		db = db.Where("lockers.location_id IN ?", query.LocationIDs)
+	default:
+		return nil, nil
+	}
+
+	column := "parcels.deposited_at"
+	if query.Rule.Anchor == reminder.AnchorExpiry {
+		column = "parcels.expires_at"
+	}
+	until, since := query.Rule.DueRange(query.Now)
+	db = db.Where(column+" <= ?", until)
+	if since != nil {
+		db = db.Where(column+" > ?", *since)
+	}
+
+	var models []gormmodels.Parcel
+	if err := db.
+		Where("NOT EXISTS (SELECT 1 FROM parcel_reminders WHERE parcel_reminders.parcel_id = parcels.id AND parcel_reminders.reminder_key = ?)", query.Rule.Key()).
+		Order("parcels.deposited_at ASC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	candidates := make([]reminder.Candidate, 0, len(models))
+	for _, m := range models {
+		lockerName := m.Locker.LockerCode
+		if m.Locker.Name != nil && *m.Locker.Name != "" {
+			lockerName = *m.Locker.Name
+		}
+		candidates = append(candidates, reminder.Candidate{
+			Parcel: &parcel.Parcel{
+				ID:            m.ID,
+				ParcelCode:    m.ParcelCode,
+				LockerID:      m.LockerID,
+				CompartmentID: m.CompartmentID,
+				Size:          m.Size,
+				ReceiverPhone: m.ReceiverPhone,
+				SenderPhone:   m.SenderPhone,
+				PickupCode:    m.PickupCode,
+				Status:        parcel.Status(m.Status),
+				DepositedAt:   m.DepositedAt,
+				PickedUpAt:    m.PickedUpAt,
+				ExpiresAt:     m.ExpiresAt,
+				CreatedAt:     m.CreatedAt,
+				UpdatedAt:     m.UpdatedAt,
+			},
+			LocationID: m.Locker.LocationID,
+			LockerName: lockerName,
+			Locale:     notification.Locale(m.Locker.Location.DefaultLocale),
+		})
+	}
+	return candidates, nil
+}
+
+func (r *GormRepository) Claim(ctx context.Context, parcelID uuid.UUID, key string, at time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&gormmodels.ParcelReminder{
+			ParcelID:    parcelID,
+			ReminderKey: key,
+			SentAt:      at,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *GormRepository) ListPolicies(ctx context.Context) ([]reminder.Policy, error) {
+	var models []gormmodels.ReminderPolicy
+	if err := r.db.WithContext(ctx).Order("created_at ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	policies := make([]reminder.Policy, 0, len(models))
+	for _, m := range models {
+		p, err := mapPolicyModelToDomain(m)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, *p)
+	}
+	return policies, nil
+}
+
+func (r *GormRepository) GetPolicy(ctx context.Context, locationID uuid.UUID) (*reminder.Policy, error) {
+	var model gormmodels.ReminderPolicy
+	if err := r.db.WithContext(ctx).First(&model, "location_id = ?", locationID).Error; err != nil {
+		return nil, err
+	}
+	return mapPolicyModelToDomain(model)
+}
+
+func (r *GormRepository) UpsertPolicy(ctx context.Context, policy *reminder.Policy) (*reminder.Policy, error) {
+	now := time.Now()
+	model := gormmodels.ReminderPolicy{
+		LocationID: policy.LocationID,
+		Rules:      reminder.FormatRules(policy.Rules),
+		CreatedAt:  now,
+		UpdatedAt:  &now,
+	}
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "location_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rules", "updated_at"}),
+		}).
+		Create(&model).Error; err != nil {
+		return nil, err
+	}
+	return r.GetPolicy(ctx, policy.LocationID)
+}
+
+func (r *GormRepository) DeletePolicy(ctx context.Context, locationID uuid.UUID) error {
+	res := r.db.WithContext(ctx).Delete(&gormmodels.ReminderPolicy{}, "location_id = ?", locationID)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func mapPolicyModelToDomain(model gormmodels.ReminderPolicy) (*reminder.Policy, error) {
+	rules, err := reminder.ParseRules(model.Rules)
+	if err != nil {
+		return nil, err
+	}
+	return &reminder.Policy{
+		LocationID: model.LocationID,
+		Rules:      rules,
+		CreatedAt:  model.CreatedAt,
+		UpdatedAt:  model.UpdatedAt,
+	}, nil
+}
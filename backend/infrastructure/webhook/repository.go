@@ -0,0 +1,295 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/webhook"
+	"smart-parcel-locker/backend/infrastructure/database"
+	gormmodels "smart-parcel-locker/backend/infrastructure/persistence/gorm/models"
+)
+
+// GormRepository stores webhook subscriptions and deliveries.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) WithDB(db *gorm.DB) webhook.Repository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) CreateSubscription(ctx context.Context, sub *webhook.Subscription) (*webhook.Subscription, error) {
+	if sub.ID == uuid.Nil {
+		sub.ID = uuid.New()
+	}
+	if sub.CreatedAt.IsZero() {
+		sub.CreatedAt = time.Now()
+	}
+	model := mapSubscriptionToModel(sub)
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return nil, err
+	}
+	return mapSubscriptionModelToDomain(model), nil
+}
+
+func (r *GormRepository) UpdateSubscription(ctx context.Context, sub *webhook.Subscription) (*webhook.Subscription, error) {
+	now := time.Now()
+	res := r.db.WithContext(ctx).Model(&gormmodels.WebhookSubscription{}).
+		Where("id = ?", sub.ID).
+		Updates(map[string]interface{}{
+			"carrier_code": sub.CarrierCode,
+			"url":          sub.URL,
+			"event_types":  joinEventTypes(sub.EventTypes),
+			"secret":       sub.Secret,
+			"is_active":    sub.IsActive,
+			"updated_at":   now,
+		})
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, webhook.ErrSubscriptionNotFound
+	}
+	sub.UpdatedAt = &now
+	return sub, nil
+}
+
+func (r *GormRepository) GetSubscription(ctx context.Context, id uuid.UUID) (*webhook.Subscription, error) {
+	var model gormmodels.WebhookSubscription
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, webhook.ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+	return mapSubscriptionModelToDomain(model), nil
+}
+
+func (r *GormRepository) ListSubscriptions(ctx context.Context, carrierCode string) ([]*webhook.Subscription, error) {
+	query := r.db.WithContext(ctx).Model(&gormmodels.WebhookSubscription{})
+	if carrierCode != "" {
+		query = query.Where("carrier_code = ?", carrierCode)
+	}
+	var models []gormmodels.WebhookSubscription
+	if err := query.Order("carrier_code ASC, created_at ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return mapSubscriptions(models), nil
+}
+
+func (r *GormRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	res := r.db.WithContext(ctx).Delete(&gormmodels.WebhookSubscription{}, "id = ?", id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return webhook.ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+func (r *GormRepository) ListActiveByCarrier(ctx context.Context, carrierCode string) ([]*webhook.Subscription, error) {
+	var models []gormmodels.WebhookSubscription
+	if err := r.db.WithContext(ctx).
+		Where("carrier_code = ? AND is_active = ?", carrierCode, true).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return mapSubscriptions(models), nil
+}
+
+func (r *GormRepository) EnqueueDeliveries(ctx context.Context, deliveries []*webhook.Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	models := make([]gormmodels.WebhookDelivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		if d.ID == uuid.Nil {
+			d.ID = uuid.New()
+		}
+		models = append(models, gormmodels.WebhookDelivery{
+			ID:             d.ID,
+			SubscriptionID: d.SubscriptionID,
+			EventID:        d.EventID,
+			EventType:      string(d.EventType),
+			Payload:        string(d.Payload),
+			Status:         string(d.Status),
+			Attempts:       d.Attempts,
+			NextAttemptAt:  d.NextAttemptAt,
+			ReplayOf:       d.ReplayOf,
+			CreatedAt:      d.CreatedAt,
+		})
+	}
+	return r.db.WithContext(ctx).Create(&models).Error
+}
+
+func (r *GormRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*webhook.Delivery, error) {
+	models, err := database.ClaimDue(ctx, r.db, string(webhook.DeliveryPending), now, leaseUntil, limit,
+		func(m gormmodels.WebhookDelivery) uuid.UUID { return m.ID })
+	if err != nil {
+		return nil, err
+	}
+	return mapDeliveries(models), nil
+}
+
+func (r *GormRepository) MarkSucceeded(ctx context.Context, id uuid.UUID, attempts int, statusCode int, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&gormmodels.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":           string(webhook.DeliverySucceeded),
+			"attempts":         attempts,
+			"last_status_code": statusCode,
+			"last_error":       nil,
+			"delivered_at":     at,
+			"updated_at":       at,
+		}).Error
+}
+
+func (r *GormRepository) MarkFailed(ctx context.Context, id uuid.UUID, attempts int, statusCode *int, nextAttemptAt time.Time, lastError string) error {
+	return r.db.WithContext(ctx).Model(&gormmodels.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":         attempts,
+			"last_status_code": statusCode,
+			"next_attempt_at":  nextAttemptAt,
+			"last_error":       lastError,
+			"updated_at":       time.Now(),
+		}).Error
+}
+
+func (r *GormRepository) MarkDead(ctx context.Context, id uuid.UUID, attempts int, statusCode *int, lastError string) error {
+	return r.db.WithContext(ctx).Model(&gormmodels.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":           string(webhook.DeliveryDead),
+			"attempts":         attempts,
+			"last_status_code": statusCode,
+			"last_error":       lastError,
+			"updated_at":       time.Now(),
+		}).Error
+}
+
+func (r *GormRepository) GetDelivery(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error) {
+	var model gormmodels.WebhookDelivery
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, webhook.ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+	return mapDeliveryModelToDomain(model), nil
+}
+
+func (r *GormRepository) ListDeliveries(ctx context.Context, filter webhook.DeliveryFilter) ([]*webhook.Delivery, int64, error) {
+	query := r.db.WithContext(ctx).Model(&gormmodels.WebhookDelivery{})
+	if filter.SubscriptionID != nil {
+		query = query.Where("subscription_id = ?", *filter.SubscriptionID)
+	}
+	if filter.EventID != nil {
+		query = query.Where("event_id = ?", *filter.EventID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var models []gormmodels.WebhookDelivery
+	if err := query.
+		Order("created_at DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+	return mapDeliveries(models), total, nil
+}
+
+func mapSubscriptionToModel(sub *webhook.Subscription) gormmodels.WebhookSubscription {
+	return gormmodels.WebhookSubscription{
+		ID:          sub.ID,
+		CarrierCode: sub.CarrierCode,
+		URL:         sub.URL,
+		EventTypes:  joinEventTypes(sub.EventTypes),
+		Secret:      sub.Secret,
+		IsActive:    sub.IsActive,
+		CreatedAt:   sub.CreatedAt,
+		UpdatedAt:   sub.UpdatedAt,
+	}
+}
+
+func mapSubscriptionModelToDomain(model gormmodels.WebhookSubscription) *webhook.Subscription {
+	return &webhook.Subscription{
+		ID:          model.ID,
+		CarrierCode: model.CarrierCode,
+		URL:         model.URL,
+		EventTypes:  splitEventTypes(model.EventTypes),
+		Secret:      model.Secret,
+		IsActive:    model.IsActive,
+		CreatedAt:   model.CreatedAt,
+		UpdatedAt:   model.UpdatedAt,
+	}
+}
+
+func mapSubscriptions(models []gormmodels.WebhookSubscription) []*webhook.Subscription {
+	results := make([]*webhook.Subscription, 0, len(models))
+	for _, m := range models {
+		results = append(results, mapSubscriptionModelToDomain(m))
+	}
+	return results
+}
+
+func mapDeliveryModelToDomain(model gormmodels.WebhookDelivery) *webhook.Delivery {
+	return &webhook.Delivery{
+		ID:             model.ID,
+		SubscriptionID: model.SubscriptionID,
+		EventID:        model.EventID,
+		EventType:      webhook.EventType(model.EventType),
+		Payload:        []byte(model.Payload),
+		Status:         webhook.DeliveryStatus(model.Status),
+		Attempts:       model.Attempts,
+		NextAttemptAt:  model.NextAttemptAt,
+		LastStatusCode: model.LastStatusCode,
+		LastError:      model.LastError,
+		DeliveredAt:    model.DeliveredAt,
+		ReplayOf:       model.ReplayOf,
+		CreatedAt:      model.CreatedAt,
+		UpdatedAt:      model.UpdatedAt,
+	}
+}
+
+func mapDeliveries(models []gormmodels.WebhookDelivery) []*webhook.Delivery {
+	results := make([]*webhook.Delivery, 0, len(models))
+	for _, m := range models {
+		results = append(results, mapDeliveryModelToDomain(m))
+	}
+	return results
+}
+
+func joinEventTypes(types []webhook.EventType) string {
+	parts := make([]string, 0, len(types))
+	for _, t := range types {
+		parts = append(parts, string(t))
+	}
+	return strings.Join(parts, ",")
+}
+
+func splitEventTypes(raw string) []webhook.EventType {
+	var types []webhook.EventType
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			types = append(types, webhook.EventType(part))
+		}
+	}
+	return types
+}
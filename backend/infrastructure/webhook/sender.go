@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"smart-parcel-locker/backend/domain/webhook"
+)
+
+// HTTPSender posts signed deliveries over HTTP.
+type HTTPSender struct {
+	client    *http.Client
+	userAgent string
+	now       func() time.Time
+}
+
+func NewHTTPSender(timeout time.Duration) *HTTPSender {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &HTTPSender{
+		client:    &http.Client{Timeout: timeout},
+		userAgent: "smart-parcel-locker-webhooks/1",
+		now:       time.Now,
+	}
+}
+
+func (s *HTTPSender) Send(ctx context.Context, sub *webhook.Subscription, d *webhook.Delivery) (webhook.Attempt, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return webhook.Attempt{}, err
+	}
+	timestamp := s.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", s.userAgent)
+	req.Header.Set(webhook.HeaderEvent, string(d.EventType))
+	req.Header.Set(webhook.HeaderDelivery, d.ID.String())
+	req.Header.Set(webhook.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhook.HeaderSignature, webhook.Sign(sub.Secret, timestamp, d.Payload))
+
+	started := s.now()
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return webhook.Attempt{Duration: s.now().Sub(started)}, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	attempt := webhook.Attempt{StatusCode: resp.StatusCode, Duration: s.now().Sub(started)}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return attempt, fmt.Errorf("%s returned status %d", req.URL.Host, resp.StatusCode)
+	}
+	return attempt, nil
+}
@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"smart-parcel-locker/backend/domain/webhook"
+)
+
+func TestHTTPSenderSignsPayload(t *testing.T) {
+	const secret = "whsec_test_secret_value"
+	var gotStatus int
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, err := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+		if err != nil || !webhook.Verify(secret, ts, body, r.Header.Get(webhook.HeaderSignature)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get(webhook.HeaderEvent) != string(webhook.EventPing) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(gotStatus)
+	}))
+	defer receiver.Close()
+
+	sub := &webhook.Subscription{ID: uuid.New(), URL: receiver.URL, Secret: secret, IsActive: true}
+	event := webhook.Event{ID: uuid.New(), Type: webhook.EventPing, OccurredAt: time.Now()}
+	payload, err := event.Payload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	delivery := webhook.NewDelivery(sub, event.ID, event.Type, payload, time.Now())
+	sender := NewHTTPSender(time.Second)
+
+	gotStatus = http.StatusNoContent
+	attempt, err := sender.Send(context.Background(), sub, delivery)
+	if err != nil || attempt.StatusCode != http.StatusNoContent {
+		t.Fatalf("signed send: status=%d err=%v", attempt.StatusCode, err)
+	}
+
+	gotStatus = http.StatusServiceUnavailable
+	attempt, err = sender.Send(context.Background(), sub, delivery)
+	if err == nil || attempt.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("non-2xx: status=%d err=%v", attempt.StatusCode, err)
+	}
+
+	sub.Secret = "whsec_wrong_secret_value"
+	gotStatus = http.StatusOK
+	if attempt, _ := sender.Send(context.Background(), sub, delivery); attempt.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("wrong secret accepted: status=%d", attempt.StatusCode)
+	}
+}
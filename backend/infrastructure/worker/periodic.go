@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"smart-parcel-locker/backend/pkg/logger"
+)
+
+// RunPeriodic invokes fn every interval until ctx is cancelled.
+// It blocks, so callers usually start it in its own goroutine.
+func RunPeriodic(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	if interval <= 0 {
+		logger.Warn(ctx, "worker disabled", map[string]interface{}{
+			"worker": name,
+		}, "")
+		return
+	}
+	logger.Info(ctx, "worker started", map[string]interface{}{
+		"worker":   name,
+		"interval": interval.String(),
+	}, "")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info(context.Background(), "worker stopped", map[string]interface{}{
+				"worker": name,
+			}, "")
+			return
+		case <-ticker.C:
+			if err := fn(ctx); err != nil {
+				logger.Error(ctx, "worker run failed unexpectedly", map[string]interface{}{
+					"worker": name,
+					"error":  err.Error(),
+				}, "")
+			}
+		}
+	}
+}
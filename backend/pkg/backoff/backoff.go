@@ -0,0 +1,17 @@
+// Package backoff computes retry delays for the outbox and webhook dispatchers.
+package backoff
+
+import "time"
+
+// Exponential returns base * 2^(attempts-1), capped at max. attempts counts the failures so far,
+// so the first retry waits base.
+func Exponential(attempts int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return delay
+}
@@ -0,0 +1,24 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExponential(t *testing.T) {
+	cases := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{7, 30 * time.Minute},
+		{100, 30 * time.Minute},
+	}
+	for _, c := range cases {
+		if got := Exponential(c.attempts, time.Minute, 30*time.Minute); got != c.want {
+			t.Fatalf("attempts %d: expected %s, got %s", c.attempts, c.want, got)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/caarlos0/env/v10"
 	"github.com/joho/godotenv"
@@ -10,9 +11,22 @@ import (
 
 // Config aggregates all application configuration.
 type Config struct {
-	App      AppConfig
-	HTTP     HTTPConfig
-	Database DatabaseConfig
+	App       AppConfig
+	HTTP      HTTPConfig
+	Database  DatabaseConfig
+	RateLimit RateLimitConfig
+	OTP       OTPConfig
+	Phone     PhoneConfig
+	Pickup    PickupConfig
+	Parcel    ParcelConfig
+	Notify    NotificationConfig
+	Reminder  ReminderConfig
+	Webhook   WebhookConfig
+	Admin     AdminConfig
+	Device    DeviceConfig
+	Hardware  HardwareConfig
+	Incident  IncidentConfig
+	Heartbeat HeartbeatConfig
 }
 
 type AppConfig struct {
@@ -22,6 +36,8 @@ type AppConfig struct {
 
 type HTTPConfig struct {
 	Port int `env:"HTTP_PORT,required"`
+	// ProxyHeader is the header carrying the client IP when running behind nginx (e.g. X-Forwarded-For).
+	ProxyHeader string `env:"HTTP_PROXY_HEADER"`
 }
 
 type DatabaseConfig struct {
@@ -33,6 +49,159 @@ type DatabaseConfig struct {
 	SSLMode  string `env:"DB_SSLMODE,required"`
 }
 
+// RateLimitConfig selects the limiter store and per-endpoint limits.
+// Limits use the "<limit>/<window>" format, e.g. "5/10m"; "off" disables a rule.
+type RateLimitConfig struct {
+	Store           string        `env:"RATE_LIMIT_STORE" envDefault:"memory"` // memory | postgres
+	PurgeInterval   time.Duration `env:"RATE_LIMIT_PURGE_INTERVAL" envDefault:"5m"`
+	OTPRequestPhone string        `env:"RATE_LIMIT_OTP_REQUEST_PHONE" envDefault:"1/30s"`
+	OTPRequestIP    string        `env:"RATE_LIMIT_OTP_REQUEST_IP" envDefault:"20/10m"`
+	OTPVerifyPhone  string        `env:"RATE_LIMIT_OTP_VERIFY_PHONE" envDefault:"10/10m"`
+	OTPVerifyIP     string        `env:"RATE_LIMIT_OTP_VERIFY_IP" envDefault:"30/10m"`
+	DepositIP       string        `env:"RATE_LIMIT_DEPOSIT_IP" envDefault:"30/1m"`
+	DepositLocker   string        `env:"RATE_LIMIT_DEPOSIT_LOCKER" envDefault:"60/1m"`
+	TrackingIP      string        `env:"RATE_LIMIT_TRACKING_IP" envDefault:"60/1m"`
+	AdminLoginIP    string        `env:"RATE_LIMIT_ADMIN_LOGIN_IP" envDefault:"10/10m"`
+}
+
+// OTPConfig controls OTP housekeeping.
+type OTPConfig struct {
+	HousekeepingInterval time.Duration `env:"OTP_HOUSEKEEPING_INTERVAL" envDefault:"1m"`
+	Retention            time.Duration `env:"OTP_RETENTION" envDefault:"720h"`
+	RetentionMode        string        `env:"OTP_RETENTION_MODE" envDefault:"delete"` // delete | anonymize
+}
+
+// PhoneConfig controls phone number normalisation.
+type PhoneConfig struct {
+	DefaultCountry string `env:"PHONE_DEFAULT_COUNTRY" envDefault:"TH"`
+}
+
+// PickupConfig selects where pickup tokens are kept.
+type PickupConfig struct {
+	TokenStore           string        `env:"PICKUP_TOKEN_STORE" envDefault:"memory"` // memory | postgres | signed
+	TokenCleanupInterval time.Duration `env:"PICKUP_TOKEN_CLEANUP_INTERVAL" envDefault:"10m"`
+	// SigningKeys maps key IDs to HMAC secrets for signed tokens, e.g. "k1:secret1,k2:secret2".
+	SigningKeys map[string]string `env:"PICKUP_TOKEN_SIGNING_KEYS"`
+	// ActiveKeyID selects which signing key issues new tokens; the others only verify.
+	ActiveKeyID string `env:"PICKUP_TOKEN_ACTIVE_KEY_ID"`
+	// Denylist backs revocation of signed tokens.
+	Denylist string `env:"PICKUP_TOKEN_DENYLIST" envDefault:"memory"` // memory | postgres
+	// RevokeTokenWhenCollected ends the session once every eligible parcel is picked up.
+	RevokeTokenWhenCollected bool `env:"PICKUP_REVOKE_TOKEN_WHEN_COLLECTED" envDefault:"false"`
+}
+
+// ParcelConfig controls deposit behaviour.
+type ParcelConfig struct {
+	// StoragePeriod is the deadline for collecting a deposited parcel.
+	StoragePeriod time.Duration `env:"PARCEL_STORAGE_PERIOD" envDefault:"72h"`
+	// PickupURL is the receiver pickup page included in deposit notifications.
+	PickupURL string `env:"PICKUP_PAGE_URL"`
+}
+
+// ReminderConfig controls pickup reminders.
+type ReminderConfig struct {
+	Interval time.Duration `env:"REMINDER_INTERVAL" envDefault:"5m"`
+	// DefaultRules apply to locations without their own policy, e.g. "DEPOSITED+24h,GRACE_END-2h,EXPIRY-24h".
+	// Anchors are DEPOSITED, GRACE_END and EXPIRY; "off" disables reminders by default.
+	DefaultRules string `env:"REMINDER_DEFAULT_RULES" envDefault:"DEPOSITED+24h,GRACE_END-2h,EXPIRY-24h"`
+}
+
+// WebhookConfig controls delivery of carrier webhooks. Failed deliveries back off exponentially
+// from BaseBackoff up to MaxBackoff and are dead-lettered after MaxAttempts.
+type WebhookConfig struct {
+	Interval    time.Duration `env:"WEBHOOK_INTERVAL" envDefault:"5s"`
+	Timeout     time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"10s"`
+	BatchSize   int           `env:"WEBHOOK_BATCH_SIZE" envDefault:"50"`
+	MaxAttempts int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"10"`
+	BaseBackoff time.Duration `env:"WEBHOOK_BASE_BACKOFF" envDefault:"30s"`
+	MaxBackoff  time.Duration `env:"WEBHOOK_MAX_BACKOFF" envDefault:"6h"`
+	Lease       time.Duration `env:"WEBHOOK_LEASE" envDefault:"15m"`
+}
+
+// AdminConfig controls admin sessions, password setup links and the bootstrap admin.
+type AdminConfig struct {
+	SessionTTL             time.Duration `env:"ADMIN_SESSION_TTL" envDefault:"30m"`
+	RefreshTTL             time.Duration `env:"ADMIN_REFRESH_TTL" envDefault:"168h"`
+	SessionCleanupInterval time.Duration `env:"ADMIN_SESSION_CLEANUP_INTERVAL" envDefault:"1h"`
+	SetupTokenTTL          time.Duration `env:"ADMIN_SETUP_TOKEN_TTL" envDefault:"72h"`
+	// SetupURL is the admin UI page that sets a password; the setup token is appended as ?token=.
+	SetupURL string `env:"ADMIN_SETUP_URL"`
+	// BootstrapUsername and BootstrapPassword create the first admin when the admins table is empty.
+	BootstrapUsername string `env:"ADMIN_BOOTSTRAP_USERNAME"`
+	BootstrapPassword string `env:"ADMIN_BOOTSTRAP_PASSWORD"`
+	// MFARequiredRoles lists the roles that must use TOTP two-factor authentication, comma separated.
+	MFARequiredRoles []string `env:"ADMIN_MFA_REQUIRED_ROLES" envSeparator:","`
+	TOTPIssuer       string   `env:"ADMIN_TOTP_ISSUER" envDefault:"Smart Parcel Locker"`
+}
+
+// DeviceConfig controls signed requests from locker kiosks.
+type DeviceConfig struct {
+	// SignatureMaxSkew is how far a request timestamp may lie from the server clock.
+	SignatureMaxSkew     time.Duration `env:"DEVICE_SIGNATURE_MAX_SKEW" envDefault:"5m"`
+	NonceCleanupInterval time.Duration `env:"DEVICE_NONCE_CLEANUP_INTERVAL" envDefault:"10m"`
+}
+
+// HardwareConfig selects the locker controller that opens compartment doors.
+type HardwareConfig struct {
+	Driver string `env:"LOCK_CONTROLLER_DRIVER" envDefault:"simulator"` // simulator | tcp | serial
+	// Address is the host:port of the board, or of its serial-to-Ethernet bridge, for the tcp driver.
+	Address      string `env:"LOCK_CONTROLLER_ADDRESS"`
+	SerialDevice string `env:"LOCK_CONTROLLER_SERIAL_DEVICE"`
+	// Timeout bounds each board command; values above 5s are capped because deposits and
+	// pickups hold row locks while the door opens.
+	Timeout time.Duration `env:"LOCK_CONTROLLER_TIMEOUT" envDefault:"5s"`
+	// SimulatorAutoClose is how long simulated doors stay open.
+	SimulatorAutoClose time.Duration `env:"LOCK_CONTROLLER_SIMULATOR_AUTO_CLOSE" envDefault:"10s"`
+}
+
+// IncidentConfig controls alerts raised from door and sensor events.
+type IncidentConfig struct {
+	DoorLeftOpenAfter time.Duration `env:"INCIDENT_DOOR_LEFT_OPEN_AFTER" envDefault:"2m"`
+	CheckInterval     time.Duration `env:"INCIDENT_CHECK_INTERVAL" envDefault:"30s"`
+}
+
+// HeartbeatConfig controls when lockers whose kiosk stops sending heartbeats go OFFLINE.
+type HeartbeatConfig struct {
+	OfflineAfter  time.Duration `env:"LOCKER_HEARTBEAT_OFFLINE_AFTER" envDefault:"3m"`
+	CheckInterval time.Duration `env:"LOCKER_HEARTBEAT_CHECK_INTERVAL" envDefault:"30s"`
+}
+
+// NotificationConfig configures outbound notification channels.
+// A channel is enabled only when its required settings are present; LOG is always available but
+// cannot be the default channel when APP_ENV is production.
+type NotificationConfig struct {
+	DefaultChannel string        `env:"NOTIFY_DEFAULT_CHANNEL" envDefault:"LOG"` // SMS | EMAIL | LINE | DISCORD | LOG
+	DefaultLocale  string        `env:"NOTIFY_DEFAULT_LOCALE" envDefault:"th"`   // th | en; used when neither receiver nor location sets one
+	Timeout        time.Duration `env:"NOTIFY_TIMEOUT" envDefault:"10s"`
+	// Timezone is the IANA zone deadlines and other times are shown in to receivers.
+	Timezone string `env:"NOTIFY_TIMEZONE" envDefault:"Asia/Bangkok"`
+
+	SMSProviderURL string `env:"SMS_PROVIDER_URL"`
+	SMSAPIKey      string `env:"SMS_API_KEY"`
+	SMSAuthHeader  string `env:"SMS_AUTH_HEADER" envDefault:"Authorization"`
+	SMSSenderName  string `env:"SMS_SENDER_NAME"`
+
+	SMTPHost     string `env:"SMTP_HOST"`
+	SMTPPort     int    `env:"SMTP_PORT" envDefault:"587"`
+	SMTPUsername string `env:"SMTP_USERNAME"`
+	SMTPPassword string `env:"SMTP_PASSWORD"`
+	SMTPFrom     string `env:"SMTP_FROM"`
+
+	LineChannelAccessToken string `env:"LINE_CHANNEL_ACCESS_TOKEN"`
+
+	DiscordWebhookURL string `env:"DISCORD_WEBHOOK_URL"`
+
+	// Outbox dispatcher: failed sends back off exponentially from OutboxBaseBackoff up to
+	// OutboxMaxBackoff and are dead-lettered after OutboxMaxAttempts.
+	OutboxInterval    time.Duration `env:"NOTIFY_OUTBOX_INTERVAL" envDefault:"5s"`
+	OutboxBatchSize   int           `env:"NOTIFY_OUTBOX_BATCH_SIZE" envDefault:"50"`
+	OutboxMaxAttempts int           `env:"NOTIFY_OUTBOX_MAX_ATTEMPTS" envDefault:"8"`
+	OutboxBaseBackoff time.Duration `env:"NOTIFY_OUTBOX_BASE_BACKOFF" envDefault:"30s"`
+	OutboxMaxBackoff  time.Duration `env:"NOTIFY_OUTBOX_MAX_BACKOFF" envDefault:"1h"`
+	// OutboxLease hides claimed messages from other replicas; it must outlast a whole batch.
+	OutboxLease time.Duration `env:"NOTIFY_OUTBOX_LEASE" envDefault:"15m"`
+}
+
 // Load reads environment variables (optionally from a .env file) into Config.
 func Load(envPaths ...string) (*Config, error) {
 	for _, p := range envPaths {
@@ -0,0 +1,106 @@
+package password
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Cost is the bcrypt work factor used for new hashes. Hashes below it are reported by
+// NeedsRehash. Tests lower it to bcrypt.MinCost; production code should leave it alone.
+var Cost = 12
+
+// MinLength is the shortest password accepted by Validate.
+const MinLength = 12
+
+// MaxLength bounds passwords to what bcrypt can hash (72 bytes).
+const MaxLength = 72
+
+var (
+	ErrTooShort        = fmt.Errorf("password must be at least %d characters", MinLength)
+	ErrTooLong         = fmt.Errorf("password must be at most %d bytes", MaxLength)
+	ErrUnsupportedHash = errors.New("unsupported password hash")
+)
+
+// Validate checks a plaintext password against the length policy.
+func Validate(plain string) error {
+	if len([]rune(plain)) < MinLength {
+		return ErrTooShort
+	}
+	if len(plain) > MaxLength {
+		return ErrTooLong
+	}
+	return nil
+}
+
+// Hash returns a bcrypt hash of plain.
+func Hash(plain string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(plain), Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Verify reports whether plain matches hash. Both bcrypt ($2a$, $2b$, $2y$) and argon2id in
+// PHC form ($argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>) are accepted, so hashes imported from
+// other systems keep working. A hash in any other format returns ErrUnsupportedHash.
+func Verify(hash, plain string) (bool, error) {
+	switch {
+	case isBcrypt(hash):
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(plain))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return err == nil, err
+	case strings.HasPrefix(hash, "$argon2id$"):
+		return verifyArgon2id(hash, plain)
+	default:
+		return false, ErrUnsupportedHash
+	}
+}
+
+// NeedsRehash reports whether hash should be replaced by a fresh Hash after a successful Verify.
+func NeedsRehash(hash string) bool {
+	if !isBcrypt(hash) {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost < Cost
+}
+
+func isBcrypt(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func verifyArgon2id(hash, plain string) (bool, error) {
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return false, ErrUnsupportedHash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, ErrUnsupportedHash
+	}
+	var memory, iterations uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iterations, &threads); err != nil {
+		return false, ErrUnsupportedHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, ErrUnsupportedHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return false, ErrUnsupportedHash
+	}
+	derived := argon2.IDKey([]byte(plain), salt, iterations, memory, threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(derived, key) == 1, nil
+}
@@ -0,0 +1,73 @@
+package password
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHashAndVerify(t *testing.T) {
+	hash, err := Hash("correct horse battery")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok, err := Verify(hash, "correct horse battery"); err != nil || !ok {
+		t.Fatalf("expected match; got %v, %v", ok, err)
+	}
+	if ok, err := Verify(hash, "wrong horse battery"); err != nil || ok {
+		t.Fatalf("expected mismatch; got %v, %v", ok, err)
+	}
+	if NeedsRehash(hash) {
+		t.Fatal("fresh hash should not need rehash")
+	}
+}
+
+func TestVerifyArgon2id(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+	key := argon2.IDKey([]byte("legacy password"), salt, 1, 64*1024, 2, 32)
+	hash := fmt.Sprintf("$argon2id$v=%d$m=65536,t=1,p=2$%s$%s", argon2.Version,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+
+	if ok, err := Verify(hash, "legacy password"); err != nil || !ok {
+		t.Fatalf("expected match; got %v, %v", ok, err)
+	}
+	if ok, err := Verify(hash, "other password"); err != nil || ok {
+		t.Fatalf("expected mismatch; got %v, %v", ok, err)
+	}
+	if !NeedsRehash(hash) {
+		t.Fatal("argon2id hash should be rehashed to bcrypt")
+	}
+}
+
+func TestVerifyRejectsUnknownFormats(t *testing.T) {
+	for _, hash := range []string{"", "plaintext", "5f4dcc3b5aa765d61d8327deb882cf99", "$argon2id$v=19$broken"} {
+		if ok, err := Verify(hash, "plaintext"); ok || err != ErrUnsupportedHash {
+			t.Fatalf("%q: expected ErrUnsupportedHash; got %v, %v", hash, ok, err)
+		}
+	}
+}
+
+func TestNeedsRehashLowCost(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct horse battery"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !NeedsRehash(string(hash)) {
+		t.Fatal("low-cost bcrypt hash should need rehash")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate("short"); err != ErrTooShort {
+		t.Fatalf("expected ErrTooShort; got %v", err)
+	}
+	if err := Validate(string(make([]byte, MaxLength+1))); err != ErrTooLong {
+		t.Fatalf("expected ErrTooLong; got %v", err)
+	}
+	if err := Validate("long enough password"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package phone
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalid is returned when a value cannot be normalised to E.164.
+var ErrInvalid = errors.New("invalid phone number")
+
+// callingCodes maps ISO 3166-1 alpha-2 regions to country calling codes.
+var callingCodes = map[string]string{
+	"TH": "66",
+	"LA": "856",
+	"KH": "855",
+	"MM": "95",
+	"MY": "60",
+	"SG": "65",
+	"VN": "84",
+	"ID": "62",
+	"PH": "63",
+	"CN": "86",
+	"JP": "81",
+	"KR": "82",
+	"IN": "91",
+	"GB": "44",
+	"US": "1",
+}
+
+// DefaultRegion is the region a zero Normalizer uses.
+const DefaultRegion = "TH"
+
+// Normalizer converts phone numbers to E.164, reading numbers without an international prefix
+// as belonging to its region. The zero value uses DefaultRegion.
+type Normalizer struct {
+	region string
+}
+
+// NewNormalizer returns a Normalizer for the given ISO 3166-1 alpha-2 region.
+func NewNormalizer(region string) (Normalizer, error) {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if _, ok := callingCodes[region]; !ok {
+		return Normalizer{}, fmt.Errorf("unsupported phone region %q", region)
+	}
+	return Normalizer{region: region}, nil
+}
+
+// Region returns the region used for numbers without an international prefix.
+func (n Normalizer) Region() string {
+	if n.region == "" {
+		return DefaultRegion
+	}
+	return n.region
+}
+
+// Normalize converts a phone number to E.164 (e.g. "+66812345678") using the normalizer's region.
+func (n Normalizer) Normalize(raw string) (string, error) {
+	return NormalizeFor(raw, n.Region())
+}
+
+// NormalizeFor converts a phone number to E.164 using the given default region.
+// Accepted inputs include "0812345678", "66812345678", "+66 81-234-5678" and "0066812345678".
+func NormalizeFor(raw string, region string) (string, error) {
+	code, ok := callingCodes[strings.ToUpper(region)]
+	if !ok {
+		return "", fmt.Errorf("unsupported phone region %q", region)
+	}
+
+	value := strings.TrimSpace(raw)
+	international := strings.HasPrefix(value, "+")
+	if international {
+		value = value[1:]
+	}
+	digits := make([]byte, 0, len(value))
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		switch {
+		case ch >= '0' && ch <= '9':
+			digits = append(digits, ch)
+		case ch == ' ' || ch == '-' || ch == '.' || ch == '(' || ch == ')':
+		default:
+			return "", ErrInvalid
+		}
+	}
+	number := string(digits)
+
+	switch {
+	case international:
+	case strings.HasPrefix(number, "00"):
+		number = number[2:]
+	case strings.HasPrefix(number, "0"):
+		number = code + number[1:]
+	case strings.HasPrefix(number, code) && len(number) >= len(code)+8:
+	default:
+		number = code + number
+	}
+
+	// E.164 allows at most 15 digits; anything shorter than 8 is not a dialable subscriber number.
+	if len(number) < 8 || len(number) > 15 || number[0] == '0' {
+		return "", ErrInvalid
+	}
+	return "+" + number, nil
+}
@@ -0,0 +1,58 @@
+package phone
+
+import "testing"
+
+func TestNormalizeForThailand(t *testing.T) {
+	cases := map[string]string{
+		"0812345678":      "+66812345678",
+		"66812345678":     "+66812345678",
+		"+66812345678":    "+66812345678",
+		"+66 81-234-5678": "+66812345678",
+		"0066812345678":   "+66812345678",
+		"(081) 234 5678":  "+66812345678",
+		"812345678":       "+66812345678",
+		"+6581234567":     "+6581234567",
+		" 02 123 4567 ":   "+6621234567",
+		"+1 415 555 2671": "+14155552671",
+	}
+	for input, want := range cases {
+		got, err := NormalizeFor(input, "TH")
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", input, err)
+		}
+		if got != want {
+			t.Fatalf("%q: expected %s; got %s", input, want, got)
+		}
+	}
+}
+
+func TestNormalizeForInvalid(t *testing.T) {
+	for _, input := range []string{"", "abc", "12345", "+0812345678", "0812345678x", "+1234567890123456"} {
+		if got, err := NormalizeFor(input, "TH"); err == nil {
+			t.Fatalf("%q: expected error; got %s", input, got)
+		}
+	}
+}
+
+func TestNormalizeForUnknownRegion(t *testing.T) {
+	if _, err := NormalizeFor("0812345678", "XX"); err == nil {
+		t.Fatal("expected error for unknown region")
+	}
+}
+
+func TestNormalizerRegion(t *testing.T) {
+	var zero Normalizer
+	if got, err := zero.Normalize("0812345678"); err != nil || got != "+66812345678" {
+		t.Fatalf("zero normalizer: got %q (%v)", got, err)
+	}
+	sg, err := NewNormalizer(" sg ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := sg.Normalize("81234567"); err != nil || got != "+6581234567" {
+		t.Fatalf("SG normalizer: got %q (%v)", got, err)
+	}
+	if _, err := NewNormalizer("XX"); err == nil {
+		t.Fatal("expected an unknown region to be rejected")
+	}
+}
@@ -0,0 +1,109 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RFC 6238 parameters every common authenticator app supports: HMAC-SHA1, 6 digits and 30
+// second steps.
+const (
+	// Digits is the length of a code.
+	Digits = 6
+	// Period is the lifetime of one time step.
+	Period = 30 * time.Second
+	// Skew is how many steps before or after the current one are still accepted, to allow for
+	// clock drift between server and phone.
+	Skew = 1
+
+	secretBytes = 20
+)
+
+var (
+	ErrInvalidSecret = errors.New("totp secret is not valid base32")
+	encoding         = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// GenerateSecret returns a random 160-bit secret, base32 encoded without padding.
+func GenerateSecret() (string, error) {
+	buf := make([]byte, secretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(buf), nil
+}
+
+// ProvisioningURI returns the otpauth:// URI that authenticator apps read from a QR code.
+func ProvisioningURI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer + ":" + account)
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(Digits))
+	query.Set("period", fmt.Sprint(int(Period/time.Second)))
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// Step returns the time step containing t.
+func Step(t time.Time) int64 {
+	return t.Unix() / int64(Period/time.Second)
+}
+
+// Code returns the code of secret for the given time step.
+func Code(secret string, step int64) (string, error) {
+	key, err := decode(secret)
+	if err != nil {
+		return "", err
+	}
+	return code(key, step), nil
+}
+
+// Verify checks code against the steps around now and returns the step it matched, so callers
+// can reject a code that was already used.
+func Verify(secret, candidate string, now time.Time) (int64, bool) {
+	candidate = strings.ReplaceAll(strings.TrimSpace(candidate), " ", "")
+	if len(candidate) != Digits {
+		return 0, false
+	}
+	key, err := decode(secret)
+	if err != nil {
+		return 0, false
+	}
+	current := Step(now)
+	for offset := int64(-Skew); offset <= Skew; offset++ {
+		step := current + offset
+		if subtle.ConstantTimeCompare([]byte(code(key, step)), []byte(candidate)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func decode(secret string) ([]byte, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidSecret
+	}
+	return key, nil
+}
+
+func code(key []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", Digits, value%1000000)
+}
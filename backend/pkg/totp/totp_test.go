@@ -0,0 +1,57 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the RFC 6238 appendix B SHA-1 key "12345678901234567890" in base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCodeMatchesRFC6238Vectors(t *testing.T) {
+	cases := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tc := range cases {
+		got, err := Code(rfcSecret, Step(time.Unix(tc.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code: %v", err)
+		}
+		if got != tc.want {
+			t.Fatalf("at %d: got %s, want %s", tc.unix, got, tc.want)
+		}
+	}
+}
+
+func TestVerifyAllowsOneStepOfDrift(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	previous, _ := Code(secret, Step(now)-1)
+	if step, ok := Verify(secret, previous, now); !ok || step != Step(now)-1 {
+		t.Fatalf("expected previous step to verify; got %d %v", step, ok)
+	}
+	stale, _ := Code(secret, Step(now)-2)
+	if _, ok := Verify(secret, stale, now); ok {
+		t.Fatal("expected a code two steps old to be rejected")
+	}
+	if _, ok := Verify(secret, "12345", now); ok {
+		t.Fatal("expected a short code to be rejected")
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("Smart Parcel Locker", "ops", rfcSecret)
+	if !strings.HasPrefix(uri, "otpauth://totp/Smart%20Parcel%20Locker:ops?") || !strings.Contains(uri, "secret="+rfcSecret) {
+		t.Fatalf("unexpected uri %s", uri)
+	}
+}
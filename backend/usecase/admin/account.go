@@ -0,0 +1,214 @@
+package admin
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/admin"
+	"smart-parcel-locker/backend/domain/audit"
+	"smart-parcel-locker/backend/pkg/logger"
+	"smart-parcel-locker/backend/pkg/password"
+	auditusecase "smart-parcel-locker/backend/usecase/audit"
+)
+
+// SetActiveInput enables or disables an admin. ActorID is the admin making the change.
+type SetActiveInput struct {
+	ID      uuid.UUID
+	Active  bool
+	ActorID uuid.UUID
+}
+
+// ChangePasswordInput replaces the password of the signed-in admin. SessionID is the session
+// making the request; it stays open while every other session is revoked.
+type ChangePasswordInput struct {
+	AdminID         uuid.UUID
+	SessionID       uuid.UUID
+	CurrentPassword string
+	NewPassword     string
+}
+
+// List returns admins ordered by username.
+func (uc *UseCase) List(ctx context.Context, filter admin.ListFilter) ([]*admin.Admin, int64, error) {
+	if filter.Role != "" && !admin.ValidRole(filter.Role) {
+		return nil, 0, admin.ErrInvalidRole
+	}
+	if filter.Limit <= 0 || filter.Limit > 200 {
+		filter.Limit = 50
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	items, total, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		logger.Error(ctx, "admin usecase list failed unexpectedly", map[string]interface{}{
+			"query": filter.Query,
+			"error": err.Error(),
+		}, "")
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// SetActive disables or re-enables an admin. Disabling revokes every open session. Admins cannot
+// disable themselves, and the last active SUPER_ADMIN cannot be disabled.
+func (uc *UseCase) SetActive(ctx context.Context, input SetActiveInput) (*admin.Admin, error) {
+	logger.Info(ctx, "admin usecase set active started", map[string]interface{}{
+		"adminId": input.ID.String(),
+		"active":  input.Active,
+		"actorId": input.ActorID.String(),
+	}, "")
+	err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo := uc.repoFor(tx)
+		current, err := repo.GetByID(ctx, input.ID)
+		if err != nil {
+			return err
+		}
+		if current.Active() == input.Active {
+			return nil
+		}
+		if input.ID == input.ActorID {
+			return admin.ErrSelfAccountChange
+		}
+		action := "admin.enable"
+		var disabledAt *time.Time
+		if !input.Active {
+			action = "admin.disable"
+			now := uc.now()
+			disabledAt = &now
+			if current.Role == admin.RoleSuperAdmin {
+				if err := ensureAnotherSuperAdmin(ctx, repo); err != nil {
+					return err
+				}
+			}
+		}
+		if err := repo.SetDisabledAt(ctx, input.ID, disabledAt); err != nil {
+			return err
+		}
+		if disabledAt != nil {
+			if _, err := repo.RevokeSessions(ctx, input.ID, *disabledAt); err != nil {
+				return err
+			}
+		}
+		after := *current
+		after.DisabledAt = disabledAt
+		return auditusecase.Bind(uc.audit, tx).Record(ctx, audit.Change{
+			Action:     action,
+			EntityType: audit.EntityAdmin,
+			EntityID:   input.ID.String(),
+			Before:     adminSnapshot(current),
+			After:      adminSnapshot(&after),
+		})
+	})
+	if err != nil {
+		uc.logWriteError(ctx, "set active", input.ID, err)
+		return nil, err
+	}
+	logger.Info(ctx, "admin usecase active set", map[string]interface{}{
+		"adminId": input.ID.String(),
+		"active":  input.Active,
+		"actorId": input.ActorID.String(),
+	}, "")
+	return uc.repo.GetByID(ctx, input.ID)
+}
+
+// ChangePassword replaces the password of the signed-in admin after checking the current one.
+func (uc *UseCase) ChangePassword(ctx context.Context, input ChangePasswordInput) error {
+	a, err := uc.repo.GetByID(ctx, input.AdminID)
+	if err != nil {
+		return err
+	}
+	if !a.HasPassword() {
+		return admin.ErrWrongPassword
+	}
+	ok, err := password.Verify(a.PasswordHash, input.CurrentPassword)
+	if err != nil || !ok {
+		logger.Warn(ctx, "admin usecase change password wrong current password", map[string]interface{}{
+			"adminId": a.ID.String(),
+		}, "")
+		return admin.ErrWrongPassword
+	}
+	if err := password.Validate(input.NewPassword); err != nil {
+		return admin.ErrWeakPassword
+	}
+	hash, err := password.Hash(input.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	now := uc.now()
+	var revoked int64
+	err = uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo := uc.repoFor(tx)
+		if err := repo.SetPasswordHash(ctx, a.ID, hash, now); err != nil {
+			return err
+		}
+		if revoked, err = repo.RevokeOtherSessions(ctx, a.ID, input.SessionID, now); err != nil {
+			return err
+		}
+		return auditusecase.Bind(uc.audit, tx).Record(ctx, audit.Change{
+			Action:     "admin.password_change",
+			EntityType: audit.EntityAdmin,
+			EntityID:   a.ID.String(),
+		})
+	})
+	if err != nil {
+		uc.logWriteError(ctx, "change password", a.ID, err)
+		return err
+	}
+	logger.Info(ctx, "admin usecase password changed", map[string]interface{}{
+		"adminId":         a.ID.String(),
+		"revokedSessions": revoked,
+	}, "")
+	return nil
+}
+
+// ResetPassword clears an admin's password, revokes their sessions and earlier setup links, and
+// issues a new one-time setup link. Admins change their own password with ChangePassword.
+func (uc *UseCase) ResetPassword(ctx context.Context, id, actorID uuid.UUID) (*SetupLink, error) {
+	logger.Info(ctx, "admin usecase reset password started", map[string]interface{}{
+		"adminId": id.String(),
+		"actorId": actorID.String(),
+	}, "")
+	if id == actorID {
+		return nil, admin.ErrSelfAccountChange
+	}
+	var link *SetupLink
+	err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo := uc.repoFor(tx)
+		if _, err := repo.GetByID(ctx, id); err != nil {
+			return err
+		}
+		now := uc.now()
+		if err := repo.ClearPassword(ctx, id, now); err != nil {
+			return err
+		}
+		if _, err := repo.RevokeSessions(ctx, id, now); err != nil {
+			return err
+		}
+		if err := repo.ExpireSetupTokens(ctx, id, now); err != nil {
+			return err
+		}
+		var err error
+		if link, err = uc.issueSetupToken(ctx, repo, id); err != nil {
+			return err
+		}
+		return auditusecase.Bind(uc.audit, tx).Record(ctx, audit.Change{
+			Action:     "admin.password_reset",
+			EntityType: audit.EntityAdmin,
+			EntityID:   id.String(),
+		})
+	})
+	if err != nil {
+		uc.logWriteError(ctx, "reset password", id, err)
+		return nil, err
+	}
+	logger.Info(ctx, "admin usecase password reset", map[string]interface{}{
+		"adminId":        id.String(),
+		"actorId":        actorID.String(),
+		"setupExpiresAt": link.ExpiresAt,
+	}, "")
+	return link, nil
+}
@@ -0,0 +1,173 @@
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"smart-parcel-locker/backend/domain/admin"
+)
+
+const testPassword = "a sufficiently long secret"
+
+// newActiveAdmin creates an admin and completes the password setup.
+func newActiveAdmin(t *testing.T, uc *UseCase, username, role string) *CreateResult {
+	t.Helper()
+	created, err := uc.Create(context.Background(), CreateInput{Username: username, Role: role})
+	if err != nil {
+		t.Fatalf("create %s: %v", username, err)
+	}
+	if _, err := uc.SetPassword(context.Background(), created.Setup.Token, testPassword); err != nil {
+		t.Fatalf("set password %s: %v", username, err)
+	}
+	return created
+}
+
+func TestDisabledAdminCannotLogin(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := NewUseCase(repo, nil, nil, nil, Config{})
+	root := newActiveAdmin(t, uc, "root", admin.RoleSuperAdmin).Admin.ID
+	ops := newActiveAdmin(t, uc, "ops", admin.RoleOperator).Admin.ID
+	session, err := uc.Login(ctx, LoginInput{Username: "ops", Password: testPassword})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	if _, err := uc.SetActive(ctx, SetActiveInput{ID: root, Active: false, ActorID: root}); err != admin.ErrSelfAccountChange {
+		t.Fatalf("expected ErrSelfAccountChange; got %v", err)
+	}
+	disabled, err := uc.SetActive(ctx, SetActiveInput{ID: ops, Active: false, ActorID: root})
+	if err != nil || disabled.Active() {
+		t.Fatalf("disable: %v, %+v", err, disabled)
+	}
+	if _, err := uc.Authenticate(ctx, session.AccessToken); err == nil {
+		t.Fatal("expected open sessions to stop working once disabled")
+	}
+	if _, err := uc.Login(ctx, LoginInput{Username: "ops", Password: "wrong long secret"}); err != admin.ErrInvalidCredentials {
+		t.Fatalf("expected ErrInvalidCredentials for a wrong password; got %v", err)
+	}
+	if _, err := uc.Login(ctx, LoginInput{Username: "ops", Password: testPassword}); err != admin.ErrAccountDisabled {
+		t.Fatalf("expected ErrAccountDisabled; got %v", err)
+	}
+
+	if _, err := uc.SetActive(ctx, SetActiveInput{ID: ops, Active: true, ActorID: root}); err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+	if _, err := uc.Login(ctx, LoginInput{Username: "ops", Password: testPassword}); err != nil {
+		t.Fatalf("login after enable: %v", err)
+	}
+}
+
+func TestLastActiveSuperAdminCannotBeDisabled(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := NewUseCase(repo, nil, nil, nil, Config{})
+	root := uuid.New()
+	repo.admins[root] = &admin.Admin{ID: root, Username: "root", Role: admin.RoleSuperAdmin}
+	other := uuid.New()
+	repo.admins[other] = &admin.Admin{ID: other, Username: "root2", Role: admin.RoleSuperAdmin}
+
+	if _, err := uc.SetActive(ctx, SetActiveInput{ID: other, Active: false, ActorID: root}); err != nil {
+		t.Fatalf("disable second super admin: %v", err)
+	}
+	third := uuid.New()
+	repo.admins[third] = &admin.Admin{ID: third, Username: "ops", Role: admin.RoleOperator}
+	if _, err := uc.SetActive(ctx, SetActiveInput{ID: root, Active: false, ActorID: third}); err != admin.ErrLastSuperAdmin {
+		t.Fatalf("expected ErrLastSuperAdmin; got %v", err)
+	}
+}
+
+func TestChangePasswordRequiresCurrentPassword(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := NewUseCase(repo, nil, nil, nil, Config{})
+	id := newActiveAdmin(t, uc, "ops", admin.RoleOperator).Admin.ID
+	current, err := uc.Login(ctx, LoginInput{Username: "ops", Password: testPassword})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	other, err := uc.Login(ctx, LoginInput{Username: "ops", Password: testPassword})
+	if err != nil {
+		t.Fatalf("second login: %v", err)
+	}
+
+	input := ChangePasswordInput{AdminID: id, SessionID: current.SessionID, CurrentPassword: "not my password", NewPassword: "a brand new long secret"}
+	if err := uc.ChangePassword(ctx, input); err != admin.ErrWrongPassword {
+		t.Fatalf("expected ErrWrongPassword; got %v", err)
+	}
+	input.CurrentPassword = testPassword
+	input.NewPassword = "short"
+	if err := uc.ChangePassword(ctx, input); err != admin.ErrWeakPassword {
+		t.Fatalf("expected ErrWeakPassword; got %v", err)
+	}
+	input.NewPassword = "a brand new long secret"
+	if err := uc.ChangePassword(ctx, input); err != nil {
+		t.Fatalf("change password: %v", err)
+	}
+
+	if _, err := uc.Authenticate(ctx, current.AccessToken); err != nil {
+		t.Fatalf("expected the requesting session to stay open; got %v", err)
+	}
+	if _, err := uc.Authenticate(ctx, other.AccessToken); err == nil {
+		t.Fatal("expected other sessions to be revoked")
+	}
+	if _, err := uc.Login(ctx, LoginInput{Username: "ops", Password: testPassword}); err != admin.ErrInvalidCredentials {
+		t.Fatalf("expected the old password to stop working; got %v", err)
+	}
+	if _, err := uc.Login(ctx, LoginInput{Username: "ops", Password: "a brand new long secret"}); err != nil {
+		t.Fatalf("login with new password: %v", err)
+	}
+}
+
+func TestResetPasswordIssuesNewSetupLink(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := NewUseCase(repo, nil, nil, nil, Config{})
+	root := newActiveAdmin(t, uc, "root", admin.RoleSuperAdmin).Admin.ID
+	created := newActiveAdmin(t, uc, "ops", admin.RoleOperator)
+
+	if _, err := uc.ResetPassword(ctx, root, root); err != admin.ErrSelfAccountChange {
+		t.Fatalf("expected ErrSelfAccountChange; got %v", err)
+	}
+	first, err := uc.ResetPassword(ctx, created.Admin.ID, root)
+	if err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	second, err := uc.ResetPassword(ctx, created.Admin.ID, root)
+	if err != nil {
+		t.Fatalf("second reset: %v", err)
+	}
+	if _, err := uc.Login(ctx, LoginInput{Username: "ops", Password: testPassword}); err != admin.ErrInvalidCredentials {
+		t.Fatalf("expected the old password to be cleared; got %v", err)
+	}
+	if _, err := uc.SetPassword(ctx, first.Token, "a brand new long secret"); err != admin.ErrInvalidSetupToken {
+		t.Fatalf("expected the earlier link to be expired; got %v", err)
+	}
+	if _, err := uc.SetPassword(ctx, second.Token, "a brand new long secret"); err != nil {
+		t.Fatalf("set password from reset link: %v", err)
+	}
+}
+
+func TestListFiltersAdmins(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := NewUseCase(repo, nil, nil, nil, Config{})
+	for _, name := range []string{"ops-bkk", "ops-cnx", "support"} {
+		if _, err := uc.Create(ctx, CreateInput{Username: name, Role: admin.RoleOperator}); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+
+	items, total, err := uc.List(ctx, admin.ListFilter{Query: "OPS", Limit: 1})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if total != 2 || len(items) != 1 || items[0].Username != "ops-bkk" {
+		t.Fatalf("unexpected page %d %+v", total, items)
+	}
+	if _, _, err := uc.List(ctx, admin.ListFilter{Role: "ROOT"}); err != admin.ErrInvalidRole {
+		t.Fatalf("expected ErrInvalidRole; got %v", err)
+	}
+}
@@ -3,48 +3,146 @@ package admin
 import (
 	"context"
 	"errors"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
 	"smart-parcel-locker/backend/domain/admin"
+	"smart-parcel-locker/backend/domain/audit"
+	"smart-parcel-locker/backend/domain/location"
+	"smart-parcel-locker/backend/infrastructure/database"
+	"smart-parcel-locker/backend/pkg/errorx"
 	"smart-parcel-locker/backend/pkg/logger"
+	auditusecase "smart-parcel-locker/backend/usecase/audit"
 )
 
-// UseCase handles admin CRUD operations.
+type txRepository interface {
+	WithDB(db *gorm.DB) admin.Repository
+}
+
+// Config controls admin sessions and password setup links.
+type Config struct {
+	SessionTTL    time.Duration
+	RefreshTTL    time.Duration
+	SetupTokenTTL time.Duration
+	// SetupURL is the page where an admin chooses a password; the token is appended as ?token=.
+	SetupURL string
+	// MFARequiredRoles must use TOTP two-factor authentication. Admins in these roles who have
+	// not enrolled can log in, but only to enrol.
+	MFARequiredRoles []string
+	// TOTPIssuer labels the account in authenticator apps.
+	TOTPIssuer string
+}
+
+// UseCase handles admin CRUD operations, role assignments, login sessions and password setup.
 type UseCase struct {
-	repo admin.Repository
+	repo         admin.Repository
+	locationRepo location.Repository
+	audit        audit.Recorder
+	tx           *database.TransactionManager
+	cfg          Config
+	now          func() time.Time
 }
 
+// CreateInput describes a new admin. LocationIDs limits a non-SUPER_ADMIN role to those locations.
 type CreateInput struct {
-	Username     string
-	PasswordHash string
-	Role         string
+	Username    string
+	Role        string
+	LocationIDs []uuid.UUID
 }
 
+// UpdateInput changes an admin's username, role and locations. ActorID is the admin making the change.
 type UpdateInput struct {
-	ID           uuid.UUID
-	Username     string
-	PasswordHash string
-	Role         string
+	ID          uuid.UUID
+	Username    string
+	Role        string
+	LocationIDs []uuid.UUID
+	ActorID     uuid.UUID
+}
+
+// SetRoleInput assigns a role and its locations. ActorID is the admin making the change.
+type SetRoleInput struct {
+	ID          uuid.UUID
+	Role        string
+	LocationIDs []uuid.UUID
+	ActorID     uuid.UUID
+}
+
+// SetupLink lets an admin choose their password. The token is only returned once.
+type SetupLink struct {
+	Token     string
+	URL       string
+	ExpiresAt time.Time
 }
 
-func NewUseCase(repo admin.Repository) *UseCase {
-	return &UseCase{repo: repo}
+// CreateResult is a new admin and the link they use to set their password.
+type CreateResult struct {
+	Admin *admin.Admin
+	Setup SetupLink
 }
 
-func (uc *UseCase) Create(ctx context.Context, input CreateInput) (*admin.Admin, error) {
+func NewUseCase(repo admin.Repository, locationRepo location.Repository, recorder audit.Recorder, tx *database.TransactionManager, cfg Config) *UseCase {
+	if tx == nil {
+		tx = database.NewTransactionManager(nil)
+	}
+	if cfg.SessionTTL <= 0 {
+		cfg.SessionTTL = 30 * time.Minute
+	}
+	if cfg.RefreshTTL <= 0 {
+		cfg.RefreshTTL = 7 * 24 * time.Hour
+	}
+	if cfg.SetupTokenTTL <= 0 {
+		cfg.SetupTokenTTL = 72 * time.Hour
+	}
+	if cfg.TOTPIssuer == "" {
+		cfg.TOTPIssuer = "Smart Parcel Locker"
+	}
+	return &UseCase{repo: repo, locationRepo: locationRepo, audit: recorder, tx: tx, cfg: cfg, now: time.Now}
+}
+
+// Create adds an admin without a password and issues a one-time setup link. The admin cannot
+// log in until the link has been used to choose a password.
+func (uc *UseCase) Create(ctx context.Context, input CreateInput) (*CreateResult, error) {
 	logger.Info(ctx, "admin usecase create started", map[string]interface{}{
 		"username": input.Username,
 		"role":     input.Role,
 	}, "")
-	entity := &admin.Admin{
-		Username:     input.Username,
-		PasswordHash: input.PasswordHash,
-		Role:         input.Role,
+	locationIDs, err := uc.validateRole(ctx, input.Role, input.LocationIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.ensureUsernameFree(ctx, input.Username, uuid.Nil); err != nil {
+		return nil, err
 	}
-	// TODO: add validations.
-	created, err := uc.repo.Create(ctx, entity)
+
+	var result CreateResult
+	err = uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo := uc.repoFor(tx)
+		created, err := repo.Create(ctx, &admin.Admin{
+			ID:          uuid.New(),
+			Username:    input.Username,
+			Role:        input.Role,
+			LocationIDs: locationIDs,
+			CreatedAt:   uc.now(),
+		})
+		if err != nil {
+			return err
+		}
+		setup, err := uc.issueSetupToken(ctx, repo, created.ID)
+		if err != nil {
+			return err
+		}
+		result = CreateResult{Admin: created, Setup: *setup}
+		return auditusecase.Bind(uc.audit, tx).Record(ctx, audit.Change{
+			Action:     "admin.create",
+			EntityType: audit.EntityAdmin,
+			EntityID:   created.ID.String(),
+			After:      adminSnapshot(created),
+		})
+	})
 	if err != nil {
 		logger.Error(ctx, "admin usecase create failed unexpectedly", map[string]interface{}{
 			"username": input.Username,
@@ -54,11 +152,12 @@ func (uc *UseCase) Create(ctx context.Context, input CreateInput) (*admin.Admin,
 		return nil, err
 	}
 	logger.Info(ctx, "admin usecase created", map[string]interface{}{
-		"adminId":  created.ID,
-		"username": created.Username,
-		"role":     created.Role,
+		"adminId":        result.Admin.ID,
+		"username":       result.Admin.Username,
+		"role":           result.Admin.Role,
+		"setupExpiresAt": result.Setup.ExpiresAt,
 	}, "")
-	return created, nil
+	return &result, nil
 }
 
 func (uc *UseCase) Get(ctx context.Context, id uuid.UUID) (*admin.Admin, error) {
@@ -87,31 +186,49 @@ func (uc *UseCase) Get(ctx context.Context, id uuid.UUID) (*admin.Admin, error)
 	return result, nil
 }
 
+// Update changes the username, role and locations. Passwords are only set through the setup flow.
 func (uc *UseCase) Update(ctx context.Context, input UpdateInput) (*admin.Admin, error) {
 	logger.Info(ctx, "admin usecase update started", map[string]interface{}{
 		"adminId":  input.ID.String(),
 		"username": input.Username,
 		"role":     input.Role,
 	}, "")
-	entity := &admin.Admin{
-		ID:           input.ID,
-		Username:     input.Username,
-		PasswordHash: input.PasswordHash,
-		Role:         input.Role,
-	}
-	// TODO: add validations.
-	updated, err := uc.repo.Update(ctx, entity)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			logger.Warn(ctx, "admin usecase update not found", map[string]interface{}{
-				"adminId": input.ID.String(),
-			}, "")
-		} else {
-			logger.Error(ctx, "admin usecase update failed unexpectedly", map[string]interface{}{
-				"adminId": input.ID.String(),
-				"error":   err.Error(),
-			}, "")
+	if err := uc.ensureUsernameFree(ctx, input.Username, input.ID); err != nil {
+		return nil, err
+	}
+	var updated *admin.Admin
+	err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo := uc.repoFor(tx)
+		before, err := repo.GetByID(ctx, input.ID)
+		if err != nil {
+			return err
+		}
+		if err := uc.assignRole(ctx, repo, SetRoleInput{
+			ID:          input.ID,
+			Role:        input.Role,
+			LocationIDs: input.LocationIDs,
+			ActorID:     input.ActorID,
+		}); err != nil {
+			return err
+		}
+		result, err := repo.Update(ctx, &admin.Admin{
+			ID:       input.ID,
+			Username: input.Username,
+		})
+		if err != nil {
+			return err
 		}
+		updated = result
+		return auditusecase.Bind(uc.audit, tx).Record(ctx, audit.Change{
+			Action:     "admin.update",
+			EntityType: audit.EntityAdmin,
+			EntityID:   input.ID.String(),
+			Before:     adminSnapshot(before),
+			After:      adminSnapshot(result),
+		})
+	})
+	if err != nil {
+		uc.logWriteError(ctx, "update", input.ID, err)
 		return nil, err
 	}
 	logger.Info(ctx, "admin usecase updated", map[string]interface{}{
@@ -122,21 +239,191 @@ func (uc *UseCase) Update(ctx context.Context, input UpdateInput) (*admin.Admin,
 	return updated, nil
 }
 
-func (uc *UseCase) Delete(ctx context.Context, id uuid.UUID) error {
+// SetRole assigns a role and its locations. Admins cannot change their own role, and the last
+// active SUPER_ADMIN cannot be demoted.
+func (uc *UseCase) SetRole(ctx context.Context, input SetRoleInput) (*admin.Admin, error) {
+	logger.Info(ctx, "admin usecase set role started", map[string]interface{}{
+		"adminId":     input.ID.String(),
+		"role":        input.Role,
+		"locationIds": input.LocationIDs,
+		"actorId":     input.ActorID.String(),
+	}, "")
+	err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo := uc.repoFor(tx)
+		before, err := repo.GetByID(ctx, input.ID)
+		if err != nil {
+			return err
+		}
+		if err := uc.assignRole(ctx, repo, input); err != nil {
+			return err
+		}
+		after, err := repo.GetByID(ctx, input.ID)
+		if err != nil {
+			return err
+		}
+		if after.Role == before.Role && sameLocations(after.LocationIDs, before.LocationIDs) {
+			return nil
+		}
+		return auditusecase.Bind(uc.audit, tx).Record(ctx, audit.Change{
+			Action:     "admin.role_assign",
+			EntityType: audit.EntityAdmin,
+			EntityID:   input.ID.String(),
+			Before:     adminSnapshot(before),
+			After:      adminSnapshot(after),
+		})
+	})
+	if err != nil {
+		uc.logWriteError(ctx, "set role", input.ID, err)
+		return nil, err
+	}
+	updated, err := uc.repo.GetByID(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info(ctx, "admin usecase role assigned", map[string]interface{}{
+		"adminId":     updated.ID.String(),
+		"role":        updated.Role,
+		"locationIds": updated.LocationIDs,
+		"actorId":     input.ActorID.String(),
+	}, "")
+	return updated, nil
+}
+
+func (uc *UseCase) assignRole(ctx context.Context, repo admin.Repository, input SetRoleInput) error {
+	locationIDs, err := uc.validateRole(ctx, input.Role, input.LocationIDs)
+	if err != nil {
+		return err
+	}
+	current, err := repo.GetByID(ctx, input.ID)
+	if err != nil {
+		return err
+	}
+	if current.Role == input.Role && sameLocations(current.LocationIDs, locationIDs) {
+		return nil
+	}
+	if input.ID == input.ActorID {
+		return admin.ErrSelfRoleChange
+	}
+	if current.Role == admin.RoleSuperAdmin && current.Active() && input.Role != admin.RoleSuperAdmin {
+		if err := ensureAnotherSuperAdmin(ctx, repo); err != nil {
+			return err
+		}
+	}
+	return repo.SetRole(ctx, input.ID, input.Role, locationIDs)
+}
+
+// validateRole checks the role, de-duplicates the locations and confirms they exist.
+func (uc *UseCase) validateRole(ctx context.Context, role string, locationIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if !admin.ValidRole(role) {
+		return nil, admin.ErrInvalidRole
+	}
+	if len(locationIDs) == 0 {
+		return nil, nil
+	}
+	if !admin.Scopeable(role) {
+		return nil, admin.ErrRoleNotScopeable
+	}
+	seen := make(map[uuid.UUID]bool, len(locationIDs))
+	unique := make([]uuid.UUID, 0, len(locationIDs))
+	for _, id := range locationIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if uc.locationRepo != nil {
+			if _, err := uc.locationRepo.GetByID(ctx, id); err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil, admin.ErrUnknownLocation
+				}
+				return nil, err
+			}
+		}
+		unique = append(unique, id)
+	}
+	return unique, nil
+}
+
+// ensureAnotherSuperAdmin must run inside the transaction that demotes, disables or deletes a
+// SUPER_ADMIN; it locks the remaining SUPER_ADMIN rows until that transaction ends.
+func ensureAnotherSuperAdmin(ctx context.Context, repo admin.Repository) error {
+	count, err := repo.CountByRoleForUpdate(ctx, admin.RoleSuperAdmin)
+	if err != nil {
+		return err
+	}
+	if count <= 1 {
+		return admin.ErrLastSuperAdmin
+	}
+	return nil
+}
+
+func sameLocations(a, b []uuid.UUID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[uuid.UUID]bool, len(a))
+	for _, id := range a {
+		set[id] = true
+	}
+	for _, id := range b {
+		if !set[id] {
+			return false
+		}
+	}
+	return true
+}
+
+func (uc *UseCase) logWriteError(ctx context.Context, action string, id uuid.UUID, err error) {
+	var appErr errorx.Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		logger.Warn(ctx, "admin usecase "+action+" not found", map[string]interface{}{
+			"adminId": id.String(),
+		}, "")
+	case errors.As(err, &appErr):
+		logger.Warn(ctx, "admin usecase "+action+" rejected", map[string]interface{}{
+			"adminId": id.String(),
+			"code":    appErr.Code,
+		}, "")
+	default:
+		logger.Error(ctx, "admin usecase "+action+" failed unexpectedly", map[string]interface{}{
+			"adminId": id.String(),
+			"error":   err.Error(),
+		}, "")
+	}
+}
+
+// Delete removes an admin. Admins cannot delete themselves, and the last active SUPER_ADMIN cannot be deleted.
+func (uc *UseCase) Delete(ctx context.Context, id, actorID uuid.UUID) error {
 	logger.Info(ctx, "admin usecase delete started", map[string]interface{}{
 		"adminId": id.String(),
+		"actorId": actorID.String(),
 	}, "")
-	if err := uc.repo.Delete(ctx, id); err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			logger.Warn(ctx, "admin usecase delete not found", map[string]interface{}{
-				"adminId": id.String(),
-			}, "")
-		} else {
-			logger.Error(ctx, "admin usecase delete failed unexpectedly", map[string]interface{}{
-				"adminId": id.String(),
-				"error":   err.Error(),
-			}, "")
+	if id == actorID {
+		return admin.ErrSelfRoleChange
+	}
+	err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo := uc.repoFor(tx)
+		current, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
 		}
+		if current.Role == admin.RoleSuperAdmin && current.Active() {
+			if err := ensureAnotherSuperAdmin(ctx, repo); err != nil {
+				return err
+			}
+		}
+		if err := repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return auditusecase.Bind(uc.audit, tx).Record(ctx, audit.Change{
+			Action:     "admin.delete",
+			EntityType: audit.EntityAdmin,
+			EntityID:   id.String(),
+			Before:     adminSnapshot(current),
+		})
+	})
+	if err != nil {
+		uc.logWriteError(ctx, "delete", id, err)
 		return err
 	}
 	logger.Info(ctx, "admin usecase deleted", map[string]interface{}{
@@ -144,3 +431,71 @@ func (uc *UseCase) Delete(ctx context.Context, id uuid.UUID) error {
 	}, "")
 	return nil
 }
+
+func (uc *UseCase) ensureUsernameFree(ctx context.Context, username string, self uuid.UUID) error {
+	existing, err := uc.repo.GetByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != self {
+		logger.Warn(ctx, "admin usecase username taken", map[string]interface{}{
+			"username": username,
+		}, "")
+		return admin.ErrUsernameTaken
+	}
+	return nil
+}
+
+func (uc *UseCase) issueSetupToken(ctx context.Context, repo admin.Repository, adminID uuid.UUID) (*SetupLink, error) {
+	token, err := admin.NewToken()
+	if err != nil {
+		return nil, err
+	}
+	now := uc.now()
+	expiresAt := now.Add(uc.cfg.SetupTokenTTL)
+	if err := repo.CreateSetupToken(ctx, &admin.SetupToken{
+		ID:        uuid.New(),
+		AdminID:   adminID,
+		TokenHash: admin.HashToken(token),
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}); err != nil {
+		return nil, err
+	}
+	return &SetupLink{Token: token, URL: uc.setupURL(token), ExpiresAt: expiresAt}, nil
+}
+
+func (uc *UseCase) setupURL(token string) string {
+	if uc.cfg.SetupURL == "" {
+		return ""
+	}
+	sep := "?"
+	if strings.Contains(uc.cfg.SetupURL, "?") {
+		sep = "&"
+	}
+	return uc.cfg.SetupURL + sep + "token=" + url.QueryEscape(token)
+}
+
+// adminSnapshot is the audited view of an admin; the password hash is never included.
+func adminSnapshot(a *admin.Admin) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           a.ID,
+		"username":     a.Username,
+		"role":         a.Role,
+		"location_ids": a.LocationIDs,
+		"is_active":    a.Active(),
+		"mfa_enabled":  a.MFAEnabled(),
+	}
+}
+
+func (uc *UseCase) repoFor(tx *gorm.DB) admin.Repository {
+	if tx != nil {
+		if r, ok := uc.repo.(txRepository); ok {
+			return r.WithDB(tx)
+		}
+	}
+	return uc.repo
+}
@@ -0,0 +1,79 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"smart-parcel-locker/backend/domain/admin"
+	"smart-parcel-locker/backend/domain/audit"
+)
+
+type fakeRecorder struct {
+	changes []audit.Change
+}
+
+func (r *fakeRecorder) Record(ctx context.Context, change audit.Change) error {
+	r.changes = append(r.changes, change)
+	return nil
+}
+
+func TestSetRoleIsAudited(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	recorder := &fakeRecorder{}
+	uc := NewUseCase(repo, nil, recorder, nil, Config{})
+	actor := uuid.New()
+	repo.admins[actor] = &admin.Admin{ID: actor, Username: "root", Role: admin.RoleSuperAdmin}
+	created, err := uc.Create(ctx, CreateInput{Username: "support", Role: admin.RoleSupport})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := uc.SetRole(ctx, SetRoleInput{ID: created.Admin.ID, Role: admin.RoleOperator, ActorID: actor}); err != nil {
+		t.Fatalf("set role: %v", err)
+	}
+	if _, err := uc.SetRole(ctx, SetRoleInput{ID: created.Admin.ID, Role: "ROOT", ActorID: actor}); err != admin.ErrInvalidRole {
+		t.Fatalf("expected ErrInvalidRole; got %v", err)
+	}
+
+	if len(recorder.changes) != 2 {
+		t.Fatalf("expected create and role change entries; got %+v", recorder.changes)
+	}
+	change := recorder.changes[1]
+	if change.Action != "admin.role_assign" || change.EntityID != created.Admin.ID.String() {
+		t.Fatalf("unexpected change %+v", change)
+	}
+	before, _ := json.Marshal(change.Before)
+	after, _ := json.Marshal(change.After)
+	var b, a map[string]interface{}
+	_ = json.Unmarshal(before, &b)
+	_ = json.Unmarshal(after, &a)
+	if b["role"] != admin.RoleSupport || a["role"] != admin.RoleOperator {
+		t.Fatalf("unexpected snapshots %s -> %s", before, after)
+	}
+	if _, ok := a["password_hash"]; ok {
+		t.Fatalf("snapshot leaks password hash: %s", after)
+	}
+}
+
+func TestSetPasswordIsAudited(t *testing.T) {
+	ctx := context.Background()
+	recorder := &fakeRecorder{}
+	uc := NewUseCase(newFakeRepo(), nil, recorder, nil, Config{})
+	created, err := uc.Create(ctx, CreateInput{Username: "support", Role: admin.RoleSupport})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := uc.SetPassword(ctx, created.Setup.Token, "a sufficiently long secret"); err != nil {
+		t.Fatalf("set password: %v", err)
+	}
+	last := recorder.changes[len(recorder.changes)-1]
+	if last.Action != "admin.password_set" || last.EntityID != created.Admin.ID.String() {
+		t.Fatalf("expected an admin.password_set entry; got %+v", last)
+	}
+	if last.Before != nil || last.After != nil {
+		t.Fatalf("password set entry must not carry snapshots: %+v", last)
+	}
+}
@@ -0,0 +1,415 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/admin"
+	"smart-parcel-locker/backend/domain/audit"
+	"smart-parcel-locker/backend/pkg/logger"
+	"smart-parcel-locker/backend/pkg/password"
+	auditusecase "smart-parcel-locker/backend/usecase/audit"
+)
+
+// LoginInput carries admin credentials and the client the session is issued to. Admins with
+// two-factor authentication also send a TOTP Code or one of their RecoveryCodes.
+type LoginInput struct {
+	Username     string
+	Password     string
+	Code         string
+	RecoveryCode string
+	IP           string
+	UserAgent    string
+}
+
+// SessionResult is a freshly issued or refreshed session. The tokens are only returned here.
+// MFAEnrollmentRequired marks a session that may only be used to enrol TOTP.
+type SessionResult struct {
+	Admin                 *admin.Admin
+	MFAEnrollmentRequired bool
+	SessionID             uuid.UUID
+	AccessToken           string
+	RefreshToken          string
+	ExpiresAt             time.Time
+	RefreshExpiresAt      time.Time
+}
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     string
+)
+
+// equalizeTiming runs a bcrypt comparison so unknown usernames take as long as wrong passwords.
+func equalizeTiming(plain string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = password.Hash("timing-equalization-only")
+	})
+	_, _ = password.Verify(dummyHash, plain)
+}
+
+// Login verifies a username and password and opens a session.
+func (uc *UseCase) Login(ctx context.Context, input LoginInput) (*SessionResult, error) {
+	a, err := uc.repo.GetByUsername(ctx, input.Username)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "admin usecase login lookup failed unexpectedly", map[string]interface{}{
+				"username": input.Username,
+				"error":    err.Error(),
+			}, "")
+			return nil, err
+		}
+		equalizeTiming(input.Password)
+		logger.Warn(ctx, "admin usecase login unknown username", map[string]interface{}{
+			"username": input.Username,
+			"ip":       input.IP,
+		}, "")
+		return nil, admin.ErrInvalidCredentials
+	}
+	if !a.HasPassword() {
+		equalizeTiming(input.Password)
+		logger.Warn(ctx, "admin usecase login password not set", map[string]interface{}{
+			"adminId": a.ID.String(),
+			"ip":      input.IP,
+		}, "")
+		return nil, admin.ErrInvalidCredentials
+	}
+	ok, err := password.Verify(a.PasswordHash, input.Password)
+	if err != nil {
+		logger.Warn(ctx, "admin usecase login hash not verifiable", map[string]interface{}{
+			"adminId": a.ID.String(),
+			"error":   err.Error(),
+		}, "")
+		return nil, admin.ErrInvalidCredentials
+	}
+	if !ok {
+		logger.Warn(ctx, "admin usecase login wrong password", map[string]interface{}{
+			"adminId": a.ID.String(),
+			"ip":      input.IP,
+		}, "")
+		return nil, admin.ErrInvalidCredentials
+	}
+	// Disabled accounts are only reported after the password checked out, so the flag does not
+	// leak to someone guessing passwords.
+	if !a.Active() {
+		logger.Warn(ctx, "admin usecase login account disabled", map[string]interface{}{
+			"adminId": a.ID.String(),
+			"ip":      input.IP,
+		}, "")
+		return nil, admin.ErrAccountDisabled
+	}
+	if a.MFAEnabled() {
+		if err := uc.verifySecondFactor(ctx, a, input.Code, input.RecoveryCode); err != nil {
+			logger.Warn(ctx, "admin usecase login second factor rejected", map[string]interface{}{
+				"adminId": a.ID.String(),
+				"ip":      input.IP,
+				"error":   err.Error(),
+			}, "")
+			return nil, err
+		}
+	}
+	if password.NeedsRehash(a.PasswordHash) {
+		uc.rehash(ctx, a, input.Password)
+	}
+
+	result, err := uc.openSession(ctx, a, input.IP, input.UserAgent)
+	if err != nil {
+		logger.Error(ctx, "admin usecase login session failed unexpectedly", map[string]interface{}{
+			"adminId": a.ID.String(),
+			"error":   err.Error(),
+		}, "")
+		return nil, err
+	}
+	logger.Info(ctx, "admin usecase logged in", map[string]interface{}{
+		"adminId":   a.ID.String(),
+		"sessionId": result.SessionID.String(),
+		"ip":        input.IP,
+	}, "")
+	return result, nil
+}
+
+// rehash upgrades an argon2id or low-cost bcrypt hash after a successful login.
+func (uc *UseCase) rehash(ctx context.Context, a *admin.Admin, plain string) {
+	hash, err := password.Hash(plain)
+	if err == nil {
+		setAt := uc.now()
+		if a.PasswordSetAt != nil {
+			setAt = *a.PasswordSetAt
+		}
+		err = uc.repo.SetPasswordHash(ctx, a.ID, hash, setAt)
+	}
+	if err != nil {
+		logger.Warn(ctx, "admin usecase password rehash failed", map[string]interface{}{
+			"adminId": a.ID.String(),
+			"error":   err.Error(),
+		}, "")
+		return
+	}
+	logger.Info(ctx, "admin usecase password rehashed", map[string]interface{}{
+		"adminId": a.ID.String(),
+	}, "")
+}
+
+func (uc *UseCase) openSession(ctx context.Context, a *admin.Admin, ip, userAgent string) (*SessionResult, error) {
+	access, refresh, err := newTokenPair()
+	if err != nil {
+		return nil, err
+	}
+	now := uc.now()
+	session := &admin.Session{
+		ID:               uuid.New(),
+		AdminID:          a.ID,
+		TokenHash:        admin.HashToken(access),
+		RefreshTokenHash: admin.HashToken(refresh),
+		ExpiresAt:        now.Add(uc.cfg.SessionTTL),
+		RefreshExpiresAt: now.Add(uc.cfg.RefreshTTL),
+		IP:               truncate(ip, 64),
+		UserAgent:        truncate(userAgent, 255),
+		CreatedAt:        now,
+	}
+	if err := uc.repo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+	return uc.sessionResult(a, session, access, refresh), nil
+}
+
+// Authenticate resolves an access token to the admin it was issued to.
+func (uc *UseCase) Authenticate(ctx context.Context, token string) (*admin.Principal, error) {
+	if token == "" {
+		return nil, admin.ErrUnauthorized
+	}
+	session, err := uc.repo.GetSessionByTokenHash(ctx, admin.HashToken(token))
+	if err != nil {
+		if errors.Is(err, admin.ErrInvalidSession) {
+			return nil, admin.ErrUnauthorized
+		}
+		return nil, err
+	}
+	if !session.Active(uc.now()) {
+		return nil, admin.ErrInvalidSession
+	}
+	a, err := uc.repo.GetByID(ctx, session.AdminID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, admin.ErrUnauthorized
+		}
+		return nil, err
+	}
+	if !a.Active() {
+		return nil, admin.ErrUnauthorized
+	}
+	return &admin.Principal{
+		AdminID:               a.ID,
+		Username:              a.Username,
+		Role:                  a.Role,
+		LocationIDs:           a.LocationIDs,
+		SessionID:             session.ID,
+		MFAEnrollmentRequired: uc.MFARequired(a.Role) && !a.MFAEnabled(),
+	}, nil
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token on the same session.
+// The presented refresh token stops working, so a stolen one can be used at most once.
+func (uc *UseCase) Refresh(ctx context.Context, refreshToken string) (*SessionResult, error) {
+	if refreshToken == "" {
+		return nil, admin.ErrInvalidSession
+	}
+	previousHash := admin.HashToken(refreshToken)
+	session, err := uc.repo.GetSessionByRefreshHash(ctx, previousHash)
+	if err != nil {
+		return nil, err
+	}
+	now := uc.now()
+	if !session.Refreshable(now) {
+		return nil, admin.ErrInvalidSession
+	}
+	a, err := uc.repo.GetByID(ctx, session.AdminID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, admin.ErrInvalidSession
+		}
+		return nil, err
+	}
+	if !a.Active() {
+		return nil, admin.ErrInvalidSession
+	}
+
+	access, refresh, err := newTokenPair()
+	if err != nil {
+		return nil, err
+	}
+	session.TokenHash = admin.HashToken(access)
+	session.RefreshTokenHash = admin.HashToken(refresh)
+	session.ExpiresAt = now.Add(uc.cfg.SessionTTL)
+	session.RefreshExpiresAt = now.Add(uc.cfg.RefreshTTL)
+	session.RefreshedAt = &now
+	rotated, err := uc.repo.RotateSession(ctx, session, previousHash)
+	if err != nil {
+		logger.Error(ctx, "admin usecase refresh failed unexpectedly", map[string]interface{}{
+			"sessionId": session.ID.String(),
+			"error":     err.Error(),
+		}, "")
+		return nil, err
+	}
+	if !rotated {
+		logger.Warn(ctx, "admin usecase refresh token already used", map[string]interface{}{
+			"sessionId": session.ID.String(),
+		}, "")
+		return nil, admin.ErrInvalidSession
+	}
+	logger.Info(ctx, "admin usecase session refreshed", map[string]interface{}{
+		"adminId":   a.ID.String(),
+		"sessionId": session.ID.String(),
+	}, "")
+	return uc.sessionResult(a, session, access, refresh), nil
+}
+
+// Logout revokes a session so neither of its tokens can be used again.
+func (uc *UseCase) Logout(ctx context.Context, sessionID uuid.UUID) error {
+	if err := uc.repo.RevokeSession(ctx, sessionID, uc.now()); err != nil {
+		logger.Error(ctx, "admin usecase logout failed unexpectedly", map[string]interface{}{
+			"sessionId": sessionID.String(),
+			"error":     err.Error(),
+		}, "")
+		return err
+	}
+	logger.Info(ctx, "admin usecase logged out", map[string]interface{}{
+		"sessionId": sessionID.String(),
+	}, "")
+	return nil
+}
+
+// SetPassword consumes a setup token, stores a bcrypt hash of the chosen password, revokes
+// every open session of the admin and records admin.password_set.
+func (uc *UseCase) SetPassword(ctx context.Context, token, plain string) (*admin.Admin, error) {
+	if err := password.Validate(plain); err != nil {
+		return nil, admin.ErrWeakPassword
+	}
+	setup, err := uc.repo.GetSetupToken(ctx, admin.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	now := uc.now()
+	if !setup.Usable(now) {
+		return nil, admin.ErrInvalidSetupToken
+	}
+	hash, err := password.Hash(plain)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo := uc.repoFor(tx)
+		used, err := repo.UseSetupToken(ctx, setup.ID, now)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return admin.ErrInvalidSetupToken
+		}
+		if err := repo.SetPasswordHash(ctx, setup.AdminID, hash, now); err != nil {
+			return err
+		}
+		if _, err := repo.RevokeSessions(ctx, setup.AdminID, now); err != nil {
+			return err
+		}
+		return auditusecase.Bind(uc.audit, tx).Record(ctx, audit.Change{
+			Action:     "admin.password_set",
+			EntityType: audit.EntityAdmin,
+			EntityID:   setup.AdminID.String(),
+		})
+	})
+	if err != nil {
+		if !errors.Is(err, admin.ErrInvalidSetupToken) {
+			logger.Error(ctx, "admin usecase set password failed unexpectedly", map[string]interface{}{
+				"adminId": setup.AdminID.String(),
+				"error":   err.Error(),
+			}, "")
+		}
+		return nil, err
+	}
+	logger.Info(ctx, "admin usecase password set", map[string]interface{}{
+		"adminId": setup.AdminID.String(),
+	}, "")
+	return uc.repo.GetByID(ctx, setup.AdminID)
+}
+
+// Bootstrap creates the first admin with the given password when no admin exists yet, so a
+// fresh installation can log in. It does nothing once any admin is present.
+func (uc *UseCase) Bootstrap(ctx context.Context, username, plain string) error {
+	count, err := uc.repo.Count(ctx)
+	if err != nil || count > 0 {
+		return err
+	}
+	if err := password.Validate(plain); err != nil {
+		return err
+	}
+	hash, err := password.Hash(plain)
+	if err != nil {
+		return err
+	}
+	now := uc.now()
+	created, err := uc.repo.Create(ctx, &admin.Admin{
+		ID:            uuid.New(),
+		Username:      username,
+		PasswordHash:  hash,
+		Role:          admin.RoleSuperAdmin,
+		PasswordSetAt: &now,
+		CreatedAt:     now,
+	})
+	if err != nil {
+		return err
+	}
+	logger.Warn(ctx, "admin usecase bootstrap admin created", map[string]interface{}{
+		"adminId":  created.ID.String(),
+		"username": created.Username,
+	}, "")
+	return nil
+}
+
+// PurgeSessions deletes sessions whose refresh token has expired.
+func (uc *UseCase) PurgeSessions(ctx context.Context) error {
+	removed, err := uc.repo.PurgeSessions(ctx, uc.now())
+	if err != nil {
+		return err
+	}
+	logger.Info(ctx, "admin session cleanup completed", map[string]interface{}{
+		"removed": removed,
+	}, "")
+	return nil
+}
+
+func newTokenPair() (string, string, error) {
+	access, err := admin.NewToken()
+	if err != nil {
+		return "", "", err
+	}
+	refresh, err := admin.NewToken()
+	if err != nil {
+		return "", "", err
+	}
+	return access, refresh, nil
+}
+
+func (uc *UseCase) sessionResult(a *admin.Admin, s *admin.Session, access, refresh string) *SessionResult {
+	return &SessionResult{
+		Admin:                 a,
+		MFAEnrollmentRequired: uc.MFARequired(a.Role) && !a.MFAEnabled(),
+		SessionID:             s.ID,
+		AccessToken:           access,
+		RefreshToken:          refresh,
+		ExpiresAt:             s.ExpiresAt,
+		RefreshExpiresAt:      s.RefreshExpiresAt,
+	}
+}
+
+func truncate(value string, max int) string {
+	if len(value) > max {
+		return value[:max]
+	}
+	return value
+}
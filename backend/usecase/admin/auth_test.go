@@ -0,0 +1,470 @@
+package admin
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/admin"
+	"smart-parcel-locker/backend/pkg/password"
+)
+
+// TestMain hashes at the lowest bcrypt cost; the production cost makes the suite crawl under -race.
+func TestMain(m *testing.M) {
+	password.Cost = bcrypt.MinCost
+	os.Exit(m.Run())
+}
+
+type fakeRepo struct {
+	admins   map[uuid.UUID]*admin.Admin
+	sessions map[uuid.UUID]*admin.Session
+	tokens   map[uuid.UUID]*admin.SetupToken
+	// recovery maps an admin to their recovery code hashes and whether each was used.
+	recovery map[uuid.UUID]map[string]bool
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		admins:   map[uuid.UUID]*admin.Admin{},
+		sessions: map[uuid.UUID]*admin.Session{},
+		tokens:   map[uuid.UUID]*admin.SetupToken{},
+		recovery: map[uuid.UUID]map[string]bool{},
+	}
+}
+
+func (r *fakeRepo) Create(ctx context.Context, a *admin.Admin) (*admin.Admin, error) {
+	copied := *a
+	r.admins[a.ID] = &copied
+	return a, nil
+}
+
+func (r *fakeRepo) GetByID(ctx context.Context, id uuid.UUID) (*admin.Admin, error) {
+	a, ok := r.admins[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *a
+	return &copied, nil
+}
+
+func (r *fakeRepo) GetByUsername(ctx context.Context, username string) (*admin.Admin, error) {
+	for _, a := range r.admins {
+		if a.Username == username {
+			copied := *a
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeRepo) List(ctx context.Context, filter admin.ListFilter) ([]*admin.Admin, int64, error) {
+	var items []*admin.Admin
+	for _, a := range r.admins {
+		if filter.Query != "" && !strings.Contains(strings.ToLower(a.Username), strings.ToLower(filter.Query)) {
+			continue
+		}
+		if filter.Role != "" && a.Role != filter.Role {
+			continue
+		}
+		if filter.Active != nil && a.Active() != *filter.Active {
+			continue
+		}
+		copied := *a
+		items = append(items, &copied)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Username < items[j].Username })
+	total := int64(len(items))
+	if filter.Offset >= len(items) {
+		return nil, total, nil
+	}
+	items = items[filter.Offset:]
+	if len(items) > filter.Limit {
+		items = items[:filter.Limit]
+	}
+	return items, total, nil
+}
+
+func (r *fakeRepo) Count(ctx context.Context) (int64, error) {
+	return int64(len(r.admins)), nil
+}
+
+func (r *fakeRepo) Update(ctx context.Context, a *admin.Admin) (*admin.Admin, error) {
+	existing, ok := r.admins[a.ID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	existing.Username = a.Username
+	return r.GetByID(ctx, a.ID)
+}
+
+func (r *fakeRepo) CountByRoleForUpdate(ctx context.Context, role string) (int64, error) {
+	var count int64
+	for _, a := range r.admins {
+		if a.Role == role && a.Active() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeRepo) SetRole(ctx context.Context, id uuid.UUID, role string, locationIDs []uuid.UUID) error {
+	a, ok := r.admins[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	a.Role = role
+	a.LocationIDs = locationIDs
+	return nil
+}
+
+func (r *fakeRepo) SetPasswordHash(ctx context.Context, id uuid.UUID, hash string, at time.Time) error {
+	a, ok := r.admins[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	a.PasswordHash = hash
+	a.PasswordSetAt = &at
+	return nil
+}
+
+func (r *fakeRepo) ClearPassword(ctx context.Context, id uuid.UUID, at time.Time) error {
+	a, ok := r.admins[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	a.PasswordHash = ""
+	a.PasswordSetAt = nil
+	return nil
+}
+
+func (r *fakeRepo) SetDisabledAt(ctx context.Context, id uuid.UUID, at *time.Time) error {
+	a, ok := r.admins[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	a.DisabledAt = at
+	return nil
+}
+
+func (r *fakeRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(r.admins, id)
+	return nil
+}
+
+func (r *fakeRepo) CreateSession(ctx context.Context, s *admin.Session) error {
+	copied := *s
+	r.sessions[s.ID] = &copied
+	return nil
+}
+
+func (r *fakeRepo) GetSessionByTokenHash(ctx context.Context, hash string) (*admin.Session, error) {
+	for _, s := range r.sessions {
+		if s.TokenHash == hash {
+			copied := *s
+			return &copied, nil
+		}
+	}
+	return nil, admin.ErrInvalidSession
+}
+
+func (r *fakeRepo) GetSessionByRefreshHash(ctx context.Context, hash string) (*admin.Session, error) {
+	for _, s := range r.sessions {
+		if s.RefreshTokenHash == hash {
+			copied := *s
+			return &copied, nil
+		}
+	}
+	return nil, admin.ErrInvalidSession
+}
+
+func (r *fakeRepo) RotateSession(ctx context.Context, s *admin.Session, previousRefreshHash string) (bool, error) {
+	existing, ok := r.sessions[s.ID]
+	if !ok || existing.RevokedAt != nil || existing.RefreshTokenHash != previousRefreshHash {
+		return false, nil
+	}
+	copied := *s
+	r.sessions[s.ID] = &copied
+	return true, nil
+}
+
+func (r *fakeRepo) RevokeSession(ctx context.Context, id uuid.UUID, at time.Time) error {
+	if s, ok := r.sessions[id]; ok && s.RevokedAt == nil {
+		s.RevokedAt = &at
+	}
+	return nil
+}
+
+func (r *fakeRepo) RevokeSessions(ctx context.Context, adminID uuid.UUID, at time.Time) (int64, error) {
+	var n int64
+	for _, s := range r.sessions {
+		if s.AdminID == adminID && s.RevokedAt == nil {
+			s.RevokedAt = &at
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *fakeRepo) RevokeOtherSessions(ctx context.Context, adminID, keep uuid.UUID, at time.Time) (int64, error) {
+	var n int64
+	for _, s := range r.sessions {
+		if s.AdminID == adminID && s.ID != keep && s.RevokedAt == nil {
+			s.RevokedAt = &at
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *fakeRepo) PurgeSessions(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *fakeRepo) CreateSetupToken(ctx context.Context, t *admin.SetupToken) error {
+	copied := *t
+	r.tokens[t.ID] = &copied
+	return nil
+}
+
+func (r *fakeRepo) GetSetupToken(ctx context.Context, hash string) (*admin.SetupToken, error) {
+	for _, t := range r.tokens {
+		if t.TokenHash == hash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, admin.ErrInvalidSetupToken
+}
+
+func (r *fakeRepo) UseSetupToken(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	t, ok := r.tokens[id]
+	if !ok || t.UsedAt != nil {
+		return false, nil
+	}
+	t.UsedAt = &at
+	return true, nil
+}
+
+func (r *fakeRepo) ExpireSetupTokens(ctx context.Context, adminID uuid.UUID, at time.Time) error {
+	for _, t := range r.tokens {
+		if t.AdminID == adminID && t.UsedAt == nil {
+			t.UsedAt = &at
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepo) SetTOTPSecret(ctx context.Context, id uuid.UUID, secret string) error {
+	a, ok := r.admins[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	a.TOTPSecret = secret
+	a.TOTPEnabledAt = nil
+	a.TOTPLastStep = 0
+	return nil
+}
+
+func (r *fakeRepo) EnableTOTP(ctx context.Context, id uuid.UUID, at time.Time, step int64) error {
+	a, ok := r.admins[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	a.TOTPEnabledAt = &at
+	a.TOTPLastStep = step
+	return nil
+}
+
+func (r *fakeRepo) UseTOTPStep(ctx context.Context, id uuid.UUID, step int64) (bool, error) {
+	a, ok := r.admins[id]
+	if !ok || step <= a.TOTPLastStep {
+		return false, nil
+	}
+	a.TOTPLastStep = step
+	return true, nil
+}
+
+func (r *fakeRepo) ReplaceRecoveryCodes(ctx context.Context, adminID uuid.UUID, hashes []string, at time.Time) error {
+	codes := map[string]bool{}
+	for _, h := range hashes {
+		codes[h] = false
+	}
+	r.recovery[adminID] = codes
+	return nil
+}
+
+func (r *fakeRepo) UseRecoveryCode(ctx context.Context, adminID uuid.UUID, hash string, at time.Time) (bool, error) {
+	used, ok := r.recovery[adminID][hash]
+	if !ok || used {
+		return false, nil
+	}
+	r.recovery[adminID][hash] = true
+	return true, nil
+}
+
+func (r *fakeRepo) CountRecoveryCodes(ctx context.Context, adminID uuid.UUID) (int64, error) {
+	var n int64
+	for _, used := range r.recovery[adminID] {
+		if !used {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func TestCreateSetPasswordAndLogin(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := NewUseCase(repo, nil, nil, nil, Config{SetupURL: "https://admin.example.com/setup"})
+
+	created, err := uc.Create(ctx, CreateInput{Username: "ops", Role: admin.RoleOperator})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if created.Admin.HasPassword() {
+		t.Fatal("new admin should not have a password")
+	}
+	if !strings.HasPrefix(created.Setup.URL, "https://admin.example.com/setup?token=") {
+		t.Fatalf("unexpected setup url %q", created.Setup.URL)
+	}
+	if _, err := uc.Login(ctx, LoginInput{Username: "ops", Password: "anything at all"}); err != admin.ErrInvalidCredentials {
+		t.Fatalf("expected ErrInvalidCredentials before setup; got %v", err)
+	}
+	if _, err := uc.SetPassword(ctx, created.Setup.Token, "short"); err != admin.ErrWeakPassword {
+		t.Fatalf("expected ErrWeakPassword; got %v", err)
+	}
+	if _, err := uc.SetPassword(ctx, created.Setup.Token, "a sufficiently long secret"); err != nil {
+		t.Fatalf("set password: %v", err)
+	}
+	stored := repo.admins[created.Admin.ID].PasswordHash
+	if !strings.HasPrefix(stored, "$2") || strings.Contains(stored, "sufficiently") {
+		t.Fatalf("expected a bcrypt hash; got %q", stored)
+	}
+	if _, err := uc.SetPassword(ctx, created.Setup.Token, "another long secret!"); err != admin.ErrInvalidSetupToken {
+		t.Fatalf("expected setup token to be single-use; got %v", err)
+	}
+
+	if _, err := uc.Login(ctx, LoginInput{Username: "ops", Password: "wrong long secret"}); err != admin.ErrInvalidCredentials {
+		t.Fatalf("expected ErrInvalidCredentials; got %v", err)
+	}
+	session, err := uc.Login(ctx, LoginInput{Username: "ops", Password: "a sufficiently long secret"})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	principal, err := uc.Authenticate(ctx, session.AccessToken)
+	if err != nil || principal.AdminID != created.Admin.ID {
+		t.Fatalf("authenticate: %v, %+v", err, principal)
+	}
+}
+
+func TestCreateRejectsDuplicateUsername(t *testing.T) {
+	ctx := context.Background()
+	uc := NewUseCase(newFakeRepo(), nil, nil, nil, Config{})
+	if _, err := uc.Create(ctx, CreateInput{Username: "ops", Role: admin.RoleOperator}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := uc.Create(ctx, CreateInput{Username: "ops", Role: admin.RoleOperator}); err != admin.ErrUsernameTaken {
+		t.Fatalf("expected ErrUsernameTaken; got %v", err)
+	}
+}
+
+func TestRefreshRotatesAndLogoutRevokes(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := NewUseCase(repo, nil, nil, nil, Config{})
+	if err := uc.Bootstrap(ctx, "root", "bootstrap password"); err != nil {
+		t.Fatalf("bootstrap: %v", err)
+	}
+	session, err := uc.Login(ctx, LoginInput{Username: "root", Password: "bootstrap password"})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	refreshed, err := uc.Refresh(ctx, session.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if refreshed.SessionID != session.SessionID || refreshed.AccessToken == session.AccessToken {
+		t.Fatal("expected new tokens on the same session")
+	}
+	if _, err := uc.Authenticate(ctx, session.AccessToken); err == nil {
+		t.Fatal("old access token should stop working after refresh")
+	}
+	if _, err := uc.Refresh(ctx, session.RefreshToken); err != admin.ErrInvalidSession {
+		t.Fatalf("old refresh token should be rejected; got %v", err)
+	}
+
+	if err := uc.Logout(ctx, refreshed.SessionID); err != nil {
+		t.Fatalf("logout: %v", err)
+	}
+	if _, err := uc.Authenticate(ctx, refreshed.AccessToken); err != admin.ErrInvalidSession {
+		t.Fatalf("expected revoked session; got %v", err)
+	}
+	if _, err := uc.Refresh(ctx, refreshed.RefreshToken); err != admin.ErrInvalidSession {
+		t.Fatalf("expected revoked refresh token; got %v", err)
+	}
+}
+
+func TestAccessTokenExpires(t *testing.T) {
+	ctx := context.Background()
+	uc := NewUseCase(newFakeRepo(), nil, nil, nil, Config{SessionTTL: time.Minute})
+	if err := uc.Bootstrap(ctx, "root", "bootstrap password"); err != nil {
+		t.Fatalf("bootstrap: %v", err)
+	}
+	session, err := uc.Login(ctx, LoginInput{Username: "root", Password: "bootstrap password"})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	uc.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if _, err := uc.Authenticate(ctx, session.AccessToken); err != admin.ErrInvalidSession {
+		t.Fatalf("expected expired session; got %v", err)
+	}
+	if _, err := uc.Refresh(ctx, session.RefreshToken); err != nil {
+		t.Fatalf("refresh after access expiry: %v", err)
+	}
+}
+
+func TestLoginRehashesArgon2id(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := NewUseCase(repo, nil, nil, nil, Config{})
+	salt := []byte("0123456789abcdef")
+	key := argon2.IDKey([]byte("imported password"), salt, 1, 64*1024, 2, 32)
+	id := uuid.New()
+	repo.admins[id] = &admin.Admin{
+		ID:       id,
+		Username: "legacy",
+		Role:     admin.RoleOperator,
+		PasswordHash: fmt.Sprintf("$argon2id$v=%d$m=65536,t=1,p=2$%s$%s", argon2.Version,
+			base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)),
+	}
+
+	if _, err := uc.Login(ctx, LoginInput{Username: "legacy", Password: "imported password"}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if !strings.HasPrefix(repo.admins[id].PasswordHash, "$2") {
+		t.Fatalf("expected argon2id hash to be upgraded to bcrypt; got %q", repo.admins[id].PasswordHash)
+	}
+}
+
+func TestLoginRejectsUnhashedPassword(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := NewUseCase(repo, nil, nil, nil, Config{})
+	id := uuid.New()
+	repo.admins[id] = &admin.Admin{ID: id, Username: "old", Role: admin.RoleOperator, PasswordHash: "stored as given"}
+
+	if _, err := uc.Login(ctx, LoginInput{Username: "old", Password: "stored as given"}); err != admin.ErrInvalidCredentials {
+		t.Fatalf("expected ErrInvalidCredentials; got %v", err)
+	}
+}
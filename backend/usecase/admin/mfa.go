@@ -0,0 +1,303 @@
+package admin
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/admin"
+	"smart-parcel-locker/backend/domain/audit"
+	"smart-parcel-locker/backend/pkg/logger"
+	"smart-parcel-locker/backend/pkg/password"
+	"smart-parcel-locker/backend/pkg/totp"
+	auditusecase "smart-parcel-locker/backend/usecase/audit"
+)
+
+// TOTPEnrollment is a pending authenticator secret. It only takes effect once ConfirmTOTP
+// verifies a code generated from it.
+type TOTPEnrollment struct {
+	Secret          string
+	ProvisioningURI string
+}
+
+// MFAStatus describes the two-factor state of an admin.
+type MFAStatus struct {
+	Enabled bool
+	// Required is true when the admin's role is listed in Config.MFARequiredRoles.
+	Required               bool
+	RecoveryCodesRemaining int64
+}
+
+// DisableTOTPInput turns off two-factor authentication for the signed-in admin.
+type DisableTOTPInput struct {
+	AdminID  uuid.UUID
+	Password string
+	Code     string
+}
+
+// MFARequired reports whether role must use two-factor authentication.
+func (uc *UseCase) MFARequired(role string) bool {
+	for _, r := range uc.cfg.MFARequiredRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// MFAStatus returns the two-factor state of an admin.
+func (uc *UseCase) MFAStatus(ctx context.Context, adminID uuid.UUID) (*MFAStatus, error) {
+	a, err := uc.repo.GetByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	status := &MFAStatus{Enabled: a.MFAEnabled(), Required: uc.MFARequired(a.Role)}
+	if status.Enabled {
+		if status.RecoveryCodesRemaining, err = uc.repo.CountRecoveryCodes(ctx, a.ID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// BeginTOTP generates a new authenticator secret for the signed-in admin. Calling it again
+// before confirming replaces the pending secret.
+func (uc *UseCase) BeginTOTP(ctx context.Context, adminID uuid.UUID) (*TOTPEnrollment, error) {
+	a, err := uc.repo.GetByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	if a.MFAEnabled() {
+		return nil, admin.ErrMFAAlreadyEnabled
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.SetTOTPSecret(ctx, a.ID, secret); err != nil {
+		return nil, err
+	}
+	logger.Info(ctx, "admin usecase totp enrolment started", map[string]interface{}{
+		"adminId": a.ID.String(),
+	}, "")
+	return &TOTPEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(uc.cfg.TOTPIssuer, a.Username, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables two-factor authentication once code matches the pending secret, and returns
+// the recovery codes. They are only shown here.
+func (uc *UseCase) ConfirmTOTP(ctx context.Context, adminID uuid.UUID, code string) ([]string, error) {
+	a, err := uc.repo.GetByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	if a.MFAEnabled() {
+		return nil, admin.ErrMFAAlreadyEnabled
+	}
+	if a.TOTPSecret == "" {
+		return nil, admin.ErrMFANotEnrolling
+	}
+	step, ok := totp.Verify(a.TOTPSecret, code, uc.now())
+	if !ok {
+		return nil, admin.ErrInvalidMFACode
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	err = uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo := uc.repoFor(tx)
+		now := uc.now()
+		if err := repo.EnableTOTP(ctx, a.ID, now, step); err != nil {
+			return err
+		}
+		if err := repo.ReplaceRecoveryCodes(ctx, a.ID, hashes, now); err != nil {
+			return err
+		}
+		return auditusecase.Bind(uc.audit, tx).Record(ctx, audit.Change{
+			Action:     "admin.mfa_enable",
+			EntityType: audit.EntityAdmin,
+			EntityID:   a.ID.String(),
+		})
+	})
+	if err != nil {
+		uc.logWriteError(ctx, "confirm totp", a.ID, err)
+		return nil, err
+	}
+	logger.Info(ctx, "admin usecase totp enabled", map[string]interface{}{
+		"adminId": a.ID.String(),
+	}, "")
+	return codes, nil
+}
+
+// RegenerateRecoveryCodes replaces every recovery code of the signed-in admin after checking a
+// current TOTP code.
+func (uc *UseCase) RegenerateRecoveryCodes(ctx context.Context, adminID uuid.UUID, code string) ([]string, error) {
+	a, err := uc.repo.GetByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	if !a.MFAEnabled() {
+		return nil, admin.ErrMFANotEnabled
+	}
+	if err := uc.useTOTP(ctx, a, code); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	err = uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		if err := uc.repoFor(tx).ReplaceRecoveryCodes(ctx, a.ID, hashes, uc.now()); err != nil {
+			return err
+		}
+		return auditusecase.Bind(uc.audit, tx).Record(ctx, audit.Change{
+			Action:     "admin.mfa_recovery_codes",
+			EntityType: audit.EntityAdmin,
+			EntityID:   a.ID.String(),
+		})
+	})
+	if err != nil {
+		uc.logWriteError(ctx, "regenerate recovery codes", a.ID, err)
+		return nil, err
+	}
+	logger.Info(ctx, "admin usecase recovery codes regenerated", map[string]interface{}{
+		"adminId": a.ID.String(),
+	}, "")
+	return codes, nil
+}
+
+// DisableTOTP turns off two-factor authentication for the signed-in admin after checking their
+// password and a current code. Admins whose role requires it cannot turn it off.
+func (uc *UseCase) DisableTOTP(ctx context.Context, input DisableTOTPInput) error {
+	a, err := uc.repo.GetByID(ctx, input.AdminID)
+	if err != nil {
+		return err
+	}
+	if !a.MFAEnabled() {
+		return admin.ErrMFANotEnabled
+	}
+	if uc.MFARequired(a.Role) {
+		return admin.ErrMFAMandatory
+	}
+	ok, err := password.Verify(a.PasswordHash, input.Password)
+	if err != nil || !ok {
+		return admin.ErrWrongPassword
+	}
+	if err := uc.useTOTP(ctx, a, input.Code); err != nil {
+		return err
+	}
+	if err := uc.clearMFA(ctx, a.ID, "admin.mfa_disable", false); err != nil {
+		uc.logWriteError(ctx, "disable totp", a.ID, err)
+		return err
+	}
+	logger.Info(ctx, "admin usecase totp disabled", map[string]interface{}{
+		"adminId": a.ID.String(),
+	}, "")
+	return nil
+}
+
+// ResetMFA removes another admin's authenticator and recovery codes, for a lost phone, and
+// revokes their sessions. If their role requires two-factor authentication they must enrol again
+// at their next login.
+func (uc *UseCase) ResetMFA(ctx context.Context, id, actorID uuid.UUID) error {
+	if id == actorID {
+		return admin.ErrSelfAccountChange
+	}
+	if _, err := uc.repo.GetByID(ctx, id); err != nil {
+		return err
+	}
+	if err := uc.clearMFA(ctx, id, "admin.mfa_reset", true); err != nil {
+		uc.logWriteError(ctx, "reset mfa", id, err)
+		return err
+	}
+	logger.Info(ctx, "admin usecase mfa reset", map[string]interface{}{
+		"adminId": id.String(),
+		"actorId": actorID.String(),
+	}, "")
+	return nil
+}
+
+func (uc *UseCase) clearMFA(ctx context.Context, id uuid.UUID, action string, revokeSessions bool) error {
+	return uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo := uc.repoFor(tx)
+		if err := repo.SetTOTPSecret(ctx, id, ""); err != nil {
+			return err
+		}
+		if err := repo.ReplaceRecoveryCodes(ctx, id, nil, uc.now()); err != nil {
+			return err
+		}
+		if revokeSessions {
+			if _, err := repo.RevokeSessions(ctx, id, uc.now()); err != nil {
+				return err
+			}
+		}
+		return auditusecase.Bind(uc.audit, tx).Record(ctx, audit.Change{
+			Action:     action,
+			EntityType: audit.EntityAdmin,
+			EntityID:   id.String(),
+		})
+	})
+}
+
+// verifySecondFactor checks the TOTP or recovery code presented at login.
+func (uc *UseCase) verifySecondFactor(ctx context.Context, a *admin.Admin, code, recoveryCode string) error {
+	switch {
+	case strings.TrimSpace(code) != "":
+		return uc.useTOTP(ctx, a, code)
+	case strings.TrimSpace(recoveryCode) != "":
+		used, err := uc.repo.UseRecoveryCode(ctx, a.ID, admin.HashRecoveryCode(recoveryCode), uc.now())
+		if err != nil {
+			return err
+		}
+		if !used {
+			return admin.ErrInvalidMFACode
+		}
+		remaining, err := uc.repo.CountRecoveryCodes(ctx, a.ID)
+		if err != nil {
+			return err
+		}
+		logger.Warn(ctx, "admin usecase recovery code used", map[string]interface{}{
+			"adminId":   a.ID.String(),
+			"remaining": remaining,
+		}, "")
+		return nil
+	default:
+		return admin.ErrMFARequired
+	}
+}
+
+// useTOTP accepts a code at most once: a code whose time step is not newer than the last
+// accepted one is rejected even while it is still valid.
+func (uc *UseCase) useTOTP(ctx context.Context, a *admin.Admin, code string) error {
+	step, ok := totp.Verify(a.TOTPSecret, code, uc.now())
+	if !ok {
+		return admin.ErrInvalidMFACode
+	}
+	used, err := uc.repo.UseTOTPStep(ctx, a.ID, step)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return admin.ErrInvalidMFACode
+	}
+	return nil
+}
+
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, admin.RecoveryCodeCount)
+	hashes := make([]string, 0, admin.RecoveryCodeCount)
+	for i := 0; i < admin.RecoveryCodeCount; i++ {
+		code, err := admin.NewRecoveryCode()
+		if err != nil {
+			return nil, nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, admin.HashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
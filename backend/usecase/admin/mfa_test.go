@@ -0,0 +1,135 @@
+package admin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"smart-parcel-locker/backend/domain/admin"
+	"smart-parcel-locker/backend/pkg/totp"
+)
+
+// enrolTOTP runs the enrolment flow for username and returns the secret and recovery codes.
+func enrolTOTP(t *testing.T, uc *UseCase, repo *fakeRepo, username string) (string, []string) {
+	t.Helper()
+	ctx := context.Background()
+	a, err := repo.GetByUsername(ctx, username)
+	if err != nil {
+		t.Fatalf("lookup %s: %v", username, err)
+	}
+	enrollment, err := uc.BeginTOTP(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("begin totp: %v", err)
+	}
+	code := totpCode(t, enrollment.Secret, uc.now())
+	codes, err := uc.ConfirmTOTP(ctx, a.ID, code)
+	if err != nil {
+		t.Fatalf("confirm totp: %v", err)
+	}
+	return enrollment.Secret, codes
+}
+
+func totpCode(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	code, err := totp.Code(secret, totp.Step(at))
+	if err != nil {
+		t.Fatalf("totp code: %v", err)
+	}
+	return code
+}
+
+func TestLoginRequiresSecondFactorOnceEnrolled(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := NewUseCase(repo, nil, nil, nil, Config{})
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return now }
+	ops := newActiveAdmin(t, uc, "ops", admin.RoleOperator).Admin.ID
+
+	secret, codes := enrolTOTP(t, uc, repo, "ops")
+	if len(codes) != admin.RecoveryCodeCount {
+		t.Fatalf("expected %d recovery codes; got %d", admin.RecoveryCodeCount, len(codes))
+	}
+	if _, err := uc.Login(ctx, LoginInput{Username: "ops", Password: testPassword}); err != admin.ErrMFARequired {
+		t.Fatalf("expected ErrMFARequired; got %v", err)
+	}
+	// The code used to confirm enrolment cannot be replayed at login.
+	if _, err := uc.Login(ctx, LoginInput{Username: "ops", Password: testPassword, Code: totpCode(t, secret, now)}); err != admin.ErrInvalidMFACode {
+		t.Fatalf("expected the confirmation code to be rejected; got %v", err)
+	}
+
+	now = now.Add(totp.Period)
+	code := totpCode(t, secret, now)
+	if _, err := uc.Login(ctx, LoginInput{Username: "ops", Password: testPassword, Code: code}); err != nil {
+		t.Fatalf("login with totp: %v", err)
+	}
+	if _, err := uc.Login(ctx, LoginInput{Username: "ops", Password: testPassword, Code: code}); err != admin.ErrInvalidMFACode {
+		t.Fatalf("expected a used code to be rejected; got %v", err)
+	}
+
+	if _, err := uc.Login(ctx, LoginInput{Username: "ops", Password: testPassword, RecoveryCode: codes[0]}); err != nil {
+		t.Fatalf("login with recovery code: %v", err)
+	}
+	if _, err := uc.Login(ctx, LoginInput{Username: "ops", Password: testPassword, RecoveryCode: codes[0]}); err != admin.ErrInvalidMFACode {
+		t.Fatalf("expected a recovery code to be single-use; got %v", err)
+	}
+	status, err := uc.MFAStatus(ctx, ops)
+	if err != nil || !status.Enabled || status.RecoveryCodesRemaining != admin.RecoveryCodeCount-1 {
+		t.Fatalf("unexpected status: %v, %+v", err, status)
+	}
+}
+
+func TestMandatoryRoleMustEnrolBeforeUsingPermissions(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := NewUseCase(repo, nil, nil, nil, Config{MFARequiredRoles: []string{admin.RoleSuperAdmin}})
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return now }
+	root := newActiveAdmin(t, uc, "root", admin.RoleSuperAdmin).Admin.ID
+
+	session, err := uc.Login(ctx, LoginInput{Username: "root", Password: testPassword})
+	if err != nil {
+		t.Fatalf("login before enrolment: %v", err)
+	}
+	if !session.MFAEnrollmentRequired {
+		t.Fatal("expected the session to require enrolment")
+	}
+	principal, err := uc.Authenticate(ctx, session.AccessToken)
+	if err != nil || !principal.MFAEnrollmentRequired {
+		t.Fatalf("authenticate: %v, %+v", err, principal)
+	}
+
+	secret, _ := enrolTOTP(t, uc, repo, "root")
+	principal, err = uc.Authenticate(ctx, session.AccessToken)
+	if err != nil || principal.MFAEnrollmentRequired {
+		t.Fatalf("expected enrolment to lift the restriction: %v, %+v", err, principal)
+	}
+
+	now = now.Add(totp.Period)
+	err = uc.DisableTOTP(ctx, DisableTOTPInput{AdminID: root, Password: testPassword, Code: totpCode(t, secret, now)})
+	if err != admin.ErrMFAMandatory {
+		t.Fatalf("expected ErrMFAMandatory; got %v", err)
+	}
+}
+
+func TestResetMFARemovesAuthenticator(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := NewUseCase(repo, nil, nil, nil, Config{})
+	root := newActiveAdmin(t, uc, "root", admin.RoleSuperAdmin).Admin.ID
+	ops := newActiveAdmin(t, uc, "ops", admin.RoleOperator).Admin.ID
+	enrolTOTP(t, uc, repo, "ops")
+
+	if err := uc.ResetMFA(ctx, ops, ops); err != admin.ErrSelfAccountChange {
+		t.Fatalf("expected ErrSelfAccountChange; got %v", err)
+	}
+	if err := uc.ResetMFA(ctx, ops, root); err != nil {
+		t.Fatalf("reset mfa: %v", err)
+	}
+	if repo.admins[ops].MFAEnabled() || len(repo.recovery[ops]) != 0 {
+		t.Fatalf("expected TOTP and recovery codes to be removed; got %+v", repo.admins[ops])
+	}
+	if _, err := uc.Login(ctx, LoginInput{Username: "ops", Password: testPassword}); err != nil {
+		t.Fatalf("login after reset: %v", err)
+	}
+}
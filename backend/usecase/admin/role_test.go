@@ -0,0 +1,94 @@
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"smart-parcel-locker/backend/domain/admin"
+)
+
+func TestSetRoleScopesLocations(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := NewUseCase(repo, nil, nil, nil, Config{})
+	actor := uuid.New()
+	repo.admins[actor] = &admin.Admin{ID: actor, Username: "root", Role: admin.RoleSuperAdmin}
+	created, err := uc.Create(ctx, CreateInput{Username: "support", Role: admin.RoleSupport})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	location := uuid.New()
+	updated, err := uc.SetRole(ctx, SetRoleInput{
+		ID:          created.Admin.ID,
+		Role:        admin.RoleOperator,
+		LocationIDs: []uuid.UUID{location, location},
+		ActorID:     actor,
+	})
+	if err != nil {
+		t.Fatalf("set role: %v", err)
+	}
+	if updated.Role != admin.RoleOperator || len(updated.LocationIDs) != 1 || updated.LocationIDs[0] != location {
+		t.Fatalf("unexpected assignment %s %v", updated.Role, updated.LocationIDs)
+	}
+
+	if _, err := uc.SetRole(ctx, SetRoleInput{
+		ID:          created.Admin.ID,
+		Role:        admin.RoleSuperAdmin,
+		LocationIDs: []uuid.UUID{location},
+		ActorID:     actor,
+	}); err != admin.ErrRoleNotScopeable {
+		t.Fatalf("expected ErrRoleNotScopeable; got %v", err)
+	}
+	if _, err := uc.SetRole(ctx, SetRoleInput{ID: created.Admin.ID, Role: "ROOT", ActorID: actor}); err != admin.ErrInvalidRole {
+		t.Fatalf("expected ErrInvalidRole; got %v", err)
+	}
+}
+
+func TestLastSuperAdminIsProtected(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	uc := NewUseCase(repo, nil, nil, nil, Config{})
+	root := uuid.New()
+	repo.admins[root] = &admin.Admin{ID: root, Username: "root", Role: admin.RoleSuperAdmin}
+	other := uuid.New()
+	repo.admins[other] = &admin.Admin{ID: other, Username: "ops", Role: admin.RoleOperator}
+
+	if _, err := uc.SetRole(ctx, SetRoleInput{ID: root, Role: admin.RoleViewer, ActorID: root}); err != admin.ErrSelfRoleChange {
+		t.Fatalf("expected ErrSelfRoleChange; got %v", err)
+	}
+	if _, err := uc.SetRole(ctx, SetRoleInput{ID: root, Role: admin.RoleViewer, ActorID: other}); err != admin.ErrLastSuperAdmin {
+		t.Fatalf("expected ErrLastSuperAdmin; got %v", err)
+	}
+	if err := uc.Delete(ctx, root, other); err != admin.ErrLastSuperAdmin {
+		t.Fatalf("expected ErrLastSuperAdmin on delete; got %v", err)
+	}
+
+	if _, err := uc.SetRole(ctx, SetRoleInput{ID: other, Role: admin.RoleSuperAdmin, ActorID: root}); err != nil {
+		t.Fatalf("promote: %v", err)
+	}
+	if _, err := uc.SetRole(ctx, SetRoleInput{ID: root, Role: admin.RoleViewer, ActorID: other}); err != nil {
+		t.Fatalf("demote with another super admin: %v", err)
+	}
+}
+
+func TestSupportCannotWriteInventory(t *testing.T) {
+	p := admin.Principal{Role: admin.RoleSupport, LocationIDs: []uuid.UUID{uuid.New()}}
+	if !p.Can(admin.PermParcelsRead) {
+		t.Fatal("support should be able to search parcels")
+	}
+	for _, perm := range []admin.Permission{admin.PermLockersWrite, admin.PermFeesWrite, admin.PermAdminsManage} {
+		if p.Can(perm) {
+			t.Fatalf("support should not have %s", perm)
+		}
+	}
+	if p.CanAccessLocation(uuid.New()) {
+		t.Fatal("scoped support should not access other locations")
+	}
+	operator := admin.Principal{Role: admin.RoleOperator}
+	if !operator.CanAccessLocation(uuid.New()) {
+		t.Fatal("unscoped operator should access every location")
+	}
+}
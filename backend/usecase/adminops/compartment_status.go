@@ -0,0 +1,160 @@
+package adminops
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/audit"
+	"smart-parcel-locker/backend/domain/compartment"
+	"smart-parcel-locker/backend/pkg/errorx"
+	"smart-parcel-locker/backend/pkg/logger"
+)
+
+const maxStatusReasonLength = 500
+
+// UpdateCompartmentStatusInput takes a compartment out of service or brings it back. Force is
+// needed to take out a compartment that holds a parcel, and always needs a Reason.
+type UpdateCompartmentStatusInput struct {
+	LockerID      uuid.UUID
+	CompartmentID uuid.UUID
+	Status        string
+	Force         bool
+	Reason        string
+}
+
+// UpdateCompartmentStatus sets a compartment OUT_OF_SERVICE or AVAILABLE and records the change
+// in its status history. A compartment returned to service while a parcel still waits inside
+// goes back to OCCUPIED. Setting the current status is a no-op.
+func (uc *UseCase) UpdateCompartmentStatus(ctx context.Context, input UpdateCompartmentStatusInput) (*compartment.Compartment, error) {
+	input.Reason = strings.TrimSpace(input.Reason)
+	logger.Info(ctx, "admin ops usecase update compartment status started", map[string]interface{}{
+		"lockerId":      input.LockerID.String(),
+		"compartmentId": input.CompartmentID.String(),
+		"status":        input.Status,
+		"force":         input.Force,
+	}, "")
+	if input.Status != compartment.StatusOutOfService && input.Status != compartment.StatusAvailable {
+		logger.Warn(ctx, "admin ops usecase update compartment invalid status", map[string]interface{}{
+			"compartmentId": input.CompartmentID.String(),
+			"status":        input.Status,
+		}, "")
+		return nil, errors.New("invalid status")
+	}
+	if input.Force && input.Reason == "" {
+		return nil, compartment.ErrReasonRequired
+	}
+	if len(input.Reason) > maxStatusReasonLength {
+		return nil, errorx.Error{Code: "INVALID_INPUT", Message: "reason must be at most 500 characters"}
+	}
+
+	var result *compartment.Compartment
+	var changed bool
+	err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repos, _ := uc.repoWithTx(tx)
+		comp, err := repos.compRepo.GetByIDForUpdate(ctx, input.CompartmentID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return compartment.ErrCompartmentNotFound
+			}
+			return err
+		}
+		if comp.LockerID != input.LockerID {
+			return compartment.ErrCompartmentNotFound
+		}
+		result = comp
+		if comp.Status == input.Status {
+			return nil
+		}
+
+		before := *comp
+		if input.Status == compartment.StatusOutOfService {
+			err = comp.TakeOutOfService(input.Force)
+		} else {
+			var active int64
+			if active, err = repos.parcelRepo.CountActiveInCompartment(ctx, comp.ID); err != nil {
+				return err
+			}
+			err = comp.ReturnToService(active > 0)
+		}
+		if err != nil {
+			logger.Warn(ctx, "admin ops usecase update compartment status rejected", map[string]interface{}{
+				"compartmentId": comp.ID.String(),
+				"from":          before.Status,
+				"to":            input.Status,
+				"error":         err.Error(),
+			}, "")
+			return err
+		}
+		if _, err := repos.compRepo.Update(ctx, comp); err != nil {
+			return err
+		}
+		// Forced marks changes that overrode the occupancy guard.
+		change := &compartment.StatusChange{
+			ID:            uuid.New(),
+			CompartmentID: comp.ID,
+			FromStatus:    before.Status,
+			ToStatus:      comp.Status,
+			Reason:        input.Reason,
+			Forced:        before.Status == compartment.StatusOccupied || before.Status == compartment.StatusReserved,
+			CreatedAt:     time.Now(),
+		}
+		if actor, ok := audit.ActorFrom(ctx); ok {
+			change.ActorID = &actor.AdminID
+		}
+		if err := repos.compRepo.CreateStatusChange(ctx, change); err != nil {
+			return err
+		}
+		changed = true
+		after := compartmentSnapshot(comp)
+		after["reason"] = change.Reason
+		after["forced"] = change.Forced
+		return repos.audit.Record(ctx, audit.Change{
+			Action:     "compartment.status_update",
+			EntityType: audit.EntityCompartment,
+			EntityID:   comp.ID.String(),
+			Before:     compartmentSnapshot(&before),
+			After:      after,
+		})
+	})
+	if err != nil {
+		var appErr errorx.Error
+		if !errors.As(err, &appErr) {
+			logger.Error(ctx, "admin ops usecase update compartment status failed unexpectedly", map[string]interface{}{
+				"compartmentId": input.CompartmentID.String(),
+				"status":        input.Status,
+				"error":         err.Error(),
+			}, "")
+		}
+		return nil, err
+	}
+	logger.Info(ctx, "admin ops usecase compartment status updated", map[string]interface{}{
+		"compartmentId": result.ID.String(),
+		"status":        result.Status,
+		"changed":       changed,
+	}, "")
+	return result, nil
+}
+
+// ListCompartmentStatusChanges returns the status history of a compartment of the locker,
+// newest first.
+func (uc *UseCase) ListCompartmentStatusChanges(ctx context.Context, lockerID, compartmentID uuid.UUID, limit int) ([]compartment.StatusChange, error) {
+	comp, err := uc.compRepo.GetByID(ctx, compartmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, compartment.ErrCompartmentNotFound
+		}
+		return nil, err
+	}
+	if comp.LockerID != lockerID {
+		return nil, compartment.ErrCompartmentNotFound
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	return uc.compRepo.ListStatusChanges(ctx, compartmentID, limit)
+}
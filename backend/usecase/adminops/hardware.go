@@ -0,0 +1,85 @@
+package adminops
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/audit"
+	"smart-parcel-locker/backend/domain/compartment"
+	"smart-parcel-locker/backend/domain/hardware"
+	"smart-parcel-locker/backend/pkg/logger"
+)
+
+// CompartmentHardware is what the locker controller reports for a compartment.
+type CompartmentHardware struct {
+	Door      hardware.DoorState
+	Occupancy hardware.Occupancy
+}
+
+// CompartmentHardware reads the door and presence sensors of a compartment of the locker.
+func (uc *UseCase) CompartmentHardware(ctx context.Context, lockerID, id uuid.UUID) (*CompartmentHardware, error) {
+	comp, err := uc.compRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, compartment.ErrCompartmentNotFound
+		}
+		return nil, err
+	}
+	if comp.LockerID != lockerID {
+		return nil, compartment.ErrCompartmentNotFound
+	}
+	if uc.doors == nil {
+		return nil, hardware.ErrControllerUnavailable
+	}
+	ref := compartmentRef(comp)
+	door, err := uc.doors.DoorState(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	occupancy, err := uc.doors.Occupancy(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return &CompartmentHardware{Door: door, Occupancy: occupancy}, nil
+}
+
+// OpenCompartment opens a compartment door for an operator, e.g. to clear a parcel from a
+// compartment taken out of service. Parcel and compartment status are left unchanged.
+func (uc *UseCase) OpenCompartment(ctx context.Context, lockerID, id uuid.UUID) error {
+	if uc.doors == nil {
+		return hardware.ErrControllerUnavailable
+	}
+	err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repos, _ := uc.repoWithTx(tx)
+		comp, err := repos.lockerCompartment(ctx, lockerID, id)
+		if err != nil {
+			return err
+		}
+		if err := repos.audit.Record(ctx, audit.Change{
+			Action:     "compartment.open",
+			EntityType: audit.EntityCompartment,
+			EntityID:   comp.ID.String(),
+			After:      compartmentSnapshot(comp),
+		}); err != nil {
+			return err
+		}
+		// Opened last so a door that stays shut leaves no audit entry behind.
+		return uc.doors.Open(ctx, compartmentRef(comp))
+	})
+	if err != nil {
+		uc.logManageError(ctx, "open compartment", id, err)
+		return err
+	}
+	logger.Info(ctx, "admin ops usecase compartment opened", map[string]interface{}{
+		"lockerId":      lockerID.String(),
+		"compartmentId": id.String(),
+	}, "")
+	return nil
+}
+
+func compartmentRef(c *compartment.Compartment) hardware.CompartmentRef {
+	return hardware.CompartmentRef{LockerID: c.LockerID, CompartmentID: c.ID, CompartmentNo: c.CompartmentNo}
+}
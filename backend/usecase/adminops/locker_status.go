@@ -0,0 +1,140 @@
+package adminops
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/audit"
+	"smart-parcel-locker/backend/domain/locker"
+	"smart-parcel-locker/backend/domain/parcel"
+	"smart-parcel-locker/backend/pkg/errorx"
+	"smart-parcel-locker/backend/pkg/logger"
+)
+
+// UpdateLockerStatusInput moves a locker to another status. Every change needs a Reason; Force is
+// needed to disable a locker that still holds parcels.
+type UpdateLockerStatusInput struct {
+	LockerID uuid.UUID
+	Status   string
+	Reason   string
+	Force    bool
+}
+
+// UpdateLockerStatus applies an admin status change following the locker state machine and
+// records it in the locker's status history.
+func (uc *UseCase) UpdateLockerStatus(ctx context.Context, input UpdateLockerStatusInput) (*locker.Locker, error) {
+	input.Reason = strings.TrimSpace(input.Reason)
+	logger.Info(ctx, "admin ops usecase update locker status started", map[string]interface{}{
+		"lockerId": input.LockerID.String(),
+		"status":   input.Status,
+		"force":    input.Force,
+	}, "")
+	if len(input.Reason) > maxStatusReasonLength {
+		return nil, errorx.Error{Code: "INVALID_INPUT", Message: "reason must be at most 500 characters"}
+	}
+
+	var result *locker.Locker
+	err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repos, _ := uc.repoWithTx(tx)
+		// Lock the locker so a deposit cannot commit between the count and the change.
+		before, err := repos.lockerRepo.GetByIDForUpdate(ctx, input.LockerID)
+		if err != nil {
+			return err
+		}
+		_, active, err := repos.parcelRepo.Search(ctx, parcel.SearchFilter{
+			LockerID: &input.LockerID,
+			Statuses: parcel.ActiveStatuses(),
+			Limit:    1,
+		})
+		if err != nil {
+			return err
+		}
+		updated, err := repos.changeLockerStatus(ctx, before, locker.StatusChangeRequest{
+			To:            input.Status,
+			Reason:        input.Reason,
+			Force:         input.Force,
+			ActiveParcels: active,
+		})
+		if err != nil {
+			return err
+		}
+		result = updated
+		after := lockerSnapshot(updated)
+		after["reason"] = input.Reason
+		after["forced"] = input.Force && active > 0
+		return repos.audit.Record(ctx, audit.Change{
+			Action:     "locker.status_update",
+			EntityType: audit.EntityLocker,
+			EntityID:   updated.ID.String(),
+			Before:     lockerSnapshot(before),
+			After:      after,
+		})
+	})
+	if err != nil {
+		fields := map[string]interface{}{
+			"lockerId": input.LockerID.String(),
+			"status":   input.Status,
+			"error":    err.Error(),
+		}
+		var appErr errorx.Error
+		if errors.Is(err, gorm.ErrRecordNotFound) || errors.As(err, &appErr) {
+			logger.Warn(ctx, "admin ops usecase update locker status rejected", fields, "")
+		} else {
+			logger.Error(ctx, "admin ops usecase update locker status failed unexpectedly", fields, "")
+		}
+		return nil, err
+	}
+	logger.Info(ctx, "admin ops usecase locker status updated", map[string]interface{}{
+		"lockerId": result.ID,
+		"status":   result.Status,
+	}, "")
+	return result, nil
+}
+
+// changeLockerStatus checks req against the state machine, saves the new status only if nobody
+// changed it meanwhile, and adds the change to the status history.
+func (uc *UseCase) changeLockerStatus(ctx context.Context, l *locker.Locker, req locker.StatusChangeRequest) (*locker.Locker, error) {
+	next := *l
+	if err := next.ChangeStatus(req); err != nil {
+		return nil, err
+	}
+	moved, err := uc.lockerRepo.TransitionStatus(ctx, l.ID, l.Status, next.Status)
+	if err != nil {
+		return nil, err
+	}
+	if !moved {
+		// The heartbeat monitor or another admin got there first.
+		return nil, locker.ErrInvalidLockerTransition
+	}
+	change := &locker.StatusChange{
+		ID:         uuid.New(),
+		LockerID:   l.ID,
+		FromStatus: l.Status,
+		ToStatus:   next.Status,
+		Reason:     strings.TrimSpace(req.Reason),
+		CreatedAt:  time.Now(),
+	}
+	if actor, ok := audit.ActorFrom(ctx); ok {
+		change.ActorID = &actor.AdminID
+	}
+	if err := uc.lockerRepo.CreateStatusChange(ctx, change); err != nil {
+		return nil, err
+	}
+	return uc.lockerRepo.GetByID(ctx, l.ID)
+}
+
+// ListLockerStatusChanges returns the status history of a locker, newest first.
+func (uc *UseCase) ListLockerStatusChanges(ctx context.Context, lockerID uuid.UUID, limit int) ([]locker.StatusChange, error) {
+	if _, err := uc.lockerRepo.GetByID(ctx, lockerID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	return uc.lockerRepo.ListStatusChanges(ctx, lockerID, limit)
+}
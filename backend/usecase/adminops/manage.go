@@ -0,0 +1,537 @@
+package adminops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/audit"
+	"smart-parcel-locker/backend/domain/compartment"
+	"smart-parcel-locker/backend/domain/location"
+	"smart-parcel-locker/backend/domain/locker"
+	"smart-parcel-locker/backend/domain/parcel"
+	"smart-parcel-locker/backend/pkg/errorx"
+	"smart-parcel-locker/backend/pkg/logger"
+)
+
+// maxBlockingParcels caps how many blocking parcels a refused delete lists.
+const maxBlockingParcels = 50
+
+// archiveStatusChange disables lockers when they are archived. Archiving already refuses while
+// parcels are active, so it never needs force.
+var archiveStatusChange = locker.StatusChangeRequest{To: locker.StatusDisabled, Reason: "archived"}
+
+// Removal reports how a delete was carried out.
+type Removal string
+
+const (
+	// RemovalDeleted means the row is gone.
+	RemovalDeleted Removal = "DELETED"
+	// RemovalArchived means finished parcels still reference the entity, so it was taken out of
+	// use instead of deleted.
+	RemovalArchived Removal = "ARCHIVED"
+)
+
+// InUseError refuses a delete while active parcels still reference the entity. Parcels holds at
+// most maxBlockingParcels of them; Total counts all.
+type InUseError struct {
+	EntityType string
+	EntityID   uuid.UUID
+	Parcels    []*parcel.Parcel
+	Total      int64
+}
+
+func (e *InUseError) Error() string {
+	return fmt.Sprintf("%s is still used by %d active parcel(s)", e.EntityType, e.Total)
+}
+
+// UpdateLocationInput changes location details. Nil fields are left as they are; an empty
+// Address clears it.
+type UpdateLocationInput struct {
+	ID            uuid.UUID
+	Code          *string
+	Name          *string
+	Address       *string
+	IsActive      *bool
+	DefaultLocale *string
+}
+
+// UpdateLockerInput renames a locker or changes its code. Nil fields are left as they are.
+type UpdateLockerInput struct {
+	ID         uuid.UUID
+	LockerCode *string
+	Name       *string
+}
+
+// UpdateCompartmentInput changes compartment details. Nil fields are left as they are. The size
+// of a compartment holding a parcel cannot change.
+type UpdateCompartmentInput struct {
+	LockerID         uuid.UUID
+	ID               uuid.UUID
+	CompartmentNo    *int
+	Size             *string
+	OverdueFeePerDay *int
+}
+
+// UpdateLocation changes the details of a location.
+func (uc *UseCase) UpdateLocation(ctx context.Context, input UpdateLocationInput) (*location.Location, error) {
+	logger.Info(ctx, "admin ops usecase update location started", map[string]interface{}{
+		"locationId": input.ID.String(),
+	}, "")
+	var result *location.Location
+	err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repos, _ := uc.repoWithTx(tx)
+		before, err := repos.locationRepo.GetByID(ctx, input.ID)
+		if err != nil {
+			return err
+		}
+		after := *before
+		if input.Code != nil {
+			after.Code = strings.TrimSpace(*input.Code)
+			if after.Code == "" {
+				return errorx.Error{Code: "INVALID_INPUT", Message: "code cannot be empty"}
+			}
+			if after.Code != before.Code {
+				existing, err := repos.locationRepo.GetByCode(ctx, after.Code)
+				if err == nil && existing.ID != before.ID {
+					return location.ErrLocationCodeTaken
+				}
+				if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+					return err
+				}
+			}
+		}
+		if input.Name != nil {
+			after.Name = strings.TrimSpace(*input.Name)
+			if after.Name == "" {
+				return errorx.Error{Code: "INVALID_INPUT", Message: "name cannot be empty"}
+			}
+		}
+		if input.Address != nil {
+			after.Address = nil
+			if address := strings.TrimSpace(*input.Address); address != "" {
+				after.Address = &address
+			}
+		}
+		if input.IsActive != nil {
+			after.IsActive = *input.IsActive
+		}
+		if input.DefaultLocale != nil {
+			after.DefaultLocale = *input.DefaultLocale
+		}
+		updated, err := repos.locationRepo.Update(ctx, &after)
+		if err != nil {
+			return err
+		}
+		result = updated
+		return repos.audit.Record(ctx, audit.Change{
+			Action:     "location.update",
+			EntityType: audit.EntityLocation,
+			EntityID:   updated.ID.String(),
+			Before:     locationSnapshot(before),
+			After:      locationSnapshot(updated),
+		})
+	})
+	if err != nil {
+		uc.logManageError(ctx, "update location", input.ID, err)
+		return nil, err
+	}
+	logger.Info(ctx, "admin ops usecase location updated", map[string]interface{}{
+		"locationId":   result.ID,
+		"locationCode": result.Code,
+	}, "")
+	return result, nil
+}
+
+// DeleteLocation deletes a location without lockers. A location with lockers is archived
+// instead: it is deactivated and its lockers are disabled. Active parcels in its lockers block
+// both.
+func (uc *UseCase) DeleteLocation(ctx context.Context, id uuid.UUID) (Removal, error) {
+	logger.Info(ctx, "admin ops usecase delete location started", map[string]interface{}{
+		"locationId": id.String(),
+	}, "")
+	var removal Removal
+	err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repos, _ := uc.repoWithTx(tx)
+		before, err := repos.locationRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		lockers, err := repos.lockerRepo.ListByLocation(ctx, id)
+		if err != nil {
+			return err
+		}
+		// Lock every locker so no deposit can commit between the count and the archive.
+		for i := range lockers {
+			locked, err := repos.lockerRepo.GetByIDForUpdate(ctx, lockers[i].ID)
+			if err != nil {
+				return err
+			}
+			lockers[i] = *locked
+		}
+		if err := repos.ensureNoActiveParcels(ctx, audit.EntityLocation, id, parcel.SearchFilter{LocationIDs: []uuid.UUID{id}}); err != nil {
+			return err
+		}
+		if len(lockers) == 0 {
+			if err := repos.locationRepo.Delete(ctx, id); err != nil {
+				return err
+			}
+			removal = RemovalDeleted
+			return repos.audit.Record(ctx, audit.Change{
+				Action:     "location.delete",
+				EntityType: audit.EntityLocation,
+				EntityID:   id.String(),
+				Before:     locationSnapshot(before),
+			})
+		}
+
+		after := *before
+		after.IsActive = false
+		updated, err := repos.locationRepo.Update(ctx, &after)
+		if err != nil {
+			return err
+		}
+		for i := range lockers {
+			if lockers[i].Status == locker.StatusDisabled {
+				continue
+			}
+			if _, err := repos.changeLockerStatus(ctx, &lockers[i], archiveStatusChange); err != nil {
+				return err
+			}
+		}
+		removal = RemovalArchived
+		return repos.audit.Record(ctx, audit.Change{
+			Action:     "location.archive",
+			EntityType: audit.EntityLocation,
+			EntityID:   id.String(),
+			Before:     locationSnapshot(before),
+			After:      locationSnapshot(updated),
+		})
+	})
+	if err != nil {
+		uc.logManageError(ctx, "delete location", id, err)
+		return "", err
+	}
+	logger.Info(ctx, "admin ops usecase location removed", map[string]interface{}{
+		"locationId": id.String(),
+		"removal":    removal,
+	}, "")
+	return removal, nil
+}
+
+// UpdateLocker changes the code or name of a locker.
+func (uc *UseCase) UpdateLocker(ctx context.Context, input UpdateLockerInput) (*locker.Locker, error) {
+	logger.Info(ctx, "admin ops usecase update locker started", map[string]interface{}{
+		"lockerId": input.ID.String(),
+	}, "")
+	var result *locker.Locker
+	err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repos, _ := uc.repoWithTx(tx)
+		before, err := repos.lockerRepo.GetByID(ctx, input.ID)
+		if err != nil {
+			return err
+		}
+		after := *before
+		if input.LockerCode != nil {
+			after.LockerCode = strings.TrimSpace(*input.LockerCode)
+			if after.LockerCode == "" {
+				return errorx.Error{Code: "INVALID_INPUT", Message: "locker_code cannot be empty"}
+			}
+			if after.LockerCode != before.LockerCode {
+				existing, err := repos.lockerRepo.GetByCode(ctx, after.LockerCode)
+				if err == nil && existing.ID != before.ID {
+					return locker.ErrLockerCodeTaken
+				}
+				if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+					return err
+				}
+			}
+		}
+		if input.Name != nil {
+			after.Name = strings.TrimSpace(*input.Name)
+		}
+		updated, err := repos.lockerRepo.Update(ctx, &after)
+		if err != nil {
+			return err
+		}
+		result = updated
+		return repos.audit.Record(ctx, audit.Change{
+			Action:     "locker.update",
+			EntityType: audit.EntityLocker,
+			EntityID:   updated.ID.String(),
+			Before:     lockerSnapshot(before),
+			After:      lockerSnapshot(updated),
+		})
+	})
+	if err != nil {
+		uc.logManageError(ctx, "update locker", input.ID, err)
+		return nil, err
+	}
+	logger.Info(ctx, "admin ops usecase locker updated", map[string]interface{}{
+		"lockerId":   result.ID,
+		"lockerCode": result.LockerCode,
+	}, "")
+	return result, nil
+}
+
+// DeleteLocker deletes a locker that never held a parcel, together with its compartments. A
+// locker with finished parcels is archived by disabling it. Active parcels block both.
+func (uc *UseCase) DeleteLocker(ctx context.Context, id uuid.UUID) (Removal, error) {
+	logger.Info(ctx, "admin ops usecase delete locker started", map[string]interface{}{
+		"lockerId": id.String(),
+	}, "")
+	var removal Removal
+	err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repos, _ := uc.repoWithTx(tx)
+		before, err := repos.lockerRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		filter := parcel.SearchFilter{LockerID: &id}
+		if err := repos.ensureNoActiveParcels(ctx, audit.EntityLocker, id, filter); err != nil {
+			return err
+		}
+		referenced, err := repos.hasParcels(ctx, filter)
+		if err != nil {
+			return err
+		}
+		if !referenced {
+			if err := repos.compRepo.DeleteByLocker(ctx, id); err != nil {
+				return err
+			}
+			if err := repos.lockerRepo.Delete(ctx, id); err != nil {
+				return err
+			}
+			removal = RemovalDeleted
+			return repos.audit.Record(ctx, audit.Change{
+				Action:     "locker.delete",
+				EntityType: audit.EntityLocker,
+				EntityID:   id.String(),
+				Before:     lockerSnapshot(before),
+			})
+		}
+
+		updated := before
+		if before.Status != locker.StatusDisabled {
+			if updated, err = repos.changeLockerStatus(ctx, before, archiveStatusChange); err != nil {
+				return err
+			}
+		}
+		removal = RemovalArchived
+		return repos.audit.Record(ctx, audit.Change{
+			Action:     "locker.archive",
+			EntityType: audit.EntityLocker,
+			EntityID:   id.String(),
+			Before:     lockerSnapshot(before),
+			After:      lockerSnapshot(updated),
+		})
+	})
+	if err != nil {
+		uc.logManageError(ctx, "delete locker", id, err)
+		return "", err
+	}
+	logger.Info(ctx, "admin ops usecase locker removed", map[string]interface{}{
+		"lockerId": id.String(),
+		"removal":  removal,
+	}, "")
+	return removal, nil
+}
+
+// UpdateCompartment changes the number, size or overdue fee of a compartment of the locker.
+func (uc *UseCase) UpdateCompartment(ctx context.Context, input UpdateCompartmentInput) (*compartment.Compartment, error) {
+	logger.Info(ctx, "admin ops usecase update compartment started", map[string]interface{}{
+		"lockerId":      input.LockerID.String(),
+		"compartmentId": input.ID.String(),
+	}, "")
+	var result *compartment.Compartment
+	err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repos, _ := uc.repoWithTx(tx)
+		before, err := repos.lockerCompartment(ctx, input.LockerID, input.ID)
+		if err != nil {
+			return err
+		}
+		after := *before
+		if input.CompartmentNo != nil && *input.CompartmentNo != before.CompartmentNo {
+			after.CompartmentNo = *input.CompartmentNo
+			siblings, err := repos.compRepo.ListByLocker(ctx, input.LockerID)
+			if err != nil {
+				return err
+			}
+			for _, s := range siblings {
+				if s.ID != before.ID && s.CompartmentNo == after.CompartmentNo {
+					return compartment.ErrCompartmentNoTaken
+				}
+			}
+		}
+		if input.Size != nil && *input.Size != before.Size {
+			if before.Status == compartment.StatusReserved || before.Status == compartment.StatusOccupied {
+				return compartment.ErrCompartmentResize
+			}
+			after.Size = *input.Size
+		}
+		if input.OverdueFeePerDay != nil {
+			after.OverdueFeePerDay = *input.OverdueFeePerDay
+		}
+		updated, err := repos.compRepo.UpdateDetails(ctx, &after)
+		if err != nil {
+			return err
+		}
+		result = updated
+		return repos.audit.Record(ctx, audit.Change{
+			Action:     "compartment.update",
+			EntityType: audit.EntityCompartment,
+			EntityID:   updated.ID.String(),
+			Before:     compartmentSnapshot(before),
+			After:      compartmentSnapshot(updated),
+		})
+	})
+	if err != nil {
+		uc.logManageError(ctx, "update compartment", input.ID, err)
+		return nil, err
+	}
+	logger.Info(ctx, "admin ops usecase compartment updated", map[string]interface{}{
+		"compartmentId":    result.ID,
+		"compartmentNo":    result.CompartmentNo,
+		"size":             result.Size,
+		"overdueFeePerDay": result.OverdueFeePerDay,
+	}, "")
+	return result, nil
+}
+
+// DeleteCompartment deletes a compartment that never held a parcel. A compartment with finished
+// parcels is archived by taking it out of service. Active parcels block both.
+func (uc *UseCase) DeleteCompartment(ctx context.Context, lockerID, id uuid.UUID) (Removal, error) {
+	logger.Info(ctx, "admin ops usecase delete compartment started", map[string]interface{}{
+		"lockerId":      lockerID.String(),
+		"compartmentId": id.String(),
+	}, "")
+	var removal Removal
+	err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repos, _ := uc.repoWithTx(tx)
+		comp, err := repos.lockerCompartment(ctx, lockerID, id)
+		if err != nil {
+			return err
+		}
+		before := *comp
+		filter := parcel.SearchFilter{CompartmentID: &id}
+		if err := repos.ensureNoActiveParcels(ctx, audit.EntityCompartment, id, filter); err != nil {
+			return err
+		}
+		referenced, err := repos.hasParcels(ctx, filter)
+		if err != nil {
+			return err
+		}
+		if !referenced {
+			if err := repos.compRepo.Delete(ctx, id); err != nil {
+				return err
+			}
+			removal = RemovalDeleted
+			return repos.audit.Record(ctx, audit.Change{
+				Action:     "compartment.delete",
+				EntityType: audit.EntityCompartment,
+				EntityID:   id.String(),
+				Before:     compartmentSnapshot(&before),
+			})
+		}
+
+		removal = RemovalArchived
+		if comp.Status != compartment.StatusOutOfService {
+			// No active parcel references it, so a leftover reservation is safe to override.
+			if err := comp.TakeOutOfService(true); err != nil {
+				return err
+			}
+			if _, err := repos.compRepo.Update(ctx, comp); err != nil {
+				return err
+			}
+			change := &compartment.StatusChange{
+				ID:            uuid.New(),
+				CompartmentID: id,
+				FromStatus:    before.Status,
+				ToStatus:      comp.Status,
+				Reason:        "archived",
+				CreatedAt:     time.Now(),
+			}
+			if actor, ok := audit.ActorFrom(ctx); ok {
+				change.ActorID = &actor.AdminID
+			}
+			if err := repos.compRepo.CreateStatusChange(ctx, change); err != nil {
+				return err
+			}
+		}
+		return repos.audit.Record(ctx, audit.Change{
+			Action:     "compartment.archive",
+			EntityType: audit.EntityCompartment,
+			EntityID:   id.String(),
+			Before:     compartmentSnapshot(&before),
+			After:      compartmentSnapshot(comp),
+		})
+	})
+	if err != nil {
+		uc.logManageError(ctx, "delete compartment", id, err)
+		return "", err
+	}
+	logger.Info(ctx, "admin ops usecase compartment removed", map[string]interface{}{
+		"compartmentId": id.String(),
+		"removal":       removal,
+	}, "")
+	return removal, nil
+}
+
+// lockerCompartment loads a compartment for update and checks it belongs to the locker.
+func (uc *UseCase) lockerCompartment(ctx context.Context, lockerID, id uuid.UUID) (*compartment.Compartment, error) {
+	comp, err := uc.compRepo.GetByIDForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, compartment.ErrCompartmentNotFound
+		}
+		return nil, err
+	}
+	if comp.LockerID != lockerID {
+		return nil, compartment.ErrCompartmentNotFound
+	}
+	return comp, nil
+}
+
+// ensureNoActiveParcels returns an InUseError listing the active parcels matched by filter.
+func (uc *UseCase) ensureNoActiveParcels(ctx context.Context, entityType string, id uuid.UUID, filter parcel.SearchFilter) error {
+	filter.Statuses = parcel.ActiveStatuses()
+	filter.Limit = maxBlockingParcels
+	items, total, err := uc.parcelRepo.Search(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if total == 0 {
+		return nil
+	}
+	return &InUseError{EntityType: entityType, EntityID: id, Parcels: items, Total: total}
+}
+
+// hasParcels reports whether any parcel, finished or not, matches filter.
+func (uc *UseCase) hasParcels(ctx context.Context, filter parcel.SearchFilter) (bool, error) {
+	filter.Limit = 1
+	_, total, err := uc.parcelRepo.Search(ctx, filter)
+	if err != nil {
+		return false, err
+	}
+	return total > 0, nil
+}
+
+func (uc *UseCase) logManageError(ctx context.Context, op string, id uuid.UUID, err error) {
+	fields := map[string]interface{}{
+		"op":    op,
+		"id":    id.String(),
+		"error": err.Error(),
+	}
+	var appErr errorx.Error
+	var inUse *InUseError
+	if errors.Is(err, gorm.ErrRecordNotFound) || errors.As(err, &appErr) || errors.As(err, &inUse) {
+		logger.Warn(ctx, "admin ops usecase change rejected", fields, "")
+		return
+	}
+	logger.Error(ctx, "admin ops usecase change failed unexpectedly", fields, "")
+}
@@ -0,0 +1,197 @@
+package adminops
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/compartment"
+	"smart-parcel-locker/backend/domain/locker"
+	"smart-parcel-locker/backend/domain/parcel"
+)
+
+type fakeLockers struct {
+	locker.Repository
+	lockers map[uuid.UUID]*locker.Locker
+	changes []locker.StatusChange
+	locked  []uuid.UUID
+}
+
+func (f *fakeLockers) GetByID(_ context.Context, id uuid.UUID) (*locker.Locker, error) {
+	l, ok := f.lockers[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	cp := *l
+	return &cp, nil
+}
+
+func (f *fakeLockers) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*locker.Locker, error) {
+	f.locked = append(f.locked, id)
+	return f.GetByID(ctx, id)
+}
+
+func (f *fakeLockers) UpdateStatus(_ context.Context, id uuid.UUID, status string) (*locker.Locker, error) {
+	f.lockers[id].Status = status
+	cp := *f.lockers[id]
+	return &cp, nil
+}
+
+func (f *fakeLockers) TransitionStatus(_ context.Context, id uuid.UUID, from, to string) (bool, error) {
+	if f.lockers[id].Status != from {
+		return false, nil
+	}
+	f.lockers[id].Status = to
+	return true, nil
+}
+
+func (f *fakeLockers) CreateStatusChange(_ context.Context, change *locker.StatusChange) error {
+	f.changes = append(f.changes, *change)
+	return nil
+}
+
+func (f *fakeLockers) Delete(_ context.Context, id uuid.UUID) error {
+	delete(f.lockers, id)
+	return nil
+}
+
+type fakeCompartments struct {
+	compartment.Repository
+	deletedLockers []uuid.UUID
+}
+
+func (f *fakeCompartments) DeleteByLocker(_ context.Context, lockerID uuid.UUID) error {
+	f.deletedLockers = append(f.deletedLockers, lockerID)
+	return nil
+}
+
+type fakeParcels struct {
+	parcel.Repository
+	parcels []*parcel.Parcel
+}
+
+func (f *fakeParcels) Search(_ context.Context, filter parcel.SearchFilter) ([]*parcel.Parcel, int64, error) {
+	var out []*parcel.Parcel
+	for _, p := range f.parcels {
+		if filter.LockerID != nil && p.LockerID != *filter.LockerID {
+			continue
+		}
+		if len(filter.Statuses) > 0 && !containsStatus(filter.Statuses, p.Status) {
+			continue
+		}
+		out = append(out, p)
+	}
+	total := int64(len(out))
+	if len(out) > filter.Limit {
+		out = out[:filter.Limit]
+	}
+	return out, total, nil
+}
+
+func containsStatus(statuses []parcel.Status, s parcel.Status) bool {
+	for _, candidate := range statuses {
+		if candidate == s {
+			return true
+		}
+	}
+	return false
+}
+
+func newManageUseCase(parcels ...*parcel.Parcel) (*UseCase, *fakeLockers, *fakeCompartments, uuid.UUID) {
+	lockerID := uuid.New()
+	lockers := &fakeLockers{lockers: map[uuid.UUID]*locker.Locker{
+		lockerID: {ID: lockerID, LockerCode: "LK-1", Status: locker.StatusActive},
+	}}
+	comps := &fakeCompartments{}
+	for _, p := range parcels {
+		p.LockerID = lockerID
+	}
+	uc := NewUseCase(nil, lockers, comps, &fakeParcels{parcels: parcels}, nil, nil, nil)
+	return uc, lockers, comps, lockerID
+}
+
+func TestDeleteLockerRefusesWithActiveParcels(t *testing.T) {
+	active := &parcel.Parcel{ID: uuid.New(), ParcelCode: "P-1", Status: parcel.StatusReadyForPickup}
+	done := &parcel.Parcel{ID: uuid.New(), ParcelCode: "P-2", Status: parcel.StatusPickedUp}
+	uc, lockers, _, lockerID := newManageUseCase(active, done)
+
+	_, err := uc.DeleteLocker(context.Background(), lockerID)
+	var inUse *InUseError
+	if !errors.As(err, &inUse) {
+		t.Fatalf("expected InUseError, got %v", err)
+	}
+	if inUse.Total != 1 || len(inUse.Parcels) != 1 || inUse.Parcels[0].ID != active.ID {
+		t.Fatalf("expected only the active parcel to block, got %+v", inUse)
+	}
+	if lockers.lockers[lockerID].Status != locker.StatusActive {
+		t.Fatalf("refused delete changed the locker")
+	}
+}
+
+func TestDeleteLockerArchivesWithFinishedParcels(t *testing.T) {
+	uc, lockers, comps, lockerID := newManageUseCase(&parcel.Parcel{ID: uuid.New(), Status: parcel.StatusPickedUp})
+
+	removal, err := uc.DeleteLocker(context.Background(), lockerID)
+	if err != nil {
+		t.Fatalf("delete locker: %v", err)
+	}
+	if removal != RemovalArchived {
+		t.Fatalf("expected %s, got %s", RemovalArchived, removal)
+	}
+	if l, ok := lockers.lockers[lockerID]; !ok || l.Status != locker.StatusDisabled {
+		t.Fatalf("expected locker to be kept and disabled")
+	}
+	if len(comps.deletedLockers) != 0 {
+		t.Fatalf("archived locker lost its compartments")
+	}
+	if len(lockers.changes) != 1 || lockers.changes[0].Reason != "archived" {
+		t.Fatalf("archive not recorded in status history: %+v", lockers.changes)
+	}
+}
+
+func TestDeleteLockerWithoutParcels(t *testing.T) {
+	uc, lockers, comps, lockerID := newManageUseCase()
+
+	removal, err := uc.DeleteLocker(context.Background(), lockerID)
+	if err != nil {
+		t.Fatalf("delete locker: %v", err)
+	}
+	if removal != RemovalDeleted {
+		t.Fatalf("expected %s, got %s", RemovalDeleted, removal)
+	}
+	if _, ok := lockers.lockers[lockerID]; ok {
+		t.Fatalf("locker was not deleted")
+	}
+	if len(comps.deletedLockers) != 1 || comps.deletedLockers[0] != lockerID {
+		t.Fatalf("compartments were not deleted with the locker")
+	}
+}
+
+func TestUpdateLockerStatusRefusesDisableWithParcels(t *testing.T) {
+	uc, lockers, _, lockerID := newManageUseCase(&parcel.Parcel{ID: uuid.New(), Status: parcel.StatusReadyForPickup})
+
+	input := UpdateLockerStatusInput{LockerID: lockerID, Status: locker.StatusDisabled, Reason: "decommissioned"}
+	if _, err := uc.UpdateLockerStatus(context.Background(), input); !errors.Is(err, locker.ErrLockerHasParcels) {
+		t.Fatalf("expected ErrLockerHasParcels, got %v", err)
+	}
+	if len(lockers.locked) != 1 || lockers.locked[0] != lockerID {
+		t.Fatalf("locker not locked before its parcels were counted: %v", lockers.locked)
+	}
+	if lockers.lockers[lockerID].Status != locker.StatusActive || len(lockers.changes) != 0 {
+		t.Fatalf("refused change touched the locker")
+	}
+	input.Force = true
+	updated, err := uc.UpdateLockerStatus(context.Background(), input)
+	if err != nil {
+		t.Fatalf("forced disable: %v", err)
+	}
+	if updated.Status != locker.StatusDisabled {
+		t.Fatalf("expected %s, got %s", locker.StatusDisabled, updated.Status)
+	}
+	if len(lockers.changes) != 1 || lockers.changes[0].FromStatus != locker.StatusActive || lockers.changes[0].Reason != "decommissioned" {
+		t.Fatalf("status change not recorded: %+v", lockers.changes)
+	}
+}
@@ -0,0 +1,402 @@
+package adminops
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/audit"
+	"smart-parcel-locker/backend/domain/compartment"
+	"smart-parcel-locker/backend/domain/location"
+	"smart-parcel-locker/backend/domain/locker"
+	"smart-parcel-locker/backend/domain/provisioning"
+	"smart-parcel-locker/backend/pkg/errorx"
+	"smart-parcel-locker/backend/pkg/logger"
+)
+
+// ImportManifestInput applies a provisioning manifest. DryRun only computes the plan.
+type ImportManifestInput struct {
+	Manifest *provisioning.Manifest
+	DryRun   bool
+}
+
+// ExportManifest returns every location with its lockers and compartments as a manifest that
+// ImportManifest accepts, ordered by code and compartment number.
+func (uc *UseCase) ExportManifest(ctx context.Context) (*provisioning.Manifest, error) {
+	locations, err := uc.locationRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lockers, err := uc.lockerRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byLocation := map[uuid.UUID][]locker.Locker{}
+	for _, l := range lockers {
+		byLocation[l.LocationID] = append(byLocation[l.LocationID], l)
+	}
+
+	m := &provisioning.Manifest{Locations: make([]provisioning.Location, 0, len(locations))}
+	for _, loc := range locations {
+		active := loc.IsActive
+		entry := provisioning.Location{
+			Code:          loc.Code,
+			Name:          loc.Name,
+			IsActive:      &active,
+			DefaultLocale: loc.DefaultLocale,
+			Lockers:       []provisioning.Locker{},
+		}
+		if loc.Address != nil {
+			entry.Address = *loc.Address
+		}
+		for _, l := range byLocation[loc.ID] {
+			comps, err := uc.compRepo.ListByLocker(ctx, l.ID)
+			if err != nil {
+				return nil, err
+			}
+			lockerEntry := provisioning.Locker{Code: l.LockerCode, Name: l.Name, Compartments: []provisioning.Compartment{}}
+			for _, c := range comps {
+				lockerEntry.Compartments = append(lockerEntry.Compartments, provisioning.Compartment{
+					No:               c.CompartmentNo,
+					Size:             c.Size,
+					OverdueFeePerDay: c.OverdueFeePerDay,
+				})
+			}
+			sort.Slice(lockerEntry.Compartments, func(i, j int) bool {
+				return lockerEntry.Compartments[i].No < lockerEntry.Compartments[j].No
+			})
+			entry.Lockers = append(entry.Lockers, lockerEntry)
+		}
+		sort.Slice(entry.Lockers, func(i, j int) bool { return entry.Lockers[i].Code < entry.Lockers[j].Code })
+		m.Locations = append(m.Locations, entry)
+	}
+	sort.Slice(m.Locations, func(i, j int) bool { return m.Locations[i].Code < m.Locations[j].Code })
+	logger.Info(ctx, "admin ops usecase manifest exported", map[string]interface{}{
+		"locations": len(m.Locations),
+		"lockers":   len(lockers),
+	}, "")
+	return m, nil
+}
+
+// ImportManifest creates and updates the locations, lockers and compartments of a manifest in
+// one transaction and returns the plan it applied. Entries missing from the manifest are left
+// alone; nothing is deleted, moved between locations or taken out of service. The whole manifest
+// is refused with a ManifestError if any entry is invalid or conflicts with stored state, such
+// as resizing a compartment that holds a parcel.
+func (uc *UseCase) ImportManifest(ctx context.Context, input ImportManifestInput) (*provisioning.Plan, error) {
+	m := input.Manifest
+	logger.Info(ctx, "admin ops usecase import manifest started", map[string]interface{}{
+		"locations": len(m.Locations),
+		"dryRun":    input.DryRun,
+	}, "")
+	if problems := m.Validate(); len(problems) > 0 {
+		logger.Warn(ctx, "admin ops usecase import manifest invalid", map[string]interface{}{
+			"problems": len(problems),
+		}, "")
+		return nil, &provisioning.ManifestError{Problems: problems}
+	}
+
+	var plan *provisioning.Plan
+	err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repos, _ := uc.repoWithTx(tx)
+		importer, err := repos.planManifest(ctx, m)
+		if err != nil {
+			return err
+		}
+		plan = importer.plan
+		if input.DryRun {
+			return nil
+		}
+		for _, step := range importer.steps {
+			if err := step(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fields := map[string]interface{}{"error": err.Error()}
+		var manifestErr *provisioning.ManifestError
+		var appErr errorx.Error
+		if errors.As(err, &manifestErr) || errors.As(err, &appErr) {
+			logger.Warn(ctx, "admin ops usecase import manifest rejected", fields, "")
+		} else {
+			logger.Error(ctx, "admin ops usecase import manifest failed unexpectedly", fields, "")
+		}
+		return nil, err
+	}
+	logger.Info(ctx, "admin ops usecase import manifest completed", map[string]interface{}{
+		"dryRun":  input.DryRun,
+		"changes": len(plan.Changes),
+	}, "")
+	return plan, nil
+}
+
+// manifestImport is a computed plan together with the writes that carry it out.
+type manifestImport struct {
+	plan     *provisioning.Plan
+	steps    []func(ctx context.Context) error
+	problems []provisioning.Problem
+}
+
+func (mi *manifestImport) change(action, entityType, path string, fields map[string]provisioning.FieldChange) {
+	mi.plan.Changes = append(mi.plan.Changes, provisioning.Change{
+		Action:     action,
+		EntityType: entityType,
+		Path:       path,
+		Fields:     fields,
+	})
+}
+
+func (mi *manifestImport) conflict(path, message string) {
+	mi.problems = append(mi.problems, provisioning.Problem{Path: path, Message: message})
+}
+
+// planManifest diffs the manifest against stored state.
+func (uc *UseCase) planManifest(ctx context.Context, m *provisioning.Manifest) (*manifestImport, error) {
+	locations, err := uc.locationRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lockers, err := uc.lockerRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	locationByCode := map[string]location.Location{}
+	locationCodeByID := map[uuid.UUID]string{}
+	for _, loc := range locations {
+		locationByCode[loc.Code] = loc
+		locationCodeByID[loc.ID] = loc.Code
+	}
+	lockerByCode := map[string]locker.Locker{}
+	for _, l := range lockers {
+		lockerByCode[l.LockerCode] = l
+	}
+
+	mi := &manifestImport{plan: &provisioning.Plan{Changes: []provisioning.Change{}}}
+	for _, spec := range m.Locations {
+		locationID := uc.planLocation(mi, spec, locationByCode)
+		for _, lockerSpec := range spec.Lockers {
+			path := provisioning.LockerPath(spec.Code, lockerSpec.Code)
+			existing, ok := lockerByCode[lockerSpec.Code]
+			if ok && existing.LocationID != locationID {
+				mi.conflict(path, "locker "+lockerSpec.Code+" belongs to location "+locationCodeByID[existing.LocationID])
+				continue
+			}
+			lockerID := uc.planLocker(mi, path, locationID, lockerSpec, existing, ok)
+			var comps []compartment.Compartment
+			if ok {
+				if comps, err = uc.compRepo.ListByLocker(ctx, existing.ID); err != nil {
+					return nil, err
+				}
+			}
+			uc.planCompartments(mi, spec.Code, lockerID, lockerSpec, comps)
+		}
+	}
+	if len(mi.problems) > 0 {
+		return nil, &provisioning.ManifestError{Problems: mi.problems}
+	}
+	return mi, nil
+}
+
+func (uc *UseCase) planLocation(mi *manifestImport, spec provisioning.Location, byCode map[string]location.Location) uuid.UUID {
+	path := provisioning.LocationPath(spec.Code)
+	var address *string
+	if spec.Address != "" {
+		a := spec.Address
+		address = &a
+	}
+	existing, ok := byCode[spec.Code]
+	if !ok {
+		entity := &location.Location{
+			ID:            uuid.New(),
+			Code:          spec.Code,
+			Name:          spec.Name,
+			Address:       address,
+			IsActive:      spec.Active(),
+			DefaultLocale: spec.DefaultLocale,
+		}
+		mi.plan.Locations.Create++
+		mi.change(provisioning.ActionCreate, audit.EntityLocation, path, provisioning.Diff(
+			provisioning.FieldPair{Name: "name", To: spec.Name},
+			provisioning.FieldPair{Name: "address", To: spec.Address},
+			provisioning.FieldPair{Name: "is_active", To: spec.Active()},
+			provisioning.FieldPair{Name: "default_locale", To: spec.DefaultLocale},
+		))
+		mi.steps = append(mi.steps, func(ctx context.Context) error {
+			created, err := uc.locationRepo.Create(ctx, entity)
+			if err != nil {
+				return err
+			}
+			return uc.audit.Record(ctx, audit.Change{
+				Action:     "location.create",
+				EntityType: audit.EntityLocation,
+				EntityID:   created.ID.String(),
+				After:      locationSnapshot(created),
+			})
+		})
+		return entity.ID
+	}
+
+	storedAddress := ""
+	if existing.Address != nil {
+		storedAddress = *existing.Address
+	}
+	fields := provisioning.Diff(
+		provisioning.FieldPair{Name: "name", From: existing.Name, To: spec.Name},
+		provisioning.FieldPair{Name: "address", From: storedAddress, To: spec.Address},
+		provisioning.FieldPair{Name: "is_active", From: existing.IsActive, To: spec.Active()},
+		provisioning.FieldPair{Name: "default_locale", From: existing.DefaultLocale, To: spec.DefaultLocale},
+	)
+	if fields == nil {
+		mi.plan.Locations.Unchanged++
+		return existing.ID
+	}
+	mi.plan.Locations.Update++
+	mi.change(provisioning.ActionUpdate, audit.EntityLocation, path, fields)
+	after := existing
+	after.Name, after.Address, after.IsActive, after.DefaultLocale = spec.Name, address, spec.Active(), spec.DefaultLocale
+	mi.steps = append(mi.steps, func(ctx context.Context) error {
+		updated, err := uc.locationRepo.Update(ctx, &after)
+		if err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, audit.Change{
+			Action:     "location.update",
+			EntityType: audit.EntityLocation,
+			EntityID:   updated.ID.String(),
+			Before:     locationSnapshot(&existing),
+			After:      locationSnapshot(updated),
+		})
+	})
+	return existing.ID
+}
+
+func (uc *UseCase) planLocker(mi *manifestImport, path string, locationID uuid.UUID, spec provisioning.Locker, existing locker.Locker, found bool) uuid.UUID {
+	if !found {
+		entity := &locker.Locker{
+			ID:         uuid.New(),
+			LocationID: locationID,
+			LockerCode: spec.Code,
+			Name:       spec.Name,
+			Status:     locker.StatusActive,
+		}
+		mi.plan.Lockers.Create++
+		mi.change(provisioning.ActionCreate, audit.EntityLocker, path, provisioning.Diff(
+			provisioning.FieldPair{Name: "name", To: spec.Name},
+		))
+		mi.steps = append(mi.steps, func(ctx context.Context) error {
+			created, err := uc.lockerRepo.Create(ctx, entity)
+			if err != nil {
+				return err
+			}
+			return uc.audit.Record(ctx, audit.Change{
+				Action:     "locker.create",
+				EntityType: audit.EntityLocker,
+				EntityID:   created.ID.String(),
+				After:      lockerSnapshot(created),
+			})
+		})
+		return entity.ID
+	}
+
+	fields := provisioning.Diff(provisioning.FieldPair{Name: "name", From: existing.Name, To: spec.Name})
+	if fields == nil {
+		mi.plan.Lockers.Unchanged++
+		return existing.ID
+	}
+	mi.plan.Lockers.Update++
+	mi.change(provisioning.ActionUpdate, audit.EntityLocker, path, fields)
+	after := existing
+	after.Name = spec.Name
+	mi.steps = append(mi.steps, func(ctx context.Context) error {
+		updated, err := uc.lockerRepo.Update(ctx, &after)
+		if err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, audit.Change{
+			Action:     "locker.update",
+			EntityType: audit.EntityLocker,
+			EntityID:   updated.ID.String(),
+			Before:     lockerSnapshot(&existing),
+			After:      lockerSnapshot(updated),
+		})
+	})
+	return existing.ID
+}
+
+func (uc *UseCase) planCompartments(mi *manifestImport, locationCode string, lockerID uuid.UUID, spec provisioning.Locker, stored []compartment.Compartment) {
+	byNo := make(map[int]compartment.Compartment, len(stored))
+	for _, c := range stored {
+		byNo[c.CompartmentNo] = c
+	}
+	var created []compartment.Compartment
+	for _, c := range spec.Compartments {
+		path := provisioning.CompartmentPath(locationCode, spec.Code, c.No)
+		existing, ok := byNo[c.No]
+		if !ok {
+			created = append(created, compartment.Compartment{
+				ID:               uuid.New(),
+				LockerID:         lockerID,
+				CompartmentNo:    c.No,
+				Size:             c.Size,
+				Status:           compartment.StatusAvailable,
+				OverdueFeePerDay: c.OverdueFeePerDay,
+				CreatedAt:        time.Now(),
+			})
+			mi.plan.Compartments.Create++
+			mi.change(provisioning.ActionCreate, audit.EntityCompartment, path, provisioning.Diff(
+				provisioning.FieldPair{Name: "size", To: c.Size},
+				provisioning.FieldPair{Name: "overdue_fee_per_day", To: c.OverdueFeePerDay},
+			))
+			continue
+		}
+		fields := provisioning.Diff(
+			provisioning.FieldPair{Name: "size", From: existing.Size, To: c.Size},
+			provisioning.FieldPair{Name: "overdue_fee_per_day", From: existing.OverdueFeePerDay, To: c.OverdueFeePerDay},
+		)
+		if fields == nil {
+			mi.plan.Compartments.Unchanged++
+			continue
+		}
+		if _, resized := fields["size"]; resized && (existing.Status == compartment.StatusReserved || existing.Status == compartment.StatusOccupied) {
+			mi.conflict(path, compartment.ErrCompartmentResize.Message)
+			continue
+		}
+		mi.plan.Compartments.Update++
+		mi.change(provisioning.ActionUpdate, audit.EntityCompartment, path, fields)
+		before, after := existing, existing
+		after.Size, after.OverdueFeePerDay = c.Size, c.OverdueFeePerDay
+		mi.steps = append(mi.steps, func(ctx context.Context) error {
+			updated, err := uc.compRepo.UpdateDetails(ctx, &after)
+			if err != nil {
+				return err
+			}
+			return uc.audit.Record(ctx, audit.Change{
+				Action:     "compartment.update",
+				EntityType: audit.EntityCompartment,
+				EntityID:   updated.ID.String(),
+				Before:     compartmentSnapshot(&before),
+				After:      compartmentSnapshot(updated),
+			})
+		})
+	}
+	if len(created) == 0 {
+		return
+	}
+	mi.steps = append(mi.steps, func(ctx context.Context) error {
+		if _, err := uc.compRepo.CreateBulk(ctx, created); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, audit.Change{
+			Action:     "compartments.create",
+			EntityType: audit.EntityLocker,
+			EntityID:   lockerID.String(),
+			After:      compartmentsSnapshot(created),
+		})
+	})
+}
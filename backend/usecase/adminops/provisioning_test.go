@@ -0,0 +1,124 @@
+package adminops
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"smart-parcel-locker/backend/domain/compartment"
+	"smart-parcel-locker/backend/domain/location"
+	"smart-parcel-locker/backend/domain/locker"
+	"smart-parcel-locker/backend/domain/provisioning"
+)
+
+type fakeLocations struct {
+	location.Repository
+	locations []location.Location
+}
+
+func (f *fakeLocations) List(context.Context) ([]location.Location, error) {
+	return f.locations, nil
+}
+
+func (f *fakeLocations) Create(_ context.Context, loc *location.Location) (*location.Location, error) {
+	f.locations = append(f.locations, *loc)
+	return loc, nil
+}
+
+func (f *fakeLockers) List(context.Context) ([]locker.Locker, error) {
+	var out []locker.Locker
+	for _, l := range f.lockers {
+		out = append(out, *l)
+	}
+	return out, nil
+}
+
+func (f *fakeLockers) Create(_ context.Context, l *locker.Locker) (*locker.Locker, error) {
+	cp := *l
+	f.lockers[l.ID] = &cp
+	return l, nil
+}
+
+type fakeManifestCompartments struct {
+	fakeCompartments
+	stored  []compartment.Compartment
+	created []compartment.Compartment
+}
+
+func (f *fakeManifestCompartments) ListByLocker(_ context.Context, lockerID uuid.UUID) ([]compartment.Compartment, error) {
+	var out []compartment.Compartment
+	for _, c := range f.stored {
+		if c.LockerID == lockerID {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeManifestCompartments) CreateBulk(_ context.Context, comps []compartment.Compartment) (int, error) {
+	f.created = append(f.created, comps...)
+	return len(comps), nil
+}
+
+func newProvisioningUseCase(status string) (*UseCase, *fakeManifestCompartments) {
+	loc := location.Location{ID: uuid.New(), Code: "LOC-1", Name: "Central", IsActive: true}
+	l := &locker.Locker{ID: uuid.New(), LocationID: loc.ID, LockerCode: "LK-1", Status: locker.StatusActive}
+	comps := &fakeManifestCompartments{stored: []compartment.Compartment{
+		{ID: uuid.New(), LockerID: l.ID, CompartmentNo: 1, Size: "S", Status: status},
+	}}
+	lockers := &fakeLockers{lockers: map[uuid.UUID]*locker.Locker{l.ID: l}}
+	uc := NewUseCase(&fakeLocations{locations: []location.Location{loc}}, lockers, comps, &fakeParcels{}, nil, nil, nil)
+	return uc, comps
+}
+
+func manifestWith(compartments ...provisioning.Compartment) *provisioning.Manifest {
+	return &provisioning.Manifest{Locations: []provisioning.Location{{
+		Code: "LOC-1", Name: "Central",
+		Lockers: []provisioning.Locker{{Code: "LK-1", Compartments: compartments}},
+	}}}
+}
+
+func TestImportManifestDryRunChangesNothing(t *testing.T) {
+	uc, comps := newProvisioningUseCase(compartment.StatusAvailable)
+	m := manifestWith(provisioning.Compartment{No: 1, Size: "S"}, provisioning.Compartment{No: 2, Size: "L", OverdueFeePerDay: 20})
+
+	plan, err := uc.ImportManifest(context.Background(), ImportManifestInput{Manifest: m, DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if plan.Locations.Unchanged != 1 || plan.Lockers.Unchanged != 1 || plan.Compartments.Unchanged != 1 || plan.Compartments.Create != 1 {
+		t.Fatalf("unexpected counts: %+v", plan)
+	}
+	if len(plan.Changes) != 1 || plan.Changes[0].Path != "LOC-1/LK-1/2" || plan.Changes[0].Action != provisioning.ActionCreate {
+		t.Fatalf("unexpected changes: %+v", plan.Changes)
+	}
+	if len(comps.created) != 0 {
+		t.Fatalf("dry run created compartments")
+	}
+
+	if _, err := uc.ImportManifest(context.Background(), ImportManifestInput{Manifest: m}); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if len(comps.created) != 1 || comps.created[0].CompartmentNo != 2 || comps.created[0].Status != compartment.StatusAvailable {
+		t.Fatalf("expected compartment 2 to be created, got %+v", comps.created)
+	}
+}
+
+func TestImportManifestRefusesResizingOccupiedCompartment(t *testing.T) {
+	uc, comps := newProvisioningUseCase(compartment.StatusOccupied)
+	m := manifestWith(provisioning.Compartment{No: 1, Size: "M"}, provisioning.Compartment{No: 2, Size: "S"})
+
+	_, err := uc.ImportManifest(context.Background(), ImportManifestInput{Manifest: m})
+	var manifestErr *provisioning.ManifestError
+	if !errors.As(err, &manifestErr) {
+		t.Fatalf("expected ManifestError, got %v", err)
+	}
+	if len(manifestErr.Problems) != 1 || manifestErr.Problems[0].Path != "LOC-1/LK-1/1" {
+		t.Fatalf("unexpected problems: %+v", manifestErr.Problems)
+	}
+	if len(comps.created) != 0 {
+		t.Fatalf("refused manifest was partly applied")
+	}
+}
@@ -0,0 +1,48 @@
+package adminops
+
+import (
+	"smart-parcel-locker/backend/domain/compartment"
+	"smart-parcel-locker/backend/domain/location"
+	"smart-parcel-locker/backend/domain/locker"
+)
+
+// Audit snapshots use the API's field names so entries read like the requests that caused them.
+
+func locationSnapshot(l *location.Location) map[string]interface{} {
+	return map[string]interface{}{
+		"location_id":    l.ID,
+		"code":           l.Code,
+		"name":           l.Name,
+		"address":        l.Address,
+		"is_active":      l.IsActive,
+		"default_locale": l.DefaultLocale,
+	}
+}
+
+func lockerSnapshot(l *locker.Locker) map[string]interface{} {
+	return map[string]interface{}{
+		"locker_id":   l.ID,
+		"location_id": l.LocationID,
+		"locker_code": l.LockerCode,
+		"name":        l.Name,
+		"status":      l.Status,
+	}
+}
+
+func compartmentsSnapshot(comps []compartment.Compartment) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(comps))
+	for i := range comps {
+		out = append(out, compartmentSnapshot(&comps[i]))
+	}
+	return out
+}
+
+func compartmentSnapshot(c *compartment.Compartment) map[string]interface{} {
+	return map[string]interface{}{
+		"compartment_id":      c.ID,
+		"compartment_no":      c.CompartmentNo,
+		"size":                c.Size,
+		"status":              c.Status,
+		"overdue_fee_per_day": c.OverdueFeePerDay,
+	}
+}
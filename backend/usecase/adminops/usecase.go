@@ -8,12 +8,15 @@ import (
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"smart-parcel-locker/backend/domain/audit"
 	"smart-parcel-locker/backend/domain/compartment"
+	"smart-parcel-locker/backend/domain/hardware"
 	"smart-parcel-locker/backend/domain/location"
 	"smart-parcel-locker/backend/domain/locker"
 	"smart-parcel-locker/backend/domain/parcel"
 	"smart-parcel-locker/backend/infrastructure/database"
 	"smart-parcel-locker/backend/pkg/logger"
+	auditusecase "smart-parcel-locker/backend/usecase/audit"
 )
 
 // UseCase handles admin operational flows.
@@ -22,6 +25,8 @@ type UseCase struct {
 	lockerRepo   locker.Repository
 	compRepo     compartment.Repository
 	parcelRepo   parcel.Repository
+	audit        audit.Recorder
+	doors        hardware.LockController
 	tx           *database.TransactionManager
 
 	locationRepoFactory func(db *gorm.DB) location.Repository
@@ -31,10 +36,11 @@ type UseCase struct {
 }
 
 type CreateLocationInput struct {
-	Code     string
-	Name     string
-	Address  *string
-	IsActive *bool
+	Code          string
+	Name          string
+	Address       *string
+	IsActive      *bool
+	DefaultLocale string
 }
 
 type CreateLockerInput struct {
@@ -43,11 +49,6 @@ type CreateLockerInput struct {
 	Name       string
 }
 
-type UpdateLockerStatusInput struct {
-	LockerID uuid.UUID
-	Status   string
-}
-
 type CompartmentSpec struct {
 	CompartmentNo    int
 	Size             string
@@ -73,6 +74,8 @@ func NewUseCase(
 	lockerRepo locker.Repository,
 	compRepo compartment.Repository,
 	parcelRepo parcel.Repository,
+	recorder audit.Recorder,
+	doors hardware.LockController,
 	tx *database.TransactionManager,
 ) *UseCase {
 	if tx == nil {
@@ -84,6 +87,8 @@ func NewUseCase(
 		lockerRepo:   lockerRepo,
 		compRepo:     compRepo,
 		parcelRepo:   parcelRepo,
+		audit:        auditusecase.Bind(recorder, nil),
+		doors:        doors,
 		tx:           tx,
 	}
 
@@ -128,6 +133,7 @@ func (uc *UseCase) repoWithTx(db *gorm.DB) (*UseCase, error) {
 	if uc.parcelRepoFactory != nil {
 		cp.parcelRepo = uc.parcelRepoFactory(db)
 	}
+	cp.audit = auditusecase.Bind(uc.audit, db)
 	return &cp, nil
 }
 
@@ -142,11 +148,12 @@ func (uc *UseCase) CreateLocation(ctx context.Context, input CreateLocationInput
 		isActive = *input.IsActive
 	}
 	entity := &location.Location{
-		ID:       uuid.New(),
-		Code:     input.Code,
-		Name:     input.Name,
-		Address:  input.Address,
-		IsActive: isActive,
+		ID:            uuid.New(),
+		Code:          input.Code,
+		Name:          input.Name,
+		Address:       input.Address,
+		IsActive:      isActive,
+		DefaultLocale: input.DefaultLocale,
 	}
 
 	var result *location.Location
@@ -162,7 +169,12 @@ func (uc *UseCase) CreateLocation(ctx context.Context, input CreateLocationInput
 			return err
 		}
 		result = created
-		return nil
+		return repos.audit.Record(ctx, audit.Change{
+			Action:     "location.create",
+			EntityType: audit.EntityLocation,
+			EntityID:   created.ID.String(),
+			After:      locationSnapshot(created),
+		})
 	})
 	if err != nil {
 		return nil, err
@@ -233,7 +245,12 @@ func (uc *UseCase) CreateLocker(ctx context.Context, input CreateLockerInput) (*
 			return err
 		}
 		result = created
-		return nil
+		return repos.audit.Record(ctx, audit.Change{
+			Action:     "locker.create",
+			EntityType: audit.EntityLocker,
+			EntityID:   created.ID.String(),
+			After:      lockerSnapshot(created),
+		})
 	})
 	if err != nil {
 		return nil, err
@@ -262,46 +279,39 @@ func (uc *UseCase) ListLockers(ctx context.Context) ([]locker.Locker, error) {
 	return items, nil
 }
 
-// UpdateLockerStatus updates the status of a locker.
-func (uc *UseCase) UpdateLockerStatus(ctx context.Context, input UpdateLockerStatusInput) (*locker.Locker, error) {
-	if input.Status != locker.StatusActive && input.Status != locker.StatusMaintenance && input.Status != locker.StatusDisabled {
-		logger.Warn(ctx, "admin ops usecase update locker invalid status", map[string]interface{}{
-			"lockerId": input.LockerID.String(),
-			"status":   input.Status,
+// GetLocker loads a locker, for resolving the location an admin request acts on.
+func (uc *UseCase) GetLocker(ctx context.Context, lockerID uuid.UUID) (*locker.Locker, error) {
+	return uc.lockerRepo.GetByID(ctx, lockerID)
+}
+
+// SearchParcels finds parcels by phone, code, status or locker, newest first.
+func (uc *UseCase) SearchParcels(ctx context.Context, filter parcel.SearchFilter) ([]*parcel.Parcel, int64, error) {
+	switch filter.Status {
+	case "", parcel.StatusDepositing, parcel.StatusReadyForPickup, parcel.StatusPickedUp, parcel.StatusCancelled, parcel.StatusExpired:
+	default:
+		logger.Warn(ctx, "admin ops usecase search parcels invalid status", map[string]interface{}{
+			"status": filter.Status,
 		}, "")
-		return nil, errors.New("invalid status")
+		return nil, 0, errors.New("invalid status")
 	}
-
-	var result *locker.Locker
-	err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
-		repos, _ := uc.repoWithTx(tx)
-		updated, err := repos.lockerRepo.UpdateStatus(ctx, input.LockerID, input.Status)
-		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				logger.Warn(ctx, "admin ops usecase update locker not found", map[string]interface{}{
-					"lockerId": input.LockerID.String(),
-					"status":   input.Status,
-				}, "")
-			} else {
-				logger.Error(ctx, "admin ops usecase update locker failed unexpectedly", map[string]interface{}{
-					"lockerId": input.LockerID.String(),
-					"status":   input.Status,
-					"error":    err.Error(),
-				}, "")
-			}
-			return err
-		}
-		result = updated
-		return nil
-	})
+	if filter.Limit <= 0 || filter.Limit > 200 {
+		filter.Limit = 50
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	items, total, err := uc.parcelRepo.Search(ctx, filter)
 	if err != nil {
-		return nil, err
+		logger.Error(ctx, "admin ops usecase search parcels failed unexpectedly", map[string]interface{}{
+			"error": err.Error(),
+		}, "")
+		return nil, 0, err
 	}
-	logger.Info(ctx, "admin ops usecase locker status updated", map[string]interface{}{
-		"lockerId": result.ID,
-		"status":   result.Status,
+	logger.Info(ctx, "admin ops usecase search parcels completed", map[string]interface{}{
+		"count": len(items),
+		"total": total,
 	}, "")
-	return result, nil
+	return items, total, nil
 }
 
 // CreateCompartments creates compartments for a locker.
@@ -352,7 +362,12 @@ func (uc *UseCase) CreateCompartments(ctx context.Context, input CreateCompartme
 			return err
 		}
 		createdCount = count
-		return nil
+		return repos.audit.Record(ctx, audit.Change{
+			Action:     "compartments.create",
+			EntityType: audit.EntityLocker,
+			EntityID:   input.LockerID.String(),
+			After:      compartmentsSnapshot(comps),
+		})
 	})
 	if err != nil {
 		return 0, err
@@ -0,0 +1,39 @@
+package audit
+
+import (
+	"context"
+
+	"smart-parcel-locker/backend/domain/audit"
+	"smart-parcel-locker/backend/pkg/logger"
+)
+
+// UseCase queries the audit log.
+type UseCase struct {
+	repo audit.Repository
+}
+
+func NewUseCase(repo audit.Repository) *UseCase {
+	return &UseCase{repo: repo}
+}
+
+// List returns entries newest first.
+func (uc *UseCase) List(ctx context.Context, filter audit.Filter) ([]*audit.Entry, int64, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, 0, audit.ErrInvalidFilter
+	}
+	if filter.Limit <= 0 || filter.Limit > 200 {
+		filter.Limit = 50
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	items, total, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		logger.Error(ctx, "audit usecase list failed unexpectedly", map[string]interface{}{
+			"entityType": filter.EntityType,
+			"error":      err.Error(),
+		}, "")
+		return nil, 0, err
+	}
+	return items, total, nil
+}
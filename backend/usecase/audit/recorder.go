@@ -0,0 +1,100 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/audit"
+)
+
+type txRepository interface {
+	WithDB(db *gorm.DB) audit.Repository
+}
+
+// Recorder writes audit entries. Callers use WithDB so an entry commits or rolls back with the
+// change it describes.
+type Recorder struct {
+	repo audit.Repository
+	now  func() time.Time
+}
+
+func NewRecorder(repo audit.Repository) *Recorder {
+	return &Recorder{repo: repo, now: time.Now}
+}
+
+// WithDB returns a recorder bound to the caller's transaction.
+func (r *Recorder) WithDB(db *gorm.DB) audit.Recorder {
+	if tx, ok := r.repo.(txRepository); ok && db != nil {
+		return &Recorder{repo: tx.WithDB(db), now: r.now}
+	}
+	return r
+}
+
+// Bind returns recorder bound to tx when it supports transactions, so an entry commits or rolls
+// back with the change it describes. A nil recorder becomes one that records nothing, for use
+// cases built without an audit log.
+func Bind(recorder audit.Recorder, tx *gorm.DB) audit.Recorder {
+	if recorder == nil {
+		return nopRecorder{}
+	}
+	if r, ok := recorder.(txRecorder); ok && tx != nil {
+		return r.WithDB(tx)
+	}
+	return recorder
+}
+
+type txRecorder interface {
+	WithDB(db *gorm.DB) audit.Recorder
+}
+
+type nopRecorder struct{}
+
+func (nopRecorder) Record(context.Context, audit.Change) error { return nil }
+
+// Record stores the change with the actor, IP and request ID attached to ctx by the admin
+// middleware. Changes made without an actor, such as bootstrap, are stored without one.
+func (r *Recorder) Record(ctx context.Context, change audit.Change) error {
+	before, err := snapshot(change.Before)
+	if err != nil {
+		return err
+	}
+	after, err := snapshot(change.After)
+	if err != nil {
+		return err
+	}
+	entry := &audit.Entry{
+		ID:         uuid.New(),
+		Action:     change.Action,
+		EntityType: change.EntityType,
+		EntityID:   change.EntityID,
+		Before:     before,
+		After:      after,
+		CreatedAt:  r.now(),
+	}
+	if actor, ok := audit.ActorFrom(ctx); ok {
+		if actor.AdminID != uuid.Nil {
+			id := actor.AdminID
+			entry.ActorID = &id
+		}
+		entry.ActorUsername = actor.Username
+		entry.IP = actor.IP
+		entry.RequestID = actor.RequestID
+	}
+	return r.repo.Create(ctx, entry)
+}
+
+// snapshot encodes value; nil values, including typed nils, are stored as SQL NULL.
+func snapshot(value interface{}) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(value)
+	if err != nil || string(raw) == "null" {
+		return nil, err
+	}
+	return raw, nil
+}
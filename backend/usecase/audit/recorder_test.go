@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"smart-parcel-locker/backend/domain/audit"
+)
+
+type fakeRepo struct {
+	entries []*audit.Entry
+}
+
+func (r *fakeRepo) Create(ctx context.Context, e *audit.Entry) error {
+	r.entries = append(r.entries, e)
+	return nil
+}
+
+func (r *fakeRepo) List(ctx context.Context, filter audit.Filter) ([]*audit.Entry, int64, error) {
+	return r.entries, int64(len(r.entries)), nil
+}
+
+func TestRecordAttachesActor(t *testing.T) {
+	repo := &fakeRepo{}
+	recorder := NewRecorder(repo)
+	adminID := uuid.New()
+	ctx := audit.WithActor(context.Background(), audit.Actor{
+		AdminID:   adminID,
+		Username:  "ops",
+		IP:        "10.0.0.7",
+		RequestID: "req-1",
+	})
+
+	var missing map[string]interface{}
+	err := recorder.Record(ctx, audit.Change{
+		Action:     "locker.status_update",
+		EntityType: audit.EntityLocker,
+		EntityID:   "L-1",
+		Before:     missing,
+		After:      map[string]interface{}{"status": "INACTIVE"},
+	})
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if len(repo.entries) != 1 {
+		t.Fatalf("expected one entry; got %d", len(repo.entries))
+	}
+	e := repo.entries[0]
+	if e.ActorID == nil || *e.ActorID != adminID || e.ActorUsername != "ops" || e.IP != "10.0.0.7" || e.RequestID != "req-1" {
+		t.Fatalf("actor not recorded: %+v", e)
+	}
+	if e.Before != nil {
+		t.Fatalf("nil snapshot stored as %s", e.Before)
+	}
+	if string(e.After) != `{"status":"INACTIVE"}` {
+		t.Fatalf("unexpected after snapshot %s", e.After)
+	}
+}
+
+func TestRecordWithoutActor(t *testing.T) {
+	repo := &fakeRepo{}
+	if err := NewRecorder(repo).Record(context.Background(), audit.Change{Action: "admin.create", EntityType: audit.EntityAdmin, EntityID: "x"}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if e := repo.entries[0]; e.ActorID != nil || e.ActorUsername != "" {
+		t.Fatalf("unexpected actor %+v", e)
+	}
+}
+
+func TestListRejectsInvertedRange(t *testing.T) {
+	uc := NewUseCase(&fakeRepo{})
+	from := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+	to := from.Add(-time.Hour)
+	if _, _, err := uc.List(context.Background(), audit.Filter{From: &from, To: &to}); err != audit.ErrInvalidFilter {
+		t.Fatalf("expected ErrInvalidFilter; got %v", err)
+	}
+}
+
+func TestBindWithoutRecorderRecordsNothing(t *testing.T) {
+	if err := Bind(nil, nil).Record(context.Background(), audit.Change{Action: "locker.update"}); err != nil {
+		t.Fatalf("expected a missing recorder to record nothing; got %v", err)
+	}
+	recorder := NewRecorder(&fakeRepo{})
+	if Bind(recorder, nil) != audit.Recorder(recorder) {
+		t.Fatal("expected a recorder without a transaction to be returned as is")
+	}
+}
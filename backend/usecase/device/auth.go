@@ -0,0 +1,90 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"smart-parcel-locker/backend/domain/device"
+	"smart-parcel-locker/backend/pkg/logger"
+)
+
+// SignedRequest is what a device sent: the signature headers and the request they cover.
+type SignedRequest struct {
+	DeviceID  string
+	Timestamp string
+	Nonce     string
+	Signature string
+	Method    string
+	Path      string
+	Body      []byte
+}
+
+// Authenticate verifies a signed device request and returns the device. The timestamp must lie
+// within Config.MaxSkew of the server clock and the nonce must not have been used by the device
+// before.
+func (uc *UseCase) Authenticate(ctx context.Context, req SignedRequest) (*device.Device, error) {
+	id, err := uuid.Parse(req.DeviceID)
+	if err != nil {
+		return nil, device.ErrUnauthorized
+	}
+	timestamp, err := strconv.ParseInt(req.Timestamp, 10, 64)
+	if err != nil {
+		return nil, device.ErrUnauthorized
+	}
+	if len(req.Nonce) < device.MinNonceLength || len(req.Nonce) > device.MaxNonceLength {
+		return nil, device.ErrUnauthorized
+	}
+	now := uc.now()
+	signedAt := time.Unix(timestamp, 0)
+	if signedAt.Before(now.Add(-uc.cfg.MaxSkew)) || signedAt.After(now.Add(uc.cfg.MaxSkew)) {
+		logger.Warn(ctx, "device usecase request expired", map[string]interface{}{
+			"deviceId":  id.String(),
+			"timestamp": timestamp,
+		}, "")
+		return nil, device.ErrRequestExpired
+	}
+	d, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, device.ErrDeviceNotFound) {
+			logger.Warn(ctx, "device usecase unknown device", map[string]interface{}{
+				"deviceId": id.String(),
+			}, "")
+			return nil, device.ErrUnauthorized
+		}
+		return nil, err
+	}
+	if !d.Active() {
+		logger.Warn(ctx, "device usecase revoked device", map[string]interface{}{
+			"deviceId": d.ID.String(),
+		}, "")
+		return nil, device.ErrUnauthorized
+	}
+	if !device.Verify(d.Secret, timestamp, req.Nonce, req.Method, req.Path, req.Body, req.Signature) {
+		logger.Warn(ctx, "device usecase signature mismatch", map[string]interface{}{
+			"deviceId": d.ID.String(),
+		}, "")
+		return nil, device.ErrUnauthorized
+	}
+	// Only nonces of correctly signed requests are stored, so forged requests cannot burn them.
+	fresh, err := uc.repo.UseNonce(ctx, d.ID, req.Nonce, now.Add(2*uc.cfg.MaxSkew))
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		logger.Warn(ctx, "device usecase nonce reused", map[string]interface{}{
+			"deviceId": d.ID.String(),
+		}, "")
+		return nil, device.ErrNonceReused
+	}
+	if err := uc.repo.TouchLastSeen(ctx, d.ID, now); err != nil {
+		logger.Warn(ctx, "device usecase touch last seen failed", map[string]interface{}{
+			"deviceId": d.ID.String(),
+			"error":    err.Error(),
+		}, "")
+	}
+	return d, nil
+}
@@ -0,0 +1,237 @@
+package device
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/audit"
+	"smart-parcel-locker/backend/domain/device"
+	"smart-parcel-locker/backend/domain/locker"
+	"smart-parcel-locker/backend/infrastructure/database"
+	"smart-parcel-locker/backend/pkg/logger"
+	auditusecase "smart-parcel-locker/backend/usecase/audit"
+)
+
+type txRepository interface {
+	WithDB(db *gorm.DB) device.Repository
+}
+
+// Config controls request signature checks.
+type Config struct {
+	// MaxSkew is how far a request timestamp may lie from the server clock. Nonces are kept for
+	// twice as long, so a request cannot be replayed while its timestamp is still accepted.
+	MaxSkew time.Duration
+}
+
+// UseCase provisions kiosk devices and authenticates their signed requests.
+type UseCase struct {
+	repo       device.Repository
+	lockerRepo locker.Repository
+	audit      audit.Recorder
+	tx         *database.TransactionManager
+	cfg        Config
+	now        func() time.Time
+}
+
+// NewUseCase builds the use case. recorder may be nil, in which case changes are not audited.
+func NewUseCase(repo device.Repository, lockerRepo locker.Repository, recorder audit.Recorder, tx *database.TransactionManager, cfg Config) *UseCase {
+	if tx == nil {
+		tx = database.NewTransactionManager(nil)
+	}
+	if cfg.MaxSkew <= 0 {
+		cfg.MaxSkew = 5 * time.Minute
+	}
+	return &UseCase{repo: repo, lockerRepo: lockerRepo, audit: recorder, tx: tx, cfg: cfg, now: time.Now}
+}
+
+// ProvisionInput registers a kiosk for a locker.
+type ProvisionInput struct {
+	LockerID uuid.UUID
+	Name     string
+}
+
+// Provision registers a device and returns it with its signing secret. The secret is not shown
+// again except after rotation.
+func (uc *UseCase) Provision(ctx context.Context, input ProvisionInput) (*device.Device, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || len(name) > 100 {
+		return nil, device.ErrInvalidDevice
+	}
+	if _, err := uc.lockerRepo.GetByID(ctx, input.LockerID); err != nil {
+		return nil, err
+	}
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+	var created *device.Device
+	err = uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo, recorder := uc.reposFor(tx)
+		var err error
+		created, err = repo.Create(ctx, &device.Device{
+			ID:        uuid.New(),
+			LockerID:  input.LockerID,
+			Name:      name,
+			Secret:    secret,
+			CreatedAt: uc.now(),
+		})
+		if err != nil {
+			return err
+		}
+		return recorder.Record(ctx, audit.Change{
+			Action:     "device.provision",
+			EntityType: audit.EntityDevice,
+			EntityID:   created.ID.String(),
+			After:      deviceSnapshot(created),
+		})
+	})
+	if err != nil {
+		logger.Error(ctx, "device usecase provision failed unexpectedly", map[string]interface{}{
+			"lockerId": input.LockerID.String(),
+			"error":    err.Error(),
+		}, "")
+		return nil, err
+	}
+	logger.Info(ctx, "device usecase provisioned", map[string]interface{}{
+		"deviceId": created.ID.String(),
+		"lockerId": created.LockerID.String(),
+	}, "")
+	return created, nil
+}
+
+// List returns the devices of a locker.
+func (uc *UseCase) List(ctx context.Context, lockerID uuid.UUID) ([]*device.Device, error) {
+	return uc.repo.ListByLocker(ctx, lockerID)
+}
+
+// Get returns a device of the given locker.
+func (uc *UseCase) Get(ctx context.Context, lockerID, id uuid.UUID) (*device.Device, error) {
+	d, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Devices are addressed under their locker so location-scoped admins only reach their own.
+	if d.LockerID != lockerID {
+		return nil, device.ErrDeviceNotFound
+	}
+	return d, nil
+}
+
+// RotateSecret issues a new signing secret and returns the device with it. The old secret stops
+// working immediately.
+func (uc *UseCase) RotateSecret(ctx context.Context, lockerID, id uuid.UUID) (*device.Device, error) {
+	d, err := uc.Get(ctx, lockerID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !d.Active() {
+		return nil, device.ErrDeviceRevoked
+	}
+	if d.Secret, err = generateSecret(); err != nil {
+		return nil, err
+	}
+	now := uc.now()
+	err = uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo, recorder := uc.reposFor(tx)
+		if err := repo.UpdateSecret(ctx, d.ID, d.Secret, now); err != nil {
+			return err
+		}
+		return recorder.Record(ctx, audit.Change{
+			Action:     "device.rotate_secret",
+			EntityType: audit.EntityDevice,
+			EntityID:   d.ID.String(),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	d.SecretRotatedAt = &now
+	d.UpdatedAt = &now
+	logger.Info(ctx, "device usecase secret rotated", map[string]interface{}{
+		"deviceId": d.ID.String(),
+	}, "")
+	return d, nil
+}
+
+// Revoke permanently stops a device from signing requests. Revoking twice is a no-op.
+func (uc *UseCase) Revoke(ctx context.Context, lockerID, id uuid.UUID) (*device.Device, error) {
+	d, err := uc.Get(ctx, lockerID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !d.Active() {
+		return d, nil
+	}
+	before := deviceSnapshot(d)
+	now := uc.now()
+	err = uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo, recorder := uc.reposFor(tx)
+		if err := repo.Revoke(ctx, d.ID, now); err != nil {
+			return err
+		}
+		d.RevokedAt = &now
+		d.UpdatedAt = &now
+		return recorder.Record(ctx, audit.Change{
+			Action:     "device.revoke",
+			EntityType: audit.EntityDevice,
+			EntityID:   d.ID.String(),
+			Before:     before,
+			After:      deviceSnapshot(d),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.Info(ctx, "device usecase revoked", map[string]interface{}{
+		"deviceId": d.ID.String(),
+		"lockerId": d.LockerID.String(),
+	}, "")
+	return d, nil
+}
+
+// PurgeNonces removes nonces that can no longer be replayed.
+func (uc *UseCase) PurgeNonces(ctx context.Context) error {
+	removed, err := uc.repo.PurgeNonces(ctx, uc.now())
+	if err != nil {
+		return err
+	}
+	logger.Info(ctx, "device nonce cleanup completed", map[string]interface{}{
+		"removed": removed,
+	}, "")
+	return nil
+}
+
+func (uc *UseCase) reposFor(tx *gorm.DB) (device.Repository, audit.Recorder) {
+	repo, recorder := uc.repo, auditusecase.Bind(uc.audit, tx)
+	if tx == nil {
+		return repo, recorder
+	}
+	if r, ok := repo.(txRepository); ok {
+		repo = r.WithDB(tx)
+	}
+	return repo, recorder
+}
+
+// deviceSnapshot leaves out the signing secret.
+func deviceSnapshot(d *device.Device) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         d.ID,
+		"locker_id":  d.LockerID,
+		"name":       d.Name,
+		"revoked_at": d.RevokedAt,
+	}
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "dvsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,186 @@
+package device
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/device"
+	"smart-parcel-locker/backend/domain/locker"
+)
+
+type fakeRepo struct {
+	devices map[uuid.UUID]*device.Device
+	nonces  map[string]time.Time
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{devices: map[uuid.UUID]*device.Device{}, nonces: map[string]time.Time{}}
+}
+
+func (r *fakeRepo) Create(ctx context.Context, d *device.Device) (*device.Device, error) {
+	copied := *d
+	r.devices[d.ID] = &copied
+	return d, nil
+}
+
+func (r *fakeRepo) GetByID(ctx context.Context, id uuid.UUID) (*device.Device, error) {
+	d, ok := r.devices[id]
+	if !ok {
+		return nil, device.ErrDeviceNotFound
+	}
+	copied := *d
+	return &copied, nil
+}
+
+func (r *fakeRepo) ListByLocker(ctx context.Context, lockerID uuid.UUID) ([]*device.Device, error) {
+	var items []*device.Device
+	for _, d := range r.devices {
+		if d.LockerID == lockerID {
+			items = append(items, d)
+		}
+	}
+	return items, nil
+}
+
+func (r *fakeRepo) UpdateSecret(ctx context.Context, id uuid.UUID, secret string, at time.Time) error {
+	r.devices[id].Secret = secret
+	r.devices[id].SecretRotatedAt = &at
+	return nil
+}
+
+func (r *fakeRepo) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	r.devices[id].RevokedAt = &at
+	return nil
+}
+
+func (r *fakeRepo) TouchLastSeen(ctx context.Context, id uuid.UUID, at time.Time) error {
+	r.devices[id].LastSeenAt = &at
+	return nil
+}
+
+func (r *fakeRepo) UseNonce(ctx context.Context, deviceID uuid.UUID, nonce string, expiresAt time.Time) (bool, error) {
+	key := deviceID.String() + "/" + nonce
+	if _, ok := r.nonces[key]; ok {
+		return false, nil
+	}
+	r.nonces[key] = expiresAt
+	return true, nil
+}
+
+func (r *fakeRepo) PurgeNonces(ctx context.Context, before time.Time) (int64, error) {
+	var removed int64
+	for key, expiresAt := range r.nonces {
+		if expiresAt.Before(before) {
+			delete(r.nonces, key)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// fakeLockers only answers GetByID; the embedded interface panics on anything else.
+type fakeLockers struct {
+	locker.Repository
+	ids map[uuid.UUID]bool
+}
+
+func (f fakeLockers) GetByID(ctx context.Context, id uuid.UUID) (*locker.Locker, error) {
+	if !f.ids[id] {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &locker.Locker{ID: id}, nil
+}
+
+func signedRequest(d *device.Device, at time.Time, nonce string, body []byte) SignedRequest {
+	ts := at.Unix()
+	return SignedRequest{
+		DeviceID:  d.ID.String(),
+		Timestamp: strconv.FormatInt(ts, 10),
+		Nonce:     nonce,
+		Signature: device.Sign(d.Secret, ts, nonce, "POST", "/api/v1/pickup/confirm", body),
+		Method:    "POST",
+		Path:      "/api/v1/pickup/confirm",
+		Body:      body,
+	}
+}
+
+func newTestUseCase(t *testing.T) (*UseCase, *device.Device, *time.Time) {
+	t.Helper()
+	lockerID := uuid.New()
+	uc := NewUseCase(newFakeRepo(), fakeLockers{ids: map[uuid.UUID]bool{lockerID: true}}, nil, nil, Config{MaxSkew: time.Minute})
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return now }
+	d, err := uc.Provision(context.Background(), ProvisionInput{LockerID: lockerID, Name: "Kiosk A"})
+	if err != nil {
+		t.Fatalf("provision: %v", err)
+	}
+	return uc, d, &now
+}
+
+func TestAuthenticateAcceptsSignedRequestOnce(t *testing.T) {
+	ctx := context.Background()
+	uc, d, now := newTestUseCase(t)
+	body := []byte(`{"parcel_id":"42"}`)
+	req := signedRequest(d, *now, "nonce-0000000000001", body)
+
+	got, err := uc.Authenticate(ctx, req)
+	if err != nil || got.ID != d.ID {
+		t.Fatalf("authenticate: %v, %+v", err, got)
+	}
+	if _, err := uc.Authenticate(ctx, req); err != device.ErrNonceReused {
+		t.Fatalf("expected ErrNonceReused on replay; got %v", err)
+	}
+
+	tampered := signedRequest(d, *now, "nonce-0000000000002", body)
+	tampered.Body = []byte(`{"parcel_id":"43"}`)
+	if _, err := uc.Authenticate(ctx, tampered); err != device.ErrUnauthorized {
+		t.Fatalf("expected ErrUnauthorized for a changed body; got %v", err)
+	}
+	// A rejected request must not use up its nonce.
+	if _, err := uc.Authenticate(ctx, signedRequest(d, *now, "nonce-0000000000002", body)); err != nil {
+		t.Fatalf("expected the nonce of a forged request to stay usable; got %v", err)
+	}
+}
+
+func TestAuthenticateRejectsStaleTimestamp(t *testing.T) {
+	uc, d, now := newTestUseCase(t)
+	req := signedRequest(d, now.Add(-2*time.Minute), "nonce-0000000000001", nil)
+	if _, err := uc.Authenticate(context.Background(), req); err != device.ErrRequestExpired {
+		t.Fatalf("expected ErrRequestExpired; got %v", err)
+	}
+}
+
+func TestRotateAndRevokeInvalidateOldSecret(t *testing.T) {
+	ctx := context.Background()
+	uc, d, now := newTestUseCase(t)
+	old := *d
+
+	rotated, err := uc.RotateSecret(ctx, d.LockerID, d.ID)
+	if err != nil || rotated.Secret == old.Secret {
+		t.Fatalf("rotate: %v", err)
+	}
+	if _, err := uc.Authenticate(ctx, signedRequest(&old, *now, "nonce-0000000000001", nil)); err != device.ErrUnauthorized {
+		t.Fatalf("expected the old secret to stop working; got %v", err)
+	}
+	if _, err := uc.Authenticate(ctx, signedRequest(rotated, *now, "nonce-0000000000002", nil)); err != nil {
+		t.Fatalf("authenticate with rotated secret: %v", err)
+	}
+
+	if _, err := uc.Revoke(ctx, uuid.New(), d.ID); err != device.ErrDeviceNotFound {
+		t.Fatalf("expected ErrDeviceNotFound under another locker; got %v", err)
+	}
+	if _, err := uc.Revoke(ctx, d.LockerID, d.ID); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if _, err := uc.Authenticate(ctx, signedRequest(rotated, *now, "nonce-0000000000003", nil)); err != device.ErrUnauthorized {
+		t.Fatalf("expected a revoked device to be rejected; got %v", err)
+	}
+	if _, err := uc.RotateSecret(ctx, d.LockerID, d.ID); err != device.ErrDeviceRevoked {
+		t.Fatalf("expected ErrDeviceRevoked; got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package hardware
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/compartment"
+	hardwaredomain "smart-parcel-locker/backend/domain/hardware"
+	"smart-parcel-locker/backend/infrastructure/database"
+	"smart-parcel-locker/backend/pkg/logger"
+)
+
+type compartmentRepository interface {
+	compartment.Repository
+	WithDB(db *gorm.DB) compartment.Repository
+}
+
+// Reconciler takes a compartment out of service when its door opened, or may have opened, for a
+// deposit or pickup that then failed to commit. The compartment is not allocated again or handed
+// out until staff check its contents.
+type Reconciler struct {
+	repo compartmentRepository
+	tx   *database.TransactionManager
+	now  func() time.Time
+}
+
+func NewReconciler(repo compartment.Repository, tx *database.TransactionManager) *Reconciler {
+	if tx == nil {
+		tx = database.NewTransactionManager(nil)
+	}
+	return &Reconciler{
+		repo: repo.(compartmentRepository),
+		tx:   tx,
+		now:  time.Now,
+	}
+}
+
+// Hold puts the compartment behind ref out of service with a status change explaining why. It
+// runs in its own transaction, even when ctx was cancelled, and logs failures instead of
+// returning them because the caller is already failing. fields are added to the log entries.
+func (r *Reconciler) Hold(ctx context.Context, ref hardwaredomain.CompartmentRef, fields map[string]interface{}, requestURL string) {
+	// The operation may have failed because the request was cancelled; the hold must still land.
+	ctx = context.WithoutCancel(ctx)
+	err := r.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		var repo compartment.Repository = r.repo
+		if tx != nil {
+			repo = r.repo.WithDB(tx)
+		}
+		comp, err := repo.GetByIDForUpdate(ctx, ref.CompartmentID)
+		if err != nil {
+			return err
+		}
+		if comp.Status == compartment.StatusOutOfService {
+			return nil
+		}
+		from := comp.Status
+		if err := comp.TakeOutOfService(true); err != nil {
+			return err
+		}
+		if _, err := repo.Update(ctx, comp); err != nil {
+			return err
+		}
+		return repo.CreateStatusChange(ctx, &compartment.StatusChange{
+			ID:            uuid.New(),
+			CompartmentID: comp.ID,
+			FromStatus:    from,
+			ToStatus:      comp.Status,
+			Reason:        compartment.ReasonUnrecordedDoorOpen,
+			Forced:        from != compartment.StatusAvailable,
+			CreatedAt:     r.now(),
+		})
+	})
+
+	logFields := map[string]interface{}{
+		"lockerId":      ref.LockerID.String(),
+		"compartmentId": ref.CompartmentID.String(),
+		"compartmentNo": ref.CompartmentNo,
+	}
+	for k, v := range fields {
+		logFields[k] = v
+	}
+	if err != nil {
+		logFields["error"] = err.Error()
+		logger.Error(ctx, "compartment reconciliation hold failed", logFields, requestURL)
+		return
+	}
+	logger.Warn(ctx, "compartment held for reconciliation", logFields, requestURL)
+}
@@ -0,0 +1,183 @@
+package heartbeat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/device"
+	"smart-parcel-locker/backend/domain/locker"
+	"smart-parcel-locker/backend/infrastructure/database"
+	"smart-parcel-locker/backend/pkg/logger"
+)
+
+type txLockers interface {
+	WithDB(db *gorm.DB) locker.Repository
+}
+
+// Config controls when a silent locker is taken offline.
+type Config struct {
+	// OfflineAfter is how long a locker may go without a heartbeat before it is marked OFFLINE.
+	OfflineAfter time.Duration
+}
+
+// UseCase records kiosk heartbeats and moves lockers between ACTIVE and OFFLINE with them.
+type UseCase struct {
+	lockerRepo locker.Repository
+	tx         *database.TransactionManager
+	cfg        Config
+	now        func() time.Time
+}
+
+func NewUseCase(lockerRepo locker.Repository, tx *database.TransactionManager, cfg Config) *UseCase {
+	if tx == nil {
+		tx = database.NewTransactionManager(nil)
+	}
+	if cfg.OfflineAfter <= 0 {
+		cfg.OfflineAfter = 3 * time.Minute
+	}
+	return &UseCase{lockerRepo: lockerRepo, tx: tx, cfg: cfg, now: time.Now}
+}
+
+// Input is what a kiosk reports with each heartbeat.
+type Input struct {
+	FirmwareVersion  string
+	TemperatureC     *float64
+	ControllerHealth string
+}
+
+// Record stores a heartbeat of lockerID, sent by the device attached to ctx. An OFFLINE locker
+// becomes ACTIVE again; lockers an admin put in maintenance or disabled keep their status.
+func (uc *UseCase) Record(ctx context.Context, lockerID uuid.UUID, input Input) (*locker.Heartbeat, string, error) {
+	input.FirmwareVersion = strings.TrimSpace(input.FirmwareVersion)
+	input.ControllerHealth = strings.ToUpper(strings.TrimSpace(input.ControllerHealth))
+	switch input.ControllerHealth {
+	case locker.ControllerOK, locker.ControllerDegraded, locker.ControllerFault:
+	default:
+		return nil, "", locker.ErrInvalidHeartbeat
+	}
+	if len(input.FirmwareVersion) > 50 {
+		return nil, "", locker.ErrInvalidHeartbeat
+	}
+	hb := &locker.Heartbeat{
+		LockerID:         lockerID,
+		DeviceID:         device.IDFrom(ctx),
+		FirmwareVersion:  input.FirmwareVersion,
+		TemperatureC:     input.TemperatureC,
+		ControllerHealth: input.ControllerHealth,
+		ReceivedAt:       uc.now(),
+	}
+	var status string
+	err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo := uc.repoFor(tx)
+		l, err := repo.GetByID(ctx, lockerID)
+		if err != nil {
+			return err
+		}
+		status = l.Status
+		if err := repo.SaveHeartbeat(ctx, hb); err != nil {
+			return err
+		}
+		if l.Status != locker.StatusOffline {
+			return nil
+		}
+		restored, err := uc.transition(ctx, repo, lockerID, locker.StatusOffline, locker.StatusActive, "heartbeat resumed")
+		if err != nil {
+			return err
+		}
+		if restored {
+			status = locker.StatusActive
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error(ctx, "heartbeat usecase record failed unexpectedly", map[string]interface{}{
+			"lockerId": lockerID.String(),
+			"error":    err.Error(),
+		}, "")
+		return nil, "", err
+	}
+	if hb.ControllerHealth != locker.ControllerOK {
+		logger.Warn(ctx, "heartbeat usecase door controller unhealthy", map[string]interface{}{
+			"lockerId": lockerID.String(),
+			"health":   hb.ControllerHealth,
+		}, "")
+	}
+	return hb, status, nil
+}
+
+// Latest returns the latest heartbeat of a locker, or nil if it never sent one.
+func (uc *UseCase) Latest(ctx context.Context, lockerID uuid.UUID) (*locker.Heartbeat, error) {
+	return uc.lockerRepo.GetHeartbeat(ctx, lockerID)
+}
+
+// Monitor marks ACTIVE lockers OFFLINE once their latest heartbeat is older than
+// Config.OfflineAfter. It runs periodically. A locker that fails to transition is logged and
+// skipped so the others still go offline; the failures are returned together.
+func (uc *UseCase) Monitor(ctx context.Context) error {
+	since := uc.now().Add(-uc.cfg.OfflineAfter)
+	silent, err := uc.lockerRepo.ListSilentSince(ctx, since)
+	if err != nil {
+		return err
+	}
+	reason := fmt.Sprintf("no heartbeat for %s", uc.cfg.OfflineAfter)
+	var errs []error
+	for _, l := range silent {
+		err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+			_, err := uc.transition(ctx, uc.repoFor(tx), l.ID, locker.StatusActive, locker.StatusOffline, reason)
+			return err
+		})
+		if err != nil {
+			logger.Error(ctx, "heartbeat monitor offline transition failed", map[string]interface{}{
+				"lockerId": l.ID.String(),
+				"error":    err.Error(),
+			}, "")
+			errs = append(errs, fmt.Errorf("locker %s: %w", l.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// transition records a status change made by the monitor. It is skipped when an admin changed
+// the status in the meantime.
+func (uc *UseCase) transition(ctx context.Context, repo locker.Repository, lockerID uuid.UUID, from, to, reason string) (bool, error) {
+	if !locker.CanMonitorTransition(from, to) {
+		return false, nil
+	}
+	changed, err := repo.TransitionStatus(ctx, lockerID, from, to)
+	if err != nil || !changed {
+		return false, err
+	}
+	if err := repo.CreateStatusChange(ctx, &locker.StatusChange{
+		ID:         uuid.New(),
+		LockerID:   lockerID,
+		FromStatus: from,
+		ToStatus:   to,
+		Reason:     reason,
+		CreatedAt:  uc.now(),
+	}); err != nil {
+		return false, err
+	}
+	logger.Warn(ctx, "locker status changed by heartbeat monitor", map[string]interface{}{
+		"lockerId": lockerID.String(),
+		"from":     from,
+		"to":       to,
+		"reason":   reason,
+	}, "")
+	return true, nil
+}
+
+func (uc *UseCase) repoFor(tx *gorm.DB) locker.Repository {
+	if tx == nil {
+		return uc.lockerRepo
+	}
+	if r, ok := uc.lockerRepo.(txLockers); ok {
+		return r.WithDB(tx)
+	}
+	return uc.lockerRepo
+}
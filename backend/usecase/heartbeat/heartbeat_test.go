@@ -0,0 +1,150 @@
+package heartbeat
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"smart-parcel-locker/backend/domain/locker"
+)
+
+type fakeLockers struct {
+	locker.Repository
+	lockers    map[uuid.UUID]*locker.Locker
+	heartbeats map[uuid.UUID]*locker.Heartbeat
+	changes    []locker.StatusChange
+	failFor    map[uuid.UUID]error
+}
+
+func newFakeLockers(status string) (*fakeLockers, uuid.UUID) {
+	id := uuid.New()
+	return &fakeLockers{
+		lockers:    map[uuid.UUID]*locker.Locker{id: {ID: id, Status: status}},
+		heartbeats: map[uuid.UUID]*locker.Heartbeat{},
+	}, id
+}
+
+func (f *fakeLockers) GetByID(_ context.Context, id uuid.UUID) (*locker.Locker, error) {
+	cp := *f.lockers[id]
+	return &cp, nil
+}
+
+func (f *fakeLockers) TransitionStatus(_ context.Context, id uuid.UUID, from, to string) (bool, error) {
+	if err := f.failFor[id]; err != nil {
+		return false, err
+	}
+	if f.lockers[id].Status != from {
+		return false, nil
+	}
+	f.lockers[id].Status = to
+	return true, nil
+}
+
+func (f *fakeLockers) CreateStatusChange(_ context.Context, change *locker.StatusChange) error {
+	f.changes = append(f.changes, *change)
+	return nil
+}
+
+func (f *fakeLockers) SaveHeartbeat(_ context.Context, hb *locker.Heartbeat) error {
+	f.heartbeats[hb.LockerID] = hb
+	return nil
+}
+
+func (f *fakeLockers) ListSilentSince(_ context.Context, before time.Time) ([]locker.Locker, error) {
+	var out []locker.Locker
+	for id, hb := range f.heartbeats {
+		if l := f.lockers[id]; l.Status == locker.StatusActive && hb.ReceivedAt.Before(before) {
+			out = append(out, *l)
+		}
+	}
+	return out, nil
+}
+
+func TestMonitorTakesSilentLockerOffline(t *testing.T) {
+	repo, id := newFakeLockers(locker.StatusActive)
+	uc := NewUseCase(repo, nil, Config{OfflineAfter: time.Minute})
+	start := time.Now()
+	uc.now = func() time.Time { return start }
+	if _, _, err := uc.Record(context.Background(), id, Input{ControllerHealth: "ok"}); err != nil {
+		t.Fatalf("record heartbeat: %v", err)
+	}
+
+	uc.now = func() time.Time { return start.Add(30 * time.Second) }
+	if err := uc.Monitor(context.Background()); err != nil {
+		t.Fatalf("monitor: %v", err)
+	}
+	if repo.lockers[id].Status != locker.StatusActive {
+		t.Fatalf("locker went offline before OfflineAfter")
+	}
+
+	uc.now = func() time.Time { return start.Add(2 * time.Minute) }
+	if err := uc.Monitor(context.Background()); err != nil {
+		t.Fatalf("monitor: %v", err)
+	}
+	if repo.lockers[id].Status != locker.StatusOffline {
+		t.Fatalf("expected OFFLINE, got %s", repo.lockers[id].Status)
+	}
+	if len(repo.changes) != 1 || repo.changes[0].ToStatus != locker.StatusOffline {
+		t.Fatalf("expected one status change to OFFLINE, got %+v", repo.changes)
+	}
+
+	_, status, err := uc.Record(context.Background(), id, Input{ControllerHealth: locker.ControllerOK})
+	if err != nil {
+		t.Fatalf("record heartbeat: %v", err)
+	}
+	if status != locker.StatusActive || len(repo.changes) != 2 {
+		t.Fatalf("heartbeat did not restore the locker: status %s, changes %+v", status, repo.changes)
+	}
+}
+
+func TestMonitorContinuesPastFailedLocker(t *testing.T) {
+	repo, first := newFakeLockers(locker.StatusActive)
+	second := uuid.New()
+	repo.lockers[second] = &locker.Locker{ID: second, Status: locker.StatusActive}
+	start := time.Now()
+	for _, id := range []uuid.UUID{first, second} {
+		repo.heartbeats[id] = &locker.Heartbeat{LockerID: id, ReceivedAt: start}
+	}
+	down := errors.New("connection reset")
+	repo.failFor = map[uuid.UUID]error{first: down}
+	uc := NewUseCase(repo, nil, Config{OfflineAfter: time.Minute})
+	uc.now = func() time.Time { return start.Add(2 * time.Minute) }
+
+	if err := uc.Monitor(context.Background()); !errors.Is(err, down) {
+		t.Fatalf("expected the failed transition to be returned, got %v", err)
+	}
+	if repo.lockers[second].Status != locker.StatusOffline {
+		t.Fatalf("expected the other silent locker to go OFFLINE, got %s", repo.lockers[second].Status)
+	}
+	if repo.lockers[first].Status != locker.StatusActive {
+		t.Fatalf("failed locker changed status: %s", repo.lockers[first].Status)
+	}
+}
+
+func TestHeartbeatKeepsAdminStatus(t *testing.T) {
+	repo, id := newFakeLockers(locker.StatusMaintenance)
+	uc := NewUseCase(repo, nil, Config{})
+
+	_, status, err := uc.Record(context.Background(), id, Input{ControllerHealth: locker.ControllerDegraded})
+	if err != nil {
+		t.Fatalf("record heartbeat: %v", err)
+	}
+	if status != locker.StatusMaintenance || len(repo.changes) != 0 {
+		t.Fatalf("heartbeat changed a locker in maintenance")
+	}
+}
+
+func TestHeartbeatRejectsUnknownHealth(t *testing.T) {
+	repo, id := newFakeLockers(locker.StatusActive)
+	uc := NewUseCase(repo, nil, Config{})
+
+	if _, _, err := uc.Record(context.Background(), id, Input{ControllerHealth: "BROKEN"}); !errors.Is(err, locker.ErrInvalidHeartbeat) {
+		t.Fatalf("expected invalid heartbeat, got %v", err)
+	}
+	if len(repo.heartbeats) != 0 {
+		t.Fatalf("invalid heartbeat was stored")
+	}
+}
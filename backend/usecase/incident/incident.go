@@ -0,0 +1,345 @@
+package incident
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/audit"
+	"smart-parcel-locker/backend/domain/compartment"
+	"smart-parcel-locker/backend/domain/device"
+	"smart-parcel-locker/backend/domain/incident"
+	"smart-parcel-locker/backend/domain/locker"
+	"smart-parcel-locker/backend/infrastructure/database"
+	"smart-parcel-locker/backend/pkg/logger"
+	auditusecase "smart-parcel-locker/backend/usecase/audit"
+)
+
+// maxEventsPerRequest bounds one batch of door events, e.g. a kiosk catching up after an outage.
+const maxEventsPerRequest = 100
+
+type txRepository interface {
+	WithDB(db *gorm.DB) incident.Repository
+}
+
+type txCompartments interface {
+	WithDB(db *gorm.DB) compartment.Repository
+}
+
+// Config controls when incidents are raised.
+type Config struct {
+	// DoorLeftOpenAfter is how long a door may stay open before a DOOR_LEFT_OPEN incident.
+	DoorLeftOpenAfter time.Duration
+}
+
+// UseCase records door and sensor events from kiosks and raises incidents from them.
+type UseCase struct {
+	repo       incident.Repository
+	compRepo   compartment.Repository
+	lockerRepo locker.Repository
+	audit      audit.Recorder
+	tx         *database.TransactionManager
+	cfg        Config
+	now        func() time.Time
+}
+
+// NewUseCase builds the use case. recorder may be nil, in which case resolutions are not audited.
+func NewUseCase(repo incident.Repository, compRepo compartment.Repository, lockerRepo locker.Repository, recorder audit.Recorder, tx *database.TransactionManager, cfg Config) *UseCase {
+	if tx == nil {
+		tx = database.NewTransactionManager(nil)
+	}
+	if cfg.DoorLeftOpenAfter <= 0 {
+		cfg.DoorLeftOpenAfter = 2 * time.Minute
+	}
+	return &UseCase{repo: repo, compRepo: compRepo, lockerRepo: lockerRepo, audit: recorder, tx: tx, cfg: cfg, now: time.Now}
+}
+
+// DoorEventInput is one event reported by a kiosk. OccurredAt defaults to the time it arrived.
+type DoorEventInput struct {
+	CompartmentNo int
+	Type          string
+	OccurredAt    *time.Time
+}
+
+// RecordDoorEvents stores events reported for compartments of lockerID, in order, and raises or
+// clears incidents from them: a forced door raises DOOR_FORCED, a closed door clears
+// DOOR_LEFT_OPEN and a sensor reading that disagrees with the compartment status raises
+// SENSOR_MISMATCH until a later reading agrees. The device attached to ctx is recorded on each
+// event.
+func (uc *UseCase) RecordDoorEvents(ctx context.Context, lockerID uuid.UUID, inputs []DoorEventInput) ([]compartment.Event, error) {
+	if len(inputs) == 0 || len(inputs) > maxEventsPerRequest {
+		return nil, incident.ErrInvalidDoorEvent
+	}
+	for i := range inputs {
+		inputs[i].Type = strings.ToUpper(strings.TrimSpace(inputs[i].Type))
+		if inputs[i].CompartmentNo <= 0 || !compartment.ValidEventType(inputs[i].Type) {
+			return nil, incident.ErrInvalidDoorEvent
+		}
+	}
+	comps, err := uc.compRepo.ListByLocker(ctx, lockerID)
+	if err != nil {
+		return nil, err
+	}
+	byNo := make(map[int]compartment.Compartment, len(comps))
+	for _, c := range comps {
+		byNo[c.CompartmentNo] = c
+	}
+	for _, in := range inputs {
+		if _, ok := byNo[in.CompartmentNo]; !ok {
+			return nil, compartment.ErrCompartmentNotFound
+		}
+	}
+
+	deviceID := device.IDFrom(ctx)
+	now := uc.now()
+	events := make([]compartment.Event, 0, len(inputs))
+	err = uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo, compRepo := uc.reposFor(tx)
+		for _, in := range inputs {
+			comp := byNo[in.CompartmentNo]
+			event := compartment.Event{
+				ID:            uuid.New(),
+				CompartmentID: comp.ID,
+				LockerID:      lockerID,
+				ParcelID:      comp.ParcelID,
+				DeviceID:      deviceID,
+				Type:          in.Type,
+				OccurredAt:    now,
+				CreatedAt:     now,
+			}
+			if in.OccurredAt != nil {
+				event.OccurredAt = *in.OccurredAt
+			}
+			if err := compRepo.CreateEvent(ctx, &event); err != nil {
+				return err
+			}
+			if err := uc.evaluate(ctx, repo, &comp, event); err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error(ctx, "incident usecase door events failed unexpectedly", map[string]interface{}{
+			"lockerId": lockerID.String(),
+			"error":    err.Error(),
+		}, "")
+		return nil, err
+	}
+	return events, nil
+}
+
+func (uc *UseCase) evaluate(ctx context.Context, repo incident.Repository, comp *compartment.Compartment, event compartment.Event) error {
+	switch event.Type {
+	case compartment.EventDoorForced:
+		return uc.raise(ctx, repo, comp, incident.TypeDoorForced, event.OccurredAt, "door opened without an open command")
+	case compartment.EventDoorClosed:
+		return uc.clear(ctx, repo, comp.ID, incident.TypeDoorLeftOpen, "door closed")
+	case compartment.EventSensorEmpty, compartment.EventSensorOccupied:
+		expected, ok := comp.ExpectsParcel()
+		if !ok {
+			return nil
+		}
+		if expected == (event.Type == compartment.EventSensorOccupied) {
+			return uc.clear(ctx, repo, comp.ID, incident.TypeSensorMismatch, "sensor agrees with compartment status")
+		}
+		detail := fmt.Sprintf("sensor reports %s but compartment is %s", event.Type, comp.Status)
+		return uc.raise(ctx, repo, comp, incident.TypeSensorMismatch, event.OccurredAt, detail)
+	}
+	return nil
+}
+
+func (uc *UseCase) raise(ctx context.Context, repo incident.Repository, comp *compartment.Compartment, incidentType string, at time.Time, detail string) error {
+	opened, err := repo.Open(ctx, &incident.Incident{
+		ID:            uuid.New(),
+		Type:          incidentType,
+		Status:        incident.StatusOpen,
+		LockerID:      comp.LockerID,
+		CompartmentID: comp.ID,
+		ParcelID:      comp.ParcelID,
+		Detail:        detail,
+		OpenedAt:      at,
+	})
+	if err != nil {
+		return err
+	}
+	if opened {
+		logger.Warn(ctx, "incident raised", map[string]interface{}{
+			"type":          incidentType,
+			"lockerId":      comp.LockerID.String(),
+			"compartmentId": comp.ID.String(),
+			"compartmentNo": comp.CompartmentNo,
+			"detail":        detail,
+		}, "")
+	}
+	return nil
+}
+
+func (uc *UseCase) clear(ctx context.Context, repo incident.Repository, compartmentID uuid.UUID, incidentType, resolution string) error {
+	cleared, err := repo.ResolveOpen(ctx, compartmentID, incidentType, uc.now(), resolution)
+	if err != nil {
+		return err
+	}
+	if cleared > 0 {
+		logger.Info(ctx, "incident cleared", map[string]interface{}{
+			"type":          incidentType,
+			"compartmentId": compartmentID.String(),
+			"resolution":    resolution,
+		}, "")
+	}
+	return nil
+}
+
+// CheckDoors raises DOOR_LEFT_OPEN for every door that has been open longer than
+// Config.DoorLeftOpenAfter. It runs periodically.
+func (uc *UseCase) CheckDoors(ctx context.Context) error {
+	now := uc.now()
+	events, err := uc.compRepo.ListDoorsOpenSince(ctx, now.Add(-uc.cfg.DoorLeftOpenAfter))
+	if err != nil {
+		return err
+	}
+	for _, e := range events {
+		comp, err := uc.compRepo.GetByID(ctx, e.CompartmentID)
+		if err != nil {
+			return err
+		}
+		detail := fmt.Sprintf("door open since %s", e.OccurredAt.UTC().Format(time.RFC3339))
+		if err := uc.raise(ctx, uc.repo, comp, incident.TypeDoorLeftOpen, e.OccurredAt, detail); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List returns incidents newest first.
+func (uc *UseCase) List(ctx context.Context, filter incident.Filter) ([]*incident.Incident, int64, error) {
+	filter.Status = strings.ToUpper(strings.TrimSpace(filter.Status))
+	filter.Type = strings.ToUpper(strings.TrimSpace(filter.Type))
+	switch filter.Status {
+	case "", incident.StatusOpen, incident.StatusResolved:
+	default:
+		return nil, 0, incident.ErrInvalidFilter
+	}
+	switch filter.Type {
+	case "", incident.TypeDoorLeftOpen, incident.TypeDoorForced, incident.TypeSensorMismatch:
+	default:
+		return nil, 0, incident.ErrInvalidFilter
+	}
+	if filter.Limit <= 0 || filter.Limit > 200 {
+		filter.Limit = 50
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return uc.repo.List(ctx, filter)
+}
+
+// Get returns an incident.
+func (uc *UseCase) Get(ctx context.Context, id uuid.UUID) (*incident.Incident, error) {
+	return uc.repo.GetByID(ctx, id)
+}
+
+// Location returns the location of the locker an incident belongs to, for location scoping.
+func (uc *UseCase) Location(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	inc, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	l, err := uc.lockerRepo.GetByID(ctx, inc.LockerID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return l.LocationID, nil
+}
+
+// Resolve closes an incident on behalf of the signed-in admin with a note of what was done.
+func (uc *UseCase) Resolve(ctx context.Context, id uuid.UUID, note string) (*incident.Incident, error) {
+	inc, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if inc.Status != incident.StatusOpen {
+		return nil, incident.ErrAlreadyResolved
+	}
+	before := incidentSnapshot(inc)
+	now := uc.now()
+	var actorID *uuid.UUID
+	if actor, ok := audit.ActorFrom(ctx); ok {
+		actorID = &actor.AdminID
+	}
+	note = strings.TrimSpace(note)
+	err = uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo, _ := uc.reposFor(tx)
+		if err := repo.Resolve(ctx, inc.ID, now, actorID, note); err != nil {
+			return err
+		}
+		inc.Status = incident.StatusResolved
+		inc.ResolvedAt = &now
+		inc.ResolvedBy = actorID
+		inc.Resolution = note
+		return auditusecase.Bind(uc.audit, tx).Record(ctx, audit.Change{
+			Action:     "incident.resolve",
+			EntityType: audit.EntityIncident,
+			EntityID:   inc.ID.String(),
+			Before:     before,
+			After:      incidentSnapshot(inc),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.Info(ctx, "incident usecase resolved", map[string]interface{}{
+		"incidentId": inc.ID.String(),
+		"type":       inc.Type,
+	}, "")
+	return inc, nil
+}
+
+// CompartmentEvents returns the door and sensor events of a compartment of the locker.
+func (uc *UseCase) CompartmentEvents(ctx context.Context, lockerID, compartmentID uuid.UUID, limit int) ([]compartment.Event, error) {
+	comp, err := uc.compRepo.GetByID(ctx, compartmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, compartment.ErrCompartmentNotFound
+		}
+		return nil, err
+	}
+	if comp.LockerID != lockerID {
+		return nil, compartment.ErrCompartmentNotFound
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	return uc.compRepo.ListEvents(ctx, compartmentID, limit)
+}
+
+func (uc *UseCase) reposFor(tx *gorm.DB) (incident.Repository, compartment.Repository) {
+	repo, compRepo := uc.repo, uc.compRepo
+	if tx == nil {
+		return repo, compRepo
+	}
+	if r, ok := repo.(txRepository); ok {
+		repo = r.WithDB(tx)
+	}
+	if r, ok := compRepo.(txCompartments); ok {
+		compRepo = r.WithDB(tx)
+	}
+	return repo, compRepo
+}
+
+func incidentSnapshot(inc *incident.Incident) map[string]interface{} {
+	return map[string]interface{}{
+		"id":             inc.ID,
+		"type":           inc.Type,
+		"status":         inc.Status,
+		"compartment_id": inc.CompartmentID,
+		"resolved_by":    inc.ResolvedBy,
+		"resolution":     inc.Resolution,
+	}
+}
@@ -0,0 +1,123 @@
+package incident
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"smart-parcel-locker/backend/domain/compartment"
+	"smart-parcel-locker/backend/domain/device"
+	"smart-parcel-locker/backend/domain/incident"
+)
+
+type fakeCompartments struct {
+	compartment.Repository
+	comps  []compartment.Compartment
+	events []compartment.Event
+}
+
+func (f *fakeCompartments) ListByLocker(_ context.Context, lockerID uuid.UUID) ([]compartment.Compartment, error) {
+	var out []compartment.Compartment
+	for _, c := range f.comps {
+		if c.LockerID == lockerID {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeCompartments) CreateEvent(_ context.Context, event *compartment.Event) error {
+	f.events = append(f.events, *event)
+	return nil
+}
+
+type fakeIncidents struct {
+	incident.Repository
+	items []*incident.Incident
+}
+
+func (f *fakeIncidents) Open(_ context.Context, inc *incident.Incident) (bool, error) {
+	for _, existing := range f.items {
+		if existing.CompartmentID == inc.CompartmentID && existing.Type == inc.Type && existing.Status == incident.StatusOpen {
+			return false, nil
+		}
+	}
+	cp := *inc
+	f.items = append(f.items, &cp)
+	return true, nil
+}
+
+func (f *fakeIncidents) ResolveOpen(_ context.Context, compartmentID uuid.UUID, incidentType string, at time.Time, resolution string) (int64, error) {
+	var n int64
+	for _, inc := range f.items {
+		if inc.CompartmentID == compartmentID && inc.Type == incidentType && inc.Status == incident.StatusOpen {
+			inc.Status = incident.StatusResolved
+			inc.ResolvedAt = &at
+			inc.Resolution = resolution
+			n++
+		}
+	}
+	return n, nil
+}
+
+func newIncidentUseCase(status string) (*UseCase, *fakeIncidents, *fakeCompartments, uuid.UUID) {
+	lockerID := uuid.New()
+	comps := &fakeCompartments{comps: []compartment.Compartment{
+		{ID: uuid.New(), LockerID: lockerID, CompartmentNo: 1, Status: status},
+	}}
+	incidents := &fakeIncidents{}
+	return NewUseCase(incidents, comps, nil, nil, nil, Config{}), incidents, comps, lockerID
+}
+
+func TestSensorMismatchRaisedAndCleared(t *testing.T) {
+	uc, incidents, _, lockerID := newIncidentUseCase(compartment.StatusOccupied)
+	ctx := context.Background()
+
+	if _, err := uc.RecordDoorEvents(ctx, lockerID, []DoorEventInput{{CompartmentNo: 1, Type: "sensor_empty"}}); err != nil {
+		t.Fatalf("record events: %v", err)
+	}
+	if len(incidents.items) != 1 || incidents.items[0].Type != incident.TypeSensorMismatch {
+		t.Fatalf("expected a sensor mismatch incident, got %+v", incidents.items)
+	}
+	if _, err := uc.RecordDoorEvents(ctx, lockerID, []DoorEventInput{{CompartmentNo: 1, Type: compartment.EventSensorOccupied}}); err != nil {
+		t.Fatalf("record events: %v", err)
+	}
+	if incidents.items[0].Status != incident.StatusResolved {
+		t.Fatalf("agreeing sensor did not clear the incident")
+	}
+}
+
+func TestForcedDoorRaisesIncidentOnce(t *testing.T) {
+	uc, incidents, comps, lockerID := newIncidentUseCase(compartment.StatusAvailable)
+	d := &device.Device{ID: uuid.New(), LockerID: lockerID}
+	ctx := device.WithDevice(context.Background(), d)
+
+	forced := DoorEventInput{CompartmentNo: 1, Type: compartment.EventDoorForced}
+	if _, err := uc.RecordDoorEvents(ctx, lockerID, []DoorEventInput{forced, forced}); err != nil {
+		t.Fatalf("record events: %v", err)
+	}
+	if len(comps.events) != 2 || comps.events[0].DeviceID == nil || *comps.events[0].DeviceID != d.ID {
+		t.Fatalf("expected both events stored with the device, got %+v", comps.events)
+	}
+	if len(incidents.items) != 1 || incidents.items[0].Type != incident.TypeDoorForced {
+		t.Fatalf("expected one forced door incident, got %+v", incidents.items)
+	}
+}
+
+func TestDoorEventsRejectUnknownCompartment(t *testing.T) {
+	uc, _, comps, lockerID := newIncidentUseCase(compartment.StatusAvailable)
+
+	_, err := uc.RecordDoorEvents(context.Background(), lockerID, []DoorEventInput{
+		{CompartmentNo: 1, Type: compartment.EventDoorOpened},
+		{CompartmentNo: 9, Type: compartment.EventDoorClosed},
+	})
+	if !errors.Is(err, compartment.ErrCompartmentNotFound) {
+		t.Fatalf("expected compartment not found, got %v", err)
+	}
+	if len(comps.events) != 0 {
+		t.Fatalf("rejected batch stored events")
+	}
+}
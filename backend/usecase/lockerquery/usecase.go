@@ -55,7 +55,7 @@ func (uc *UseCase) ListAvailable(ctx context.Context) ([]AvailableLocker, error)
 
 	result := make([]AvailableLocker, 0, len(lockers))
 	for _, l := range lockers {
-		if l.Status != locker.StatusActive {
+		if l.CheckDeposit() != nil {
 			continue
 		}
 		result = append(result, AvailableLocker{
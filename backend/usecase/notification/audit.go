@@ -0,0 +1,101 @@
+package notification
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/audit"
+	notificationdomain "smart-parcel-locker/backend/domain/notification"
+	auditusecase "smart-parcel-locker/backend/usecase/audit"
+)
+
+type txPreferences interface {
+	WithDB(db *gorm.DB) notificationdomain.PreferenceRepository
+}
+
+type txTemplates interface {
+	WithDB(db *gorm.DB) notificationdomain.TemplateRepository
+}
+
+type txOutbox interface {
+	WithDB(db *gorm.DB) notificationdomain.OutboxRepository
+}
+
+// txScope holds the repositories and audit recorder bound to one transaction, so an admin
+// change and its audit entry commit together.
+type txScope struct {
+	prefs     notificationdomain.PreferenceRepository
+	templates notificationdomain.TemplateRepository
+	outbox    notificationdomain.OutboxRepository
+	audit     audit.Recorder
+}
+
+func (uc *UseCase) scopeFor(tx *gorm.DB) txScope {
+	scope := txScope{prefs: uc.prefs, templates: uc.templates, audit: auditusecase.Bind(uc.audit, tx)}
+	if tx == nil {
+		return scope
+	}
+	if r, ok := scope.prefs.(txPreferences); ok {
+		scope.prefs = r.WithDB(tx)
+	}
+	if r, ok := scope.templates.(txTemplates); ok {
+		scope.templates = r.WithDB(tx)
+	}
+	return scope
+}
+
+func (d *Dispatcher) scopeFor(tx *gorm.DB) txScope {
+	scope := txScope{outbox: d.outbox, audit: auditusecase.Bind(d.audit, tx)}
+	if tx == nil {
+		return scope
+	}
+	if r, ok := scope.outbox.(txOutbox); ok {
+		scope.outbox = r.WithDB(tx)
+	}
+	return scope
+}
+
+// storedTemplate returns the audit snapshot of an admin-edited template, or nil when the
+// built-in default applies.
+func storedTemplate(ctx context.Context, repo notificationdomain.TemplateRepository, messageType string, locale notificationdomain.Locale) (map[string]interface{}, error) {
+	tmpl, err := repo.Get(ctx, messageType, locale)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, notificationdomain.ErrTemplateNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return templateSnapshot(tmpl), nil
+}
+
+func templateSnapshot(t *notificationdomain.Template) map[string]interface{} {
+	return map[string]interface{}{
+		"type":    t.Type,
+		"locale":  t.Locale,
+		"subject": t.Subject,
+		"body":    t.Body,
+	}
+}
+
+func preferenceSnapshot(p *notificationdomain.Preference) map[string]interface{} {
+	return map[string]interface{}{
+		"phone":   p.Phone,
+		"channel": p.Channel,
+		"address": p.Address,
+		"locale":  p.Locale,
+	}
+}
+
+func outboxSnapshot(m *notificationdomain.OutboxMessage) map[string]interface{} {
+	return map[string]interface{}{
+		"id":              m.ID,
+		"type":            m.Type,
+		"phone":           m.Phone,
+		"status":          m.Status,
+		"attempts":        m.Attempts,
+		"next_attempt_at": m.NextAttemptAt,
+		"last_error":      m.LastError,
+	}
+}
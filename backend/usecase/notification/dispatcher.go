@@ -0,0 +1,228 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/audit"
+	notificationdomain "smart-parcel-locker/backend/domain/notification"
+	"smart-parcel-locker/backend/infrastructure/database"
+	"smart-parcel-locker/backend/pkg/backoff"
+	"smart-parcel-locker/backend/pkg/logger"
+	phonepkg "smart-parcel-locker/backend/pkg/phone"
+)
+
+// DispatcherConfig controls outbox delivery retries.
+type DispatcherConfig struct {
+	BatchSize   int
+	MaxAttempts int
+	// BaseBackoff is the delay after the first failure; it doubles per attempt up to MaxBackoff.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// Lease is how long a claimed message is hidden from other dispatchers while being sent.
+	Lease time.Duration
+	// Phones normalises the phone filter of outbox listings to E.164.
+	Phones phonepkg.Normalizer
+}
+
+// Dispatcher delivers outbox messages through the notification use case.
+type Dispatcher struct {
+	outbox notificationdomain.OutboxRepository
+	sender *UseCase
+	audit  audit.Recorder
+	tx     *database.TransactionManager
+	cfg    DispatcherConfig
+	now    func() time.Time
+}
+
+// NewDispatcher constructs the outbox dispatcher.
+func NewDispatcher(outbox notificationdomain.OutboxRepository, sender *UseCase, recorder audit.Recorder, tx *database.TransactionManager, cfg DispatcherConfig) *Dispatcher {
+	if tx == nil {
+		tx = database.NewTransactionManager(nil)
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 50
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 8
+	}
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = 30 * time.Second
+	}
+	if cfg.MaxBackoff < cfg.BaseBackoff {
+		cfg.MaxBackoff = cfg.BaseBackoff
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = time.Minute
+	}
+	return &Dispatcher{
+		outbox: outbox,
+		sender: sender,
+		audit:  recorder,
+		tx:     tx,
+		cfg:    cfg,
+		now:    time.Now,
+	}
+}
+
+// Run delivers one batch of due messages.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	now := d.now()
+	batch, err := d.outbox.ClaimDue(ctx, now, now.Add(d.cfg.Lease), d.cfg.BatchSize)
+	if err != nil {
+		logger.Error(ctx, "notification dispatcher claim failed unexpectedly", map[string]interface{}{
+			"error": err.Error(),
+		}, "")
+		return err
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+
+	var sent, retried, dead int
+	for _, msg := range batch {
+		switch d.deliver(ctx, msg) {
+		case notificationdomain.OutboxSent:
+			sent++
+		case notificationdomain.OutboxDead:
+			dead++
+		default:
+			retried++
+		}
+	}
+	logger.Info(ctx, "notification dispatcher run completed", map[string]interface{}{
+		"claimed": len(batch),
+		"sent":    sent,
+		"retried": retried,
+		"dead":    dead,
+	}, "")
+	return nil
+}
+
+// deliver sends one message and records the outcome, returning the resulting status.
+func (d *Dispatcher) deliver(ctx context.Context, msg *notificationdomain.OutboxMessage) notificationdomain.OutboxStatus {
+	now := d.now()
+	if msg.ExpiresAt != nil && !now.Before(*msg.ExpiresAt) {
+		d.markDead(ctx, msg, msg.Attempts, "expired before delivery")
+		return notificationdomain.OutboxDead
+	}
+
+	channel, err := d.sender.Deliver(ctx, msg.Message())
+	if err == nil {
+		if err := d.outbox.MarkSent(ctx, msg.ID, channel, d.now(), msg.Sensitive()); err != nil {
+			logger.Error(ctx, "notification dispatcher mark sent failed unexpectedly", map[string]interface{}{
+				"outboxId": msg.ID.String(),
+				"error":    err.Error(),
+			}, "")
+		}
+		return notificationdomain.OutboxSent
+	}
+
+	attempts := msg.Attempts + 1
+	if attempts >= d.cfg.MaxAttempts {
+		d.markDead(ctx, msg, attempts, err.Error())
+		return notificationdomain.OutboxDead
+	}
+	next := now.Add(backoff.Exponential(attempts, d.cfg.BaseBackoff, d.cfg.MaxBackoff))
+	if markErr := d.outbox.MarkFailed(ctx, msg.ID, attempts, next, err.Error()); markErr != nil {
+		logger.Error(ctx, "notification dispatcher mark failed failed unexpectedly", map[string]interface{}{
+			"outboxId": msg.ID.String(),
+			"error":    markErr.Error(),
+		}, "")
+	}
+	logger.Warn(ctx, "notification dispatcher delivery failed", map[string]interface{}{
+		"outboxId":      msg.ID.String(),
+		"messageType":   msg.Type,
+		"attempts":      attempts,
+		"nextAttemptAt": next,
+		"error":         err.Error(),
+	}, "")
+	return notificationdomain.OutboxPending
+}
+
+func (d *Dispatcher) markDead(ctx context.Context, msg *notificationdomain.OutboxMessage, attempts int, reason string) {
+	if err := d.outbox.MarkDead(ctx, msg.ID, attempts, reason, msg.Sensitive()); err != nil {
+		logger.Error(ctx, "notification dispatcher mark dead failed unexpectedly", map[string]interface{}{
+			"outboxId": msg.ID.String(),
+			"error":    err.Error(),
+		}, "")
+		return
+	}
+	logger.Warn(ctx, "notification dispatcher message dead-lettered", map[string]interface{}{
+		"outboxId":    msg.ID.String(),
+		"messageType": msg.Type,
+		"attempts":    attempts,
+		"reason":      reason,
+	}, "")
+}
+
+// ListOutbox returns outbox messages for operators.
+func (d *Dispatcher) ListOutbox(ctx context.Context, filter notificationdomain.OutboxFilter) ([]*notificationdomain.OutboxMessage, int64, error) {
+	switch filter.Status {
+	case "", notificationdomain.OutboxPending, notificationdomain.OutboxSent, notificationdomain.OutboxDead:
+	default:
+		return nil, 0, notificationdomain.ErrInvalidRequest
+	}
+	if filter.Phone != "" {
+		normalized, err := d.cfg.Phones.Normalize(filter.Phone)
+		if err != nil {
+			return nil, 0, notificationdomain.ErrInvalidRequest
+		}
+		filter.Phone = normalized
+	}
+	if filter.Limit <= 0 || filter.Limit > 200 {
+		filter.Limit = 50
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return d.outbox.List(ctx, filter)
+}
+
+// GetOutbox returns one outbox message.
+func (d *Dispatcher) GetOutbox(ctx context.Context, id uuid.UUID) (*notificationdomain.OutboxMessage, error) {
+	return d.outbox.GetByID(ctx, id)
+}
+
+// Redrive queues a dead-lettered message for another round of attempts. Messages carrying a
+// one-time password are never re-driven: their secret is gone and a late code would be stale.
+func (d *Dispatcher) Redrive(ctx context.Context, id uuid.UUID) (*notificationdomain.OutboxMessage, error) {
+	var msg *notificationdomain.OutboxMessage
+	err := d.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		scope := d.scopeFor(tx)
+		before, err := scope.outbox.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if before.Sensitive() {
+			return notificationdomain.ErrNotRedrivable
+		}
+		if msg, err = scope.outbox.Redrive(ctx, id, d.now()); err != nil {
+			return err
+		}
+		return scope.audit.Record(ctx, audit.Change{
+			Action:     "outbox.redrive",
+			EntityType: audit.EntityOutboxMessage,
+			EntityID:   id.String(),
+			Before:     outboxSnapshot(before),
+			After:      outboxSnapshot(msg),
+		})
+	})
+	if err != nil {
+		if !errors.Is(err, notificationdomain.ErrOutboxNotFound) && !errors.Is(err, notificationdomain.ErrNotRedrivable) {
+			logger.Error(ctx, "notification dispatcher redrive failed unexpectedly", map[string]interface{}{
+				"outboxId": id.String(),
+				"error":    err.Error(),
+			}, "")
+		}
+		return nil, err
+	}
+	logger.Info(ctx, "notification dispatcher message re-driven", map[string]interface{}{
+		"outboxId": id.String(),
+	}, "")
+	return msg, nil
+}
@@ -0,0 +1,165 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	notificationdomain "smart-parcel-locker/backend/domain/notification"
+)
+
+type fakeOutbox struct {
+	due      []*notificationdomain.OutboxMessage
+	sent     map[uuid.UUID]bool
+	redacted map[uuid.UUID]bool
+	failed   map[uuid.UUID]time.Time
+	dead     map[uuid.UUID]string
+	stored   map[uuid.UUID]*notificationdomain.OutboxMessage
+}
+
+func newFakeOutbox(msgs ...*notificationdomain.OutboxMessage) *fakeOutbox {
+	return &fakeOutbox{
+		due:      msgs,
+		sent:     map[uuid.UUID]bool{},
+		redacted: map[uuid.UUID]bool{},
+		failed:   map[uuid.UUID]time.Time{},
+		dead:     map[uuid.UUID]string{},
+		stored:   map[uuid.UUID]*notificationdomain.OutboxMessage{},
+	}
+}
+
+func (f *fakeOutbox) Enqueue(ctx context.Context, msg *notificationdomain.OutboxMessage) error {
+	f.due = append(f.due, msg)
+	return nil
+}
+
+func (f *fakeOutbox) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*notificationdomain.OutboxMessage, error) {
+	out := f.due
+	f.due = nil
+	return out, nil
+}
+
+func (f *fakeOutbox) MarkSent(ctx context.Context, id uuid.UUID, channel notificationdomain.Channel, at time.Time, redact bool) error {
+	f.sent[id] = true
+	f.redacted[id] = redact
+	return nil
+}
+
+func (f *fakeOutbox) MarkFailed(ctx context.Context, id uuid.UUID, attempts int, next time.Time, lastError string) error {
+	f.failed[id] = next
+	return nil
+}
+
+func (f *fakeOutbox) MarkDead(ctx context.Context, id uuid.UUID, attempts int, lastError string, redact bool) error {
+	f.dead[id] = lastError
+	f.redacted[id] = redact
+	return nil
+}
+
+func (f *fakeOutbox) GetByID(ctx context.Context, id uuid.UUID) (*notificationdomain.OutboxMessage, error) {
+	if msg, ok := f.stored[id]; ok {
+		return msg, nil
+	}
+	return nil, notificationdomain.ErrOutboxNotFound
+}
+
+func (f *fakeOutbox) List(ctx context.Context, filter notificationdomain.OutboxFilter) ([]*notificationdomain.OutboxMessage, int64, error) {
+	return nil, 0, nil
+}
+
+func (f *fakeOutbox) Redrive(ctx context.Context, id uuid.UUID, now time.Time) (*notificationdomain.OutboxMessage, error) {
+	msg, ok := f.stored[id]
+	if !ok || msg.Status != notificationdomain.OutboxDead {
+		return nil, notificationdomain.ErrNotRedrivable
+	}
+	msg.Status = notificationdomain.OutboxPending
+	msg.Attempts = 0
+	return msg, nil
+}
+
+func TestDispatcherOutcomes(t *testing.T) {
+	now := time.Date(2025, 1, 9, 12, 0, 0, 0, time.UTC)
+	newMsg := func(msgType string, attempts int) *notificationdomain.OutboxMessage {
+		m := notificationdomain.NewOutboxMessage(notificationdomain.Message{Type: msgType, Phone: "+66812345678", Body: "x"}, now)
+		m.Attempts = attempts
+		return m
+	}
+
+	t.Run("sent and redacted", func(t *testing.T) {
+		msg := newMsg(notificationdomain.TypeOTP, 0)
+		outbox := newFakeOutbox(msg)
+		uc, _ := NewUseCase(fakePrefs{}, nil, nil, nil, Config{DefaultChannel: notificationdomain.ChannelLog}, &fakeSender{channel: notificationdomain.ChannelLog})
+		d := NewDispatcher(outbox, uc, nil, nil, DispatcherConfig{})
+		d.now = func() time.Time { return now }
+		if err := d.Run(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if !outbox.sent[msg.ID] || !outbox.redacted[msg.ID] {
+			t.Fatalf("expected OTP message sent and redacted")
+		}
+	})
+
+	t.Run("failure backs off then dead-letters", func(t *testing.T) {
+		retry := newMsg(notificationdomain.TypeParcelDeposited, 2)
+		last := newMsg(notificationdomain.TypeParcelDeposited, 4)
+		outbox := newFakeOutbox(retry, last)
+		uc, _ := NewUseCase(fakePrefs{}, nil, nil, nil, Config{DefaultChannel: notificationdomain.ChannelLog}, &fakeSender{channel: notificationdomain.ChannelLog, err: errors.New("down")})
+		d := NewDispatcher(outbox, uc, nil, nil, DispatcherConfig{MaxAttempts: 5, BaseBackoff: time.Minute, MaxBackoff: time.Hour})
+		d.now = func() time.Time { return now }
+		if err := d.Run(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if got := outbox.failed[retry.ID]; !got.Equal(now.Add(4 * time.Minute)) {
+			t.Fatalf("expected retry after 4m, got %s", got.Sub(now))
+		}
+		if _, ok := outbox.dead[last.ID]; !ok {
+			t.Fatal("expected message past max attempts to be dead-lettered")
+		}
+	})
+
+	t.Run("expired message is dead-lettered unsent", func(t *testing.T) {
+		msg := newMsg(notificationdomain.TypeOTP, 0)
+		expired := now.Add(-time.Second)
+		msg.ExpiresAt = &expired
+		outbox := newFakeOutbox(msg)
+		sender := &fakeSender{channel: notificationdomain.ChannelLog}
+		uc, _ := NewUseCase(fakePrefs{}, nil, nil, nil, Config{DefaultChannel: notificationdomain.ChannelLog}, sender)
+		d := NewDispatcher(outbox, uc, nil, nil, DispatcherConfig{})
+		d.now = func() time.Time { return now }
+		if err := d.Run(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if len(sender.sent) != 0 {
+			t.Fatal("expired message was sent")
+		}
+		if _, ok := outbox.dead[msg.ID]; !ok || !outbox.redacted[msg.ID] {
+			t.Fatal("expected expired message to be dead-lettered and redacted")
+		}
+	})
+
+	t.Run("only non-OTP messages are re-driven", func(t *testing.T) {
+		otpMsg := newMsg(notificationdomain.TypeOTP, 8)
+		deposited := newMsg(notificationdomain.TypeParcelDeposited, 8)
+		outbox := newFakeOutbox()
+		for _, m := range []*notificationdomain.OutboxMessage{otpMsg, deposited} {
+			m.Status = notificationdomain.OutboxDead
+			outbox.stored[m.ID] = m
+		}
+		uc, _ := NewUseCase(fakePrefs{}, nil, nil, nil, Config{DefaultChannel: notificationdomain.ChannelLog}, &fakeSender{channel: notificationdomain.ChannelLog})
+		d := NewDispatcher(outbox, uc, nil, nil, DispatcherConfig{})
+		d.now = func() time.Time { return now }
+		if _, err := d.Redrive(context.Background(), otpMsg.ID); !errors.Is(err, notificationdomain.ErrNotRedrivable) {
+			t.Fatalf("expected ErrNotRedrivable for an OTP message, got %v", err)
+		}
+		if otpMsg.Status != notificationdomain.OutboxDead {
+			t.Fatal("OTP message was re-driven")
+		}
+		msg, err := d.Redrive(context.Background(), deposited.ID)
+		if err != nil || msg.Status != notificationdomain.OutboxPending {
+			t.Fatalf("expected the deposit message to be re-driven, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,293 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/audit"
+	notificationdomain "smart-parcel-locker/backend/domain/notification"
+	"smart-parcel-locker/backend/infrastructure/database"
+	"smart-parcel-locker/backend/pkg/logger"
+	phonepkg "smart-parcel-locker/backend/pkg/phone"
+)
+
+// Config selects the fallbacks used when a receiver has no preference.
+type Config struct {
+	DefaultChannel notificationdomain.Channel
+	DefaultLocale  notificationdomain.Locale
+	// Phones normalises preference phones to E.164.
+	Phones phonepkg.Normalizer
+}
+
+// UseCase renders messages from templates and routes them to the receiver's preferred channel.
+type UseCase struct {
+	prefs          notificationdomain.PreferenceRepository
+	templates      notificationdomain.TemplateRepository
+	audit          audit.Recorder
+	tx             *database.TransactionManager
+	senders        map[notificationdomain.Channel]notificationdomain.Sender
+	defaultChannel notificationdomain.Channel
+	defaultLocale  notificationdomain.Locale
+	phones         phonepkg.Normalizer
+	now            func() time.Time
+}
+
+// NewUseCase constructs the notification use case. The default channel must be one of the senders.
+func NewUseCase(
+	prefs notificationdomain.PreferenceRepository,
+	templates notificationdomain.TemplateRepository,
+	recorder audit.Recorder,
+	tx *database.TransactionManager,
+	cfg Config,
+	senders ...notificationdomain.Sender,
+) (*UseCase, error) {
+	byChannel := make(map[notificationdomain.Channel]notificationdomain.Sender, len(senders))
+	for _, s := range senders {
+		byChannel[s.Channel()] = s
+	}
+	if _, ok := byChannel[cfg.DefaultChannel]; !ok {
+		return nil, errors.New("default notification channel " + string(cfg.DefaultChannel) + " is not configured")
+	}
+	if cfg.DefaultLocale == "" {
+		cfg.DefaultLocale = notificationdomain.LocaleTH
+	}
+	if !cfg.DefaultLocale.Valid() {
+		return nil, errors.New("unknown notification locale " + string(cfg.DefaultLocale))
+	}
+	if tx == nil {
+		tx = database.NewTransactionManager(nil)
+	}
+	return &UseCase{
+		prefs:          prefs,
+		templates:      templates,
+		audit:          recorder,
+		tx:             tx,
+		senders:        byChannel,
+		defaultChannel: cfg.DefaultChannel,
+		defaultLocale:  cfg.DefaultLocale,
+		phones:         cfg.Phones,
+		now:            time.Now,
+	}, nil
+}
+
+// Channels lists the configured channels.
+func (uc *UseCase) Channels() []notificationdomain.Channel {
+	out := make([]notificationdomain.Channel, 0, len(uc.senders))
+	for _, c := range []notificationdomain.Channel{
+		notificationdomain.ChannelSMS,
+		notificationdomain.ChannelEmail,
+		notificationdomain.ChannelLine,
+		notificationdomain.ChannelDiscord,
+		notificationdomain.ChannelLog,
+	} {
+		if _, ok := uc.senders[c]; ok {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Notify delivers msg over the receiver's preferred channel, falling back to the default channel.
+func (uc *UseCase) Notify(ctx context.Context, msg notificationdomain.Message) error {
+	_, err := uc.Deliver(ctx, msg)
+	return err
+}
+
+// Deliver is Notify that also reports which channel carried the message.
+func (uc *UseCase) Deliver(ctx context.Context, msg notificationdomain.Message) (notificationdomain.Channel, error) {
+	pref := uc.preference(ctx, msg.Phone)
+	msg, err := uc.render(ctx, msg, pref)
+	if err != nil {
+		logger.Error(ctx, "notification usecase render failed unexpectedly", map[string]interface{}{
+			"receiverPhone": msg.Phone,
+			"messageType":   msg.Type,
+			"error":         err.Error(),
+		}, "")
+		return "", err
+	}
+
+	delivery := notificationdomain.Delivery{
+		Channel:     uc.defaultChannel,
+		Address:     defaultAddress(uc.defaultChannel, msg.Phone),
+		MessageType: msg.Type,
+		Subject:     msg.Subject,
+		Body:        msg.Body,
+	}
+
+	if preferred, ok := uc.preferredDelivery(pref, msg); ok && preferred.Channel != uc.defaultChannel {
+		err := uc.senders[preferred.Channel].Send(ctx, preferred)
+		if err == nil {
+			logger.Info(ctx, "notification usecase sent", map[string]interface{}{
+				"receiverPhone": msg.Phone,
+				"messageType":   msg.Type,
+				"channel":       preferred.Channel,
+			}, "")
+			return preferred.Channel, nil
+		}
+		logger.Warn(ctx, "notification usecase preferred channel failed", map[string]interface{}{
+			"receiverPhone": msg.Phone,
+			"messageType":   msg.Type,
+			"channel":       preferred.Channel,
+			"error":         err.Error(),
+		}, "")
+	} else if ok {
+		delivery = preferred
+	}
+
+	if err := uc.senders[delivery.Channel].Send(ctx, delivery); err != nil {
+		logger.Warn(ctx, "notification usecase send failed", map[string]interface{}{
+			"receiverPhone": msg.Phone,
+			"messageType":   msg.Type,
+			"channel":       delivery.Channel,
+			"error":         err.Error(),
+		}, "")
+		return delivery.Channel, err
+	}
+	logger.Info(ctx, "notification usecase sent", map[string]interface{}{
+		"receiverPhone": msg.Phone,
+		"messageType":   msg.Type,
+		"channel":       delivery.Channel,
+	}, "")
+	return delivery.Channel, nil
+}
+
+// preference loads the receiver's stored preference; nil when there is none.
+func (uc *UseCase) preference(ctx context.Context, phone string) *notificationdomain.Preference {
+	if uc.prefs == nil || phone == "" {
+		return nil
+	}
+	pref, err := uc.prefs.GetByPhone(ctx, phone)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Warn(ctx, "notification usecase preference lookup failed", map[string]interface{}{
+				"receiverPhone": phone,
+				"error":         err.Error(),
+			}, "")
+		}
+		return nil
+	}
+	return pref
+}
+
+// preferredDelivery resolves the receiver's stored preference into a delivery, if usable.
+func (uc *UseCase) preferredDelivery(pref *notificationdomain.Preference, msg notificationdomain.Message) (notificationdomain.Delivery, bool) {
+	if pref == nil {
+		return notificationdomain.Delivery{}, false
+	}
+	if _, ok := uc.senders[pref.Channel]; !ok {
+		return notificationdomain.Delivery{}, false
+	}
+	address := pref.Address
+	if address == "" {
+		address = defaultAddress(pref.Channel, msg.Phone)
+	}
+	if address == "" && pref.Channel != notificationdomain.ChannelDiscord {
+		return notificationdomain.Delivery{}, false
+	}
+	return notificationdomain.Delivery{
+		Channel:     pref.Channel,
+		Address:     address,
+		MessageType: msg.Type,
+		Subject:     msg.Subject,
+		Body:        msg.Body,
+	}, true
+}
+
+// GetPreference returns the stored preference for a phone.
+func (uc *UseCase) GetPreference(ctx context.Context, rawPhone string) (*notificationdomain.Preference, error) {
+	phone, err := uc.phones.Normalize(rawPhone)
+	if err != nil {
+		return nil, notificationdomain.ErrInvalidRequest
+	}
+	return uc.prefs.GetByPhone(ctx, phone)
+}
+
+// SetPreferenceInput describes a preference update.
+type SetPreferenceInput struct {
+	Phone   string
+	Channel notificationdomain.Channel
+	Address string
+	Locale  string // optional; empty uses the location or system default
+}
+
+// SetPreference stores a receiver's preferred channel.
+func (uc *UseCase) SetPreference(ctx context.Context, input SetPreferenceInput) (*notificationdomain.Preference, error) {
+	phone, err := uc.phones.Normalize(input.Phone)
+	if err != nil {
+		return nil, notificationdomain.ErrInvalidRequest
+	}
+	channel := notificationdomain.Channel(strings.ToUpper(strings.TrimSpace(string(input.Channel))))
+	if !channel.Valid() {
+		return nil, notificationdomain.ErrInvalidRequest
+	}
+	if _, ok := uc.senders[channel]; !ok {
+		return nil, notificationdomain.ErrChannelUnavailable
+	}
+	address := strings.TrimSpace(input.Address)
+	if (channel == notificationdomain.ChannelEmail || channel == notificationdomain.ChannelLine) && address == "" {
+		return nil, notificationdomain.ErrInvalidRequest
+	}
+	if channel == notificationdomain.ChannelEmail && !strings.Contains(address, "@") {
+		return nil, notificationdomain.ErrInvalidRequest
+	}
+	var locale notificationdomain.Locale
+	if strings.TrimSpace(input.Locale) != "" {
+		if locale = notificationdomain.ParseLocale(input.Locale); locale == "" {
+			return nil, notificationdomain.ErrInvalidRequest
+		}
+	}
+
+	var pref *notificationdomain.Preference
+	err = uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		scope := uc.scopeFor(tx)
+		var before interface{}
+		if existing, err := scope.prefs.GetByPhone(ctx, phone); err == nil {
+			before = preferenceSnapshot(existing)
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if pref, err = scope.prefs.Upsert(ctx, &notificationdomain.Preference{
+			Phone:     phone,
+			Channel:   channel,
+			Address:   address,
+			Locale:    locale,
+			CreatedAt: uc.now(),
+		}); err != nil {
+			return err
+		}
+		return scope.audit.Record(ctx, audit.Change{
+			Action:     "notification_preference.set",
+			EntityType: audit.EntityNotificationPreference,
+			EntityID:   phone,
+			Before:     before,
+			After:      preferenceSnapshot(pref),
+		})
+	})
+	if err != nil {
+		logger.Error(ctx, "notification usecase preference upsert failed unexpectedly", map[string]interface{}{
+			"receiverPhone": phone,
+			"channel":       channel,
+			"error":         err.Error(),
+		}, "")
+		return nil, err
+	}
+	logger.Info(ctx, "notification usecase preference saved", map[string]interface{}{
+		"receiverPhone": phone,
+		"channel":       channel,
+	}, "")
+	return pref, nil
+}
+
+// defaultAddress returns the address implied by the phone for channels that need no stored address.
+func defaultAddress(channel notificationdomain.Channel, phone string) string {
+	switch channel {
+	case notificationdomain.ChannelSMS, notificationdomain.ChannelLog, notificationdomain.ChannelDiscord:
+		return phone
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,153 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+
+	notificationdomain "smart-parcel-locker/backend/domain/notification"
+)
+
+type fakeSender struct {
+	channel notificationdomain.Channel
+	err     error
+	sent    []notificationdomain.Delivery
+}
+
+func (f *fakeSender) Channel() notificationdomain.Channel { return f.channel }
+
+func (f *fakeSender) Send(ctx context.Context, d notificationdomain.Delivery) error {
+	f.sent = append(f.sent, d)
+	return f.err
+}
+
+type fakePrefs map[string]notificationdomain.Preference
+
+func (f fakePrefs) GetByPhone(ctx context.Context, phone string) (*notificationdomain.Preference, error) {
+	p, ok := f[phone]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &p, nil
+}
+
+func (f fakePrefs) Upsert(ctx context.Context, pref *notificationdomain.Preference) (*notificationdomain.Preference, error) {
+	f[pref.Phone] = *pref
+	return pref, nil
+}
+
+func TestNotifyRouting(t *testing.T) {
+	const phone = "+66812345678"
+	msg := notificationdomain.Message{Type: notificationdomain.TypeOTP, Phone: phone, Body: "hi"}
+
+	tests := []struct {
+		name      string
+		pref      *notificationdomain.Preference
+		emailErr  error
+		wantSMS   int
+		wantEmail int
+		wantErr   bool
+	}{
+		{name: "no preference uses default", wantSMS: 1},
+		{name: "preferred channel", pref: &notificationdomain.Preference{Phone: phone, Channel: notificationdomain.ChannelEmail, Address: "a@b.co"}, wantEmail: 1},
+		{name: "preferred failure falls back", pref: &notificationdomain.Preference{Phone: phone, Channel: notificationdomain.ChannelEmail, Address: "a@b.co"}, emailErr: errors.New("smtp down"), wantSMS: 1, wantEmail: 1},
+		{name: "unconfigured preference ignored", pref: &notificationdomain.Preference{Phone: phone, Channel: notificationdomain.ChannelLine, Address: "U123"}, wantSMS: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefs := fakePrefs{}
+			if tt.pref != nil {
+				prefs[phone] = *tt.pref
+			}
+			sms := &fakeSender{channel: notificationdomain.ChannelSMS}
+			email := &fakeSender{channel: notificationdomain.ChannelEmail, err: tt.emailErr}
+			uc, err := NewUseCase(prefs, nil, nil, nil, Config{DefaultChannel: notificationdomain.ChannelSMS}, sms, email)
+			if err != nil {
+				t.Fatalf("NewUseCase: %v", err)
+			}
+			err = uc.Notify(context.Background(), msg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Notify err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(sms.sent) != tt.wantSMS || len(email.sent) != tt.wantEmail {
+				t.Fatalf("sent sms=%d email=%d, want sms=%d email=%d", len(sms.sent), len(email.sent), tt.wantSMS, tt.wantEmail)
+			}
+			if tt.wantSMS > 0 && sms.sent[0].Address != phone {
+				t.Fatalf("sms address = %q", sms.sent[0].Address)
+			}
+		})
+	}
+}
+
+func TestNewUseCaseRequiresDefaultSender(t *testing.T) {
+	if _, err := NewUseCase(fakePrefs{}, nil, nil, nil, Config{DefaultChannel: notificationdomain.ChannelSMS}, &fakeSender{channel: notificationdomain.ChannelLog}); err == nil {
+		t.Fatal("expected error for unconfigured default channel")
+	}
+}
+
+func TestSetPreferenceValidation(t *testing.T) {
+	uc, err := NewUseCase(fakePrefs{}, nil, nil, nil, Config{DefaultChannel: notificationdomain.ChannelLog},
+		&fakeSender{channel: notificationdomain.ChannelLog},
+		&fakeSender{channel: notificationdomain.ChannelEmail})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := uc.SetPreference(ctx, SetPreferenceInput{Phone: "0812345678", Channel: "EMAIL"}); !errors.Is(err, notificationdomain.ErrInvalidRequest) {
+		t.Fatalf("missing email address: err = %v", err)
+	}
+	if _, err := uc.SetPreference(ctx, SetPreferenceInput{Phone: "0812345678", Channel: "SMS"}); !errors.Is(err, notificationdomain.ErrChannelUnavailable) {
+		t.Fatalf("unconfigured channel: err = %v", err)
+	}
+	pref, err := uc.SetPreference(ctx, SetPreferenceInput{Phone: "0812345678", Channel: "email", Address: "r@example.com"})
+	if err != nil {
+		t.Fatalf("SetPreference: %v", err)
+	}
+	if pref.Phone != "+66812345678" || pref.Channel != notificationdomain.ChannelEmail {
+		t.Fatalf("unexpected preference %+v", pref)
+	}
+}
+
+func TestDeliverRendersLocale(t *testing.T) {
+	const phone = "+66812345678"
+	msg := notificationdomain.Message{
+		Type:   notificationdomain.TypeParcelDeposited,
+		Phone:  phone,
+		Locale: notificationdomain.LocaleTH,
+		Data:   map[string]string{"parcel_code": "PR-0000000001", "pickup_code": "PU-123456"},
+	}
+	tests := []struct {
+		name   string
+		pref   *notificationdomain.Preference
+		locale notificationdomain.Locale
+	}{
+		{name: "location default", locale: notificationdomain.LocaleTH},
+		{name: "receiver preference wins", pref: &notificationdomain.Preference{Phone: phone, Channel: notificationdomain.ChannelLog, Locale: notificationdomain.LocaleEN}, locale: notificationdomain.LocaleEN},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefs := fakePrefs{}
+			if tt.pref != nil {
+				prefs[phone] = *tt.pref
+			}
+			sender := &fakeSender{channel: notificationdomain.ChannelLog}
+			uc, err := NewUseCase(prefs, nil, nil, nil, Config{DefaultChannel: notificationdomain.ChannelLog, DefaultLocale: notificationdomain.LocaleEN}, sender)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := uc.Notify(context.Background(), msg); err != nil {
+				t.Fatalf("Notify: %v", err)
+			}
+			want, _ := notificationdomain.DefaultTemplate(msg.Type, tt.locale)
+			rendered, err := want.Render(msg.Data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(sender.sent) != 1 || sender.sent[0].Body != rendered.Body {
+				t.Fatalf("sent %+v, want body %q", sender.sent, rendered.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/audit"
+	notificationdomain "smart-parcel-locker/backend/domain/notification"
+	"smart-parcel-locker/backend/pkg/logger"
+)
+
+// render fills msg.Subject and msg.Body from the template for msg.Type. The locale is the
+// receiver's preference, then the message fallback (location default), then the system default.
+// Messages without data are sent as-is.
+func (uc *UseCase) render(ctx context.Context, msg notificationdomain.Message, pref *notificationdomain.Preference) (notificationdomain.Message, error) {
+	if len(msg.Data) == 0 {
+		return msg, nil
+	}
+	locale := uc.defaultLocale
+	if msg.Locale.Valid() {
+		locale = msg.Locale
+	}
+	if pref != nil && pref.Locale.Valid() {
+		locale = pref.Locale
+	}
+	tmpl, err := uc.template(ctx, msg.Type, locale)
+	if err != nil {
+		return msg, err
+	}
+	rendered, err := tmpl.Render(msg.Data)
+	if err != nil {
+		return msg, err
+	}
+	msg.Subject = rendered.Subject
+	msg.Body = rendered.Body
+	return msg, nil
+}
+
+// template returns the admin-edited template, falling back to the built-in default.
+func (uc *UseCase) template(ctx context.Context, messageType string, locale notificationdomain.Locale) (notificationdomain.Template, error) {
+	if uc.templates != nil {
+		tmpl, err := uc.templates.Get(ctx, messageType, locale)
+		if err == nil {
+			return *tmpl, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return notificationdomain.Template{}, err
+		}
+	}
+	if tmpl, ok := notificationdomain.DefaultTemplate(messageType, locale); ok {
+		return tmpl, nil
+	}
+	return notificationdomain.Template{}, notificationdomain.ErrTemplateNotFound
+}
+
+// TemplateView is a template together with whether an admin has overridden the default.
+type TemplateView struct {
+	notificationdomain.Template
+	Customized bool
+}
+
+// ListTemplates returns every message type and locale, customised or default.
+func (uc *UseCase) ListTemplates(ctx context.Context) ([]TemplateView, error) {
+	custom := map[string]notificationdomain.Template{}
+	if uc.templates != nil {
+		stored, err := uc.templates.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range stored {
+			custom[t.Type+"/"+string(t.Locale)] = t
+		}
+	}
+	var views []TemplateView
+	for _, messageType := range notificationdomain.MessageTypes() {
+		for _, locale := range notificationdomain.Locales() {
+			if t, ok := custom[messageType+"/"+string(locale)]; ok {
+				views = append(views, TemplateView{Template: t, Customized: true})
+				continue
+			}
+			if t, ok := notificationdomain.DefaultTemplate(messageType, locale); ok {
+				views = append(views, TemplateView{Template: t})
+			}
+		}
+	}
+	return views, nil
+}
+
+// SetTemplateInput describes a template edit.
+type SetTemplateInput struct {
+	Type    string
+	Locale  string
+	Subject string
+	Body    string
+}
+
+// SetTemplate stores an admin-edited template after checking that it parses.
+func (uc *UseCase) SetTemplate(ctx context.Context, input SetTemplateInput) (*TemplateView, error) {
+	tmpl, err := validateTemplateInput(input)
+	if err != nil {
+		return nil, err
+	}
+	var saved *notificationdomain.Template
+	err = uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		scope := uc.scopeFor(tx)
+		before, err := storedTemplate(ctx, scope.templates, tmpl.Type, tmpl.Locale)
+		if err != nil {
+			return err
+		}
+		if saved, err = scope.templates.Upsert(ctx, &tmpl); err != nil {
+			return err
+		}
+		return scope.audit.Record(ctx, audit.Change{
+			Action:     "notification_template.set",
+			EntityType: audit.EntityNotificationTemplate,
+			EntityID:   saved.Type + "/" + string(saved.Locale),
+			Before:     before,
+			After:      templateSnapshot(saved),
+		})
+	})
+	if err != nil {
+		logger.Error(ctx, "notification usecase template upsert failed unexpectedly", map[string]interface{}{
+			"messageType": tmpl.Type,
+			"locale":      tmpl.Locale,
+			"error":       err.Error(),
+		}, "")
+		return nil, err
+	}
+	logger.Info(ctx, "notification usecase template saved", map[string]interface{}{
+		"messageType": saved.Type,
+		"locale":      saved.Locale,
+	}, "")
+	return &TemplateView{Template: *saved, Customized: true}, nil
+}
+
+// ResetTemplate removes the admin edit so the built-in default applies again.
+func (uc *UseCase) ResetTemplate(ctx context.Context, messageType, rawLocale string) error {
+	messageType = strings.ToUpper(strings.TrimSpace(messageType))
+	locale := notificationdomain.ParseLocale(rawLocale)
+	if locale == "" || !knownType(messageType) {
+		return notificationdomain.ErrInvalidRequest
+	}
+	err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		scope := uc.scopeFor(tx)
+		before, err := storedTemplate(ctx, scope.templates, messageType, locale)
+		if err != nil {
+			return err
+		}
+		if err := scope.templates.Delete(ctx, messageType, locale); err != nil {
+			return err
+		}
+		return scope.audit.Record(ctx, audit.Change{
+			Action:     "notification_template.reset",
+			EntityType: audit.EntityNotificationTemplate,
+			EntityID:   messageType + "/" + string(locale),
+			Before:     before,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	logger.Info(ctx, "notification usecase template reset", map[string]interface{}{
+		"messageType": messageType,
+		"locale":      locale,
+	}, "")
+	return nil
+}
+
+// PreviewInput renders a stored template, or a draft when Subject/Body are given.
+// Data overrides the sample fields for the message type.
+type PreviewInput struct {
+	Type    string
+	Locale  string
+	Subject *string
+	Body    *string
+	Data    map[string]string
+}
+
+// Preview renders a template with sample data without sending anything.
+func (uc *UseCase) Preview(ctx context.Context, input PreviewInput) (*notificationdomain.Rendered, error) {
+	messageType := strings.ToUpper(strings.TrimSpace(input.Type))
+	locale := notificationdomain.ParseLocale(input.Locale)
+	if locale == "" || !knownType(messageType) {
+		return nil, notificationdomain.ErrInvalidRequest
+	}
+	tmpl, err := uc.template(ctx, messageType, locale)
+	if err != nil {
+		return nil, err
+	}
+	if input.Subject != nil {
+		tmpl.Subject = *input.Subject
+	}
+	if input.Body != nil {
+		tmpl.Body = *input.Body
+	}
+	if err := tmpl.Validate(); err != nil {
+		return nil, notificationdomain.ErrInvalidTemplate
+	}
+	data := notificationdomain.SampleData(messageType)
+	for k, v := range input.Data {
+		data[k] = v
+	}
+	rendered, err := tmpl.Render(data)
+	if err != nil {
+		return nil, notificationdomain.ErrInvalidTemplate
+	}
+	return &rendered, nil
+}
+
+func validateTemplateInput(input SetTemplateInput) (notificationdomain.Template, error) {
+	tmpl := notificationdomain.Template{
+		Type:    strings.ToUpper(strings.TrimSpace(input.Type)),
+		Locale:  notificationdomain.ParseLocale(input.Locale),
+		Subject: input.Subject,
+		Body:    input.Body,
+	}
+	if tmpl.Locale == "" || !knownType(tmpl.Type) || strings.TrimSpace(tmpl.Body) == "" {
+		return tmpl, notificationdomain.ErrInvalidRequest
+	}
+	if err := tmpl.Validate(); err != nil {
+		return tmpl, notificationdomain.ErrInvalidTemplate
+	}
+	if _, err := tmpl.Render(notificationdomain.SampleData(tmpl.Type)); err != nil {
+		return tmpl, notificationdomain.ErrInvalidTemplate
+	}
+	return tmpl, nil
+}
+
+func knownType(messageType string) bool {
+	for _, t := range notificationdomain.MessageTypes() {
+		if t == messageType {
+			return true
+		}
+	}
+	return false
+}
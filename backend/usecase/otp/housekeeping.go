@@ -0,0 +1,76 @@
+package otp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"smart-parcel-locker/backend/domain/otp"
+	"smart-parcel-locker/backend/pkg/logger"
+)
+
+const (
+	RetentionModeDelete    = "delete"
+	RetentionModeAnonymize = "anonymize"
+)
+
+// HousekeepingConfig controls how old OTP records are handled.
+type HousekeepingConfig struct {
+	// Retention is how long an OTP is kept after it expires. Zero disables the purge step.
+	Retention time.Duration
+	// Mode is RetentionModeDelete or RetentionModeAnonymize.
+	Mode string
+}
+
+// Housekeeping expires stale OTPs and purges records past retention.
+type Housekeeping struct {
+	repo otp.Repository
+	cfg  HousekeepingConfig
+	now  func() time.Time
+}
+
+// NewHousekeeping constructs the OTP housekeeping job.
+func NewHousekeeping(repo otp.Repository, cfg HousekeepingConfig) (*Housekeeping, error) {
+	switch cfg.Mode {
+	case "":
+		cfg.Mode = RetentionModeDelete
+	case RetentionModeDelete, RetentionModeAnonymize:
+	default:
+		return nil, fmt.Errorf("unknown otp retention mode %q", cfg.Mode)
+	}
+	return &Housekeeping{
+		repo: repo,
+		cfg:  cfg,
+		now:  time.Now,
+	}, nil
+}
+
+// Run performs one housekeeping pass. Failures are returned for the worker to log.
+func (h *Housekeeping) Run(ctx context.Context) error {
+	now := h.now()
+	expired, err := h.repo.ExpireActive(ctx, now)
+	if err != nil {
+		return fmt.Errorf("expire otps: %w", err)
+	}
+
+	var purged int64
+	if h.cfg.Retention > 0 {
+		cutoff := now.Add(-h.cfg.Retention)
+		if h.cfg.Mode == RetentionModeAnonymize {
+			purged, err = h.repo.AnonymizeExpiredBefore(ctx, cutoff)
+		} else {
+			purged, err = h.repo.DeleteExpiredBefore(ctx, cutoff)
+		}
+		if err != nil {
+			return fmt.Errorf("purge otps (%s): %w", h.cfg.Mode, err)
+		}
+	}
+
+	logger.Info(ctx, "otp housekeeping completed", map[string]interface{}{
+		"expired":   expired,
+		"purged":    purged,
+		"mode":      h.cfg.Mode,
+		"retention": h.cfg.Retention.String(),
+	}, "")
+	return nil
+}
@@ -0,0 +1,113 @@
+package otp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"smart-parcel-locker/backend/domain/otp"
+)
+
+// fakeHousekeepingRepo records the cutoffs it was called with.
+type fakeHousekeepingRepo struct {
+	otp.Repository
+	expiredAt    time.Time
+	deleteCutoff time.Time
+	anonCutoff   time.Time
+	expireErr    error
+	purgeErr     error
+}
+
+func (f *fakeHousekeepingRepo) ExpireActive(_ context.Context, now time.Time) (int64, error) {
+	f.expiredAt = now
+	return 3, f.expireErr
+}
+
+func (f *fakeHousekeepingRepo) DeleteExpiredBefore(_ context.Context, cutoff time.Time) (int64, error) {
+	f.deleteCutoff = cutoff
+	return 2, f.purgeErr
+}
+
+func (f *fakeHousekeepingRepo) AnonymizeExpiredBefore(_ context.Context, cutoff time.Time) (int64, error) {
+	f.anonCutoff = cutoff
+	return 2, f.purgeErr
+}
+
+func newTestHousekeeping(t *testing.T, repo otp.Repository, cfg HousekeepingConfig, now time.Time) *Housekeeping {
+	t.Helper()
+	h, err := NewHousekeeping(repo, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.now = func() time.Time { return now }
+	return h
+}
+
+func TestHousekeepingRetentionModes(t *testing.T) {
+	now := time.Date(2025, 1, 9, 12, 0, 0, 0, time.UTC)
+	cutoff := now.Add(-30 * 24 * time.Hour)
+
+	t.Run("delete", func(t *testing.T) {
+		repo := &fakeHousekeepingRepo{}
+		h := newTestHousekeeping(t, repo, HousekeepingConfig{Retention: 30 * 24 * time.Hour}, now)
+		if err := h.Run(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if !repo.expiredAt.Equal(now) {
+			t.Fatalf("expected active OTPs to be expired at %s, got %s", now, repo.expiredAt)
+		}
+		if !repo.deleteCutoff.Equal(cutoff) || !repo.anonCutoff.IsZero() {
+			t.Fatalf("expected a delete before %s, got delete %s anonymise %s", cutoff, repo.deleteCutoff, repo.anonCutoff)
+		}
+	})
+
+	t.Run("anonymise", func(t *testing.T) {
+		repo := &fakeHousekeepingRepo{}
+		h := newTestHousekeeping(t, repo, HousekeepingConfig{Retention: 30 * 24 * time.Hour, Mode: RetentionModeAnonymize}, now)
+		if err := h.Run(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if !repo.anonCutoff.Equal(cutoff) || !repo.deleteCutoff.IsZero() {
+			t.Fatalf("expected an anonymise before %s, got delete %s anonymise %s", cutoff, repo.deleteCutoff, repo.anonCutoff)
+		}
+	})
+
+	t.Run("no retention only expires", func(t *testing.T) {
+		repo := &fakeHousekeepingRepo{}
+		h := newTestHousekeeping(t, repo, HousekeepingConfig{Mode: RetentionModeAnonymize}, now)
+		if err := h.Run(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if repo.expiredAt.IsZero() || !repo.deleteCutoff.IsZero() || !repo.anonCutoff.IsZero() {
+			t.Fatalf("expected only the expire step, got %+v", repo)
+		}
+	})
+
+	t.Run("expire failure skips the purge", func(t *testing.T) {
+		down := errors.New("connection reset")
+		repo := &fakeHousekeepingRepo{expireErr: down}
+		h := newTestHousekeeping(t, repo, HousekeepingConfig{Retention: time.Hour}, now)
+		if err := h.Run(context.Background()); !errors.Is(err, down) {
+			t.Fatalf("expected the expire error, got %v", err)
+		}
+		if !repo.deleteCutoff.IsZero() {
+			t.Fatal("purge ran after the expire step failed")
+		}
+	})
+
+	t.Run("purge failure is returned", func(t *testing.T) {
+		down := errors.New("connection reset")
+		repo := &fakeHousekeepingRepo{purgeErr: down}
+		h := newTestHousekeeping(t, repo, HousekeepingConfig{Retention: time.Hour, Mode: RetentionModeAnonymize}, now)
+		if err := h.Run(context.Background()); !errors.Is(err, down) {
+			t.Fatalf("expected the purge error, got %v", err)
+		}
+	})
+}
+
+func TestNewHousekeepingRejectsUnknownMode(t *testing.T) {
+	if _, err := NewHousekeeping(&fakeHousekeepingRepo{}, HousekeepingConfig{Mode: "shred"}); err == nil {
+		t.Fatal("expected an unknown retention mode to be rejected")
+	}
+}
@@ -6,24 +6,35 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"math/big"
-	"sync"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"smart-parcel-locker/backend/domain/device"
+	"smart-parcel-locker/backend/domain/notification"
 	"smart-parcel-locker/backend/domain/otp"
 	pickupdomain "smart-parcel-locker/backend/domain/pickup"
 	"smart-parcel-locker/backend/infrastructure/database"
 	"smart-parcel-locker/backend/pkg/errorx"
 	"smart-parcel-locker/backend/pkg/logger"
+	phonepkg "smart-parcel-locker/backend/pkg/phone"
 )
 
 const otpTTL = 5 * time.Minute
 
-// Notifier delivers OTP codes to users.
-type Notifier interface {
-	SendOTP(ctx context.Context, phone string, otpCode string) error
+// Outbox queues notifications for the background dispatcher.
+type Outbox interface {
+	Enqueue(ctx context.Context, msg *notification.OutboxMessage) error
+}
+
+type txOutbox interface {
+	WithDB(db *gorm.DB) notification.OutboxRepository
+}
+
+type txTokenStore interface {
+	WithDB(db *gorm.DB) pickupdomain.TokenStore
 }
 
 type otpRepository interface {
@@ -33,12 +44,12 @@ type otpRepository interface {
 
 // UseCase handles OTP request/verify flow.
 type UseCase struct {
-	repo        otpRepository
-	notifier    Notifier
-	tokenStore  pickupdomain.TokenStore
-	now         func() time.Time
-	tx          *database.TransactionManager
-	rateLimiter *phoneRateLimiter
+	repo       otpRepository
+	outbox     Outbox
+	tokenStore pickupdomain.TokenStore
+	phones     phonepkg.Normalizer
+	now        func() time.Time
+	tx         *database.TransactionManager
 }
 
 type RequestResult struct {
@@ -55,12 +66,13 @@ type VerifyResult struct {
 // NewUseCase constructs OTP use case.
 func NewUseCase(
 	repo otp.Repository,
-	notifier Notifier,
+	outbox Outbox,
 	tokenStore pickupdomain.TokenStore,
 	tx *database.TransactionManager,
+	phones phonepkg.Normalizer,
 ) *UseCase {
-	if notifier == nil {
-		notifier = noopNotifier{}
+	if outbox == nil {
+		outbox = noopOutbox{}
 	}
 	if tokenStore == nil {
 		tokenStore = noopTokenStore{}
@@ -73,33 +85,27 @@ func NewUseCase(
 			otp.Repository
 			WithDB(db *gorm.DB) otp.Repository
 		}),
-		notifier:    notifier,
-		tokenStore:  tokenStore,
-		now:         time.Now,
-		tx:          tx,
-		rateLimiter: newPhoneRateLimiter(30 * time.Second),
+		outbox:     outbox,
+		tokenStore: tokenStore,
+		phones:     phones,
+		now:        time.Now,
+		tx:         tx,
 	}
 }
 
-// RequestOTP generates and stores an OTP, then notifies the user.
-func (uc *UseCase) RequestOTP(ctx context.Context, phone string) (*RequestResult, error) {
+// RequestOTP generates and stores an OTP and queues its notification.
+func (uc *UseCase) RequestOTP(ctx context.Context, rawPhone string) (*RequestResult, error) {
 	logger.Info(ctx, "otp usecase request started", map[string]interface{}{
-		"receiverPhone": phone,
+		"receiverPhone": rawPhone,
 	}, "")
-	if !isValidPhone(phone) {
+	phone, err := uc.phones.Normalize(rawPhone)
+	if err != nil {
 		logger.Warn(ctx, "otp usecase request invalid phone", map[string]interface{}{
-			"receiverPhone": phone,
+			"receiverPhone": rawPhone,
 		}, "")
 		return nil, otp.ErrInvalidRequest
 	}
 
-	if !uc.rateLimiter.Allow(phone, uc.now()) {
-		logger.Warn(ctx, "otp usecase request rate limited", map[string]interface{}{
-			"receiverPhone": phone,
-		}, "")
-		return nil, errorx.Error{Code: "TOO_MANY_REQUESTS", Message: "too many requests"}
-	}
-
 	otpCode := generateNumericCode(6)
 	now := uc.now()
 	entity := &otp.OTP{
@@ -110,14 +116,51 @@ func (uc *UseCase) RequestOTP(ctx context.Context, phone string) (*RequestResult
 		Status:    otp.StatusActive,
 		ExpiresAt: now.Add(otpTTL),
 		CreatedAt: now,
+
+		RequestDeviceID: device.IDFrom(ctx),
 	}
 
-	created, err := uc.repo.Create(ctx, entity)
+	// The OTP and its notification commit together; the dispatcher delivers it with retries.
+	var created *otp.OTP
+	err = uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		var repo otp.Repository = uc.repo
+		var outbox Outbox = uc.outbox
+		if tx != nil {
+			repo = uc.repo.WithDB(tx)
+			if o, ok := uc.outbox.(txOutbox); ok {
+				outbox = o.WithDB(tx)
+			}
+		}
+
+		var err error
+		created, err = repo.Create(ctx, entity)
+		if err != nil {
+			logger.Error(ctx, "otp usecase create failed unexpectedly", map[string]interface{}{
+				"receiverPhone": phone,
+				"error":         err.Error(),
+			}, "")
+			return err
+		}
+
+		msg := notification.NewOutboxMessage(notification.Message{
+			Type:  notification.TypeOTP,
+			Phone: phone,
+			Data: map[string]string{
+				"otp_code":    otpCode,
+				"ttl_minutes": strconv.Itoa(int(otpTTL.Minutes())),
+			},
+		}, now)
+		msg.ExpiresAt = &created.ExpiresAt
+		if err := outbox.Enqueue(ctx, msg); err != nil {
+			logger.Error(ctx, "otp usecase enqueue notification failed unexpectedly", map[string]interface{}{
+				"receiverPhone": phone,
+				"error":         err.Error(),
+			}, "")
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		logger.Error(ctx, "otp usecase create failed unexpectedly", map[string]interface{}{
-			"receiverPhone": phone,
-			"error":         err.Error(),
-		}, "")
 		return nil, err
 	}
 
@@ -126,32 +169,23 @@ func (uc *UseCase) RequestOTP(ctx context.Context, phone string) (*RequestResult
 		"otpRef":        created.OtpRef,
 	}, "")
 
-	if err := uc.notifier.SendOTP(ctx, phone, otpCode); err != nil {
-		logger.Warn(ctx, "otp usecase notify failed", map[string]interface{}{
-			"receiverPhone": phone,
-			"error":         err.Error(),
-		}, "")
-	} else {
-		logger.Info(ctx, "otp usecase notified", map[string]interface{}{
-			"receiverPhone": phone,
-		}, "")
-	}
-
 	return &RequestResult{
 		OtpRef:    created.OtpRef,
 		ExpiresAt: created.ExpiresAt,
 	}, nil
 }
 
-// VerifyOTP checks OTP code and marks it as verified.
-func (uc *UseCase) VerifyOTP(ctx context.Context, phone string, otpRef string, otpCode string) (*VerifyResult, error) {
+// VerifyOTP checks OTP code and marks it as verified. When lockerID is set the
+// issued pickup token is only valid at that locker.
+func (uc *UseCase) VerifyOTP(ctx context.Context, rawPhone string, otpRef string, otpCode string, lockerID *uuid.UUID) (*VerifyResult, error) {
 	logger.Info(ctx, "otp usecase verify started", map[string]interface{}{
-		"receiverPhone": phone,
+		"receiverPhone": rawPhone,
 		"otpRef":        otpRef,
 	}, "")
-	if otpRef == "" || otpCode == "" || !isValidPhone(phone) || !isNumeric(otpCode) {
+	phone, phoneErr := uc.phones.Normalize(rawPhone)
+	if otpRef == "" || otpCode == "" || phoneErr != nil || !isNumeric(otpCode) {
 		logger.Warn(ctx, "otp usecase verify invalid request", map[string]interface{}{
-			"receiverPhone": phone,
+			"receiverPhone": rawPhone,
 			"otpRef":        otpRef,
 		}, "")
 		return nil, otp.ErrInvalidRequest
@@ -167,8 +201,12 @@ func (uc *UseCase) VerifyOTP(ctx context.Context, phone string, otpRef string, o
 	var result *VerifyResult
 	err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
 		var repo otp.Repository = uc.repo
+		tokens := uc.tokenStore
 		if tx != nil {
 			repo = uc.repo.WithDB(tx)
+			if s, ok := uc.tokenStore.(txTokenStore); ok {
+				tokens = s.WithDB(tx)
+			}
 		}
 		record, err := repo.GetByRefAndPhone(ctx, otpRef, phone)
 		if err != nil {
@@ -231,6 +269,7 @@ func (uc *UseCase) VerifyOTP(ctx context.Context, phone string, otpRef string, o
 
 		record.Status = otp.StatusVerified
 		record.VerifiedAt = &now
+		record.VerifyDeviceID = device.IDFrom(ctx)
 		if _, err := repo.Update(ctx, record); err != nil {
 			logger.Error(ctx, "otp usecase verify update failed unexpectedly", map[string]interface{}{
 				"receiverPhone": phone,
@@ -240,12 +279,26 @@ func (uc *UseCase) VerifyOTP(ctx context.Context, phone string, otpRef string, o
 			return err
 		}
 
+		expiresAt := now.Add(15 * time.Minute)
+		token, err := tokens.Issue(ctx, pickupdomain.TokenInfo{
+			Phone:     phone,
+			ExpiresAt: expiresAt,
+			OtpRef:    record.OtpRef,
+			LockerID:  lockerID,
+		})
+		if err != nil {
+			logger.Error(ctx, "otp usecase verify store token failed unexpectedly", map[string]interface{}{
+				"receiverPhone": phone,
+				"otpRef":        otpRef,
+				"error":         err.Error(),
+			}, "")
+			return err
+		}
 		result = &VerifyResult{
 			Status:      record.Status,
-			PickupToken: uuid.NewString(),
-			ExpiresAt:   now.Add(15 * time.Minute),
+			PickupToken: token,
+			ExpiresAt:   expiresAt,
 		}
-		uc.tokenStore.Store(result.PickupToken, phone, result.ExpiresAt)
 		return nil
 	})
 	if err != nil {
@@ -271,17 +324,24 @@ func (uc *UseCase) VerifyOTP(ctx context.Context, phone string, otpRef string, o
 	return result, nil
 }
 
-type noopNotifier struct{}
+type noopOutbox struct{}
 
-func (noopNotifier) SendOTP(ctx context.Context, phone string, otpCode string) error {
+func (noopOutbox) Enqueue(ctx context.Context, msg *notification.OutboxMessage) error {
 	return nil
 }
 
 type noopTokenStore struct{}
 
-func (noopTokenStore) Store(token string, phone string, expiresAt time.Time) {}
-func (noopTokenStore) Get(token string) (pickupdomain.TokenInfo, bool) {
-	return pickupdomain.TokenInfo{}, false
+func (noopTokenStore) Issue(ctx context.Context, info pickupdomain.TokenInfo) (string, error) {
+	return "", nil
+}
+
+func (noopTokenStore) Get(ctx context.Context, token string) (pickupdomain.TokenInfo, bool, error) {
+	return pickupdomain.TokenInfo{}, false, nil
+}
+
+func (noopTokenStore) Revoke(ctx context.Context, token string) error {
+	return nil
 }
 
 func generateNumericCode(digits int) string {
@@ -311,34 +371,3 @@ func isNumeric(value string) bool {
 	}
 	return true
 }
-
-func isValidPhone(value string) bool {
-	if value == "" || len(value) < 9 || len(value) > 15 {
-		return false
-	}
-	return isNumeric(value)
-}
-
-type phoneRateLimiter struct {
-	window time.Duration
-	mu     sync.Mutex
-	last   map[string]time.Time
-}
-
-func newPhoneRateLimiter(window time.Duration) *phoneRateLimiter {
-	return &phoneRateLimiter{
-		window: window,
-		last:   make(map[string]time.Time),
-	}
-}
-
-func (rl *phoneRateLimiter) Allow(phone string, now time.Time) bool {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
-	lastTime, ok := rl.last[phone]
-	if ok && now.Sub(lastTime) < rl.window {
-		return false
-	}
-	rl.last[phone] = now
-	return true
-}
@@ -3,7 +3,9 @@ package parcel
 import (
 	"context"
 	"crypto/rand"
+	"errors"
 	"math/big"
+	"net/url"
 	"strings"
 	"time"
 
@@ -11,11 +13,18 @@ import (
 	"gorm.io/gorm"
 
 	"smart-parcel-locker/backend/domain/compartment"
+	"smart-parcel-locker/backend/domain/device"
+	"smart-parcel-locker/backend/domain/hardware"
+	"smart-parcel-locker/backend/domain/location"
 	"smart-parcel-locker/backend/domain/locker"
+	"smart-parcel-locker/backend/domain/notification"
 	"smart-parcel-locker/backend/domain/parcel"
+	"smart-parcel-locker/backend/domain/webhook"
 	"smart-parcel-locker/backend/infrastructure/database"
 	"smart-parcel-locker/backend/pkg/errorx"
 	"smart-parcel-locker/backend/pkg/logger"
+	"smart-parcel-locker/backend/pkg/phone"
+	hardwareusecase "smart-parcel-locker/backend/usecase/hardware"
 )
 
 type parcelRepository interface {
@@ -33,11 +42,47 @@ type compartmentRepository interface {
 	WithDB(db *gorm.DB) compartment.Repository
 }
 
+// Outbox queues notifications for the background dispatcher.
+type Outbox interface {
+	Enqueue(ctx context.Context, msg *notification.OutboxMessage) error
+}
+
+type txOutbox interface {
+	WithDB(db *gorm.DB) notification.OutboxRepository
+}
+
+// Webhooks queues carrier webhook deliveries for parcel events.
+type Webhooks interface {
+	Publish(ctx context.Context, p *parcel.Parcel, e *parcel.Event) error
+}
+
+type txWebhooks interface {
+	WithDB(db *gorm.DB) webhook.Publisher
+}
+
+// Config controls deposit behaviour.
+type Config struct {
+	// StoragePeriod is how long a parcel may stay in the locker; zero leaves expires_at unset.
+	StoragePeriod time.Duration
+	// PickupURL is the receiver-facing pickup page linked from notifications.
+	PickupURL string
+	// Timezone is the zone times in notifications are shown in; nil keeps UTC.
+	Timezone *time.Location
+	// Phones normalises receiver and sender numbers to E.164.
+	Phones phone.Normalizer
+}
+
 // UseCase handles parcel workflows.
 type UseCase struct {
 	parcelRepo      parcelRepository
 	lockerRepo      lockerRepository
 	compartmentRepo compartmentRepository
+	locationRepo    location.Repository
+	outbox          Outbox
+	webhooks        Webhooks
+	doors           hardware.LockController
+	reconciler      *hardwareusecase.Reconciler
+	cfg             Config
 	tx              *database.TransactionManager
 }
 
@@ -46,6 +91,7 @@ type DepositInput struct {
 	Size          string
 	ReceiverPhone string
 	SenderPhone   string
+	CarrierCode   string // optional; carrier webhooks only fire for parcels that carry one
 	RequestURL    string
 }
 
@@ -60,8 +106,22 @@ func NewUseCase(
 	parcelRepo parcel.Repository,
 	lockerRepo locker.Repository,
 	compartmentRepo compartment.Repository,
+	locationRepo location.Repository,
+	outbox Outbox,
+	webhooks Webhooks,
+	doors hardware.LockController,
 	tx *database.TransactionManager,
+	cfg Config,
 ) *UseCase {
+	if outbox == nil {
+		outbox = noopOutbox{}
+	}
+	if webhooks == nil {
+		webhooks = noopWebhooks{}
+	}
+	if doors == nil {
+		doors = noopDoors{}
+	}
 	if tx == nil {
 		tx = database.NewTransactionManager(nil)
 	}
@@ -78,7 +138,13 @@ func NewUseCase(
 			compartment.Repository
 			WithDB(*gorm.DB) compartment.Repository
 		}),
-		tx: tx,
+		locationRepo: locationRepo,
+		outbox:       outbox,
+		webhooks:     webhooks,
+		doors:        doors,
+		reconciler:   hardwareusecase.NewReconciler(compartmentRepo, tx),
+		cfg:          cfg,
+		tx:           tx,
 	}
 }
 
@@ -87,32 +153,53 @@ func (uc *UseCase) GetByID(ctx context.Context, id uuid.UUID) (*parcel.Parcel, e
 	return uc.parcelRepo.GetByID(ctx, id)
 }
 
-// Deposit creates a parcel and assigns it to an available compartment.
+// Deposit creates a parcel and assigns it to an available compartment. A kiosk device attached
+// to ctx may only deposit into its own locker and is recorded on the deposit event. The door is
+// opened once the compartment is locked and before anything is written, so a door that fails to
+// open leaves no trace; a deposit that fails to commit after that puts the compartment out of
+// service until staff check it.
 func (uc *UseCase) Deposit(ctx context.Context, input DepositInput) (*DepositResult, error) {
-	if err := validateDepositInput(input); err != nil {
+	input, err := normalizeDepositInput(input, uc.cfg.Phones)
+	if err != nil {
 		return nil, err
 	}
+	if d, ok := device.FromContext(ctx); ok && d.LockerID != input.LockerID {
+		logger.Warn(ctx, "deposit device locker mismatch", map[string]interface{}{
+			"deviceId": d.ID.String(),
+			"lockerId": input.LockerID.String(),
+		}, input.RequestURL)
+		return nil, device.ErrLockerMismatch
+	}
 
 	var result *DepositResult
-	err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+	var opened *hardware.CompartmentRef
+	err = uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
 		var parcelRepo parcel.Repository = uc.parcelRepo
 		var lockerRepo locker.Repository = uc.lockerRepo
 		var compartmentRepo compartment.Repository = uc.compartmentRepo
+		var outbox Outbox = uc.outbox
+		var webhooks Webhooks = uc.webhooks
 		if tx != nil {
 			parcelRepo = uc.parcelRepo.WithDB(tx)
 			lockerRepo = uc.lockerRepo.WithDB(tx)
 			compartmentRepo = uc.compartmentRepo.WithDB(tx)
+			if o, ok := uc.outbox.(txOutbox); ok {
+				outbox = o.WithDB(tx)
+			}
+			if w, ok := uc.webhooks.(txWebhooks); ok {
+				webhooks = w.WithDB(tx)
+			}
 		}
 
-		lockerEntity, err := lockerRepo.GetByID(ctx, input.LockerID)
+		lockerEntity, err := lockerRepo.GetByIDForShare(ctx, input.LockerID)
 		if err != nil {
 			if err == gorm.ErrRecordNotFound {
 				return errorx.Error{Code: "NOT_FOUND", Message: "locker not found"}
 			}
 			return err
 		}
-		if lockerEntity.Status != locker.StatusActive {
-			return locker.ErrLockerInactive
+		if err := lockerEntity.CheckDeposit(); err != nil {
+			return err
 		}
 
 		// Best-fit selection: try the smallest compartment that can fit the requested size.
@@ -140,6 +227,21 @@ func (uc *UseCase) Deposit(ctx context.Context, input DepositInput) (*DepositRes
 			"compartmentSize": comp.Size,
 		}, input.RequestURL)
 
+		ref := hardware.CompartmentRef{LockerID: comp.LockerID, CompartmentID: comp.ID, CompartmentNo: comp.CompartmentNo}
+		if err := uc.doors.Open(ctx, ref); err != nil {
+			if errors.Is(err, hardware.ErrDoorOutcomeUnknown) {
+				// The board may have opened the door without answering.
+				opened = &ref
+			}
+			logger.Error(ctx, "deposit door open failed", map[string]interface{}{
+				"lockerId":      input.LockerID.String(),
+				"compartmentId": comp.ID.String(),
+				"error":         err.Error(),
+			}, input.RequestURL)
+			return err
+		}
+		opened = &ref
+
 		parcelID := uuid.New()
 		if err := comp.Reserve(parcelID); err != nil {
 			return err
@@ -162,7 +264,16 @@ func (uc *UseCase) Deposit(ctx context.Context, input DepositInput) (*DepositRes
 		}
 
 		now := time.Now()
+		var expiresAt *time.Time
+		if uc.cfg.StoragePeriod > 0 {
+			deadline := now.Add(uc.cfg.StoragePeriod)
+			expiresAt = &deadline
+		}
 		pickupCode := generateCode("PU-", 6)
+		var carrierCode *string
+		if input.CarrierCode != "" {
+			carrierCode = &input.CarrierCode
+		}
 		entity := &parcel.Parcel{
 			ID:            parcelID,
 			ParcelCode:    generateCode("PR-", 10),
@@ -171,9 +282,11 @@ func (uc *UseCase) Deposit(ctx context.Context, input DepositInput) (*DepositRes
 			Size:          input.Size,
 			ReceiverPhone: input.ReceiverPhone,
 			SenderPhone:   input.SenderPhone,
+			CarrierCode:   carrierCode,
 			PickupCode:    &pickupCode,
 			Status:        parcel.StatusReadyForPickup,
 			DepositedAt:   &now,
+			ExpiresAt:     expiresAt,
 			CreatedAt:     now,
 			UpdatedAt:     &now,
 		}
@@ -182,12 +295,17 @@ func (uc *UseCase) Deposit(ctx context.Context, input DepositInput) (*DepositRes
 		if err != nil {
 			return err
 		}
-		if err := parcelRepo.CreateEvent(ctx, &parcel.Event{
+		event := &parcel.Event{
 			ID:        uuid.New(),
 			ParcelID:  created.ID,
 			EventType: string(parcel.StatusReadyForPickup),
+			DeviceID:  device.IDFrom(ctx),
 			CreatedAt: now,
-		}); err != nil {
+		}
+		if err := parcelRepo.CreateEvent(ctx, event); err != nil {
+			return err
+		}
+		if err := webhooks.Publish(ctx, created, event); err != nil {
 			return err
 		}
 
@@ -197,6 +315,13 @@ func (uc *UseCase) Deposit(ctx context.Context, input DepositInput) (*DepositRes
 			PickupCode: created.PickupCode,
 			Status:     created.Status,
 		}
+
+		// Queued in the same transaction: a rolled-back deposit never reaches the receiver.
+		msg := uc.depositMessage(ctx, created, lockerEntity, comp.Size, input.RequestURL)
+		if err := outbox.Enqueue(ctx, notification.NewOutboxMessage(msg, now)); err != nil {
+			return err
+		}
+
 		logger.Info(ctx, "deposit completed", map[string]interface{}{
 			"lockerId":      input.LockerID.String(),
 			"parcelId":      created.ID.String(),
@@ -206,11 +331,99 @@ func (uc *UseCase) Deposit(ctx context.Context, input DepositInput) (*DepositRes
 		return nil
 	})
 	if err != nil {
+		if opened != nil {
+			// The door is already open but the deposit did not commit, so a parcel may sit in a
+			// compartment recorded as AVAILABLE.
+			logger.Error(ctx, "deposit rolled back after door opened", map[string]interface{}{
+				"lockerId":      input.LockerID.String(),
+				"compartmentId": opened.CompartmentID.String(),
+				"compartmentNo": opened.CompartmentNo,
+				"error":         err.Error(),
+			}, input.RequestURL)
+			uc.reconciler.Hold(ctx, *opened, nil, input.RequestURL)
+		}
 		return nil, err
 	}
 	return result, nil
 }
 
+// depositMessage builds the receiver notification for a new parcel.
+func (uc *UseCase) depositMessage(ctx context.Context, p *parcel.Parcel, l *locker.Locker, compartmentSize, requestURL string) notification.Message {
+	data := map[string]string{
+		"parcel_code": p.ParcelCode,
+		"locker_name": l.Name,
+		"size":        compartmentSize,
+		"pickup_link": uc.pickupLink(p.LockerID),
+	}
+	if p.PickupCode != nil {
+		data["pickup_code"] = *p.PickupCode
+	}
+	if p.ExpiresAt != nil {
+		data["deadline"] = notification.FormatTime(*p.ExpiresAt, uc.cfg.Timezone)
+	}
+
+	var locale notification.Locale
+	if uc.locationRepo != nil {
+		loc, err := uc.locationRepo.GetByID(ctx, l.LocationID)
+		if err != nil {
+			logger.Warn(ctx, "deposit notification location lookup failed", map[string]interface{}{
+				"parcelId":   p.ID.String(),
+				"locationId": l.LocationID.String(),
+				"error":      err.Error(),
+			}, requestURL)
+		} else {
+			data["location_name"] = loc.Name
+			locale = notification.Locale(loc.DefaultLocale)
+		}
+	}
+	return notification.Message{
+		Type:   notification.TypeParcelDeposited,
+		Phone:  p.ReceiverPhone,
+		Locale: locale,
+		Data:   data,
+	}
+}
+
+func (uc *UseCase) pickupLink(lockerID uuid.UUID) string {
+	if uc.cfg.PickupURL == "" {
+		return ""
+	}
+	link, err := url.Parse(uc.cfg.PickupURL)
+	if err != nil {
+		return uc.cfg.PickupURL
+	}
+	q := link.Query()
+	q.Set("locker_id", lockerID.String())
+	link.RawQuery = q.Encode()
+	return link.String()
+}
+
+type noopOutbox struct{}
+
+func (noopOutbox) Enqueue(ctx context.Context, msg *notification.OutboxMessage) error {
+	return nil
+}
+
+type noopDoors struct{}
+
+func (noopDoors) Open(ctx context.Context, ref hardware.CompartmentRef) error {
+	return nil
+}
+
+func (noopDoors) DoorState(ctx context.Context, ref hardware.CompartmentRef) (hardware.DoorState, error) {
+	return hardware.DoorUnknown, nil
+}
+
+func (noopDoors) Occupancy(ctx context.Context, ref hardware.CompartmentRef) (hardware.Occupancy, error) {
+	return hardware.OccupancyUnknown, nil
+}
+
+type noopWebhooks struct{}
+
+func (noopWebhooks) Publish(ctx context.Context, p *parcel.Parcel, e *parcel.Event) error {
+	return nil
+}
+
 func bestFitSizes(requested string) []string {
 	switch requested {
 	case "S":
@@ -224,28 +437,31 @@ func bestFitSizes(requested string) []string {
 	}
 }
 
-func validateDepositInput(input DepositInput) error {
+// normalizeDepositInput validates the input and rewrites phone numbers to E.164.
+func normalizeDepositInput(input DepositInput, phones phone.Normalizer) (DepositInput, error) {
 	if input.ReceiverPhone == "" || input.SenderPhone == "" {
-		return parcel.ErrInvalidRequest
+		return input, parcel.ErrInvalidRequest
+	}
+	receiverPhone, err := phones.Normalize(input.ReceiverPhone)
+	if err != nil {
+		return input, errorx.Error{Code: "INVALID_REQUEST", Message: "invalid phone format"}
 	}
-	if !isNumeric(input.ReceiverPhone) || !isNumeric(input.SenderPhone) {
-		return errorx.Error{Code: "INVALID_REQUEST", Message: "invalid phone format"}
+	senderPhone, err := phones.Normalize(input.SenderPhone)
+	if err != nil {
+		return input, errorx.Error{Code: "INVALID_REQUEST", Message: "invalid phone format"}
 	}
 	switch input.Size {
 	case "S", "M", "L":
 	default:
-		return errorx.Error{Code: "INVALID_REQUEST", Message: "invalid size"}
+		return input, errorx.Error{Code: "INVALID_REQUEST", Message: "invalid size"}
 	}
-	return nil
-}
-
-func isNumeric(value string) bool {
-	for _, r := range value {
-		if r < '0' || r > '9' {
-			return false
-		}
+	input.CarrierCode = strings.ToUpper(strings.TrimSpace(input.CarrierCode))
+	if len(input.CarrierCode) > 40 {
+		return input, errorx.Error{Code: "INVALID_REQUEST", Message: "invalid carrier_code"}
 	}
-	return true
+	input.ReceiverPhone = receiverPhone
+	input.SenderPhone = senderPhone
+	return input, nil
 }
 
 func generateCode(prefix string, digits int) string {
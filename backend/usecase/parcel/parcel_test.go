@@ -0,0 +1,207 @@
+package parcel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/compartment"
+	"smart-parcel-locker/backend/domain/hardware"
+	"smart-parcel-locker/backend/domain/locker"
+	"smart-parcel-locker/backend/domain/parcel"
+)
+
+type fakeParcels struct {
+	parcel.Repository
+	parcels  map[uuid.UUID]*parcel.Parcel
+	events   []*parcel.Event
+	eventErr error
+}
+
+func (f *fakeParcels) WithDB(*gorm.DB) parcel.Repository { return f }
+
+func (f *fakeParcels) Create(_ context.Context, p *parcel.Parcel) (*parcel.Parcel, error) {
+	cp := *p
+	f.parcels[p.ID] = &cp
+	return p, nil
+}
+
+func (f *fakeParcels) CreateEvent(_ context.Context, e *parcel.Event) error {
+	if f.eventErr != nil {
+		return f.eventErr
+	}
+	f.events = append(f.events, e)
+	return nil
+}
+
+type fakeLockers struct {
+	locker.Repository
+	lockers map[uuid.UUID]*locker.Locker
+}
+
+func (f *fakeLockers) WithDB(*gorm.DB) locker.Repository { return f }
+
+func (f *fakeLockers) GetByID(_ context.Context, id uuid.UUID) (*locker.Locker, error) {
+	l, ok := f.lockers[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	cp := *l
+	return &cp, nil
+}
+
+func (f *fakeLockers) GetByIDForShare(ctx context.Context, id uuid.UUID) (*locker.Locker, error) {
+	return f.GetByID(ctx, id)
+}
+
+type fakeCompartments struct {
+	compartment.Repository
+	comps   map[uuid.UUID]*compartment.Compartment
+	changes []*compartment.StatusChange
+}
+
+func (f *fakeCompartments) WithDB(*gorm.DB) compartment.Repository { return f }
+
+func (f *fakeCompartments) FindAvailableByLockerSizesForUpdate(_ context.Context, lockerID uuid.UUID, sizes []string) (*compartment.Compartment, error) {
+	for _, size := range sizes {
+		for _, c := range f.comps {
+			if c.LockerID == lockerID && c.Size == size && c.Status == compartment.StatusAvailable {
+				cp := *c
+				return &cp, nil
+			}
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeCompartments) GetByIDForUpdate(_ context.Context, id uuid.UUID) (*compartment.Compartment, error) {
+	c, ok := f.comps[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	cp := *c
+	return &cp, nil
+}
+
+func (f *fakeCompartments) Update(_ context.Context, c *compartment.Compartment) (*compartment.Compartment, error) {
+	cp := *c
+	f.comps[c.ID] = &cp
+	return c, nil
+}
+
+func (f *fakeCompartments) CreateStatusChange(_ context.Context, change *compartment.StatusChange) error {
+	f.changes = append(f.changes, change)
+	return nil
+}
+
+// fakeDoors opens every door unless err is set.
+type fakeDoors struct {
+	err    error
+	opened []hardware.CompartmentRef
+}
+
+func (f *fakeDoors) Open(_ context.Context, ref hardware.CompartmentRef) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.opened = append(f.opened, ref)
+	return nil
+}
+
+func (f *fakeDoors) DoorState(context.Context, hardware.CompartmentRef) (hardware.DoorState, error) {
+	return hardware.DoorUnknown, nil
+}
+
+func (f *fakeDoors) Occupancy(context.Context, hardware.CompartmentRef) (hardware.Occupancy, error) {
+	return hardware.OccupancyUnknown, nil
+}
+
+type depositFixture struct {
+	uc      *UseCase
+	parcels *fakeParcels
+	comps   *fakeCompartments
+	doors   *fakeDoors
+	input   DepositInput
+	compID  uuid.UUID
+}
+
+// newDepositFixture stores an active locker with one available S compartment.
+func newDepositFixture(t *testing.T) *depositFixture {
+	t.Helper()
+	lockerID := uuid.New()
+	compID := uuid.New()
+	f := &depositFixture{
+		parcels: &fakeParcels{parcels: map[uuid.UUID]*parcel.Parcel{}},
+		comps: &fakeCompartments{comps: map[uuid.UUID]*compartment.Compartment{
+			compID: {ID: compID, LockerID: lockerID, CompartmentNo: 1, Size: "S", Status: compartment.StatusAvailable},
+		}},
+		doors:  &fakeDoors{},
+		compID: compID,
+		input: DepositInput{
+			LockerID:      lockerID,
+			Size:          "S",
+			ReceiverPhone: "0812345678",
+			SenderPhone:   "0898765432",
+		},
+	}
+	lockers := &fakeLockers{lockers: map[uuid.UUID]*locker.Locker{
+		lockerID: {ID: lockerID, Status: locker.StatusActive},
+	}}
+	f.uc = NewUseCase(f.parcels, lockers, f.comps, nil, nil, nil, f.doors, nil, Config{})
+	return f
+}
+
+func TestDepositLeavesStateUnchangedWhenDoorFails(t *testing.T) {
+	f := newDepositFixture(t)
+	f.doors.err = errors.New("board timeout")
+
+	if _, err := f.uc.Deposit(context.Background(), f.input); err == nil {
+		t.Fatalf("expected the door failure to fail the deposit")
+	}
+	if len(f.parcels.parcels) != 0 || len(f.parcels.events) != 0 {
+		t.Fatalf("parcel recorded although the door stayed shut")
+	}
+	comp := f.comps.comps[f.compID]
+	if comp.Status != compartment.StatusAvailable || comp.ParcelID != nil {
+		t.Fatalf("compartment changed although the door stayed shut: %+v", comp)
+	}
+	if len(f.comps.changes) != 0 {
+		t.Fatalf("unexpected status changes: %+v", f.comps.changes)
+	}
+}
+
+func TestDepositHoldsCompartmentWhenNotRecordedAfterDoorOpened(t *testing.T) {
+	f := newDepositFixture(t)
+	f.parcels.eventErr = errors.New("connection reset")
+
+	if _, err := f.uc.Deposit(context.Background(), f.input); err == nil {
+		t.Fatalf("expected the failed write to fail the deposit")
+	}
+	if len(f.doors.opened) != 1 {
+		t.Fatalf("expected the door to have opened once, got %d", len(f.doors.opened))
+	}
+	if comp := f.comps.comps[f.compID]; comp.Status != compartment.StatusOutOfService {
+		t.Fatalf("expected the compartment to be held out of service, got %s", comp.Status)
+	}
+	if len(f.comps.changes) != 1 || f.comps.changes[0].Reason != compartment.ReasonUnrecordedDoorOpen {
+		t.Fatalf("expected one reconciliation status change, got %+v", f.comps.changes)
+	}
+}
+
+func TestDepositHoldsCompartmentWhenDoorOutcomeUnknown(t *testing.T) {
+	f := newDepositFixture(t)
+	f.doors.err = hardware.ErrDoorOutcomeUnknown
+
+	if _, err := f.uc.Deposit(context.Background(), f.input); !errors.Is(err, hardware.ErrDoorOutcomeUnknown) {
+		t.Fatalf("expected ErrDoorOutcomeUnknown, got %v", err)
+	}
+	if len(f.parcels.parcels) != 0 {
+		t.Fatalf("parcel recorded although the door was not confirmed open")
+	}
+	if comp := f.comps.comps[f.compID]; comp.Status != compartment.StatusOutOfService {
+		t.Fatalf("expected the compartment to be held out of service, got %s", comp.Status)
+	}
+}
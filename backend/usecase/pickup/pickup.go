@@ -2,17 +2,24 @@ package pickup
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
 	"smart-parcel-locker/backend/domain/compartment"
+	"smart-parcel-locker/backend/domain/device"
+	"smart-parcel-locker/backend/domain/hardware"
+	"smart-parcel-locker/backend/domain/locker"
 	"smart-parcel-locker/backend/domain/parcel"
 	pickupdomain "smart-parcel-locker/backend/domain/pickup"
+	"smart-parcel-locker/backend/domain/webhook"
 	"smart-parcel-locker/backend/infrastructure/database"
 	"smart-parcel-locker/backend/pkg/errorx"
 	"smart-parcel-locker/backend/pkg/logger"
+	"smart-parcel-locker/backend/pkg/phone"
+	hardwareusecase "smart-parcel-locker/backend/usecase/hardware"
 )
 
 type parcelRepository interface {
@@ -20,30 +27,67 @@ type parcelRepository interface {
 	WithDB(db *gorm.DB) parcel.Repository
 }
 
+type lockerRepository interface {
+	locker.Repository
+	WithDB(db *gorm.DB) locker.Repository
+}
+
 type compartmentRepository interface {
 	compartment.Repository
 	WithDB(db *gorm.DB) compartment.Repository
 }
 
+// Webhooks queues carrier webhook deliveries for parcel events.
+type Webhooks interface {
+	Publish(ctx context.Context, p *parcel.Parcel, e *parcel.Event) error
+}
+
+type txWebhooks interface {
+	WithDB(db *gorm.DB) webhook.Publisher
+}
+
+// Config tunes pickup session behaviour.
+type Config struct {
+	// RevokeTokenWhenCollected revokes the pickup token once no eligible parcels remain.
+	RevokeTokenWhenCollected bool
+	// Phones normalises the phone carried by older pickup tokens to E.164.
+	Phones phone.Normalizer
+}
+
 // UseCase handles pickup parcel listing.
 type UseCase struct {
 	parcelRepo      parcelRepository
 	compartmentRepo compartmentRepository
+	lockerRepo      lockerRepository
 	tokenStore      pickupdomain.TokenStore
+	webhooks        Webhooks
+	doors           hardware.LockController
+	reconciler      *hardwareusecase.Reconciler
 	tx              *database.TransactionManager
 	now             func() time.Time
+	cfg             Config
 }
 
 // NewUseCase constructs pickup use case.
 func NewUseCase(
 	parcelRepo parcel.Repository,
 	compartmentRepo compartment.Repository,
+	lockerRepo locker.Repository,
 	tokenStore pickupdomain.TokenStore,
+	webhooks Webhooks,
+	doors hardware.LockController,
 	tx *database.TransactionManager,
+	cfg Config,
 ) *UseCase {
 	if tokenStore == nil {
 		tokenStore = noopTokenStore{}
 	}
+	if webhooks == nil {
+		webhooks = noopWebhooks{}
+	}
+	if doors == nil {
+		doors = noopDoors{}
+	}
 	if tx == nil {
 		tx = database.NewTransactionManager(nil)
 	}
@@ -56,9 +100,17 @@ func NewUseCase(
 			compartment.Repository
 			WithDB(db *gorm.DB) compartment.Repository
 		}),
+		lockerRepo: lockerRepo.(interface {
+			locker.Repository
+			WithDB(db *gorm.DB) locker.Repository
+		}),
 		tokenStore: tokenStore,
+		webhooks:   webhooks,
+		doors:      doors,
+		reconciler: hardwareusecase.NewReconciler(compartmentRepo, tx),
 		tx:         tx,
 		now:        time.Now,
+		cfg:        cfg,
 	}
 }
 
@@ -74,7 +126,7 @@ func (uc *UseCase) ListParcels(ctx context.Context, token string) ([]*parcel.Par
 		}, "")
 		return nil, err
 	}
-	items, err := uc.parcelRepo.ListReadyForPickupByPhone(ctx, info.Phone)
+	items, err := uc.eligibleParcels(ctx, info)
 	if err != nil {
 		logger.Error(ctx, "pickup usecase list parcels failed unexpectedly", map[string]interface{}{
 			"receiverPhone": maskPhone(info.Phone),
@@ -90,14 +142,18 @@ func (uc *UseCase) ListParcels(ctx context.Context, token string) ([]*parcel.Par
 }
 
 type ConfirmResult struct {
-	ParcelID    uuid.UUID
-	Status      parcel.Status
-	PickedUpAt  time.Time
-	OverdueDays int
-	OverdueFee  int
+	ParcelID     uuid.UUID
+	Status       parcel.Status
+	PickedUpAt   time.Time
+	OverdueDays  int
+	OverdueFee   int
+	TokenRevoked bool
 }
 
-// ConfirmPickup updates parcel and compartment status after pickup.
+// ConfirmPickup opens the compartment door and marks the parcel picked up. The door is opened
+// once every check has passed and before anything is written, so a door that fails to open
+// leaves the parcel waiting; a pickup that fails to commit after that puts the compartment out
+// of service until staff check it.
 func (uc *UseCase) ConfirmPickup(ctx context.Context, token string, parcelID uuid.UUID) (*ConfirmResult, error) {
 	if parcelID == uuid.Nil {
 		logger.Warn(ctx, "pickup usecase confirm invalid parcel_id", map[string]interface{}{
@@ -118,12 +174,19 @@ func (uc *UseCase) ConfirmPickup(ctx context.Context, token string, parcelID uui
 	}
 
 	var result *ConfirmResult
+	var opened *hardware.CompartmentRef
 	err = uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
 		var parcelRepo parcel.Repository = uc.parcelRepo
+		var lockerRepo locker.Repository = uc.lockerRepo
 		var compartmentRepo compartment.Repository = uc.compartmentRepo
+		var webhooks Webhooks = uc.webhooks
 		if tx != nil {
 			parcelRepo = uc.parcelRepo.WithDB(tx)
+			lockerRepo = uc.lockerRepo.WithDB(tx)
 			compartmentRepo = uc.compartmentRepo.WithDB(tx)
+			if w, ok := uc.webhooks.(txWebhooks); ok {
+				webhooks = w.WithDB(tx)
+			}
 		}
 
 		entity, err := parcelRepo.GetByIDForUpdate(ctx, parcelID)
@@ -155,6 +218,33 @@ func (uc *UseCase) ConfirmPickup(ctx context.Context, token string, parcelID uui
 			}, "")
 			return errorx.Error{Code: "FORBIDDEN", Message: "parcel does not belong to token"}
 		}
+		if !info.AllowsLocker(entity.LockerID) {
+			logger.Warn(ctx, "pickup usecase confirm locker out of token scope", map[string]interface{}{
+				"parcelId": parcelID.String(),
+				"lockerId": entity.LockerID.String(),
+			}, "")
+			return pickupdomain.ErrLockerScope
+		}
+		if d, ok := device.FromContext(ctx); ok && d.LockerID != entity.LockerID {
+			logger.Warn(ctx, "pickup usecase confirm device locker mismatch", map[string]interface{}{
+				"parcelId": parcelID.String(),
+				"deviceId": d.ID.String(),
+				"lockerId": entity.LockerID.String(),
+			}, "")
+			return device.ErrLockerMismatch
+		}
+		lockerEntity, err := lockerRepo.GetByIDForShare(ctx, entity.LockerID)
+		if err != nil {
+			return err
+		}
+		if err := lockerEntity.CheckPickup(); err != nil {
+			logger.Warn(ctx, "pickup usecase confirm locker unavailable", map[string]interface{}{
+				"parcelId": parcelID.String(),
+				"lockerId": entity.LockerID.String(),
+				"status":   lockerEntity.Status,
+			}, "")
+			return err
+		}
 		if entity.CompartmentID == nil {
 			logger.Warn(ctx, "pickup usecase confirm missing compartment", map[string]interface{}{
 				"parcelId": parcelID.String(),
@@ -188,6 +278,21 @@ func (uc *UseCase) ConfirmPickup(ctx context.Context, token string, parcelID uui
 			return err
 		}
 
+		ref := hardware.CompartmentRef{LockerID: comp.LockerID, CompartmentID: comp.ID, CompartmentNo: comp.CompartmentNo}
+		if err := uc.doors.Open(ctx, ref); err != nil {
+			if errors.Is(err, hardware.ErrDoorOutcomeUnknown) {
+				// The board may have opened the door without answering.
+				opened = &ref
+			}
+			logger.Error(ctx, "pickup usecase confirm door open failed", map[string]interface{}{
+				"parcelId":      parcelID.String(),
+				"compartmentId": comp.ID.String(),
+				"error":         err.Error(),
+			}, "")
+			return err
+		}
+		opened = &ref
+
 		entity.Status = parcel.StatusPickedUp
 		entity.PickedUpAt = &now
 		if _, err := parcelRepo.Update(ctx, entity); err != nil {
@@ -205,18 +310,27 @@ func (uc *UseCase) ConfirmPickup(ctx context.Context, token string, parcelID uui
 			}, "")
 			return err
 		}
-		if err := parcelRepo.CreateEvent(ctx, &parcel.Event{
+		event := &parcel.Event{
 			ID:        uuid.New(),
 			ParcelID:  entity.ID,
 			EventType: string(parcel.StatusPickedUp),
+			DeviceID:  device.IDFrom(ctx),
 			CreatedAt: now,
-		}); err != nil {
+		}
+		if err := parcelRepo.CreateEvent(ctx, event); err != nil {
 			logger.Error(ctx, "pickup usecase confirm create event failed unexpectedly", map[string]interface{}{
 				"parcelId": parcelID.String(),
 				"error":    err.Error(),
 			}, "")
 			return err
 		}
+		if err := webhooks.Publish(ctx, entity, event); err != nil {
+			logger.Error(ctx, "pickup usecase confirm queue webhooks failed unexpectedly", map[string]interface{}{
+				"parcelId": parcelID.String(),
+				"error":    err.Error(),
+			}, "")
+			return err
+		}
 
 		result = &ConfirmResult{
 			ParcelID:    entity.ID,
@@ -233,17 +347,118 @@ func (uc *UseCase) ConfirmPickup(ctx context.Context, token string, parcelID uui
 		return nil
 	})
 	if err != nil {
+		if opened != nil {
+			// The receiver may already have taken the parcel although it is still recorded as
+			// waiting.
+			logger.Error(ctx, "pickup usecase confirm rolled back after door opened", map[string]interface{}{
+				"parcelId":      parcelID.String(),
+				"compartmentId": opened.CompartmentID.String(),
+				"compartmentNo": opened.CompartmentNo,
+				"error":         err.Error(),
+			}, "")
+			uc.reconciler.Hold(ctx, *opened, map[string]interface{}{"parcelId": parcelID.String()}, "")
+		}
 		return nil, err
 	}
+	if uc.cfg.RevokeTokenWhenCollected {
+		result.TokenRevoked = uc.revokeIfCollected(ctx, token, info)
+	}
 	return result, nil
 }
 
+// Logout revokes a pickup token so the session cannot be reused.
+func (uc *UseCase) Logout(ctx context.Context, token string) error {
+	if token == "" {
+		logger.Warn(ctx, "pickup usecase logout token missing", map[string]interface{}{}, "")
+		return pickupdomain.ErrInvalidToken
+	}
+	if err := uc.tokenStore.Revoke(ctx, token); err != nil {
+		logger.Error(ctx, "pickup usecase logout revoke failed unexpectedly", map[string]interface{}{
+			"error": err.Error(),
+		}, "")
+		return err
+	}
+	logger.Info(ctx, "pickup usecase logout completed", map[string]interface{}{}, "")
+	return nil
+}
+
+// revokeIfCollected revokes the token when no eligible parcels remain. Failures are
+// logged rather than returned because the pickup itself has already committed.
+func (uc *UseCase) revokeIfCollected(ctx context.Context, token string, info pickupdomain.TokenInfo) bool {
+	remaining, err := uc.eligibleParcels(ctx, info)
+	if err != nil {
+		logger.Error(ctx, "pickup usecase auto revoke list failed unexpectedly", map[string]interface{}{
+			"phone": maskPhone(info.Phone),
+			"error": err.Error(),
+		}, "")
+		return false
+	}
+	if len(remaining) > 0 {
+		return false
+	}
+	if err := uc.tokenStore.Revoke(ctx, token); err != nil {
+		logger.Error(ctx, "pickup usecase auto revoke failed unexpectedly", map[string]interface{}{
+			"phone": maskPhone(info.Phone),
+			"error": err.Error(),
+		}, "")
+		return false
+	}
+	logger.Info(ctx, "pickup usecase token revoked after collection", map[string]interface{}{
+		"phone": maskPhone(info.Phone),
+	}, "")
+	return true
+}
+
+// eligibleParcels lists parcels the token holder may collect, honouring locker scope.
+func (uc *UseCase) eligibleParcels(ctx context.Context, info pickupdomain.TokenInfo) ([]*parcel.Parcel, error) {
+	items, err := uc.parcelRepo.ListReadyForPickupByPhone(ctx, info.Phone)
+	if err != nil {
+		return nil, err
+	}
+	if info.LockerID == nil {
+		return items, nil
+	}
+	scoped := make([]*parcel.Parcel, 0, len(items))
+	for _, p := range items {
+		if info.AllowsLocker(p.LockerID) {
+			scoped = append(scoped, p)
+		}
+	}
+	return scoped, nil
+}
+
+type noopDoors struct{}
+
+func (noopDoors) Open(ctx context.Context, ref hardware.CompartmentRef) error {
+	return nil
+}
+
+func (noopDoors) DoorState(ctx context.Context, ref hardware.CompartmentRef) (hardware.DoorState, error) {
+	return hardware.DoorUnknown, nil
+}
+
+func (noopDoors) Occupancy(ctx context.Context, ref hardware.CompartmentRef) (hardware.Occupancy, error) {
+	return hardware.OccupancyUnknown, nil
+}
+
+type noopWebhooks struct{}
+
+func (noopWebhooks) Publish(ctx context.Context, p *parcel.Parcel, e *parcel.Event) error {
+	return nil
+}
+
 type noopTokenStore struct{}
 
-func (noopTokenStore) Store(token string, phone string, expiresAt time.Time) {}
+func (noopTokenStore) Issue(ctx context.Context, info pickupdomain.TokenInfo) (string, error) {
+	return "", nil
+}
+
+func (noopTokenStore) Get(ctx context.Context, token string) (pickupdomain.TokenInfo, bool, error) {
+	return pickupdomain.TokenInfo{}, false, nil
+}
 
-func (noopTokenStore) Get(token string) (pickupdomain.TokenInfo, bool) {
-	return pickupdomain.TokenInfo{}, false
+func (noopTokenStore) Revoke(ctx context.Context, token string) error {
+	return nil
 }
 
 func (uc *UseCase) validateToken(ctx context.Context, token string) (pickupdomain.TokenInfo, error) {
@@ -251,7 +466,13 @@ func (uc *UseCase) validateToken(ctx context.Context, token string) (pickupdomai
 		logger.Warn(ctx, "pickup usecase token missing", map[string]interface{}{}, "")
 		return pickupdomain.TokenInfo{}, pickupdomain.ErrInvalidToken
 	}
-	info, ok := uc.tokenStore.Get(token)
+	info, ok, err := uc.tokenStore.Get(ctx, token)
+	if err != nil {
+		logger.Error(ctx, "pickup usecase token lookup failed unexpectedly", map[string]interface{}{
+			"error": err.Error(),
+		}, "")
+		return pickupdomain.TokenInfo{}, err
+	}
 	if !ok {
 		logger.Warn(ctx, "pickup usecase token invalid", map[string]interface{}{}, "")
 		return pickupdomain.TokenInfo{}, pickupdomain.ErrInvalidToken
@@ -262,6 +483,10 @@ func (uc *UseCase) validateToken(ctx context.Context, token string) (pickupdomai
 		}, "")
 		return pickupdomain.TokenInfo{}, pickupdomain.ErrTokenExpired
 	}
+	// Tokens issued before phone normalisation may still carry a raw number.
+	if normalized, err := uc.cfg.Phones.Normalize(info.Phone); err == nil {
+		info.Phone = normalized
+	}
 	return info, nil
 }
 
@@ -0,0 +1,302 @@
+package pickup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/compartment"
+	"smart-parcel-locker/backend/domain/hardware"
+	"smart-parcel-locker/backend/domain/locker"
+	"smart-parcel-locker/backend/domain/parcel"
+	pickupdomain "smart-parcel-locker/backend/domain/pickup"
+)
+
+const testPhone = "+66812345678"
+
+type fakeParcels struct {
+	parcel.Repository
+	parcels  map[uuid.UUID]*parcel.Parcel
+	events   []*parcel.Event
+	eventErr error
+}
+
+func (f *fakeParcels) WithDB(*gorm.DB) parcel.Repository { return f }
+
+func (f *fakeParcels) GetByIDForUpdate(_ context.Context, id uuid.UUID) (*parcel.Parcel, error) {
+	p, ok := f.parcels[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	cp := *p
+	return &cp, nil
+}
+
+func (f *fakeParcels) Update(_ context.Context, p *parcel.Parcel) (*parcel.Parcel, error) {
+	cp := *p
+	f.parcels[p.ID] = &cp
+	return p, nil
+}
+
+func (f *fakeParcels) CreateEvent(_ context.Context, e *parcel.Event) error {
+	if f.eventErr != nil {
+		return f.eventErr
+	}
+	f.events = append(f.events, e)
+	return nil
+}
+
+func (f *fakeParcels) ListReadyForPickupByPhone(_ context.Context, phone string) ([]*parcel.Parcel, error) {
+	var out []*parcel.Parcel
+	for _, p := range f.parcels {
+		if p.ReceiverPhone == phone && p.Status == parcel.StatusReadyForPickup {
+			cp := *p
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+
+type fakeCompartments struct {
+	compartment.Repository
+	comps   map[uuid.UUID]*compartment.Compartment
+	changes []*compartment.StatusChange
+}
+
+func (f *fakeCompartments) WithDB(*gorm.DB) compartment.Repository { return f }
+
+func (f *fakeCompartments) GetByIDForUpdate(_ context.Context, id uuid.UUID) (*compartment.Compartment, error) {
+	c, ok := f.comps[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	cp := *c
+	return &cp, nil
+}
+
+func (f *fakeCompartments) Update(_ context.Context, c *compartment.Compartment) (*compartment.Compartment, error) {
+	cp := *c
+	f.comps[c.ID] = &cp
+	return c, nil
+}
+
+func (f *fakeCompartments) CreateStatusChange(_ context.Context, change *compartment.StatusChange) error {
+	f.changes = append(f.changes, change)
+	return nil
+}
+
+type fakeLockers struct {
+	locker.Repository
+	lockers map[uuid.UUID]*locker.Locker
+}
+
+func (f *fakeLockers) WithDB(*gorm.DB) locker.Repository { return f }
+
+func (f *fakeLockers) GetByIDForShare(_ context.Context, id uuid.UUID) (*locker.Locker, error) {
+	l, ok := f.lockers[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	cp := *l
+	return &cp, nil
+}
+
+type fakeTokens struct {
+	tokens  map[string]pickupdomain.TokenInfo
+	revoked []string
+}
+
+func (f *fakeTokens) Issue(context.Context, pickupdomain.TokenInfo) (string, error) {
+	return "", errors.New("not used")
+}
+
+func (f *fakeTokens) Get(_ context.Context, token string) (pickupdomain.TokenInfo, bool, error) {
+	info, ok := f.tokens[token]
+	return info, ok, nil
+}
+
+func (f *fakeTokens) Revoke(_ context.Context, token string) error {
+	delete(f.tokens, token)
+	f.revoked = append(f.revoked, token)
+	return nil
+}
+
+// fakeDoors opens every door unless err is set.
+type fakeDoors struct {
+	err    error
+	opened []hardware.CompartmentRef
+}
+
+func (f *fakeDoors) Open(_ context.Context, ref hardware.CompartmentRef) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.opened = append(f.opened, ref)
+	return nil
+}
+
+func (f *fakeDoors) DoorState(context.Context, hardware.CompartmentRef) (hardware.DoorState, error) {
+	return hardware.DoorUnknown, nil
+}
+
+func (f *fakeDoors) Occupancy(context.Context, hardware.CompartmentRef) (hardware.Occupancy, error) {
+	return hardware.OccupancyUnknown, nil
+}
+
+type pickupFixture struct {
+	uc       *UseCase
+	parcels  *fakeParcels
+	comps    *fakeCompartments
+	tokens   *fakeTokens
+	doors    *fakeDoors
+	lockerID uuid.UUID
+}
+
+// newFixture stores one locker with a waiting parcel per compartment and a token for testPhone
+// scoped to scope, or unscoped when scope is nil.
+func newFixture(t *testing.T, parcelCount int, scope *uuid.UUID, cfg Config) (*pickupFixture, []*parcel.Parcel) {
+	t.Helper()
+	lockerID := uuid.New()
+	f := &pickupFixture{
+		parcels:  &fakeParcels{parcels: map[uuid.UUID]*parcel.Parcel{}},
+		comps:    &fakeCompartments{comps: map[uuid.UUID]*compartment.Compartment{}},
+		tokens:   &fakeTokens{tokens: map[string]pickupdomain.TokenInfo{}},
+		doors:    &fakeDoors{},
+		lockerID: lockerID,
+	}
+	var waiting []*parcel.Parcel
+	deposited := time.Now().Add(-time.Hour)
+	for i := 0; i < parcelCount; i++ {
+		compID := uuid.New()
+		p := &parcel.Parcel{
+			ID:            uuid.New(),
+			LockerID:      lockerID,
+			CompartmentID: &compID,
+			ReceiverPhone: testPhone,
+			Status:        parcel.StatusReadyForPickup,
+			DepositedAt:   &deposited,
+		}
+		f.parcels.parcels[p.ID] = p
+		f.comps.comps[compID] = &compartment.Compartment{
+			ID: compID, LockerID: lockerID, CompartmentNo: i + 1, Size: "S",
+			Status: compartment.StatusOccupied, ParcelID: &p.ID,
+		}
+		waiting = append(waiting, p)
+	}
+	f.tokens.tokens["token"] = pickupdomain.TokenInfo{Phone: testPhone, ExpiresAt: time.Now().Add(time.Hour), LockerID: scope}
+	lockers := &fakeLockers{lockers: map[uuid.UUID]*locker.Locker{
+		lockerID: {ID: lockerID, Status: locker.StatusActive},
+	}}
+	f.uc = NewUseCase(f.parcels, f.comps, lockers, f.tokens, nil, f.doors, nil, cfg)
+	return f, waiting
+}
+
+func TestLogoutRevokesToken(t *testing.T) {
+	f, _ := newFixture(t, 0, nil, Config{})
+
+	if err := f.uc.Logout(context.Background(), ""); !errors.Is(err, pickupdomain.ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken for a missing token, got %v", err)
+	}
+	if err := f.uc.Logout(context.Background(), "token"); err != nil {
+		t.Fatalf("logout: %v", err)
+	}
+	if len(f.tokens.revoked) != 1 || f.tokens.revoked[0] != "token" {
+		t.Fatalf("token not revoked: %v", f.tokens.revoked)
+	}
+	if _, err := f.uc.ListParcels(context.Background(), "token"); !errors.Is(err, pickupdomain.ErrInvalidToken) {
+		t.Fatalf("revoked token still accepted: %v", err)
+	}
+}
+
+func TestConfirmPickupRefusesParcelOutsideTokenScope(t *testing.T) {
+	otherLocker := uuid.New()
+	f, waiting := newFixture(t, 1, &otherLocker, Config{})
+
+	_, err := f.uc.ConfirmPickup(context.Background(), "token", waiting[0].ID)
+	if !errors.Is(err, pickupdomain.ErrLockerScope) {
+		t.Fatalf("expected ErrLockerScope, got %v", err)
+	}
+	if f.parcels.parcels[waiting[0].ID].Status != parcel.StatusReadyForPickup {
+		t.Fatalf("parcel outside the token scope was picked up")
+	}
+	items, err := f.uc.ListParcels(context.Background(), "token")
+	if err != nil || len(items) != 0 {
+		t.Fatalf("expected no parcels listed outside the scope, got %d (%v)", len(items), err)
+	}
+}
+
+func TestConfirmPickupRevokesTokenOnceCollected(t *testing.T) {
+	f, waiting := newFixture(t, 2, nil, Config{RevokeTokenWhenCollected: true})
+
+	first, err := f.uc.ConfirmPickup(context.Background(), "token", waiting[0].ID)
+	if err != nil {
+		t.Fatalf("first pickup: %v", err)
+	}
+	if first.TokenRevoked || len(f.tokens.revoked) != 0 {
+		t.Fatalf("token revoked while a parcel still waits")
+	}
+	last, err := f.uc.ConfirmPickup(context.Background(), "token", waiting[1].ID)
+	if err != nil {
+		t.Fatalf("last pickup: %v", err)
+	}
+	if !last.TokenRevoked || len(f.tokens.revoked) != 1 {
+		t.Fatalf("token not revoked after the last parcel was collected")
+	}
+}
+
+func TestConfirmPickupLeavesStateUnchangedWhenDoorFails(t *testing.T) {
+	f, waiting := newFixture(t, 1, nil, Config{RevokeTokenWhenCollected: true})
+	f.doors.err = errors.New("board timeout")
+
+	if _, err := f.uc.ConfirmPickup(context.Background(), "token", waiting[0].ID); err == nil {
+		t.Fatalf("expected the door failure to fail the pickup")
+	}
+	if got := f.parcels.parcels[waiting[0].ID]; got.Status != parcel.StatusReadyForPickup || got.PickedUpAt != nil {
+		t.Fatalf("parcel changed although the door stayed shut: %+v", got)
+	}
+	comp := f.comps.comps[*waiting[0].CompartmentID]
+	if comp.Status != compartment.StatusOccupied || comp.ParcelID == nil || *comp.ParcelID != waiting[0].ID {
+		t.Fatalf("compartment changed although the door stayed shut: %+v", comp)
+	}
+	if len(f.parcels.events) != 0 || len(f.comps.changes) != 0 || len(f.tokens.revoked) != 0 {
+		t.Fatalf("unexpected writes: %d events, %d status changes, %d revocations", len(f.parcels.events), len(f.comps.changes), len(f.tokens.revoked))
+	}
+}
+
+func TestConfirmPickupHoldsCompartmentWhenNotRecordedAfterDoorOpened(t *testing.T) {
+	f, waiting := newFixture(t, 1, nil, Config{})
+	f.parcels.eventErr = errors.New("connection reset")
+
+	if _, err := f.uc.ConfirmPickup(context.Background(), "token", waiting[0].ID); err == nil {
+		t.Fatalf("expected the failed write to fail the pickup")
+	}
+	if len(f.doors.opened) != 1 {
+		t.Fatalf("expected the door to have opened once, got %d", len(f.doors.opened))
+	}
+	comp := f.comps.comps[*waiting[0].CompartmentID]
+	if comp.Status != compartment.StatusOutOfService {
+		t.Fatalf("expected the compartment to be held out of service, got %s", comp.Status)
+	}
+	if len(f.comps.changes) != 1 || f.comps.changes[0].Reason != compartment.ReasonUnrecordedDoorOpen {
+		t.Fatalf("expected one reconciliation status change, got %+v", f.comps.changes)
+	}
+}
+
+func TestConfirmPickupHoldsCompartmentWhenDoorOutcomeUnknown(t *testing.T) {
+	f, waiting := newFixture(t, 1, nil, Config{})
+	f.doors.err = hardware.ErrDoorOutcomeUnknown
+
+	if _, err := f.uc.ConfirmPickup(context.Background(), "token", waiting[0].ID); !errors.Is(err, hardware.ErrDoorOutcomeUnknown) {
+		t.Fatalf("expected ErrDoorOutcomeUnknown, got %v", err)
+	}
+	if got := f.parcels.parcels[waiting[0].ID]; got.Status != parcel.StatusReadyForPickup {
+		t.Fatalf("parcel marked collected although the door was not confirmed open: %s", got.Status)
+	}
+	if comp := f.comps.comps[*waiting[0].CompartmentID]; comp.Status != compartment.StatusOutOfService {
+		t.Fatalf("expected the compartment to be held out of service, got %s", comp.Status)
+	}
+}
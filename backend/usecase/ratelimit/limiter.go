@@ -0,0 +1,136 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	ratelimitdomain "smart-parcel-locker/backend/domain/ratelimit"
+	"smart-parcel-locker/backend/pkg/logger"
+)
+
+const (
+	EndpointOTPRequest = "otp_request"
+	EndpointOTPVerify  = "otp_verify"
+	EndpointDeposit    = "deposit"
+	EndpointTracking   = "tracking"
+	EndpointAdminLogin = "admin_login"
+)
+
+// Policy lists the rules enforced for an endpoint, one per dimension.
+type Policy struct {
+	Endpoint string
+	Rules    map[ratelimitdomain.Dimension]ratelimitdomain.Rule
+}
+
+// Limiter checks endpoint policies against a shared store.
+type Limiter struct {
+	store    ratelimitdomain.Store
+	policies map[string]Policy
+	now      func() time.Time
+}
+
+// NewLimiter constructs a limiter for the given policies.
+func NewLimiter(store ratelimitdomain.Store, policies []Policy) *Limiter {
+	byEndpoint := make(map[string]Policy, len(policies))
+	for _, p := range policies {
+		byEndpoint[p.Endpoint] = p
+	}
+	return &Limiter{
+		store:    store,
+		policies: byEndpoint,
+		now:      time.Now,
+	}
+}
+
+// Check takes one hit for every configured dimension of the endpoint and returns the most
+// restrictive decision. Hits are only recorded when every dimension allows the request, so a
+// request denied on one dimension does not use up the others. ok is false when no rule applied.
+func (l *Limiter) Check(ctx context.Context, endpoint string, subjects map[ratelimitdomain.Dimension]string) (decision ratelimitdomain.Decision, ok bool) {
+	if l == nil || l.store == nil {
+		return ratelimitdomain.Decision{}, false
+	}
+	policy, found := l.policies[endpoint]
+	if !found {
+		return ratelimitdomain.Decision{}, false
+	}
+	var hits []ratelimitdomain.Hit
+	for dimension, rule := range policy.Rules {
+		if !rule.Enabled() {
+			continue
+		}
+		subject := subjects[dimension]
+		if subject == "" {
+			continue
+		}
+		hits = append(hits, ratelimitdomain.Hit{Key: endpoint + ":" + string(dimension) + ":" + subject, Rule: rule})
+	}
+	if len(hits) == 0 {
+		return ratelimitdomain.Decision{}, false
+	}
+	decisions, err := l.store.Take(ctx, hits, l.now())
+	if err != nil {
+		// Fail open: a broken limiter store must not take the service down.
+		logger.Error(ctx, "rate limit store failed unexpectedly", map[string]interface{}{
+			"endpoint": endpoint,
+			"error":    err.Error(),
+		}, "")
+		return ratelimitdomain.Decision{}, false
+	}
+	for _, current := range decisions {
+		if !ok || moreRestrictive(current, decision) {
+			decision = current
+			ok = true
+		}
+	}
+	if ok && !decision.Allowed {
+		logger.Warn(ctx, "rate limit exceeded", map[string]interface{}{
+			"endpoint": endpoint,
+			"limit":    decision.Limit,
+			"resetAt":  decision.ResetAt,
+		}, "")
+	}
+	return decision, ok
+}
+
+// Purge removes hits older than the given age.
+func (l *Limiter) Purge(ctx context.Context, olderThan time.Duration) error {
+	if l == nil || l.store == nil {
+		return nil
+	}
+	removed, err := l.store.Purge(ctx, l.now().Add(-olderThan))
+	if err != nil {
+		return err
+	}
+	if removed > 0 {
+		logger.Info(ctx, "rate limit hits purged", map[string]interface{}{
+			"removed": removed,
+		}, "")
+	}
+	return nil
+}
+
+// MaxWindow returns the longest window across all policies.
+func (l *Limiter) MaxWindow() time.Duration {
+	var longest time.Duration
+	if l == nil {
+		return longest
+	}
+	for _, p := range l.policies {
+		for _, r := range p.Rules {
+			if r.Window > longest {
+				longest = r.Window
+			}
+		}
+	}
+	return longest
+}
+
+func moreRestrictive(a, b ratelimitdomain.Decision) bool {
+	if a.Allowed != b.Allowed {
+		return !a.Allowed
+	}
+	if a.Remaining != b.Remaining {
+		return a.Remaining < b.Remaining
+	}
+	return a.ResetAt.After(b.ResetAt)
+}
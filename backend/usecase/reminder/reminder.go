@@ -0,0 +1,332 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/audit"
+	"smart-parcel-locker/backend/domain/location"
+	"smart-parcel-locker/backend/domain/notification"
+	"smart-parcel-locker/backend/domain/parcel"
+	"smart-parcel-locker/backend/domain/reminder"
+	"smart-parcel-locker/backend/infrastructure/database"
+	"smart-parcel-locker/backend/pkg/logger"
+	auditusecase "smart-parcel-locker/backend/usecase/audit"
+)
+
+// Outbox queues notifications for the background dispatcher.
+type Outbox interface {
+	Enqueue(ctx context.Context, msg *notification.OutboxMessage) error
+}
+
+type txOutbox interface {
+	WithDB(db *gorm.DB) notification.OutboxRepository
+}
+
+type txRepository interface {
+	WithDB(db *gorm.DB) reminder.Repository
+}
+
+// UseCase sends pickup reminders and manages per-location reminder policies.
+type UseCase struct {
+	repo         reminder.Repository
+	locationRepo location.Repository
+	outbox       Outbox
+	audit        audit.Recorder
+	tx           *database.TransactionManager
+	defaultRules []reminder.Rule
+	timezone     *time.Location
+	now          func() time.Time
+}
+
+// NewUseCase constructs the reminder use case. defaultRules apply to locations without a policy.
+func NewUseCase(
+	repo reminder.Repository,
+	locationRepo location.Repository,
+	outbox Outbox,
+	recorder audit.Recorder,
+	tx *database.TransactionManager,
+	defaultRules []reminder.Rule,
+	timezone *time.Location,
+) *UseCase {
+	if tx == nil {
+		tx = database.NewTransactionManager(nil)
+	}
+	return &UseCase{
+		repo:         repo,
+		locationRepo: locationRepo,
+		outbox:       outbox,
+		audit:        recorder,
+		tx:           tx,
+		defaultRules: defaultRules,
+		timezone:     timezone,
+		now:          time.Now,
+	}
+}
+
+// Run queues every reminder that is due and not yet recorded. It is safe to run on several
+// replicas: each reminder is claimed in parcel_reminders in the same transaction that queues it.
+func (uc *UseCase) Run(ctx context.Context) error {
+	policies, err := uc.repo.ListPolicies(ctx)
+	if err != nil {
+		logger.Error(ctx, "reminder usecase list policies failed unexpectedly", map[string]interface{}{
+			"error": err.Error(),
+		}, "")
+		return err
+	}
+	now := uc.now()
+	var found, queued, failed int
+	for _, query := range uc.dueQueries(policies, now) {
+		candidates, err := uc.repo.ListDue(ctx, query)
+		if err != nil {
+			logger.Error(ctx, "reminder usecase list due failed unexpectedly", map[string]interface{}{
+				"reminderKey": query.Rule.Key(),
+				"error":       err.Error(),
+			}, "")
+			return err
+		}
+		found += len(candidates)
+		for _, c := range candidates {
+			if err := uc.queue(ctx, c, query.Rule, now); err != nil {
+				failed++
+				continue
+			}
+			queued++
+		}
+	}
+
+	logger.Info(ctx, "reminder usecase run completed", map[string]interface{}{
+		"candidates": found,
+		"queued":     queued,
+		"failed":     failed,
+	}, "")
+	return nil
+}
+
+// dueQueries builds one query per distinct rule, covering the locations whose policy lists it and,
+// for default rules, the locations without a policy.
+func (uc *UseCase) dueQueries(policies []reminder.Policy, now time.Time) []reminder.DueQuery {
+	policyLocations := make([]uuid.UUID, 0, len(policies))
+	byKey := map[string]*reminder.DueQuery{}
+	var keys []string
+	queryFor := func(rule reminder.Rule) *reminder.DueQuery {
+		q, ok := byKey[rule.Key()]
+		if !ok {
+			q = &reminder.DueQuery{Rule: rule, Now: now}
+			byKey[rule.Key()] = q
+			keys = append(keys, rule.Key())
+		}
+		return q
+	}
+	for _, p := range policies {
+		policyLocations = append(policyLocations, p.LocationID)
+		for _, rule := range p.Rules {
+			q := queryFor(rule)
+			q.LocationIDs = append(q.LocationIDs, p.LocationID)
+		}
+	}
+	for _, rule := range uc.defaultRules {
+		queryFor(rule).Default = true
+	}
+
+	queries := make([]reminder.DueQuery, 0, len(keys))
+	for _, key := range keys {
+		q := byKey[key]
+		q.PolicyLocationIDs = policyLocations
+		queries = append(queries, *q)
+	}
+	return queries
+}
+
+func (uc *UseCase) queue(ctx context.Context, c reminder.Candidate, rule reminder.Rule, now time.Time) error {
+	key := rule.Key()
+	queued := false
+	err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		var repo reminder.Repository = uc.repo
+		var outbox Outbox = uc.outbox
+		if tx != nil {
+			if r, ok := uc.repo.(txRepository); ok {
+				repo = r.WithDB(tx)
+			}
+			if o, ok := uc.outbox.(txOutbox); ok {
+				outbox = o.WithDB(tx)
+			}
+		}
+
+		claimed, err := repo.Claim(ctx, c.Parcel.ID, key, now)
+		if err != nil || !claimed {
+			// Not claimed means another replica already queued it.
+			return err
+		}
+		queued = true
+		return outbox.Enqueue(ctx, notification.NewOutboxMessage(notification.Message{
+			Type:   notification.TypeParcelReminder,
+			Phone:  c.Parcel.ReceiverPhone,
+			Locale: c.Locale,
+			Data:   reminderData(c, rule, uc.timezone),
+		}, now))
+	})
+	if err != nil {
+		logger.Error(ctx, "reminder usecase queue failed unexpectedly", map[string]interface{}{
+			"parcelId":    c.Parcel.ID.String(),
+			"reminderKey": key,
+			"error":       err.Error(),
+		}, "")
+		return err
+	}
+	if queued {
+		logger.Info(ctx, "reminder usecase queued", map[string]interface{}{
+			"parcelId":      c.Parcel.ID.String(),
+			"receiverPhone": c.Parcel.ReceiverPhone,
+			"reminderKey":   key,
+		}, "")
+	}
+	return nil
+}
+
+func reminderData(c reminder.Candidate, rule reminder.Rule, loc *time.Location) map[string]string {
+	p := c.Parcel
+	data := map[string]string{
+		"parcel_code": p.ParcelCode,
+		"locker_name": c.LockerName,
+		"size":        p.Size,
+	}
+	if p.PickupCode != nil {
+		data["pickup_code"] = *p.PickupCode
+	}
+	if rule.Anchor == reminder.AnchorGraceEnd && p.DepositedAt != nil {
+		data["fees_from"] = notification.FormatTime(p.DepositedAt.Add(parcel.GracePeriod), loc)
+	}
+	if p.ExpiresAt != nil {
+		data["deadline"] = notification.FormatTime(*p.ExpiresAt, loc)
+	}
+	return data
+}
+
+// EffectivePolicy is the rule set applied to a location.
+type EffectivePolicy struct {
+	LocationID uuid.UUID
+	Rules      []reminder.Rule
+	// Default is true when the location has no policy of its own.
+	Default   bool
+	UpdatedAt *time.Time
+}
+
+// GetPolicy returns the reminder rules that apply to a location.
+func (uc *UseCase) GetPolicy(ctx context.Context, locationID uuid.UUID) (*EffectivePolicy, error) {
+	if _, err := uc.locationRepo.GetByID(ctx, locationID); err != nil {
+		return nil, err
+	}
+	policy, err := uc.repo.GetPolicy(ctx, locationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &EffectivePolicy{LocationID: locationID, Rules: uc.defaultRules, Default: true}, nil
+		}
+		return nil, err
+	}
+	return &EffectivePolicy{LocationID: locationID, Rules: policy.Rules, UpdatedAt: policy.UpdatedAt}, nil
+}
+
+// SetPolicy stores location-specific rules. An empty spec disables reminders for the location.
+func (uc *UseCase) SetPolicy(ctx context.Context, locationID uuid.UUID, spec string) (*EffectivePolicy, error) {
+	rules, err := reminder.ParseRules(spec)
+	if err != nil {
+		return nil, reminder.ErrInvalidPolicy
+	}
+	if _, err := uc.locationRepo.GetByID(ctx, locationID); err != nil {
+		return nil, err
+	}
+	var policy *reminder.Policy
+	err = uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo, recorder := uc.reposFor(tx)
+		before, err := uc.storedPolicy(ctx, repo, locationID)
+		if err != nil {
+			return err
+		}
+		if policy, err = repo.UpsertPolicy(ctx, &reminder.Policy{
+			LocationID: locationID,
+			Rules:      rules,
+		}); err != nil {
+			return err
+		}
+		return recorder.Record(ctx, audit.Change{
+			Action:     "reminder_policy.set",
+			EntityType: audit.EntityReminderPolicy,
+			EntityID:   locationID.String(),
+			Before:     before,
+			After:      policySnapshot(policy),
+		})
+	})
+	if err != nil {
+		logger.Error(ctx, "reminder usecase upsert policy failed unexpectedly", map[string]interface{}{
+			"locationId": locationID.String(),
+			"error":      err.Error(),
+		}, "")
+		return nil, err
+	}
+	logger.Info(ctx, "reminder usecase policy saved", map[string]interface{}{
+		"locationId": locationID.String(),
+		"rules":      reminder.FormatRules(policy.Rules),
+	}, "")
+	return &EffectivePolicy{LocationID: locationID, Rules: policy.Rules, UpdatedAt: policy.UpdatedAt}, nil
+}
+
+// DeletePolicy reverts a location to the default rules.
+func (uc *UseCase) DeletePolicy(ctx context.Context, locationID uuid.UUID) error {
+	err := uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo, recorder := uc.reposFor(tx)
+		before, err := uc.storedPolicy(ctx, repo, locationID)
+		if err != nil {
+			return err
+		}
+		if err := repo.DeletePolicy(ctx, locationID); err != nil {
+			return err
+		}
+		return recorder.Record(ctx, audit.Change{
+			Action:     "reminder_policy.delete",
+			EntityType: audit.EntityReminderPolicy,
+			EntityID:   locationID.String(),
+			Before:     before,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	logger.Info(ctx, "reminder usecase policy deleted", map[string]interface{}{
+		"locationId": locationID.String(),
+	}, "")
+	return nil
+}
+
+func (uc *UseCase) reposFor(tx *gorm.DB) (reminder.Repository, audit.Recorder) {
+	repo, recorder := uc.repo, auditusecase.Bind(uc.audit, tx)
+	if tx != nil {
+		if r, ok := repo.(txRepository); ok {
+			repo = r.WithDB(tx)
+		}
+	}
+	return repo, recorder
+}
+
+// storedPolicy returns the audit snapshot of a location's policy, or nil when it has none.
+func (uc *UseCase) storedPolicy(ctx context.Context, repo reminder.Repository, locationID uuid.UUID) (map[string]interface{}, error) {
+	policy, err := repo.GetPolicy(ctx, locationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return policySnapshot(policy), nil
+}
+
+func policySnapshot(p *reminder.Policy) map[string]interface{} {
+	return map[string]interface{}{
+		"location_id": p.LocationID,
+		"rules":       reminder.FormatRules(p.Rules),
+	}
+}
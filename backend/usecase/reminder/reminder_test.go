@@ -0,0 +1,82 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"smart-parcel-locker/backend/domain/parcel"
+	"smart-parcel-locker/backend/domain/reminder"
+)
+
+type fakeRepo struct {
+	reminder.Repository
+	policies []reminder.Policy
+	queries  []reminder.DueQuery
+}
+
+func (f *fakeRepo) ListPolicies(context.Context) ([]reminder.Policy, error) {
+	return f.policies, nil
+}
+
+func (f *fakeRepo) ListDue(_ context.Context, q reminder.DueQuery) ([]reminder.Candidate, error) {
+	f.queries = append(f.queries, q)
+	return nil, nil
+}
+
+func TestRunQueriesEachRuleForTheLocationsItAppliesTo(t *testing.T) {
+	custom, off := uuid.New(), uuid.New()
+	defaults, err := reminder.ParseRules("DEPOSITED+24h,EXPIRY-24h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	customRules, err := reminder.ParseRules("EXPIRY-24h,EXPIRY-2h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &fakeRepo{policies: []reminder.Policy{
+		{LocationID: custom, Rules: customRules},
+		{LocationID: off},
+	}}
+	uc := NewUseCase(repo, nil, nil, nil, nil, defaults, nil)
+
+	if err := uc.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	byKey := map[string]reminder.DueQuery{}
+	for _, q := range repo.queries {
+		byKey[q.Rule.Key()] = q
+	}
+	if len(byKey) != 3 {
+		t.Fatalf("expected one query per distinct rule, got %+v", repo.queries)
+	}
+	if q := byKey["DEPOSITED+24h"]; !q.Default || len(q.LocationIDs) != 0 || len(q.PolicyLocationIDs) != 2 {
+		t.Fatalf("default-only rule must skip every location with a policy: %+v", q)
+	}
+	if q := byKey["EXPIRY-24h"]; !q.Default || len(q.LocationIDs) != 1 || q.LocationIDs[0] != custom {
+		t.Fatalf("shared rule must cover the custom location and the defaults: %+v", q)
+	}
+	if q := byKey["EXPIRY-2h"]; q.Default || len(q.LocationIDs) != 1 || q.LocationIDs[0] != custom {
+		t.Fatalf("custom rule must only cover its location: %+v", q)
+	}
+	for _, q := range repo.queries {
+		if q.Now.IsZero() || time.Since(q.Now) > time.Minute {
+			t.Fatalf("query without the run time: %+v", q)
+		}
+	}
+}
+
+func TestReminderDataShowsTimesInTimezone(t *testing.T) {
+	bangkok, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		t.Skip("no zoneinfo database:", err)
+	}
+	expires := time.Date(2025, 1, 9, 17, 0, 0, 0, time.UTC)
+	c := reminder.Candidate{Parcel: &parcel.Parcel{ParcelCode: "P-1", ExpiresAt: &expires}}
+	data := reminderData(c, reminder.Rule{Anchor: reminder.AnchorExpiry}, bangkok)
+	if want := "2025-01-10 00:00 +07"; data["deadline"] != want {
+		t.Fatalf("expected deadline %q, got %q", want, data["deadline"])
+	}
+}
@@ -0,0 +1,44 @@
+package webhook
+
+import (
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/audit"
+	"smart-parcel-locker/backend/domain/webhook"
+	auditusecase "smart-parcel-locker/backend/usecase/audit"
+)
+
+// reposFor binds the repository and audit recorder to tx, so a subscription change and its
+// audit entry commit together.
+func (uc *UseCase) reposFor(tx *gorm.DB) (webhook.Repository, audit.Recorder) {
+	repo, recorder := uc.repo, auditusecase.Bind(uc.audit, tx)
+	if tx == nil {
+		return repo, recorder
+	}
+	if r, ok := repo.(txRepository); ok {
+		repo = r.WithDB(tx)
+	}
+	return repo, recorder
+}
+
+// subscriptionSnapshot leaves out the signing secret.
+func subscriptionSnapshot(s *webhook.Subscription) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           s.ID,
+		"carrier_code": s.CarrierCode,
+		"url":          s.URL,
+		"event_types":  s.EventTypes,
+		"is_active":    s.IsActive,
+	}
+}
+
+func deliverySnapshot(d *webhook.Delivery) map[string]interface{} {
+	return map[string]interface{}{
+		"id":              d.ID,
+		"subscription_id": d.SubscriptionID,
+		"event_id":        d.EventID,
+		"event_type":      d.EventType,
+		"status":          d.Status,
+		"replay_of":       d.ReplayOf,
+	}
+}
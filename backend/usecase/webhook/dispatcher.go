@@ -0,0 +1,160 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"smart-parcel-locker/backend/domain/webhook"
+	"smart-parcel-locker/backend/pkg/backoff"
+	"smart-parcel-locker/backend/pkg/logger"
+)
+
+// DispatcherConfig controls webhook delivery retries.
+type DispatcherConfig struct {
+	BatchSize   int
+	MaxAttempts int
+	// BaseBackoff is the delay after the first failure; it doubles per attempt up to MaxBackoff.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// Lease is how long a claimed delivery is hidden from other dispatchers while being sent.
+	Lease time.Duration
+}
+
+// Dispatcher sends pending deliveries and records each outcome in the delivery log.
+type Dispatcher struct {
+	repo   webhook.Repository
+	sender webhook.Sender
+	cfg    DispatcherConfig
+	now    func() time.Time
+}
+
+func NewDispatcher(repo webhook.Repository, sender webhook.Sender, cfg DispatcherConfig) *Dispatcher {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 50
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 10
+	}
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = 30 * time.Second
+	}
+	if cfg.MaxBackoff < cfg.BaseBackoff {
+		cfg.MaxBackoff = cfg.BaseBackoff
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = time.Minute
+	}
+	return &Dispatcher{repo: repo, sender: sender, cfg: cfg, now: time.Now}
+}
+
+// Run sends one batch of due deliveries.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	now := d.now()
+	batch, err := d.repo.ClaimDue(ctx, now, now.Add(d.cfg.Lease), d.cfg.BatchSize)
+	if err != nil {
+		logger.Error(ctx, "webhook dispatcher claim failed unexpectedly", map[string]interface{}{
+			"error": err.Error(),
+		}, "")
+		return err
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+
+	subs := map[uuid.UUID]*webhook.Subscription{}
+	var succeeded, retried, dead int
+	for _, delivery := range batch {
+		sub, ok := subs[delivery.SubscriptionID]
+		if !ok {
+			sub, err = d.repo.GetSubscription(ctx, delivery.SubscriptionID)
+			if err != nil && !errors.Is(err, webhook.ErrSubscriptionNotFound) {
+				logger.Error(ctx, "webhook dispatcher load subscription failed unexpectedly", map[string]interface{}{
+					"subscriptionId": delivery.SubscriptionID.String(),
+					"error":          err.Error(),
+				}, "")
+				continue
+			}
+			subs[delivery.SubscriptionID] = sub
+		}
+		switch d.deliver(ctx, sub, delivery) {
+		case webhook.DeliverySucceeded:
+			succeeded++
+		case webhook.DeliveryDead:
+			dead++
+		default:
+			retried++
+		}
+	}
+	logger.Info(ctx, "webhook dispatcher run completed", map[string]interface{}{
+		"claimed":   len(batch),
+		"succeeded": succeeded,
+		"retried":   retried,
+		"dead":      dead,
+	}, "")
+	return nil
+}
+
+// deliver sends one delivery and records the outcome, returning the resulting status.
+func (d *Dispatcher) deliver(ctx context.Context, sub *webhook.Subscription, delivery *webhook.Delivery) webhook.DeliveryStatus {
+	if sub == nil || !sub.IsActive {
+		d.markDead(ctx, delivery, delivery.Attempts, nil, "subscription is inactive or deleted")
+		return webhook.DeliveryDead
+	}
+
+	attempts := delivery.Attempts + 1
+	attempt, err := d.sender.Send(ctx, sub, delivery)
+	var statusCode *int
+	if attempt.StatusCode != 0 {
+		statusCode = &attempt.StatusCode
+	}
+	if err == nil {
+		if err := d.repo.MarkSucceeded(ctx, delivery.ID, attempts, attempt.StatusCode, d.now()); err != nil {
+			logger.Error(ctx, "webhook dispatcher mark succeeded failed unexpectedly", map[string]interface{}{
+				"deliveryId": delivery.ID.String(),
+				"error":      err.Error(),
+			}, "")
+		}
+		return webhook.DeliverySucceeded
+	}
+
+	if attempts >= d.cfg.MaxAttempts {
+		d.markDead(ctx, delivery, attempts, statusCode, err.Error())
+		return webhook.DeliveryDead
+	}
+	next := d.now().Add(backoff.Exponential(attempts, d.cfg.BaseBackoff, d.cfg.MaxBackoff))
+	if markErr := d.repo.MarkFailed(ctx, delivery.ID, attempts, statusCode, next, err.Error()); markErr != nil {
+		logger.Error(ctx, "webhook dispatcher mark failed failed unexpectedly", map[string]interface{}{
+			"deliveryId": delivery.ID.String(),
+			"error":      markErr.Error(),
+		}, "")
+	}
+	logger.Warn(ctx, "webhook dispatcher delivery failed", map[string]interface{}{
+		"deliveryId":     delivery.ID.String(),
+		"subscriptionId": sub.ID.String(),
+		"eventType":      delivery.EventType,
+		"attempts":       attempts,
+		"nextAttemptAt":  next,
+		"error":          err.Error(),
+	}, "")
+	return webhook.DeliveryPending
+}
+
+func (d *Dispatcher) markDead(ctx context.Context, delivery *webhook.Delivery, attempts int, statusCode *int, reason string) {
+	if err := d.repo.MarkDead(ctx, delivery.ID, attempts, statusCode, reason); err != nil {
+		logger.Error(ctx, "webhook dispatcher mark dead failed unexpectedly", map[string]interface{}{
+			"deliveryId": delivery.ID.String(),
+			"error":      err.Error(),
+		}, "")
+		return
+	}
+	logger.Warn(ctx, "webhook dispatcher delivery dead-lettered", map[string]interface{}{
+		"deliveryId":     delivery.ID.String(),
+		"subscriptionId": delivery.SubscriptionID.String(),
+		"eventType":      delivery.EventType,
+		"attempts":       attempts,
+		"reason":         reason,
+	}, "")
+}
@@ -0,0 +1,198 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"smart-parcel-locker/backend/domain/parcel"
+	"smart-parcel-locker/backend/domain/webhook"
+)
+
+type fakeRepo struct {
+	subs       map[uuid.UUID]*webhook.Subscription
+	deliveries map[uuid.UUID]*webhook.Delivery
+}
+
+func newFakeRepo(subs ...*webhook.Subscription) *fakeRepo {
+	f := &fakeRepo{subs: map[uuid.UUID]*webhook.Subscription{}, deliveries: map[uuid.UUID]*webhook.Delivery{}}
+	for _, s := range subs {
+		f.subs[s.ID] = s
+	}
+	return f
+}
+
+func (f *fakeRepo) CreateSubscription(ctx context.Context, sub *webhook.Subscription) (*webhook.Subscription, error) {
+	f.subs[sub.ID] = sub
+	return sub, nil
+}
+
+func (f *fakeRepo) UpdateSubscription(ctx context.Context, sub *webhook.Subscription) (*webhook.Subscription, error) {
+	f.subs[sub.ID] = sub
+	return sub, nil
+}
+
+func (f *fakeRepo) GetSubscription(ctx context.Context, id uuid.UUID) (*webhook.Subscription, error) {
+	s, ok := f.subs[id]
+	if !ok {
+		return nil, webhook.ErrSubscriptionNotFound
+	}
+	return s, nil
+}
+
+func (f *fakeRepo) ListSubscriptions(ctx context.Context, carrierCode string) ([]*webhook.Subscription, error) {
+	return nil, nil
+}
+
+func (f *fakeRepo) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	delete(f.subs, id)
+	return nil
+}
+
+func (f *fakeRepo) ListActiveByCarrier(ctx context.Context, carrierCode string) ([]*webhook.Subscription, error) {
+	var out []*webhook.Subscription
+	for _, s := range f.subs {
+		if s.CarrierCode == carrierCode && s.IsActive {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) EnqueueDeliveries(ctx context.Context, deliveries []*webhook.Delivery) error {
+	for _, d := range deliveries {
+		f.deliveries[d.ID] = d
+	}
+	return nil
+}
+
+func (f *fakeRepo) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*webhook.Delivery, error) {
+	var out []*webhook.Delivery
+	for _, d := range f.deliveries {
+		if d.Status == webhook.DeliveryPending && !d.NextAttemptAt.After(now) {
+			d.NextAttemptAt = leaseUntil
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) MarkSucceeded(ctx context.Context, id uuid.UUID, attempts int, statusCode int, at time.Time) error {
+	d := f.deliveries[id]
+	d.Status, d.Attempts, d.LastStatusCode, d.DeliveredAt = webhook.DeliverySucceeded, attempts, &statusCode, &at
+	return nil
+}
+
+func (f *fakeRepo) MarkFailed(ctx context.Context, id uuid.UUID, attempts int, statusCode *int, next time.Time, lastError string) error {
+	d := f.deliveries[id]
+	d.Attempts, d.LastStatusCode, d.NextAttemptAt, d.LastError = attempts, statusCode, next, &lastError
+	return nil
+}
+
+func (f *fakeRepo) MarkDead(ctx context.Context, id uuid.UUID, attempts int, statusCode *int, lastError string) error {
+	d := f.deliveries[id]
+	d.Status, d.Attempts, d.LastStatusCode, d.LastError = webhook.DeliveryDead, attempts, statusCode, &lastError
+	return nil
+}
+
+func (f *fakeRepo) GetDelivery(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error) {
+	d, ok := f.deliveries[id]
+	if !ok {
+		return nil, webhook.ErrDeliveryNotFound
+	}
+	return d, nil
+}
+
+func (f *fakeRepo) ListDeliveries(ctx context.Context, filter webhook.DeliveryFilter) ([]*webhook.Delivery, int64, error) {
+	return nil, 0, nil
+}
+
+type fakeSender struct {
+	status int
+	err    error
+	calls  int
+}
+
+func (f *fakeSender) Send(ctx context.Context, sub *webhook.Subscription, d *webhook.Delivery) (webhook.Attempt, error) {
+	f.calls++
+	return webhook.Attempt{StatusCode: f.status}, f.err
+}
+
+func TestPublishAndDispatch(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 9, 12, 0, 0, 0, time.UTC)
+	carrier := "KERRY"
+	wantsDeposit := &webhook.Subscription{ID: uuid.New(), CarrierCode: carrier, EventTypes: []webhook.EventType{webhook.EventParcelDeposited}, IsActive: true}
+	wantsPickup := &webhook.Subscription{ID: uuid.New(), CarrierCode: carrier, EventTypes: []webhook.EventType{webhook.EventParcelPickedUp}, IsActive: true}
+	otherCarrier := &webhook.Subscription{ID: uuid.New(), CarrierCode: "FLASH", EventTypes: []webhook.EventType{webhook.EventParcelDeposited}, IsActive: true}
+	repo := newFakeRepo(wantsDeposit, wantsPickup, otherCarrier)
+
+	publisher := NewPublisher(repo)
+	publisher.now = func() time.Time { return now }
+	p := &parcel.Parcel{ID: uuid.New(), ParcelCode: "PR-1", CarrierCode: &carrier, Status: parcel.StatusReadyForPickup}
+	if err := publisher.Publish(ctx, p, &parcel.Event{ID: uuid.New(), ParcelID: p.ID, EventType: string(parcel.StatusReadyForPickup), CreatedAt: now}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if len(repo.deliveries) != 1 {
+		t.Fatalf("deliveries = %d, want 1", len(repo.deliveries))
+	}
+	var delivery *webhook.Delivery
+	for _, d := range repo.deliveries {
+		delivery = d
+	}
+	if delivery.SubscriptionID != wantsDeposit.ID || delivery.EventType != webhook.EventParcelDeposited {
+		t.Fatalf("unexpected delivery %+v", delivery)
+	}
+
+	sender := &fakeSender{status: 500, err: errors.New("boom")}
+	dispatcher := NewDispatcher(repo, sender, DispatcherConfig{MaxAttempts: 2, BaseBackoff: time.Minute})
+	dispatcher.now = func() time.Time { return now }
+	if err := dispatcher.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if delivery.Status != webhook.DeliveryPending || delivery.Attempts != 1 || !delivery.NextAttemptAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("after first failure: %+v", delivery)
+	}
+
+	now = now.Add(time.Minute)
+	if err := dispatcher.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if delivery.Status != webhook.DeliveryDead || *delivery.LastStatusCode != 500 {
+		t.Fatalf("after max attempts: %+v", delivery)
+	}
+
+	uc := NewUseCase(repo, nil, nil)
+	uc.now = func() time.Time { return now }
+	replay, err := uc.Replay(ctx, delivery.ID)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if replay.EventID != delivery.EventID || *replay.ReplayOf != delivery.ID {
+		t.Fatalf("unexpected replay %+v", replay)
+	}
+	sender.status, sender.err = 200, nil
+	if err := dispatcher.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if replay.Status != webhook.DeliverySucceeded || sender.calls != 3 {
+		t.Fatalf("replay status=%s calls=%d", replay.Status, sender.calls)
+	}
+}
+
+func TestDispatchInactiveSubscription(t *testing.T) {
+	sub := &webhook.Subscription{ID: uuid.New(), CarrierCode: "KERRY", EventTypes: []webhook.EventType{webhook.EventParcelDeposited}}
+	repo := newFakeRepo(sub)
+	d := webhook.NewDelivery(sub, uuid.New(), webhook.EventParcelDeposited, []byte(`{}`), time.Now().Add(-time.Second))
+	_ = repo.EnqueueDeliveries(context.Background(), []*webhook.Delivery{d})
+	sender := &fakeSender{status: 200}
+	if err := NewDispatcher(repo, sender, DispatcherConfig{}).Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if d.Status != webhook.DeliveryDead || sender.calls != 0 {
+		t.Fatalf("status=%s calls=%d", d.Status, sender.calls)
+	}
+}
@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/parcel"
+	"smart-parcel-locker/backend/domain/webhook"
+	"smart-parcel-locker/backend/pkg/logger"
+)
+
+type txRepository interface {
+	WithDB(db *gorm.DB) webhook.Repository
+}
+
+// Publisher queues deliveries for a parcel event. Callers use WithDB so the deliveries commit
+// with the event itself.
+type Publisher struct {
+	repo webhook.Repository
+	now  func() time.Time
+}
+
+func NewPublisher(repo webhook.Repository) *Publisher {
+	return &Publisher{repo: repo, now: time.Now}
+}
+
+// WithDB returns a publisher bound to the caller's transaction.
+func (p *Publisher) WithDB(db *gorm.DB) webhook.Publisher {
+	if r, ok := p.repo.(txRepository); ok {
+		return &Publisher{repo: r.WithDB(db), now: p.now}
+	}
+	return p
+}
+
+// Publish queues one delivery per active subscription of the parcel's carrier that wants the event.
+func (p *Publisher) Publish(ctx context.Context, pc *parcel.Parcel, e *parcel.Event) error {
+	event, ok := webhook.NewParcelEvent(pc, e)
+	if !ok {
+		return nil
+	}
+	subs, err := p.repo.ListActiveByCarrier(ctx, event.Data.CarrierCode)
+	if err != nil {
+		return err
+	}
+	var payload []byte
+	var deliveries []*webhook.Delivery
+	now := p.now()
+	for _, sub := range subs {
+		if !sub.Wants(event.Type) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = event.Payload(); err != nil {
+				return err
+			}
+		}
+		deliveries = append(deliveries, webhook.NewDelivery(sub, event.ID, event.Type, payload, now))
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := p.repo.EnqueueDeliveries(ctx, deliveries); err != nil {
+		return err
+	}
+	logger.Info(ctx, "webhook publisher event queued", map[string]interface{}{
+		"parcelId":    pc.ID.String(),
+		"eventType":   event.Type,
+		"carrierCode": event.Data.CarrierCode,
+		"deliveries":  len(deliveries),
+	}, "")
+	return nil
+}
@@ -0,0 +1,364 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"smart-parcel-locker/backend/domain/audit"
+	"smart-parcel-locker/backend/domain/webhook"
+	"smart-parcel-locker/backend/infrastructure/database"
+	"smart-parcel-locker/backend/pkg/logger"
+)
+
+// UseCase manages carrier webhook subscriptions and the delivery log.
+type UseCase struct {
+	repo  webhook.Repository
+	audit audit.Recorder
+	tx    *database.TransactionManager
+	now   func() time.Time
+}
+
+// NewUseCase builds the use case. recorder may be nil, in which case changes are not audited.
+func NewUseCase(repo webhook.Repository, recorder audit.Recorder, tx *database.TransactionManager) *UseCase {
+	if tx == nil {
+		tx = database.NewTransactionManager(nil)
+	}
+	return &UseCase{repo: repo, audit: recorder, tx: tx, now: time.Now}
+}
+
+// CreateSubscriptionInput describes a new subscription. An empty Secret is generated.
+type CreateSubscriptionInput struct {
+	CarrierCode string
+	URL         string
+	EventTypes  []string
+	Secret      string
+	IsActive    *bool
+}
+
+// CreateSubscription registers a carrier endpoint. The returned subscription carries the secret;
+// it is not shown again except after rotation.
+func (uc *UseCase) CreateSubscription(ctx context.Context, input CreateSubscriptionInput) (*webhook.Subscription, error) {
+	carrier := normalizeCarrier(input.CarrierCode)
+	endpoint, err := validateURL(input.URL)
+	if err != nil || carrier == "" {
+		return nil, webhook.ErrInvalidSubscription
+	}
+	types, err := parseEventTypes(input.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+	secret := strings.TrimSpace(input.Secret)
+	if secret == "" {
+		if secret, err = generateSecret(); err != nil {
+			return nil, err
+		}
+	} else if len(secret) < 16 {
+		return nil, webhook.ErrInvalidSubscription
+	}
+	isActive := true
+	if input.IsActive != nil {
+		isActive = *input.IsActive
+	}
+
+	var created *webhook.Subscription
+	err = uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo, recorder := uc.reposFor(tx)
+		var err error
+		created, err = repo.CreateSubscription(ctx, &webhook.Subscription{
+			ID:          uuid.New(),
+			CarrierCode: carrier,
+			URL:         endpoint,
+			EventTypes:  types,
+			Secret:      secret,
+			IsActive:    isActive,
+			CreatedAt:   uc.now(),
+		})
+		if err != nil {
+			return err
+		}
+		return recorder.Record(ctx, audit.Change{
+			Action:     "webhook_subscription.create",
+			EntityType: audit.EntityWebhookSubscription,
+			EntityID:   created.ID.String(),
+			After:      subscriptionSnapshot(created),
+		})
+	})
+	if err != nil {
+		logger.Error(ctx, "webhook usecase create subscription failed unexpectedly", map[string]interface{}{
+			"carrierCode": carrier,
+			"error":       err.Error(),
+		}, "")
+		return nil, err
+	}
+	logger.Info(ctx, "webhook usecase subscription created", map[string]interface{}{
+		"subscriptionId": created.ID.String(),
+		"carrierCode":    carrier,
+		"eventTypes":     joinTypes(types),
+	}, "")
+	return created, nil
+}
+
+// UpdateSubscriptionInput changes the fields that are set.
+type UpdateSubscriptionInput struct {
+	ID         uuid.UUID
+	URL        *string
+	EventTypes []string
+	IsActive   *bool
+}
+
+func (uc *UseCase) UpdateSubscription(ctx context.Context, input UpdateSubscriptionInput) (*webhook.Subscription, error) {
+	sub, err := uc.repo.GetSubscription(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	before := subscriptionSnapshot(sub)
+	if input.URL != nil {
+		if sub.URL, err = validateURL(*input.URL); err != nil {
+			return nil, webhook.ErrInvalidSubscription
+		}
+	}
+	if input.EventTypes != nil {
+		if sub.EventTypes, err = parseEventTypes(input.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+	if input.IsActive != nil {
+		sub.IsActive = *input.IsActive
+	}
+	var updated *webhook.Subscription
+	err = uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo, recorder := uc.reposFor(tx)
+		var err error
+		if updated, err = repo.UpdateSubscription(ctx, sub); err != nil {
+			return err
+		}
+		return recorder.Record(ctx, audit.Change{
+			Action:     "webhook_subscription.update",
+			EntityType: audit.EntityWebhookSubscription,
+			EntityID:   updated.ID.String(),
+			Before:     before,
+			After:      subscriptionSnapshot(updated),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.Info(ctx, "webhook usecase subscription updated", map[string]interface{}{
+		"subscriptionId": updated.ID.String(),
+		"eventTypes":     joinTypes(updated.EventTypes),
+		"isActive":       updated.IsActive,
+	}, "")
+	return updated, nil
+}
+
+// RotateSecret replaces the signing secret and returns the subscription with the new one. The
+// audit entry records the rotation but never the secret.
+func (uc *UseCase) RotateSecret(ctx context.Context, id uuid.UUID) (*webhook.Subscription, error) {
+	sub, err := uc.repo.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub.Secret, err = generateSecret(); err != nil {
+		return nil, err
+	}
+	var updated *webhook.Subscription
+	err = uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo, recorder := uc.reposFor(tx)
+		var err error
+		if updated, err = repo.UpdateSubscription(ctx, sub); err != nil {
+			return err
+		}
+		return recorder.Record(ctx, audit.Change{
+			Action:     "webhook_subscription.rotate_secret",
+			EntityType: audit.EntityWebhookSubscription,
+			EntityID:   id.String(),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.Info(ctx, "webhook usecase secret rotated", map[string]interface{}{
+		"subscriptionId": id.String(),
+	}, "")
+	return updated, nil
+}
+
+func (uc *UseCase) GetSubscription(ctx context.Context, id uuid.UUID) (*webhook.Subscription, error) {
+	return uc.repo.GetSubscription(ctx, id)
+}
+
+func (uc *UseCase) ListSubscriptions(ctx context.Context, carrierCode string) ([]*webhook.Subscription, error) {
+	return uc.repo.ListSubscriptions(ctx, normalizeCarrier(carrierCode))
+}
+
+// DeleteSubscription removes a subscription together with its delivery log.
+func (uc *UseCase) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	sub, err := uc.repo.GetSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo, recorder := uc.reposFor(tx)
+		if err := repo.DeleteSubscription(ctx, id); err != nil {
+			return err
+		}
+		return recorder.Record(ctx, audit.Change{
+			Action:     "webhook_subscription.delete",
+			EntityType: audit.EntityWebhookSubscription,
+			EntityID:   id.String(),
+			Before:     subscriptionSnapshot(sub),
+		})
+	})
+	if err != nil {
+		return err
+	}
+	logger.Info(ctx, "webhook usecase subscription deleted", map[string]interface{}{
+		"subscriptionId": id.String(),
+	}, "")
+	return nil
+}
+
+// Ping queues a ping event so operators and carriers can check an endpoint and its signature.
+func (uc *UseCase) Ping(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error) {
+	sub, err := uc.repo.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	now := uc.now()
+	event := webhook.Event{ID: uuid.New(), Type: webhook.EventPing, OccurredAt: now}
+	payload, err := event.Payload()
+	if err != nil {
+		return nil, err
+	}
+	delivery := webhook.NewDelivery(sub, event.ID, event.Type, payload, now)
+	if err := uc.enqueue(ctx, delivery, "webhook_delivery.ping"); err != nil {
+		return nil, err
+	}
+	logger.Info(ctx, "webhook usecase ping queued", map[string]interface{}{
+		"subscriptionId": id.String(),
+		"deliveryId":     delivery.ID.String(),
+	}, "")
+	return delivery, nil
+}
+
+// ListDeliveries returns the delivery log, newest first.
+func (uc *UseCase) ListDeliveries(ctx context.Context, filter webhook.DeliveryFilter) ([]*webhook.Delivery, int64, error) {
+	switch filter.Status {
+	case "", webhook.DeliveryPending, webhook.DeliverySucceeded, webhook.DeliveryDead:
+	default:
+		return nil, 0, webhook.ErrInvalidRequest
+	}
+	if filter.Limit <= 0 || filter.Limit > 200 {
+		filter.Limit = 50
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return uc.repo.ListDeliveries(ctx, filter)
+}
+
+func (uc *UseCase) GetDelivery(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error) {
+	return uc.repo.GetDelivery(ctx, id)
+}
+
+// Replay queues a fresh delivery of the same payload, whatever the original's outcome. The event
+// id is unchanged so receivers can de-duplicate.
+func (uc *UseCase) Replay(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error) {
+	original, err := uc.repo.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := uc.repo.GetSubscription(ctx, original.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	replay := webhook.NewDelivery(sub, original.EventID, original.EventType, original.Payload, uc.now())
+	replay.ReplayOf = &original.ID
+	if err := uc.enqueue(ctx, replay, "webhook_delivery.replay"); err != nil {
+		logger.Error(ctx, "webhook usecase replay failed unexpectedly", map[string]interface{}{
+			"deliveryId": id.String(),
+			"error":      err.Error(),
+		}, "")
+		return nil, err
+	}
+	logger.Info(ctx, "webhook usecase delivery replayed", map[string]interface{}{
+		"deliveryId":     id.String(),
+		"replayId":       replay.ID.String(),
+		"subscriptionId": sub.ID.String(),
+	}, "")
+	return replay, nil
+}
+
+// enqueue queues an admin-initiated delivery and audits it in the same transaction.
+func (uc *UseCase) enqueue(ctx context.Context, delivery *webhook.Delivery, action string) error {
+	return uc.tx.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		repo, recorder := uc.reposFor(tx)
+		if err := repo.EnqueueDeliveries(ctx, []*webhook.Delivery{delivery}); err != nil {
+			return err
+		}
+		return recorder.Record(ctx, audit.Change{
+			Action:     action,
+			EntityType: audit.EntityWebhookDelivery,
+			EntityID:   delivery.ID.String(),
+			After:      deliverySnapshot(delivery),
+		})
+	})
+}
+
+func normalizeCarrier(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func validateURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", webhook.ErrInvalidSubscription
+	}
+	return u.String(), nil
+}
+
+func parseEventTypes(raw []string) ([]webhook.EventType, error) {
+	seen := map[webhook.EventType]bool{}
+	var types []webhook.EventType
+	for _, r := range raw {
+		t := webhook.EventType(strings.ToLower(strings.TrimSpace(r)))
+		if !t.Valid() {
+			return nil, webhook.ErrInvalidSubscription
+		}
+		if !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+	if len(types) == 0 {
+		return nil, webhook.ErrInvalidSubscription
+	}
+	return types, nil
+}
+
+func joinTypes(types []webhook.EventType) string {
+	parts := make([]string, 0, len(types))
+	for _, t := range types {
+		parts = append(parts, string(t))
+	}
+	return strings.Join(parts, ",")
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}